@@ -23,6 +23,7 @@ func admin() *cobra.Command {
 				adminPlugins,
 				adminBroadcasts,
 				adminErrors,
+				adminSecrets,
 				usr,
 				group,
 				worker,
@@ -37,5 +38,6 @@ func admin() *cobra.Command {
 			adminPlugins,
 			adminBroadcasts,
 			adminErrors,
+			adminSecrets,
 		})
 }
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	adminSecretsCmd = cli.Command{
+		Name:  "secrets",
+		Short: "Manage the encryption of CDS secrets",
+	}
+
+	adminSecrets = cli.NewCommand(adminSecretsCmd, nil,
+		[]*cobra.Command{
+			cli.NewCommand(adminSecretsStatusCmd, adminSecretsStatusRun, nil),
+			cli.NewCommand(adminSecretsReencryptCmd, adminSecretsReencryptRun, nil),
+		})
+)
+
+var adminSecretsStatusCmd = cli.Command{
+	Name:  "status",
+	Short: "Show the progress of the re-encryption of the secrets with the current master key",
+}
+
+func adminSecretsStatusRun(v cli.Values) error {
+	status, err := client.SecretReencryptionStatus()
+	if err != nil {
+		return err
+	}
+	printSecretReencryptionStatus(status)
+	return nil
+}
+
+var adminSecretsReencryptCmd = cli.Command{
+	Name:  "reencrypt",
+	Short: "Re-encrypt the secrets stored by CDS with the current master key",
+	Long: `Re-encrypt the secrets stored by CDS with the current master key, set by keyID in the secrets section of the API configuration: the variables and the keys of the projects, applications and environments, their audits, the repositories managers, the platforms and the workflow hooks.
+
+The re-encryption runs in background on the API, its progress is shown by "cdsctl admin secrets status". It is saved in the database: a re-encryption interrupted by the restart of the API is resumed by the next API started.`,
+}
+
+func adminSecretsReencryptRun(v cli.Values) error {
+	status, err := client.SecretReencryptionStart()
+	if err != nil {
+		return err
+	}
+	printSecretReencryptionStatus(status)
+	return nil
+}
+
+func printSecretReencryptionStatus(status *sdk.SecretReencryptionStatus) {
+	keyID := status.KeyID
+	if keyID == "" {
+		keyID = "legacy key"
+	}
+	fmt.Printf("Current master key: %s\n", keyID)
+	switch {
+	case status.Running:
+		fmt.Printf("Re-encryption running since %s: %d secrets re-encrypted\n", status.Started.Format(time.RFC3339), status.Reencrypted)
+	case status.Ended != nil:
+		fmt.Printf("Last re-encryption ended at %s: %d secrets re-encrypted\n", status.Ended.Format(time.RFC3339), status.Reencrypted)
+	}
+	if status.Error != "" {
+		fmt.Printf("Error: %s\n", status.Error)
+	}
+	for _, t := range status.Tables {
+		fmt.Printf("  %-40s %d/%d\n", t.Name, t.Done, t.Total)
+	}
+}
@@ -23,6 +23,7 @@ type config struct {
 	Host                  string
 	User                  string
 	Token                 string
+	AccessToken           string
 	InsecureSkipVerifyTLS bool
 }
 
@@ -43,6 +44,7 @@ func loadConfig(configFile string) (*cdsclient.Config, error) {
 	c.Host = os.Getenv("CDS_API_URL")
 	c.User = os.Getenv("CDS_USER")
 	c.Token = os.Getenv("CDS_TOKEN")
+	c.AccessToken = os.Getenv("CDS_ACCESS_TOKEN")
 	c.InsecureSkipVerifyTLS, _ = strconv.ParseBool(os.Getenv("CDS_INSECURE"))
 	if insecureSkipVerifyTLS { // if set from command line
 		c.InsecureSkipVerifyTLS = true
@@ -105,6 +107,7 @@ func loadConfig(configFile string) (*cdsclient.Config, error) {
 		Host:                  c.Host,
 		User:                  c.User,
 		Token:                 c.Token,
+		AccessToken:           c.AccessToken,
 		Verbose:               verbose,
 		InsecureSkipVerifyTLS: c.InsecureSkipVerifyTLS,
 	}
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/howeyc/gopass"
 
@@ -42,6 +43,10 @@ var loginCmd = cli.Command{
 			Name:  "env",
 			Usage: "Display the commands to set up the environment for the cds client",
 			Kind:  reflect.Bool,
+		}, {
+			Name:  "sso",
+			Usage: "Login with the OpenID Connect provider of CDS, by entering a code in a browser",
+			Kind:  reflect.Bool,
 		},
 	},
 }
@@ -52,6 +57,13 @@ func loginRun(v cli.Values) error {
 	password := v.GetString("password")
 	env := v.GetBool("env")
 
+	if v.GetBool("sso") {
+		if env && url == "" {
+			return fmt.Errorf("Please set flags to use --env option")
+		}
+		return doLoginOIDC(url, env)
+	}
+
 	if env &&
 		(url == "" || username == "" || password == "") {
 		return fmt.Errorf("Please set flags to use --env option")
@@ -105,7 +117,49 @@ func doLogin(url, username, password string, env bool) error {
 	if !ok {
 		return fmt.Errorf("login failed")
 	}
+	return saveLogin(url, username, token, env)
+}
+
+// doLoginOIDC logs in with the device flow: the user enters a code on the provider, while cdsctl waits for the session
+func doLoginOIDC(url string, env bool) error {
+	conf := cdsclient.Config{
+		Host:    url,
+		Verbose: os.Getenv("CDS_VERBOSE") == "true",
+	}
+
+	client = cdsclient.New(conf)
+	a, err := client.UserLoginOIDCDevice()
+	if err != nil {
+		return fmt.Errorf("Unable to start login: %v", err)
+	}
+
+	// Instructions are printed on stderr to keep stdout for the --env option
+	if a.VerificationURIComplete != "" {
+		fmt.Fprintf(os.Stderr, "To login, open %s and check that the code is %s\n", a.VerificationURIComplete, a.UserCode)
+	} else {
+		fmt.Fprintf(os.Stderr, "To login, open %s and enter the code %s\n", a.VerificationURI, a.UserCode)
+	}
+
+	interval := time.Duration(a.Interval) * time.Second
+	if interval == 0 {
+		interval = 5 * time.Second
+	}
+	deadline := time.Now().Add(time.Duration(a.ExpiresIn) * time.Second)
+	for time.Now().Before(deadline) {
+		time.Sleep(interval)
+		res, err := client.UserLoginOIDCDeviceToken(a.DeviceCode)
+		if sdk.ErrorIs(err, sdk.ErrOIDCAuthorizationPending) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("login failed: %v", err)
+		}
+		return saveLogin(url, res.User.Username, res.Token, env)
+	}
+	return fmt.Errorf("login failed: the code has expired")
+}
 
+func saveLogin(url, username, token string, env bool) error {
 	if env && sdk.GOOS == "windows" {
 		fmt.Println("env option is not supported on windows yet")
 		os.Exit(1)
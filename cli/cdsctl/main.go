@@ -53,6 +53,7 @@ func getRoot(isShell bool) *cobra.Command {
 			environment,
 			pipeline,
 			group,
+			role,
 			health,
 			project(),
 			worker,
@@ -64,6 +65,7 @@ func getRoot(isShell bool) *cobra.Command {
 			version,
 			encrypt,
 			token,
+			template,
 			admin(),
 		}
 	}
@@ -111,6 +113,10 @@ You can bypass keychain tools by using environment variables:
 
 	CDS_API_URL="https://instance.cds.api" CDS_USER="username" CDS_TOKEN="yourtoken" cdsctl [command]
 
+Scripts and bots should rather use a personal access token, see ` + "`cdsctl token access`" + `:
+
+	CDS_API_URL="https://instance.cds.api" CDS_ACCESS_TOKEN="cdsat_..." cdsctl [command]
+
 
 Want to debug something? You can use ` + "`CDS_VERBOSE`" + ` environment variable.
 
@@ -30,6 +30,8 @@ func project() *cobra.Command {
 		projectGroup,
 		projectVariable,
 		projectPlatform,
+		projectFreeze,
+		projectRole,
 	}
 	if cli.ShellMode {
 		cmds = append(cmds, application, workflow, environment)
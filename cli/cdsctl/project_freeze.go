@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	projectFreezeCmd = cli.Command{
+		Name:  "freeze",
+		Short: "Manage CDS project freeze windows",
+	}
+
+	projectFreeze = cli.NewCommand(projectFreezeCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(projectFreezeListCmd, projectFreezeListRun, nil, withAllCommandModifiers()...),
+			cli.NewCommand(projectFreezeAddCmd, projectFreezeAddRun, nil, withAllCommandModifiers()...),
+			cli.NewDeleteCommand(projectFreezeDeleteCmd, projectFreezeDeleteRun, nil, withAllCommandModifiers()...),
+			cli.NewListCommand(projectFreezeOverridesCmd, projectFreezeOverridesRun, nil, withAllCommandModifiers()...),
+			cli.NewListCommand(projectFreezeAuditCmd, projectFreezeAuditRun, nil, withAllCommandModifiers()...),
+		})
+)
+
+var projectFreezeListCmd = cli.Command{
+	Name:  "list",
+	Short: "List CDS project freeze windows",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+}
+
+func projectFreezeListRun(v cli.Values) (cli.ListResult, error) {
+	fs, err := client.ProjectFreezeWindowsList(v[_ProjectKey])
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(fs), nil
+}
+
+var projectFreezeAddCmd = cli.Command{
+	Name:  "add",
+	Short: "Add a freeze window on project, or on one of its environments",
+	Long: `Add a freeze window on project, or on one of its environments. The pipelines targeting the environment are not started during the freeze.
+
+A freeze window is either a date range, or a cron expression starting a recurrent freeze of a given duration.`,
+	Example: `cdsctl project freeze add MYPROJ "Christmas holidays" --environment production --start 2018-12-22T00:00:00Z --end 2019-01-02T00:00:00Z
+cdsctl project freeze add MYPROJ "No deployment during the weekend" --cron "0 18 * * 5" --duration 62h --timezone Europe/Paris --override-group ops`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "reason"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "environment",
+			Usage: "Environment frozen, all the environments of the project by default",
+			Kind:  reflect.String,
+		},
+		{
+			Name:  "start",
+			Usage: "Start date of the freeze, in RFC3339 format",
+			Kind:  reflect.String,
+		},
+		{
+			Name:  "end",
+			Usage: "End date of the freeze, in RFC3339 format",
+			Kind:  reflect.String,
+		},
+		{
+			Name:  "cron",
+			Usage: "Cron expression starting a recurrent freeze",
+			Kind:  reflect.String,
+		},
+		{
+			Name:  "duration",
+			Usage: "Duration of a recurrent freeze, ie. 48h",
+			Kind:  reflect.String,
+		},
+		{
+			Name:  "timezone",
+			Usage: "Timezone of the cron expression, UTC by default",
+			Kind:  reflect.String,
+		},
+		{
+			Name:  "override-group",
+			Usage: "Group allowed to override the freeze",
+			Kind:  reflect.Slice,
+		},
+	},
+}
+
+func projectFreezeAddRun(v cli.Values) error {
+	f := &sdk.FreezeWindow{
+		Reason:          v["reason"],
+		EnvironmentName: v.GetString("environment"),
+		Cron:            v.GetString("cron"),
+		Timezone:        v.GetString("timezone"),
+		OverrideGroups:  v.GetStringSlice("override-group"),
+	}
+	if s := v.GetString("start"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return fmt.Errorf("invalid start date %s: %v", s, err)
+		}
+		f.Start = t
+	}
+	if s := v.GetString("end"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return fmt.Errorf("invalid end date %s: %v", s, err)
+		}
+		f.End = t
+	}
+	if s := v.GetString("duration"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid duration %s: %v", s, err)
+		}
+		f.Duration = int64(d.Seconds())
+	}
+
+	if err := client.ProjectFreezeWindowCreate(v[_ProjectKey], f); err != nil {
+		return err
+	}
+	fmt.Printf("Freeze window %d created\n", f.ID)
+	return nil
+}
+
+var projectFreezeDeleteCmd = cli.Command{
+	Name:  "delete",
+	Short: "Delete a CDS project freeze window",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "id"},
+	},
+}
+
+func projectFreezeDeleteRun(v cli.Values) error {
+	id, err := strconv.ParseInt(v["id"], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid freeze window id %s", v["id"])
+	}
+	return client.ProjectFreezeWindowDelete(v[_ProjectKey], id)
+}
+
+var projectFreezeOverridesCmd = cli.Command{
+	Name:  "overrides",
+	Short: "List the pipelines started despite a CDS project freeze window",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "id"},
+	},
+}
+
+func projectFreezeOverridesRun(v cli.Values) (cli.ListResult, error) {
+	id, err := strconv.ParseInt(v["id"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid freeze window id %s", v["id"])
+	}
+	overrides, err := client.ProjectFreezeWindowOverrides(v[_ProjectKey], id)
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(overrides), nil
+}
+
+var projectFreezeAuditCmd = cli.Command{
+	Name:  "audit",
+	Short: "List the updates and the deletions of CDS project freeze windows",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+}
+
+func projectFreezeAuditRun(v cli.Values) (cli.ListResult, error) {
+	audits, err := client.ProjectFreezeWindowAudits(v[_ProjectKey])
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(audits), nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	projectRoleCmd = cli.Command{
+		Name:  "role",
+		Short: "Manage CDS roles bound to groups on a project",
+	}
+
+	projectRole = cli.NewCommand(projectRoleCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(projectRoleListCmd, projectRoleListRun, nil, withAllCommandModifiers()...),
+			cli.NewCommand(projectRoleAddCmd, projectRoleAddRun, nil, withAllCommandModifiers()...),
+			cli.NewDeleteCommand(projectRoleDeleteCmd, projectRoleDeleteRun, nil, withAllCommandModifiers()...),
+		})
+)
+
+var projectRoleListCmd = cli.Command{
+	Name:  "list",
+	Short: "List the roles bound to groups on a project and on its objects",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+}
+
+func projectRoleListRun(v cli.Values) (cli.ListResult, error) {
+	bs, err := client.ProjectRoleBindingsList(v[_ProjectKey])
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(bs), nil
+}
+
+var projectRoleAddCmd = cli.Command{
+	Name:  "add",
+	Short: "Bind a role to a group on a project, or on one of its objects",
+	Example: `cdsctl project role add MYPROJ ops read-execute
+cdsctl project role add MYPROJ ops deployer --type environment --name production`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "group"},
+		{Name: "role"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:    "type",
+			Usage:   "Type of object: project, application, pipeline, environment or workflow",
+			Kind:    reflect.String,
+			Default: sdk.RoleBindingProject,
+		},
+		{
+			Name:  "name",
+			Usage: "Name of the object, mandatory unless the type is project",
+			Kind:  reflect.String,
+		},
+	},
+}
+
+func projectRoleAddRun(v cli.Values) error {
+	b := &sdk.RoleBinding{
+		GroupName:  v["group"],
+		RoleName:   v["role"],
+		ObjectType: v.GetString("type"),
+		ObjectName: v.GetString("name"),
+	}
+	if err := client.ProjectRoleBindingCreate(v[_ProjectKey], b); err != nil {
+		return err
+	}
+	fmt.Printf("Role binding %d created\n", b.ID)
+	return nil
+}
+
+var projectRoleDeleteCmd = cli.Command{
+	Name:  "delete",
+	Short: "Delete a role binding of a project",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "id"},
+	},
+}
+
+func projectRoleDeleteRun(v cli.Values) error {
+	id, err := strconv.ParseInt(v["id"], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid role binding id %s", v["id"])
+	}
+	return client.ProjectRoleBindingDelete(v[_ProjectKey], id)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"reflect"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	roleCmd = cli.Command{
+		Name:  "role",
+		Short: "Manage CDS roles",
+	}
+
+	role = cli.NewCommand(roleCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(roleListCmd, roleListRun, nil),
+			cli.NewCommand(roleAddCmd, roleAddRun, nil),
+			cli.NewCommand(roleUpdateCmd, roleUpdateRun, nil),
+			cli.NewDeleteCommand(roleDeleteCmd, roleDeleteRun, nil),
+		})
+)
+
+var roleListCmd = cli.Command{
+	Name:  "list",
+	Short: "List CDS roles",
+}
+
+func roleListRun(v cli.Values) (cli.ListResult, error) {
+	roles, err := client.RoleList()
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(roles), nil
+}
+
+var roleAddCmd = cli.Command{
+	Name:  "add",
+	Short: "Add a CDS role (admin only)",
+	Long: `Add a CDS role, granting a set of permissions among: read, run, deploy, approve, manage-variables, edit.
+
+The role can then be bound to a group on a project, or on one of its applications, pipelines, environments or workflows.`,
+	Example: `cdsctl role add deployer --permission read --permission deploy`,
+	Args: []cli.Arg{
+		{Name: "name"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "permission",
+			Usage: "Permission granted by the role",
+			Kind:  reflect.Slice,
+		},
+		{
+			Name:  "description",
+			Usage: "Description of the role",
+			Kind:  reflect.String,
+		},
+	},
+}
+
+func roleAddRun(v cli.Values) error {
+	return client.RoleCreate(&sdk.Role{
+		Name:        v["name"],
+		Description: v.GetString("description"),
+		Permissions: v.GetStringSlice("permission"),
+	})
+}
+
+var roleUpdateCmd = cli.Command{
+	Name:    "update",
+	Short:   "Update the permissions of a CDS role (admin only)",
+	Example: `cdsctl role update deployer --permission read --permission deploy --permission approve`,
+	Args: []cli.Arg{
+		{Name: "name"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "permission",
+			Usage: "Permission granted by the role",
+			Kind:  reflect.Slice,
+		},
+		{
+			Name:  "description",
+			Usage: "Description of the role",
+			Kind:  reflect.String,
+		},
+	},
+}
+
+func roleUpdateRun(v cli.Values) error {
+	return client.RoleUpdate(&sdk.Role{
+		Name:        v["name"],
+		Description: v.GetString("description"),
+		Permissions: v.GetStringSlice("permission"),
+	})
+}
+
+var roleDeleteCmd = cli.Command{
+	Name:  "delete",
+	Short: "Delete a CDS role which is not bound to any group (admin only)",
+	Args: []cli.Arg{
+		{Name: "name"},
+	},
+}
+
+func roleDeleteRun(v cli.Values) error {
+	return client.RoleDelete(v["name"])
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+var (
+	templateCmd = cli.Command{
+		Name:  "template",
+		Short: "Manage CDS workflow templates",
+	}
+
+	template = cli.NewCommand(templateCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(templateListCmd, templateListRun, nil),
+			cli.NewGetCommand(templateShowCmd, templateShowRun, nil),
+			cli.NewCommand(templatePullCmd, templatePullRun, nil),
+			cli.NewCommand(templatePushCmd, templatePushRun, nil),
+			cli.NewDeleteCommand(templateDeleteCmd, templateDeleteRun, nil),
+			cli.NewCommand(templateApplyCmd, templateApplyRun, nil),
+			cli.NewListCommand(templateInstancesCmd, templateInstancesRun, nil),
+		})
+)
+
+type templateDisplay struct {
+	Group       string `cli:"group,key"`
+	Name        string `cli:"name,key"`
+	Version     int64  `cli:"version"`
+	Description string `cli:"description"`
+}
+
+func newTemplateDisplay(t sdk.WorkflowTemplate) templateDisplay {
+	d := templateDisplay{
+		Name:        t.Name,
+		Version:     t.Version,
+		Description: t.Description,
+	}
+	if t.Group != nil {
+		d.Group = t.Group.Name
+	}
+	return d
+}
+
+var templateListCmd = cli.Command{
+	Name:  "list",
+	Short: "List CDS workflow templates",
+}
+
+func templateListRun(v cli.Values) (cli.ListResult, error) {
+	ts, err := client.TemplateList()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]templateDisplay, len(ts))
+	for i := range ts {
+		res[i] = newTemplateDisplay(ts[i])
+	}
+	return cli.AsListResult(res), nil
+}
+
+var templateShowCmd = cli.Command{
+	Name:  "show",
+	Short: "Show a CDS workflow template",
+	Args: []cli.Arg{
+		{Name: "group-name"},
+		{Name: "template-name"},
+	},
+}
+
+func templateShowRun(v cli.Values) (interface{}, error) {
+	t, err := client.TemplateGet(v.GetString("group-name"), v.GetString("template-name"))
+	if err != nil {
+		return nil, err
+	}
+	return newTemplateDisplay(*t), nil
+}
+
+var templatePullCmd = cli.Command{
+	Name:  "pull",
+	Short: "Print a CDS workflow template in yaml format",
+	Args: []cli.Arg{
+		{Name: "group-name"},
+		{Name: "template-name"},
+	},
+}
+
+func templatePullRun(v cli.Values) error {
+	t, err := client.TemplateGet(v.GetString("group-name"), v.GetString("template-name"))
+	if err != nil {
+		return err
+	}
+	b, err := yaml.Marshal(exportentities.NewTemplate(*t))
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
+var templatePushCmd = cli.Command{
+	Name:  "push",
+	Short: "Create or update a CDS workflow template from a yaml file",
+	Args: []cli.Arg{
+		{Name: "group-name"},
+		{Name: "path"},
+	},
+}
+
+func templatePushRun(v cli.Values) error {
+	b, err := ioutil.ReadFile(v.GetString("path"))
+	if err != nil {
+		return fmt.Errorf("Error while reading file: %s", err)
+	}
+	var e exportentities.Template
+	if err := yaml.Unmarshal(b, &e); err != nil {
+		return fmt.Errorf("Invalid template file: %s", err)
+	}
+	t := e.WorkflowTemplate()
+	if err := client.TemplatePush(v.GetString("group-name"), &t); err != nil {
+		return err
+	}
+	fmt.Printf("Template %s pushed\n", t.Name)
+	return nil
+}
+
+var templateDeleteCmd = cli.Command{
+	Name:  "delete",
+	Short: "Delete a CDS workflow template",
+	Args: []cli.Arg{
+		{Name: "group-name"},
+		{Name: "template-name"},
+	},
+}
+
+func templateDeleteRun(v cli.Values) error {
+	err := client.TemplateDelete(v.GetString("group-name"), v.GetString("template-name"))
+	if v.GetBool("force") && sdk.ErrorIs(err, sdk.ErrWorkflowTemplateNotFound) {
+		fmt.Println(err)
+		return nil
+	}
+	return err
+}
+
+var templateApplyCmd = cli.Command{
+	Name:  "apply",
+	Short: "Generate or update a workflow of a project from a CDS workflow template",
+	Long:  "Without any parameter, the parameters of the previous apply on the workflow are reused.",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "group-name"},
+		{Name: "template-name"},
+		{Name: "workflow-name"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:      "params",
+			ShortHand: "p",
+			Usage:     "Value of a template parameter: key=value",
+			IsValid: func(s string) bool {
+				if s == "" {
+					return true
+				}
+				for _, p := range strings.Split(s, "||") {
+					if !strings.Contains(p, "=") {
+						return false
+					}
+				}
+				return true
+			},
+			Kind: reflect.Slice,
+		},
+	},
+}
+
+func templateApplyRun(v cli.Values) error {
+	req := sdk.WorkflowTemplateRequest{WorkflowName: v.GetString("workflow-name")}
+	for _, p := range v.GetStringSlice("params") {
+		if p == "" {
+			continue
+		}
+		t := strings.SplitN(p, "=", 2)
+		if req.Parameters == nil {
+			req.Parameters = map[string]string{}
+		}
+		req.Parameters[t[0]] = t[1]
+	}
+
+	msgs, err := client.TemplateApply(v[_ProjectKey], v.GetString("group-name"), v.GetString("template-name"), req)
+	for _, msg := range msgs {
+		fmt.Println(msg)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Workflow %s generated\n", req.WorkflowName)
+	return nil
+}
+
+var templateInstancesCmd = cli.Command{
+	Name:  "instances",
+	Short: "List the workflows of a project generated from CDS workflow templates",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+}
+
+type templateInstanceDisplay struct {
+	Workflow        string `cli:"workflow,key"`
+	Template        string `cli:"template"`
+	Version         int64  `cli:"version"`
+	TemplateVersion int64  `cli:"template_version"`
+	Outdated        bool   `cli:"outdated"`
+	Modified        bool   `cli:"modified"`
+}
+
+func templateInstancesRun(v cli.Values) (cli.ListResult, error) {
+	is, err := client.TemplateInstances(v[_ProjectKey])
+	if err != nil {
+		return nil, err
+	}
+	res := make([]templateInstanceDisplay, len(is))
+	for i, inst := range is {
+		res[i] = templateInstanceDisplay{
+			Workflow: inst.Request.WorkflowName,
+			Version:  inst.WorkflowTemplateVersion,
+		}
+		if inst.Template != nil {
+			res[i].Template = inst.Template.Name
+			res[i].TemplateVersion = inst.Template.Version
+			if inst.Template.Group != nil {
+				res[i].Template = inst.Template.Group.Name + "/" + inst.Template.Name
+			}
+		}
+		if inst.Drift != nil {
+			res[i].Outdated = inst.Drift.Outdated
+			res[i].Modified = inst.Drift.Modified
+		}
+	}
+	return cli.AsListResult(res), nil
+}
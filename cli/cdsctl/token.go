@@ -11,7 +11,7 @@ import (
 var (
 	tokenCmd = cli.Command{
 		Name:  "token",
-		Short: "Manage CDS group token",
+		Short: "Manage CDS group token and personal access tokens",
 	}
 
 	token = cli.NewCommand(tokenCmd, nil,
@@ -20,6 +20,7 @@ var (
 			cli.NewGetCommand(tokenCreateCmd, tokenCreateRun, nil),
 			cli.NewGetCommand(tokenFindCmd, tokenFindRun, nil),
 			cli.NewDeleteCommand(tokenDeleteCmd, tokenDeleteRun, nil),
+			tokenAccess,
 		})
 )
 
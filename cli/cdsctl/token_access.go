@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	tokenAccessCmd = cli.Command{
+		Name:  "access",
+		Short: "Manage your personal access tokens",
+		Long: `Manage your personal access tokens, used by scripts and bots to call the API on your behalf.
+
+Use an access token with cdsctl:
+
+	CDS_API_URL="https://instance.cds.api" CDS_ACCESS_TOKEN="cdsat_..." cdsctl [command]
+
+Or with the header "Authorization: Bearer cdsat_..." on the API.`,
+	}
+
+	tokenAccess = cli.NewCommand(tokenAccessCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(tokenAccessListCmd, tokenAccessListRun, nil),
+			cli.NewGetCommand(tokenAccessCreateCmd, tokenAccessCreateRun, nil),
+			cli.NewCommand(tokenAccessRevokeCmd, tokenAccessRevokeRun, nil),
+			cli.NewListCommand(tokenAccessAuditCmd, tokenAccessAuditRun, nil),
+		})
+)
+
+var tokenAccessListCmd = cli.Command{
+	Name:  "list",
+	Short: "List your access tokens, revoked tokens included",
+}
+
+func tokenAccessListRun(v cli.Values) (cli.ListResult, error) {
+	ts, err := client.UserAccessTokenList()
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(ts), nil
+}
+
+var tokenAccessCreateCmd = cli.Command{
+	Name:  "create",
+	Short: "Create an access token",
+	Long: `Create an access token. The value of the token is only displayed once.
+
+The scope must be [read|run|admin]:
+
+Read allows the GET requests.
+
+Run also allows to run workflows.
+
+Admin allows all your rights, including the CDS administration if you are an administrator.`,
+	Example: `cdsctl token access create my-bot run --project MYPROJ --expire 720h`,
+	Args: []cli.Arg{
+		{Name: "name"},
+		{Name: "scope"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "description",
+			Usage: "Description of the access token",
+			Kind:  reflect.String,
+		},
+		{
+			Name:  "project",
+			Usage: "Restrict the access token to a project",
+			Kind:  reflect.String,
+		},
+		{
+			Name:  "expire",
+			Usage: "Validity of the access token, ie. 720h. The token does not expire by default",
+			Kind:  reflect.String,
+		},
+	},
+}
+
+func tokenAccessCreateRun(v cli.Values) (interface{}, error) {
+	t := sdk.AccessToken{
+		Name:        v["name"],
+		Scope:       v["scope"],
+		Description: v.GetString("description"),
+		ProjectKey:  v.GetString("project"),
+	}
+	if s := v.GetString("expire"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expiration %s: %v", s, err)
+		}
+		expire := time.Now().Add(d)
+		t.Expire = &expire
+	}
+
+	res, err := client.UserAccessTokenCreate(t)
+	if err != nil {
+		return nil, err
+	}
+	return *res, nil
+}
+
+var tokenAccessRevokeCmd = cli.Command{
+	Name:  "revoke",
+	Short: "Revoke an access token",
+	Args: []cli.Arg{
+		{Name: "id"},
+	},
+}
+
+func tokenAccessRevokeRun(v cli.Values) error {
+	id, err := v.GetInt64("id")
+	if err != nil {
+		return fmt.Errorf("Token id is bad formatted")
+	}
+	return client.UserAccessTokenRevoke(id)
+}
+
+var tokenAccessAuditCmd = cli.Command{
+	Name:  "audit",
+	Short: "Display the creation, the revocation and the last uses of an access token",
+	Args: []cli.Arg{
+		{Name: "id"},
+	},
+	Flags: []cli.Flag{
+		{
+			Kind:    reflect.String,
+			Name:    "limit",
+			Usage:   "Number of audits to display",
+			Default: "100",
+		},
+	},
+}
+
+func tokenAccessAuditRun(v cli.Values) (cli.ListResult, error) {
+	id, err := v.GetInt64("id")
+	if err != nil {
+		return nil, fmt.Errorf("Token id is bad formatted")
+	}
+	limit, err := v.GetInt64("limit")
+	if err != nil {
+		return nil, err
+	}
+	as, err := client.UserAccessTokenAudits(id, int(limit))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(as), nil
+}
@@ -21,16 +21,21 @@ var (
 			cli.NewCommand(workflowInitCmd, workflowInitRun, nil),
 			cli.NewListCommand(workflowListCmd, workflowListRun, nil, withAllCommandModifiers()...),
 			cli.NewListCommand(workflowHistoryCmd, workflowHistoryRun, nil, withAllCommandModifiers()...),
+			cli.NewListCommand(workflowDeliveriesCmd, workflowDeliveriesRun, nil, withAllCommandModifiers()...),
 			cli.NewGetCommand(workflowShowCmd, workflowShowRun, nil, withAllCommandModifiers()...),
 			cli.NewGetCommand(workflowStatusCmd, workflowStatusRun, nil, withAllCommandModifiers()...),
 			cli.NewCommand(workflowRunManualCmd, workflowRunManualRun, nil, withAllCommandModifiers()...),
 			cli.NewCommand(workflowStopCmd, workflowStopRun, nil, withAllCommandModifiers()...),
+			cli.NewCommand(workflowApproveCmd, workflowApproveRun, nil, withAllCommandModifiers()...),
+			cli.NewCommand(workflowRejectCmd, workflowRejectRun, nil, withAllCommandModifiers()...),
+			cli.NewListCommand(workflowApprovalsCmd, workflowApprovalsRun, nil, withAllCommandModifiers()...),
 			cli.NewCommand(workflowExportCmd, workflowExportRun, nil, withAllCommandModifiers()...),
 			cli.NewCommand(workflowImportCmd, workflowImportRun, nil, withAllCommandModifiers()...),
 			cli.NewCommand(workflowPullCmd, workflowPullRun, nil, withAllCommandModifiers()...),
 			cli.NewCommand(workflowPushCmd, workflowPushRun, nil, withAllCommandModifiers()...),
 			cli.NewCommand(workflowFavoriteCmd, workflowFavoriteRun, nil, withAllCommandModifiers()...),
 			workflowArtifact,
+			workflowLogs,
 			workflowAdvanced,
 		})
 )
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var workflowApproveCmd = cli.Command{
+	Name:  "approve",
+	Short: "Approve a pipeline waiting for approval in a workflow run",
+	Example: `cdsctl workflow approve MYPROJECT myworkflow 5 deploy
+cdsctl workflow approve MYPROJECT myworkflow 5 deploy --comment "Checked on staging"`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Args: []cli.Arg{
+		{Name: "run-number"},
+		{Name: "node-name"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "comment",
+			Kind:  reflect.String,
+			Usage: "Comment recorded with the approval",
+		},
+	},
+}
+
+var workflowRejectCmd = cli.Command{
+	Name:    "reject",
+	Short:   "Reject a pipeline waiting for approval in a workflow run",
+	Example: `cdsctl workflow reject MYPROJECT myworkflow 5 deploy --comment "Freeze in progress"`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Args: []cli.Arg{
+		{Name: "run-number"},
+		{Name: "node-name"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "comment",
+			Kind:  reflect.String,
+			Usage: "Comment recorded with the rejection",
+		},
+	},
+}
+
+var workflowApprovalsCmd = cli.Command{
+	Name:    "approvals",
+	Short:   "Display the decisions taken on the approval gates of a workflow",
+	Example: `cdsctl workflow approvals MYPROJECT myworkflow`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+}
+
+func workflowApproveRun(v cli.Values) error {
+	return workflowApprovalDecide(v, true)
+}
+
+func workflowRejectRun(v cli.Values) error {
+	return workflowApprovalDecide(v, false)
+}
+
+func workflowApprovalsRun(v cli.Values) (cli.ListResult, error) {
+	audits, err := client.WorkflowApprovalAudits(v[_ProjectKey], v[_WorkflowName])
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(audits), nil
+}
+
+func workflowApprovalDecide(v cli.Values, approved bool) error {
+	runNumber, err := v.GetInt64("run-number")
+	if err != nil {
+		return err
+	}
+	wr, err := client.WorkflowRunGet(v[_ProjectKey], v[_WorkflowName], runNumber)
+	if err != nil {
+		return err
+	}
+
+	var nodeRun *sdk.WorkflowNodeRun
+	for _, wnrs := range wr.WorkflowNodeRuns {
+		for i := range wnrs {
+			if wnrs[i].WorkflowNodeName == v.GetString("node-name") && wnrs[i].Approval != nil && wnrs[i].Approval.Status == sdk.ApprovalPending {
+				nodeRun = &wnrs[i]
+				break
+			}
+		}
+	}
+	if nodeRun == nil {
+		return fmt.Errorf("No pipeline %s waiting for approval in %s #%d", v.GetString("node-name"), v[_WorkflowName], runNumber)
+	}
+
+	res, err := client.WorkflowNodeRunApprove(v[_ProjectKey], v[_WorkflowName], runNumber, nodeRun.ID, approved, v.GetString("comment"))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Approval of %s from workflow %s #%d: %s\n", res.WorkflowNodeName, v[_WorkflowName], res.Number, res.Approval)
+	return nil
+}
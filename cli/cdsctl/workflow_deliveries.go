@@ -0,0 +1,36 @@
+package main
+
+import (
+	"reflect"
+
+	"github.com/ovh/cds/cli"
+)
+
+var workflowDeliveriesCmd = cli.Command{
+	Name:  "deliveries",
+	Short: "Display the last deliveries of CDS workflow webhook notifications",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Flags: []cli.Flag{
+		{
+			Kind:    reflect.String,
+			Name:    "limit",
+			Usage:   "Number of deliveries to display",
+			Default: "20",
+		},
+	},
+}
+
+func workflowDeliveriesRun(v cli.Values) (cli.ListResult, error) {
+	limit, err := v.GetInt64("limit")
+	if err != nil {
+		return nil, err
+	}
+	ds, err := client.WorkflowNotificationDeliveries(v[_ProjectKey], v[_WorkflowName], int(limit))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(ds), nil
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	workflowLogsCmd = cli.Command{
+		Name:  "logs",
+		Short: "Read logs of a Workflow Run",
+	}
+
+	workflowLogs = cli.NewCommand(workflowLogsCmd, nil,
+		[]*cobra.Command{
+			cli.NewCommand(workflowLogsShowCmd, workflowLogsShowRun, nil, withAllCommandModifiers()...),
+			cli.NewListCommand(workflowLogsSearchCmd, workflowLogsSearchRun, nil, withAllCommandModifiers()...),
+		})
+)
+
+var workflowLogsShowCmd = cli.Command{
+	Name:  "show",
+	Short: "Show lines of a step log of a Workflow Run",
+	Example: `cdsctl workflow logs show MYPROJECT myworkflow 5 1234 0 --tail 100 # Show the last 100 lines of the first step of the job 1234
+cdsctl workflow logs show MYPROJECT myworkflow 5 1234 2 --offset 1000 --limit 50 # Show lines 1001 to 1050 of the third step`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Args: []cli.Arg{
+		{Name: "run-number"},
+		{Name: "job-id"},
+		{Name: "step-order"},
+	},
+	Flags: []cli.Flag{
+		{
+			Kind:  reflect.String,
+			Name:  "head",
+			Usage: "Show the first lines of the log",
+		},
+		{
+			Kind:  reflect.String,
+			Name:  "tail",
+			Usage: "Show the last lines of the log",
+		},
+		{
+			Kind:    reflect.String,
+			Name:    "offset",
+			Usage:   "Number of lines to skip",
+			Default: "0",
+		},
+		{
+			Kind:    reflect.String,
+			Name:    "limit",
+			Usage:   "Maximum number of lines to show",
+			Default: "1000",
+		},
+	},
+}
+
+func workflowLogsShowRun(v cli.Values) error {
+	runNumber, err := v.GetInt64("run-number")
+	if err != nil {
+		return err
+	}
+	jobID, err := v.GetInt64("job-id")
+	if err != nil {
+		return err
+	}
+	stepOrder, err := strconv.Atoi(v.GetString("step-order"))
+	if err != nil {
+		return fmt.Errorf("step-order parameter have to be an integer")
+	}
+
+	positiveFlag := func(f string) (int64, error) {
+		i, err := strconv.ParseInt(v.GetString(f), 10, 64)
+		if err != nil || i < 0 {
+			return 0, fmt.Errorf("%s have to be a positive integer", f)
+		}
+		return i, nil
+	}
+
+	var offset, limit int64
+	switch {
+	case v.GetString("head") != "":
+		if limit, err = positiveFlag("head"); err != nil {
+			return err
+		}
+	case v.GetString("tail") != "":
+		if limit, err = positiveFlag("tail"); err != nil {
+			return err
+		}
+		offset = -limit
+	default:
+		if offset, err = positiveFlag("offset"); err != nil {
+			return err
+		}
+		if limit, err = positiveFlag("limit"); err != nil {
+			return err
+		}
+	}
+
+	nodeRunID, err := workflowNodeRunOfJob(v[_ProjectKey], v[_WorkflowName], runNumber, jobID)
+	if err != nil {
+		return err
+	}
+
+	lines, err := client.WorkflowNodeRunJobStepLines(v[_ProjectKey], v[_WorkflowName], runNumber, nodeRunID, jobID, stepOrder, offset, limit)
+	if err != nil {
+		return err
+	}
+	for _, l := range lines.Lines {
+		fmt.Printf("%d\t%s\n", l.Number, l.Value)
+	}
+	return nil
+}
+
+var workflowLogsSearchCmd = cli.Command{
+	Name:    "search",
+	Short:   "Search lines matching a regular expression in the logs of a Workflow Run",
+	Example: `cdsctl workflow logs search MYPROJECT myworkflow 5 "(?i)error"`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Args: []cli.Arg{
+		{Name: "run-number"},
+		{Name: "regex"},
+	},
+	Flags: []cli.Flag{
+		{
+			Kind:    reflect.String,
+			Name:    "limit",
+			Usage:   "Maximum number of lines by node",
+			Default: "100",
+		},
+	},
+}
+
+func workflowLogsSearchRun(v cli.Values) (cli.ListResult, error) {
+	runNumber, err := v.GetInt64("run-number")
+	if err != nil {
+		return nil, err
+	}
+	limit, err := strconv.Atoi(v.GetString("limit"))
+	if err != nil {
+		return nil, fmt.Errorf("limit have to be an integer")
+	}
+
+	wr, err := client.WorkflowRunGet(v[_ProjectKey], v[_WorkflowName], runNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := []sdk.LogLine{}
+	for _, wnrs := range wr.WorkflowNodeRuns {
+		found, err := client.WorkflowNodeRunLogsSearch(v[_ProjectKey], v[_WorkflowName], runNumber, wnrs[0].ID, v.GetString("regex"), limit)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, found...)
+	}
+	return cli.AsListResult(lines), nil
+}
+
+// workflowNodeRunOfJob returns the ID of the latest node run which contains the job
+func workflowNodeRunOfJob(projectKey, workflowName string, runNumber, jobID int64) (int64, error) {
+	wr, err := client.WorkflowRunGet(projectKey, workflowName, runNumber)
+	if err != nil {
+		return 0, err
+	}
+	for _, wnrs := range wr.WorkflowNodeRuns {
+		for _, wnr := range wnrs {
+			for _, s := range wnr.Stages {
+				for _, rj := range s.RunJobs {
+					if rj.ID == jobID {
+						return wnr.ID, nil
+					}
+				}
+			}
+		}
+	}
+	return 0, fmt.Errorf("Job %d not found in workflow run %d", jobID, runNumber)
+}
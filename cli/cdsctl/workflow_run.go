@@ -83,11 +83,16 @@ var workflowRunManualCmd = cli.Command{
 			Usage:     "Open web browser on the workflow run",
 			Kind:      reflect.Bool,
 		},
+		{
+			Name:  "freeze-override",
+			Usage: "Start the pipelines despite the active freeze windows, if you are allowed to override them",
+			Kind:  reflect.Bool,
+		},
 	},
 }
 
 func workflowRunManualRun(v cli.Values) error {
-	manual := sdk.WorkflowNodeRunManual{}
+	manual := sdk.WorkflowNodeRunManual{FreezeOverride: v.GetBool("freeze-override")}
 	if strings.TrimSpace(v.GetString("data")) != "" {
 		data := map[string]interface{}{}
 		if err := json.Unmarshal([]byte(v["data"]), &data); err != nil {
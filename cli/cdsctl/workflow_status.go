@@ -112,6 +112,10 @@ func workflowRunFormatDisplay(run *sdk.WorkflowRun, commit repo.Commit, currentD
 		}
 
 		nodeRun := nodeRuns[0]
+		if nodeRun.Approval != nil && nodeRun.Approval.Status == sdk.ApprovalPending {
+			output += cli.Blue("%s %s (%s)", cli.BuildingChar, cli.Blue(nodeRun.WorkflowNodeName), nodeRun.Approval)
+			continue
+		}
 		switch nodeRun.Status {
 		case sdk.StatusSuccess.String():
 			output += cli.Green("%s %s", cli.OKChar, cli.Green(nodeRun.WorkflowNodeName))
@@ -151,8 +155,10 @@ func workflowStatusRunWithoutTrack(v cli.Values) (interface{}, error) {
 
 	type wtags struct {
 		sdk.WorkflowRun
-		Payload string `cli:"payload"`
-		Tags    string `cli:"tags"`
+		Payload   string `cli:"payload"`
+		Tags      string `cli:"tags"`
+		Retries   string `cli:"retries"`
+		Approvals string `cli:"approvals"`
 	}
 
 	var payload []string
@@ -175,6 +181,52 @@ func workflowStatusRunWithoutTrack(v cli.Values) (interface{}, error) {
 		}
 	}
 
-	wt := &wtags{*run, strings.Join(payload, " "), strings.Join(tags, " ")}
+	wt := &wtags{*run, strings.Join(payload, " "), strings.Join(tags, " "), strings.Join(workflowRunRetries(run), " "), strings.Join(workflowRunApprovals(run), " ")}
 	return *wt, nil
 }
+
+// workflowRunRetries returns the jobs of the latest node runs which have been retried, with their current attempt
+func workflowRunRetries(run *sdk.WorkflowRun) []string {
+	var retries []string
+	for _, nodeRuns := range run.WorkflowNodeRuns {
+		if len(nodeRuns) == 0 {
+			continue
+		}
+		nodeRun := nodeRuns[0]
+		for _, nr := range nodeRuns {
+			if nr.SubNumber > nodeRun.SubNumber {
+				nodeRun = nr
+			}
+		}
+		for _, s := range nodeRun.Stages {
+			for _, rj := range s.RunJobs {
+				if rj.Attempt > 0 {
+					retries = append(retries, fmt.Sprintf("%s/%s(attempt %d)", nodeRun.WorkflowNodeName, rj.Job.Action.Name, rj.Attempt+1))
+				}
+			}
+		}
+	}
+	sort.Strings(retries)
+	return retries
+}
+
+// workflowRunApprovals returns the approval gates of the latest node runs with their status
+func workflowRunApprovals(run *sdk.WorkflowRun) []string {
+	var approvals []string
+	for _, nodeRuns := range run.WorkflowNodeRuns {
+		if len(nodeRuns) == 0 {
+			continue
+		}
+		nodeRun := nodeRuns[0]
+		for _, nr := range nodeRuns {
+			if nr.SubNumber > nodeRun.SubNumber {
+				nodeRun = nr
+			}
+		}
+		if nodeRun.Approval != nil {
+			approvals = append(approvals, fmt.Sprintf("%s:%s", nodeRun.WorkflowNodeName, nodeRun.Approval))
+		}
+	}
+	sort.Strings(approvals)
+	return approvals
+}
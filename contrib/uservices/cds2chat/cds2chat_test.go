@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fatih/structs"
+	"github.com/pelletier/go-toml"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+type fakeMessage struct {
+	Channel  string `json:"channel"`
+	Text     string `json:"text"`
+	ThreadTS string `json:"thread_ts"`
+}
+
+// fakeSlack is a fake of the chat.postMessage method of the Slack Web API
+type fakeSlack struct {
+	mutex    sync.Mutex
+	messages []fakeMessage
+}
+
+func (f *fakeSlack) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if r.URL.Path != "/chat.postMessage" || r.Header.Get("Authorization") != "Bearer xoxb-token" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	var m fakeMessage
+	_ = json.NewDecoder(r.Body).Decode(&m)
+	f.messages = append(f.messages, m)
+	fmt.Fprintf(w, `{"ok": true, "ts": "%d.0001"}`, len(f.messages))
+}
+
+// newTestEvent returns an event serialized as the events sent by the API
+func newTestEvent(t *testing.T, payload interface{}, workflowName string) sdk.Event {
+	e := sdk.Event{
+		EventType:    fmt.Sprintf("%T", payload),
+		Payload:      structs.Map(payload),
+		ProjectKey:   "PROJ",
+		WorkflowName: workflowName,
+	}
+	b, err := json.Marshal(e)
+	assert.NoError(t, err)
+	var res sdk.Event
+	assert.NoError(t, json.Unmarshal(b, &res))
+	return res
+}
+
+func Test_notifier(t *testing.T) {
+	slack := &fakeSlack{}
+	srv := httptest.NewServer(slack)
+	defer srv.Close()
+
+	conf := Configuration{
+		Chat: ChatConf{Platform: "slack", URL: srv.URL, Token: "xoxb-token"},
+		Channels: []ChannelConf{
+			{Project: "PROJ", Channel: "C-proj"},
+			{Project: "PROJ", Workflow: "deploy", Channel: "C-deploy"},
+		},
+	}
+	chat, err := newChatClient(conf.Chat)
+	assert.NoError(t, err)
+	n := newNotifier(conf, chat)
+
+	events := []sdk.Event{
+		newTestEvent(t, sdk.EventRunWorkflow{Number: 12, Status: sdk.StatusBuilding.String()}, "deploy"),
+		newTestEvent(t, sdk.EventRunWorkflowNode{Number: 12, NodeName: "build", Status: sdk.StatusWaiting.String()}, "deploy"),
+		newTestEvent(t, sdk.EventRunWorkflowNode{Number: 12, NodeName: "build", Status: sdk.StatusBuilding.String()}, "deploy"),
+		newTestEvent(t, sdk.EventRunWorkflowNode{Number: 12, NodeName: "build", Status: sdk.StatusSuccess.String()}, "deploy"),
+		newTestEvent(t, sdk.EventRunWorkflowNode{Number: 12, NodeName: "prod", Status: sdk.StatusWaiting.String(), ApprovalStatus: sdk.ApprovalPending, RequiredApprovers: 2}, "deploy"),
+		newTestEvent(t, sdk.EventRunWorkflow{Number: 12, Status: sdk.StatusSuccess.String()}, "deploy"),
+		newTestEvent(t, sdk.EventRunWorkflow{Number: 3, Status: sdk.StatusFail.String()}, "build"),
+		newTestEvent(t, sdk.EventRunWorkflow{Number: 3, Status: sdk.StatusFail.String()}, "build"),
+	}
+	for _, e := range events {
+		assert.NoError(t, n.process(e))
+	}
+
+	assert.Equal(t, []fakeMessage{
+		{Channel: "C-deploy", Text: "PROJ/deploy #12"},
+		{Channel: "C-deploy", Text: "build #12.0 Building", ThreadTS: "1.0001"},
+		{Channel: "C-deploy", Text: "build #12.0 Success", ThreadTS: "1.0001"},
+		{Channel: "C-deploy", Text: "prod #12.0 waiting for approval (0/2)", ThreadTS: "1.0001"},
+		{Channel: "C-deploy", Text: "Workflow Success", ThreadTS: "1.0001"},
+		{Channel: "C-proj", Text: "PROJ/build #3"},
+		{Channel: "C-proj", Text: "Workflow Fail", ThreadTS: "6.0001"},
+	}, slack.messages)
+
+	// No channel for the workflows of other projects
+	e := newTestEvent(t, sdk.EventRunWorkflow{Number: 1, Status: sdk.StatusBuilding.String()}, "deploy")
+	e.ProjectKey = "OTHER"
+	assert.NoError(t, n.process(e))
+	assert.Len(t, slack.messages, 7)
+}
+
+func Test_commander(t *testing.T) {
+	var runPayload map[string]interface{}
+	var approvalRequest *sdk.WorkflowNodeRunApprovalRequest
+	cds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, token, _ := r.BasicAuth(); token != "alice-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/user/alice":
+			fmt.Fprint(w, `{"username": "alice"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/project/PROJ/workflows/deploy/runs":
+			var opts sdk.WorkflowRunPostHandlerOption
+			_ = json.NewDecoder(r.Body).Decode(&opts)
+			runPayload, _ = opts.Manual.Payload.(map[string]interface{})
+			fmt.Fprint(w, `{"num": 13}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/project/PROJ/workflows/deploy/runs":
+			fmt.Fprint(w, `[{"num": 13}]`)
+		case r.Method == http.MethodGet && r.URL.Path == "/project/PROJ/workflows/deploy/runs/13":
+			fmt.Fprint(w, `{"num": 13, "last_subnumber": 0, "status": "Building", "nodes": {"1": [{"status": "Success"}], "2": [{"id": 7, "workflow_node_name": "deploy", "status": "Waiting", "approval": {"status": "Pending", "gate": {"min_approvers": 2}}}]}}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/project/PROJ/workflows/deploy/runs/13/nodes/7/approval":
+			var req sdk.WorkflowNodeRunApprovalRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			approvalRequest = &req
+			fmt.Fprint(w, `{"id": 7, "approval": {"status": "Pending", "gate": {"min_approvers": 2}, "decisions": [{"username": "alice", "approved": true}]}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer cds.Close()
+
+	ids, err := loadIdentities("")
+	assert.NoError(t, err)
+	c := newCommander(Configuration{CDS: CDSConf{URL: cds.URL}, Chat: ChatConf{VerificationToken: "verif"}}, ids)
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	command := func(token, text string) commandResponse {
+		resp, err := http.PostForm(srv.URL, url.Values{"token": {token}, "user_id": {"U1"}, "user_name": {"alice.chat"}, "text": {text}})
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		var res commandResponse
+		if resp.StatusCode == http.StatusOK {
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+		}
+		return res
+	}
+
+	assert.Equal(t, "", command("invalid", "help").Text)
+	assert.Equal(t, commandHelp, command("verif", "help").Text)
+	assert.Equal(t, "pong", command("verif", "ping").Text)
+	assert.True(t, strings.HasPrefix(command("verif", "run PROJ deploy").Text, "You must link"))
+	assert.True(t, strings.HasPrefix(command("verif", "link alice wrong-token").Text, "Unable to authenticate alice"))
+	assert.Equal(t, "You are linked to the CDS user alice", command("verif", "link alice alice-token").Text)
+
+	res := command("verif", "run PROJ deploy git.branch=master")
+	assert.Equal(t, commandResponse{ResponseType: "in_channel", Text: "alice.chat started PROJ/deploy #13"}, res)
+	assert.Equal(t, map[string]interface{}{"git.branch": "master"}, runPayload)
+
+	assert.Equal(t, "PROJ/deploy #13.0 Building\n1: Success\n2: Waiting", command("verif", "status PROJ deploy").Text)
+	assert.Equal(t, "alice.chat approved deploy in PROJ/deploy #13: Pending (1/2)", command("verif", "approve PROJ deploy 13 deploy checked on staging").Text)
+	assert.Equal(t, &sdk.WorkflowNodeRunApprovalRequest{Approved: true, Comment: "checked on staging"}, approvalRequest)
+	assert.True(t, strings.HasPrefix(command("verif", "reject PROJ deploy 13 build").Text, "No pipeline build waiting for approval"))
+	assert.Equal(t, commandHelp, command("verif", "stop PROJ deploy").Text)
+	assert.True(t, strings.HasPrefix(command("verif", "stop PROJ deploy 13").Text, "Error:"))
+
+	assert.Equal(t, "You are not linked to a CDS user anymore", command("verif", "unlink").Text)
+	assert.True(t, strings.HasPrefix(command("verif", "status PROJ deploy").Text, "You must link"))
+
+	// Without verification token nor signing secret, all the commands are rejected
+	c.conf.Chat.VerificationToken = ""
+	assert.Equal(t, "", command("", "help").Text)
+}
+
+func Test_configuration(t *testing.T) {
+	var conf Configuration
+	assert.NoError(t, toml.Unmarshal([]byte(`
+[chat]
+  platform = "mattermost"
+  url = "https://mattermost.local"
+
+[[channels]]
+  project = "PROJ"
+  channel = "proj-channel-id"
+
+[[channels]]
+  project = "PROJ"
+  workflow = "deploy"
+  channel = "deploy-channel-id"
+`), &conf))
+	assert.Equal(t, "mattermost", conf.Chat.Platform)
+	assert.Equal(t, "deploy-channel-id", conf.channel("PROJ", "deploy"))
+	assert.Equal(t, "proj-channel-id", conf.channel("PROJ", "build"))
+	assert.Equal(t, "", conf.channel("OTHER", "deploy"))
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// chatClient posts messages on a chat platform
+type chatClient interface {
+	// PostMessage posts a message in a channel, or in the thread of a message if threadID is set,
+	// and returns the id of the message
+	PostMessage(channel, threadID, text string) (string, error)
+}
+
+func newChatClient(conf ChatConf) (chatClient, error) {
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	url := strings.TrimSuffix(conf.URL, "/")
+	switch conf.Platform {
+	case "slack":
+		if url == "" {
+			url = "https://slack.com/api"
+		}
+		return &slackClient{url: url, token: conf.Token, http: httpClient}, nil
+	case "mattermost":
+		return &mattermostClient{url: url, token: conf.Token, http: httpClient}, nil
+	}
+	return nil, fmt.Errorf("unsupported chat platform %s", conf.Platform)
+}
+
+func postJSON(c *http.Client, url, token string, in, out interface{}) error {
+	b, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s: HTTP %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// slackClient uses the Slack Web API, messages are threaded with their timestamp
+type slackClient struct {
+	url   string
+	token string
+	http  *http.Client
+}
+
+func (c *slackClient) PostMessage(channel, threadID, text string) (string, error) {
+	in := map[string]string{"channel": channel, "text": text}
+	if threadID != "" {
+		in["thread_ts"] = threadID
+	}
+	var out struct {
+		OK    bool   `json:"ok"`
+		TS    string `json:"ts"`
+		Error string `json:"error"`
+	}
+	if err := postJSON(c.http, c.url+"/chat.postMessage", c.token, in, &out); err != nil {
+		return "", err
+	}
+	if !out.OK {
+		return "", fmt.Errorf("chat.postMessage: %s", out.Error)
+	}
+	return out.TS, nil
+}
+
+// mattermostClient uses the Mattermost API v4, the channels are channel ids and the messages are
+// threaded with the id of the root post
+type mattermostClient struct {
+	url   string
+	token string
+	http  *http.Client
+}
+
+func (c *mattermostClient) PostMessage(channel, threadID, text string) (string, error) {
+	in := map[string]string{"channel_id": channel, "message": text}
+	if threadID != "" {
+		in["root_id"] = threadID
+	}
+	var out struct {
+		ID string `json:"id"`
+	}
+	if err := postJSON(c.http, c.url+"/api/v4/posts", c.token, in, &out); err != nil {
+		return "", err
+	}
+	return out.ID, nil
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/bot"
+	"github.com/ovh/cds/sdk/cdsclient"
+)
+
+const commandHelp = "Usage:\n" +
+	"link <cds username> <token>: link your chat user to your CDS user\n" +
+	"unlink: remove the link to your CDS user\n" +
+	"run <project> <workflow> [key=value...]: run a workflow with a payload\n" +
+	"stop <project> <workflow> <number>: stop a workflow run\n" +
+	"status <project> <workflow> [number]: display the status of the last run, or of a run\n" +
+	"approve <project> <workflow> <number> <pipeline> [comment]: approve a pipeline waiting for approval\n" +
+	"reject <project> <workflow> <number> <pipeline> [comment]: reject a pipeline waiting for approval"
+
+// commandResponse is the response to a slash command, the ephemeral responses are only visible by the user
+type commandResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+// commander executes the slash commands with the CDS identity of the chat user
+type commander struct {
+	conf       Configuration
+	identities *identities
+	newClient  func(identity) cdsclient.Interface
+}
+
+func newCommander(conf Configuration, ids *identities) *commander {
+	return &commander{
+		conf:       conf,
+		identities: ids,
+		newClient: func(id identity) cdsclient.Interface {
+			return cdsclient.New(cdsclient.Config{
+				Host:                  conf.CDS.URL,
+				User:                  id.User,
+				Token:                 id.Token,
+				InsecureSkipVerifyTLS: conf.CDS.InsecureSkipVerify,
+			})
+		},
+	}
+}
+
+// ServeHTTP handles the slash commands sent by the chat platform as forms
+func (c *commander) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := c.checkRequest(r.Header, body); err != nil {
+		log.Warnf("command> Invalid request: %v", err)
+		http.Error(w, "invalid request", http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if c.conf.Chat.VerificationToken != "" && subtle.ConstantTimeCompare([]byte(form.Get("token")), []byte(c.conf.Chat.VerificationToken)) != 1 {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	log.Infof("command> %s from %s (%s)", strings.Fields(form.Get("text") + " ?")[0], form.Get("user_name"), form.Get("user_id"))
+	resp := c.execute(form.Get("user_id"), form.Get("user_name"), strings.Fields(form.Get("text")))
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// checkRequest checks the signature of the requests sent by Slack, if a signing secret is set. Without signing
+// secret the verification token is mandatory, it is checked with the form.
+func (c *commander) checkRequest(header http.Header, body []byte) error {
+	if c.conf.Chat.SigningSecret == "" {
+		if c.conf.Chat.VerificationToken == "" {
+			return fmt.Errorf("neither verification token nor signing secret configured")
+		}
+		return nil
+	}
+	ts, err := strconv.ParseInt(header.Get("X-Slack-Request-Timestamp"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp")
+	}
+	if math.Abs(time.Since(time.Unix(ts, 0)).Minutes()) > 5 {
+		return fmt.Errorf("expired timestamp")
+	}
+	mac := hmac.New(sha256.New, []byte(c.conf.Chat.SigningSecret))
+	fmt.Fprintf(mac, "v0:%d:", ts)
+	mac.Write(body) // nolint
+	if !hmac.Equal([]byte(header.Get("X-Slack-Signature")), []byte("v0="+hex.EncodeToString(mac.Sum(nil)))) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+func (c *commander) execute(chatUserID, chatUserName string, args []string) commandResponse {
+	if len(args) == 0 || args[0] == "help" {
+		return commandResponse{ResponseType: "ephemeral", Text: commandHelp}
+	}
+
+	switch args[0] {
+	case "link":
+		if len(args) != 3 {
+			return commandResponse{ResponseType: "ephemeral", Text: commandHelp}
+		}
+		id := identity{User: args[1], Token: args[2]}
+		if _, err := c.newClient(id).UserGet(id.User); err != nil {
+			return commandResponse{ResponseType: "ephemeral", Text: fmt.Sprintf("Unable to authenticate %s: %v", id.User, err)}
+		}
+		if err := c.identities.set(chatUserID, &id); err != nil {
+			return errorResponse(err)
+		}
+		return commandResponse{ResponseType: "ephemeral", Text: fmt.Sprintf("You are linked to the CDS user %s", id.User)}
+	case "unlink":
+		if err := c.identities.set(chatUserID, nil); err != nil {
+			return errorResponse(err)
+		}
+		return commandResponse{ResponseType: "ephemeral", Text: "You are not linked to a CDS user anymore"}
+	case "run", "stop", "status", "approve", "reject":
+	default:
+		return commandResponse{ResponseType: "ephemeral", Text: bot.Answer(strings.Join(args, " "))}
+	}
+
+	id, ok := c.identities.get(chatUserID)
+	if !ok {
+		return commandResponse{ResponseType: "ephemeral", Text: "You must link your CDS user first: link <cds username> <token>"}
+	}
+	client := c.newClient(id)
+
+	switch {
+	case args[0] == "run" && len(args) >= 3:
+		payload := map[string]string{}
+		for _, a := range args[3:] {
+			kv := strings.SplitN(a, "=", 2)
+			if len(kv) != 2 {
+				return commandResponse{ResponseType: "ephemeral", Text: fmt.Sprintf("Invalid payload %s, expected key=value", a)}
+			}
+			payload[kv[0]] = kv[1]
+		}
+		run, err := client.WorkflowRunFromManual(args[1], args[2], sdk.WorkflowNodeRunManual{Payload: payload}, 0, 0)
+		if err != nil {
+			return errorResponse(err)
+		}
+		return commandResponse{ResponseType: "in_channel", Text: fmt.Sprintf("%s started %s/%s #%d", chatUserName, args[1], args[2], run.Number)}
+	case args[0] == "stop" && len(args) == 4:
+		number, err := strconv.ParseInt(args[3], 10, 64)
+		if err != nil {
+			return commandResponse{ResponseType: "ephemeral", Text: fmt.Sprintf("Invalid run number %s", args[3])}
+		}
+		if _, err := client.WorkflowStop(args[1], args[2], number); err != nil {
+			return errorResponse(err)
+		}
+		return commandResponse{ResponseType: "in_channel", Text: fmt.Sprintf("%s stopped %s/%s #%d", chatUserName, args[1], args[2], number)}
+	case (args[0] == "approve" || args[0] == "reject") && len(args) >= 5:
+		number, err := strconv.ParseInt(args[3], 10, 64)
+		if err != nil {
+			return commandResponse{ResponseType: "ephemeral", Text: fmt.Sprintf("Invalid run number %s", args[3])}
+		}
+		run, err := client.WorkflowRunGet(args[1], args[2], number)
+		if err != nil {
+			return errorResponse(err)
+		}
+		var nodeRunID int64
+		for _, nodeRuns := range run.WorkflowNodeRuns {
+			for _, nr := range nodeRuns {
+				if nr.WorkflowNodeName == args[4] && nr.Approval != nil && nr.Approval.Status == sdk.ApprovalPending {
+					nodeRunID = nr.ID
+				}
+			}
+		}
+		if nodeRunID == 0 {
+			return commandResponse{ResponseType: "ephemeral", Text: fmt.Sprintf("No pipeline %s waiting for approval in %s/%s #%d", args[4], args[1], args[2], number)}
+		}
+		nodeRun, err := client.WorkflowNodeRunApprove(args[1], args[2], number, nodeRunID, args[0] == "approve", strings.Join(args[5:], " "))
+		if err != nil {
+			return errorResponse(err)
+		}
+		verb := "approved"
+		if args[0] == "reject" {
+			verb = "rejected"
+		}
+		return commandResponse{ResponseType: "in_channel", Text: fmt.Sprintf("%s %s %s in %s/%s #%d: %s", chatUserName, verb, args[4], args[1], args[2], number, nodeRun.Approval)}
+	case args[0] == "status" && (len(args) == 3 || len(args) == 4):
+		var run *sdk.WorkflowRun
+		if len(args) == 4 {
+			number, err := strconv.ParseInt(args[3], 10, 64)
+			if err != nil {
+				return commandResponse{ResponseType: "ephemeral", Text: fmt.Sprintf("Invalid run number %s", args[3])}
+			}
+			if run, err = client.WorkflowRunGet(args[1], args[2], number); err != nil {
+				return errorResponse(err)
+			}
+		} else {
+			runs, err := client.WorkflowRunList(args[1], args[2], 0, 1)
+			if err != nil {
+				return errorResponse(err)
+			}
+			if len(runs) == 0 {
+				return commandResponse{ResponseType: "ephemeral", Text: fmt.Sprintf("%s/%s has no run", args[1], args[2])}
+			}
+			if run, err = client.WorkflowRunGet(args[1], args[2], runs[0].Number); err != nil {
+				return errorResponse(err)
+			}
+		}
+		return commandResponse{ResponseType: "ephemeral", Text: runStatus(args[1], args[2], run)}
+	}
+
+	return commandResponse{ResponseType: "ephemeral", Text: commandHelp}
+}
+
+func errorResponse(err error) commandResponse {
+	return commandResponse{ResponseType: "ephemeral", Text: fmt.Sprintf("Error: %v", err)}
+}
+
+// runStatus returns the status of the run and the status of the last run of each pipeline
+func runStatus(projectKey, workflowName string, run *sdk.WorkflowRun) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s/%s #%d.%d %s", projectKey, workflowName, run.Number, run.LastSubNumber, run.Status)
+
+	var lines []string
+	for id, nodeRuns := range run.WorkflowNodeRuns {
+		if len(nodeRuns) == 0 {
+			continue
+		}
+		name := fmt.Sprintf("%d", id)
+		if run.Workflow.Root != nil {
+			if n := run.Workflow.GetNode(id); n != nil {
+				name = n.Name
+			}
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", name, nodeRuns[0].Status))
+	}
+	sort.Strings(lines)
+	for _, l := range lines {
+		buf.WriteString("\n" + l)
+	}
+	return buf.String()
+}
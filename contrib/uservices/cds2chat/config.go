@@ -0,0 +1,80 @@
+package main
+
+// Configuration is the configuration structure for cds2chat
+type Configuration struct {
+	Debug    DebugConf     `toml:"debug"`
+	HTTP     HTTPConf      `toml:"http"`
+	CDS      CDSConf       `toml:"cds"`
+	Kafka    KafkaConf     `toml:"kafka"`
+	Chat     ChatConf      `toml:"chat"`
+	Channels []ChannelConf `toml:"channels"`
+}
+
+// HTTPConf represents http configuration, the slash commands are received on /command
+type HTTPConf struct {
+	Port int `toml:"port"`
+}
+
+// CDSConf represents the CDS API configuration. The user and the token are used to listen to the events
+// of the API if kafka is not configured, the commands are run with the identity of the chat users.
+type CDSConf struct {
+	URL                string `toml:"url"`
+	UIURL              string `toml:"ui_url"`
+	User               string `toml:"user"`
+	Token              string `toml:"token"`
+	InsecureSkipVerify bool   `toml:"insecure_skip_verify"`
+}
+
+// KafkaConf represents kafka configuration, the events are consumed from kafka if brokers are set
+type KafkaConf struct {
+	Brokers  string `toml:"brokers"`
+	Topic    string `toml:"topic"`
+	User     string `toml:"user"`
+	Password string `toml:"password"`
+	Group    string `toml:"group"`
+}
+
+// ChatConf represents the chat platform configuration
+type ChatConf struct {
+	// Platform is slack or mattermost
+	Platform string `toml:"platform"`
+	// URL of the chat API: https://slack.com/api or https://mattermost.mycompany.com
+	URL   string `toml:"url"`
+	Token string `toml:"token"`
+	// VerificationToken is the token sent by the chat platform with the slash commands
+	VerificationToken string `toml:"verification_token"`
+	// SigningSecret is used to check the signature of the slash commands sent by Slack
+	SigningSecret string `toml:"signing_secret"`
+	// IdentitiesFile stores the CDS identities of the chat users, they are kept in memory if empty. The CDS tokens
+	// are written in clear in this file, only the user running cds2chat can read it.
+	IdentitiesFile string `toml:"identities_file"`
+}
+
+// ChannelConf maps a project, or a workflow of a project, to a chat channel
+type ChannelConf struct {
+	Project  string `toml:"project"`
+	Workflow string `toml:"workflow"`
+	Channel  string `toml:"channel"`
+}
+
+// DebugConf represents log configuration
+type DebugConf struct {
+	LogLevel string `toml:"log_level"`
+}
+
+// channel returns the channel of a workflow, the channels of workflows have the precedence on the channels of projects
+func (c Configuration) channel(projectKey, workflowName string) string {
+	var channel string
+	for _, ch := range c.Channels {
+		if ch.Project != projectKey {
+			continue
+		}
+		if ch.Workflow == workflowName {
+			return ch.Channel
+		}
+		if ch.Workflow == "" {
+			channel = ch.Channel
+		}
+	}
+	return channel
+}
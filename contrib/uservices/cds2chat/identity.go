@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// identity is the CDS user linked to a chat user
+type identity struct {
+	User  string `json:"user"`
+	Token string `json:"token"`
+}
+
+// identities stores the identities by chat user id, in a file if a path is set
+type identities struct {
+	path   string
+	mutex  sync.Mutex
+	values map[string]identity
+}
+
+func loadIdentities(path string) (*identities, error) {
+	ids := &identities{path: path, values: map[string]identity{}}
+	if path == "" {
+		return ids, nil
+	}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return ids, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &ids.values); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (ids *identities) get(chatUserID string) (identity, bool) {
+	ids.mutex.Lock()
+	defer ids.mutex.Unlock()
+	id, ok := ids.values[chatUserID]
+	return id, ok
+}
+
+func (ids *identities) set(chatUserID string, id *identity) error {
+	ids.mutex.Lock()
+	defer ids.mutex.Unlock()
+	if id == nil {
+		delete(ids.values, chatUserID)
+	} else {
+		ids.values[chatUserID] = *id
+	}
+	if ids.path == "" {
+		return nil
+	}
+	b, err := json.Marshal(ids.values)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(ids.path, b, 0600)
+}
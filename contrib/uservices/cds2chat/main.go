@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pelletier/go-toml"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/cds/sdk/event"
+)
+
+// VERSION ...
+const VERSION = "0.1.0"
+
+func main() {
+	configPath := flag.String("f", "", "path to the config file")
+	flag.Parse()
+
+	if *configPath == "" {
+		fmt.Println("Usage: cds2chat -f config.toml")
+		os.Exit(1)
+	}
+
+	configData, err := ioutil.ReadFile(*configPath)
+	if err != nil {
+		fmt.Printf("Unable to read configuration: %s\n", err)
+		os.Exit(2)
+	}
+	var conf Configuration
+	if err := toml.Unmarshal(configData, &conf); err != nil {
+		fmt.Printf("Unable to unmarshal configuration: %s", err)
+		os.Exit(3)
+	}
+	if conf.Chat.VerificationToken == "" && conf.Chat.SigningSecret == "" {
+		fmt.Println("Invalid configuration: chat.verification_token or chat.signing_secret must be set to authenticate the slash commands")
+		os.Exit(4)
+	}
+
+	switch conf.Debug.LogLevel {
+	case "debug":
+		log.SetLevel(log.DebugLevel)
+	case "error":
+		log.SetLevel(log.WarnLevel)
+	default:
+		log.SetLevel(log.InfoLevel)
+	}
+
+	chat, err := newChatClient(conf.Chat)
+	if err != nil {
+		log.Fatalf("Unable to initialize chat client: %v", err)
+	}
+	ids, err := loadIdentities(conf.Chat.IdentitiesFile)
+	if err != nil {
+		log.Fatalf("Unable to load identities: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mon/ping", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"version": "%s"}`, VERSION)
+	})
+	mux.Handle("/command", newCommander(conf, ids))
+
+	s := &http.Server{
+		Addr:           ":" + strconv.Itoa(conf.HTTP.Port),
+		Handler:        mux,
+		ReadTimeout:    30 * time.Second,
+		WriteTimeout:   30 * time.Second,
+		MaxHeaderBytes: 1 << 20,
+	}
+
+	go consume(conf, newNotifier(conf, chat))
+
+	log.Infof("Running cds2chat on %d", conf.HTTP.Port)
+	if err := s.ListenAndServe(); err != nil {
+		log.Errorf("Error while running ListenAndServe: %s", err.Error())
+	}
+}
+
+// consume processes the events from kafka if it is configured, from the API otherwise
+func consume(conf Configuration, n *notifier) {
+	process := func(e sdk.Event) error {
+		if err := n.process(e); err != nil {
+			log.Errorf("consume> Unable to process event %s: %v", e.EventType, err)
+		}
+		return nil
+	}
+
+	if conf.Kafka.Brokers != "" {
+		if err := event.ConsumeKafka(conf.Kafka.Brokers, conf.Kafka.Topic, conf.Kafka.Group, conf.Kafka.User, conf.Kafka.Password, process, log.Errorf); err != nil {
+			log.Fatalf("Unable to consume kafka: %v", err)
+		}
+		return
+	}
+
+	client := cdsclient.New(cdsclient.Config{
+		Host:                  conf.CDS.URL,
+		User:                  conf.CDS.User,
+		Token:                 conf.CDS.Token,
+		InsecureSkipVerifyTLS: conf.CDS.InsecureSkipVerify,
+	})
+	events := make(chan sdk.Event)
+	go client.EventsListen(context.Background(), events)
+	for e := range events {
+		_ = process(e)
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// threadTTL is the time the thread of a run is kept after the last event
+const threadTTL = 24 * time.Hour
+
+type runKey struct {
+	project  string
+	workflow string
+	number   int64
+}
+
+type runThread struct {
+	channel string
+	id      string
+	status  string
+	updated time.Time
+}
+
+// notifier posts a message for each workflow run in the channel of the workflow,
+// and the changes of the status of the run and its pipelines in the thread of this message
+type notifier struct {
+	conf    Configuration
+	chat    chatClient
+	mutex   sync.Mutex
+	threads map[runKey]*runThread
+}
+
+func newNotifier(conf Configuration, chat chatClient) *notifier {
+	return &notifier{conf: conf, chat: chat, threads: map[runKey]*runThread{}}
+}
+
+func (n *notifier) process(e sdk.Event) error {
+	switch e.EventType {
+	case fmt.Sprintf("%T", sdk.EventRunWorkflow{}):
+		var run sdk.EventRunWorkflow
+		if err := mapstructure.Decode(e.Payload, &run); err != nil {
+			log.Warnf("process> Unable to decode %s: %v", e.EventType, err)
+			return nil
+		}
+		return n.processRun(e, run)
+	case fmt.Sprintf("%T", sdk.EventRunWorkflowNode{}):
+		var node sdk.EventRunWorkflowNode
+		if err := mapstructure.Decode(e.Payload, &node); err != nil {
+			log.Warnf("process> Unable to decode %s: %v", e.EventType, err)
+			return nil
+		}
+		return n.processNodeRun(e, node)
+	}
+	return nil
+}
+
+func (n *notifier) processRun(e sdk.Event, run sdk.EventRunWorkflow) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	t, err := n.thread(e, run.Number)
+	if t == nil || err != nil {
+		return err
+	}
+	if t.status == run.Status {
+		return nil
+	}
+	t.status = run.Status
+	if !sdk.StatusIsTerminated(run.Status) {
+		return nil
+	}
+	_, err = n.chat.PostMessage(t.channel, t.id, fmt.Sprintf("Workflow %s", run.Status))
+	return err
+}
+
+func (n *notifier) processNodeRun(e sdk.Event, node sdk.EventRunWorkflowNode) error {
+	waitingApproval := node.Status == sdk.StatusWaiting.String() && node.ApprovalStatus == sdk.ApprovalPending
+	if node.Status != sdk.StatusBuilding.String() && !sdk.StatusIsTerminated(node.Status) && !waitingApproval {
+		return nil
+	}
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	t, err := n.thread(e, node.Number)
+	if t == nil || err != nil {
+		return err
+	}
+	name := node.NodeName
+	if name == "" {
+		name = e.PipelineName
+	}
+	text := fmt.Sprintf("%s #%d.%d %s", name, node.Number, node.SubNumber, node.Status)
+	if waitingApproval {
+		text = fmt.Sprintf("%s #%d.%d waiting for approval (%d/%d)", name, node.Number, node.SubNumber, len(node.Approvers), node.RequiredApprovers)
+	}
+	_, err = n.chat.PostMessage(t.channel, t.id, text)
+	return err
+}
+
+// thread returns the thread of the run, the first message is posted if the run has no thread
+// and nil is returned if there is no channel for the workflow
+func (n *notifier) thread(e sdk.Event, number int64) (*runThread, error) {
+	n.purge()
+
+	k := runKey{project: e.ProjectKey, workflow: e.WorkflowName, number: number}
+	if t, ok := n.threads[k]; ok {
+		t.updated = time.Now()
+		return t, nil
+	}
+
+	channel := n.conf.channel(e.ProjectKey, e.WorkflowName)
+	if channel == "" {
+		return nil, nil
+	}
+	text := fmt.Sprintf("%s/%s #%d", e.ProjectKey, e.WorkflowName, number)
+	if n.conf.CDS.UIURL != "" {
+		text += fmt.Sprintf(" %s/project/%s/workflow/%s/run/%d", n.conf.CDS.UIURL, e.ProjectKey, e.WorkflowName, number)
+	}
+	if e.Username != "" {
+		text += " started by " + e.Username
+	}
+	id, err := n.chat.PostMessage(channel, "", text)
+	if err != nil {
+		return nil, err
+	}
+	t := &runThread{channel: channel, id: id, updated: time.Now()}
+	n.threads[k] = t
+	return t, nil
+}
+
+func (n *notifier) purge() {
+	for k, t := range n.threads {
+		if time.Since(t.updated) > threadTTL {
+			delete(n.threads, k)
+		}
+	}
+}
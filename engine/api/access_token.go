@@ -0,0 +1,221 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func (api *API) getUserAccessTokensHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		ts, err := user.LoadAccessTokens(api.mustDB(), getUser(ctx).ID)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, ts, http.StatusOK)
+	}
+}
+
+// postUserAccessTokenHandler creates an access token for the current user, the value of the token is only returned here
+func (api *API) postUserAccessTokenHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		u := getUser(ctx)
+		if getAccessToken(ctx) != nil {
+			return sdk.WrapError(sdk.ErrAccessTokenForbidden, "postUserAccessTokenHandler> An access token cannot create another access token")
+		}
+
+		var t sdk.AccessToken
+		if err := UnmarshalBody(r, &t); err != nil {
+			return err
+		}
+		if err := t.IsValid(); err != nil {
+			return err
+		}
+		if t.ProjectKey != "" {
+			if _, err := project.Load(api.mustDB(), api.Cache, t.ProjectKey, u); err != nil {
+				return sdk.WrapError(err, "postUserAccessTokenHandler> Unable to load project %s", t.ProjectKey)
+			}
+			if !u.Admin && u.Permissions.ProjectsPerm[t.ProjectKey] < permission.PermissionRead {
+				return sdk.WrapError(sdk.ErrForbidden, "postUserAccessTokenHandler> User %s has no permission on project %s", u.Username, t.ProjectKey)
+			}
+		}
+
+		t.UserID = u.ID
+		t.Revoked = nil
+		t.RevokedBy = ""
+		t.LastUsed = nil
+		if err := user.InsertAccessToken(api.mustDB(), &t); err != nil {
+			return err
+		}
+		log.Info("postUserAccessTokenHandler> Access token %d %s created by %s with scope %s", t.ID, t.Name, u.Username, t.Scope)
+		if err := user.InsertAccessTokenAudit(api.mustDB(), &sdk.AccessTokenAudit{
+			AccessTokenID: t.ID,
+			Type:          sdk.AccessTokenAuditCreate,
+			Username:      u.Username,
+			RemoteAddr:    r.RemoteAddr,
+		}); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, t, http.StatusCreated)
+	}
+}
+
+// deleteUserAccessTokenHandler revokes an access token of the current user
+func (api *API) deleteUserAccessTokenHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		u := getUser(ctx)
+		id, err := requestVarInt(r, "id")
+		if err != nil {
+			return err
+		}
+
+		t, err := user.LoadAccessTokenByID(api.mustDB(), u.ID, id)
+		if err != nil {
+			return err
+		}
+		if t.Revoked == nil {
+			if err := user.RevokeAccessToken(api.mustDB(), t, u.Username); err != nil {
+				return err
+			}
+			log.Info("deleteUserAccessTokenHandler> Access token %d %s revoked by %s", t.ID, t.Name, u.Username)
+			if err := user.InsertAccessTokenAudit(api.mustDB(), &sdk.AccessTokenAudit{
+				AccessTokenID: t.ID,
+				Type:          sdk.AccessTokenAuditRevoke,
+				Username:      u.Username,
+				RemoteAddr:    r.RemoteAddr,
+			}); err != nil {
+				return err
+			}
+		}
+		return service.WriteJSON(w, t, http.StatusOK)
+	}
+}
+
+// getUserAccessTokenAuditsHandler returns the last creation, revocation and uses of an access token of the current user
+func (api *API) getUserAccessTokenAuditsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := requestVarInt(r, "id")
+		if err != nil {
+			return err
+		}
+		limit, err := FormInt(r, "limit")
+		if err != nil {
+			return sdk.WrapError(err, "getUserAccessTokenAuditsHandler> Invalid limit")
+		}
+		if limit <= 0 || limit > 500 {
+			limit = 100
+		}
+
+		if _, err := user.LoadAccessTokenByID(api.mustDB(), getUser(ctx).ID, id); err != nil {
+			return err
+		}
+		as, err := user.LoadAccessTokenAudits(api.mustDB(), id, limit)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, as, http.StatusOK)
+	}
+}
+
+// insertAccessTokenUse records a request made with an access token, allowed or denied by its scope
+func (api *API) insertAccessTokenUse(t *sdk.AccessToken, u *sdk.User, req *http.Request, allowed bool) {
+	a := sdk.AccessTokenAudit{
+		AccessTokenID: t.ID,
+		Type:          sdk.AccessTokenAuditUse,
+		Username:      u.Username,
+		Method:        req.Method,
+		URL:           req.URL.Path,
+		RemoteAddr:    req.RemoteAddr,
+	}
+	if !allowed {
+		a.Type = sdk.AccessTokenAuditDenied
+	}
+	if err := user.InsertAccessTokenAudit(api.mustDB(), &a); err != nil {
+		log.Warning("insertAccessTokenUse> %v", err)
+	}
+}
+
+// accessTokenPermission returns the highest permission allowed by the scope of an access token
+func accessTokenPermission(t *sdk.AccessToken) int {
+	switch t.Scope {
+	case sdk.AccessTokenScopeAdmin:
+		return permission.PermissionReadWriteExecute
+	case sdk.AccessTokenScopeRun:
+		return permission.PermissionReadExecute
+	default:
+		return permission.PermissionRead
+	}
+}
+
+// restrictToAccessToken removes from the user the rights not granted by the access token:
+// the CDS administration if the scope is not admin, and the permissions on the other projects
+// and the administration of the groups if the token is restricted to a project
+func restrictToAccessToken(u *sdk.User, t *sdk.AccessToken) {
+	if t.Scope != sdk.AccessTokenScopeAdmin || t.ProjectKey != "" {
+		u.Admin = false
+	}
+	if t.ProjectKey == "" {
+		return
+	}
+
+	filter := func(perms map[string]int) map[string]int {
+		res := make(map[string]int)
+		for k, v := range perms {
+			if strings.HasPrefix(k, t.ProjectKey+"/") {
+				res[k] = v
+			}
+		}
+		return res
+	}
+	projectPerm, ok := u.Permissions.ProjectsPerm[t.ProjectKey]
+	u.Permissions.ProjectsPerm = map[string]int{}
+	if ok {
+		u.Permissions.ProjectsPerm[t.ProjectKey] = projectPerm
+	}
+	u.Permissions.ApplicationsPerm = filter(u.Permissions.ApplicationsPerm)
+	u.Permissions.WorkflowsPerm = filter(u.Permissions.WorkflowsPerm)
+	u.Permissions.PipelinesPerm = filter(u.Permissions.PipelinesPerm)
+	u.Permissions.EnvironmentsPerm = filter(u.Permissions.EnvironmentsPerm)
+
+	u.Permissions.GroupsAdmin = nil
+	groups := make([]sdk.Group, len(u.Groups))
+	for i, g := range u.Groups {
+		g.Admins = nil
+		groups[i] = g
+	}
+	u.Groups = groups
+}
+
+// checkAccessTokenScope checks that the route is allowed by the scope and the project of the access token
+func checkAccessTokenScope(t *sdk.AccessToken, rc *service.HandlerConfig, method string, vars map[string]string) error {
+	if rc.Options["needAdmin"] == "true" && t.Scope != sdk.AccessTokenScopeAdmin {
+		return sdk.NewError(sdk.ErrAccessTokenForbidden, fmt.Errorf("scope %s does not allow administration", t.Scope))
+	}
+	if getPermissionByMethod(method, rc.Options["isExecution"] == "true") > accessTokenPermission(t) {
+		return sdk.NewError(sdk.ErrAccessTokenForbidden, fmt.Errorf("scope %s does not allow %s", t.Scope, method))
+	}
+	if t.ProjectKey == "" || rc.Options["allowProjectAccessToken"] == "true" {
+		return nil
+	}
+	// a token restricted to a project is only allowed on the routes of this project
+	var found bool
+	for _, k := range []string{"key", "permProjectKey"} {
+		v, ok := vars[k]
+		if ok && v != t.ProjectKey {
+			return sdk.NewError(sdk.ErrAccessTokenForbidden, fmt.Errorf("access token restricted to project %s", t.ProjectKey))
+		}
+		found = found || ok
+	}
+	if !found {
+		return sdk.NewError(sdk.ErrAccessTokenForbidden, fmt.Errorf("access token restricted to project %s", t.ProjectKey))
+	}
+	return nil
+}
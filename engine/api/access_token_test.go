@@ -0,0 +1,66 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_checkAccessTokenScope(t *testing.T) {
+	check := func(tk sdk.AccessToken, method string, options map[string]string) error {
+		return checkAccessTokenScope(&tk, &service.HandlerConfig{Options: options}, method, map[string]string{"key": "PROJ"})
+	}
+
+	read := sdk.AccessToken{Scope: sdk.AccessTokenScopeRead}
+	assert.NoError(t, check(read, http.MethodGet, nil))
+	assert.Error(t, check(read, http.MethodPost, map[string]string{"isExecution": "true"}))
+
+	run := sdk.AccessToken{Scope: sdk.AccessTokenScopeRun}
+	assert.NoError(t, check(run, http.MethodPost, map[string]string{"isExecution": "true"}))
+	assert.Error(t, check(run, http.MethodPut, nil))
+	assert.Error(t, check(run, http.MethodGet, map[string]string{"needAdmin": "true"}))
+
+	admin := sdk.AccessToken{Scope: sdk.AccessTokenScopeAdmin}
+	assert.NoError(t, check(admin, http.MethodPut, nil))
+	assert.NoError(t, check(admin, http.MethodGet, map[string]string{"needAdmin": "true"}))
+
+	admin.ProjectKey = "OTHER"
+	assert.Error(t, check(admin, http.MethodGet, nil))
+
+	// a token restricted to a project is denied on the routes without project
+	admin.ProjectKey = "PROJ"
+	assert.NoError(t, check(admin, http.MethodPut, nil))
+	rc := &service.HandlerConfig{Options: map[string]string{}}
+	assert.Error(t, checkAccessTokenScope(&admin, rc, http.MethodPut, map[string]string{"permGroupName": "my-group"}))
+	assert.Error(t, checkAccessTokenScope(&admin, rc, http.MethodDelete, map[string]string{"username": "alice"}))
+	assert.Error(t, checkAccessTokenScope(&admin, rc, http.MethodPost, nil))
+	rc.Options["allowProjectAccessToken"] = "true"
+	assert.NoError(t, checkAccessTokenScope(&admin, rc, http.MethodGet, nil))
+}
+
+func Test_restrictToAccessToken(t *testing.T) {
+	u := &sdk.User{
+		Admin: true,
+		Permissions: sdk.UserPermissions{
+			ProjectsPerm:  map[string]int{"PROJ": permission.PermissionReadWriteExecute, "OTHER": permission.PermissionRead},
+			WorkflowsPerm: sdk.UserPermissionsMap{"PROJ/w": permission.PermissionReadWriteExecute, "OTHER/w": permission.PermissionRead},
+			GroupsAdmin:   []string{"my-group"},
+		},
+		Groups: []sdk.Group{{Name: "my-group", Admins: []sdk.User{{Username: "alice"}}}},
+	}
+	restrictToAccessToken(u, &sdk.AccessToken{Scope: sdk.AccessTokenScopeAdmin, ProjectKey: "PROJ"})
+	assert.False(t, u.Admin)
+	assert.Equal(t, map[string]int{"PROJ": permission.PermissionReadWriteExecute}, u.Permissions.ProjectsPerm)
+	assert.Len(t, u.Permissions.WorkflowsPerm, 1)
+	assert.Contains(t, u.Permissions.WorkflowsPerm, "PROJ/w")
+	assert.Empty(t, u.Permissions.GroupsAdmin)
+	if assert.Len(t, u.Groups, 1) {
+		assert.Equal(t, "my-group", u.Groups[0].Name)
+		assert.Empty(t, u.Groups[0].Admins)
+	}
+}
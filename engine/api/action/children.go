@@ -1,6 +1,8 @@
 package action
 
 import (
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -10,11 +12,11 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
-func insertEdge(db gorp.SqlExecutor, parentID, childID int64, execOrder int, stepName string, optional, alwaysExecuted, enabled bool) (int64, error) {
-	query := `INSERT INTO action_edge (parent_id, child_id, exec_order, step_name, optional, always_executed, enabled) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+func insertEdge(db gorp.SqlExecutor, parentID, childID int64, execOrder int, stepName string, optional, alwaysExecuted, enabled bool, timeout int64, retryPolicy sql.NullString) (int64, error) {
+	query := `INSERT INTO action_edge (parent_id, child_id, exec_order, step_name, optional, always_executed, enabled, timeout, retry_policy) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
 
 	var id int64
-	err := db.QueryRow(query, parentID, childID, execOrder, stepName, optional, alwaysExecuted, enabled).Scan(&id)
+	err := db.QueryRow(query, parentID, childID, execOrder, stepName, optional, alwaysExecuted, enabled, timeout, retryPolicy).Scan(&id)
 	if err != nil {
 		return 0, err
 	}
@@ -32,7 +34,12 @@ func insertActionChild(db gorp.SqlExecutor, actionID int64, child sdk.Action, ex
 		child.StepName = ""
 	}
 
-	id, err := insertEdge(db, actionID, child.ID, execOrder, child.StepName, child.Optional, child.AlwaysExecuted, child.Enabled)
+	retryPolicy, err := RetryPolicyValue(child.RetryPolicy, child.Name)
+	if err != nil {
+		return err
+	}
+
+	id, err := insertEdge(db, actionID, child.ID, execOrder, child.StepName, child.Optional, child.AlwaysExecuted, child.Enabled, child.Timeout, retryPolicy)
 	if err != nil {
 		return err
 	}
@@ -53,6 +60,23 @@ func insertActionChild(db gorp.SqlExecutor, actionID int64, child sdk.Action, ex
 	return nil
 }
 
+// RetryPolicyValue checks the retry policy of a job or a step and returns its database value
+func RetryPolicyValue(p *sdk.RetryPolicy, name string) (sql.NullString, error) {
+	if p == nil {
+		return sql.NullString{}, nil
+	}
+	if err := p.IsValid(); err != nil {
+		httpErr := sdk.ErrInvalidRetryPolicy
+		httpErr.Message = fmt.Sprintf("%s %s: %v", httpErr, name, err)
+		return sql.NullString{}, sdk.NewError(httpErr, err)
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		return sql.NullString{}, sdk.WrapError(err, "RetryPolicyValue> cannot marshal retry policy")
+	}
+	return sql.NullString{String: string(b), Valid: true}, nil
+}
+
 func insertChildActionParameter(db gorp.SqlExecutor, edgeID, parentID, childID int64, param sdk.Parameter) error {
 	query := `INSERT INTO action_edge_parameter (
 					action_edge_id,
@@ -73,7 +97,7 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 	var children []sdk.Action
 	var edgeIDs []int64
 	var childrenIDs []int64
-	query := `SELECT id, child_id, exec_order, step_name, optional, always_executed, enabled FROM action_edge WHERE parent_id = $1 ORDER BY exec_order ASC`
+	query := `SELECT id, child_id, exec_order, step_name, optional, always_executed, enabled, timeout, retry_policy FROM action_edge WHERE parent_id = $1 ORDER BY exec_order ASC`
 
 	rows, err := db.Query(query, actionID)
 	if err != nil {
@@ -81,17 +105,20 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 	}
 	defer rows.Close()
 
-	var edgeID, childID int64
+	var edgeID, childID, timeout int64
 	var execOrder int
 	var stepName string
 	var optional, alwaysExecuted, enabled bool
+	var retryPolicy sql.NullString
 	var mapStepName = make(map[int64]string)
 	var mapOptional = make(map[int64]bool)
 	var mapAlwaysExecuted = make(map[int64]bool)
 	var mapEnabled = make(map[int64]bool)
+	var mapTimeout = make(map[int64]int64)
+	var mapRetryPolicy = make(map[int64]*sdk.RetryPolicy)
 
 	for rows.Next() {
-		err = rows.Scan(&edgeID, &childID, &execOrder, &stepName, &optional, &alwaysExecuted, &enabled)
+		err = rows.Scan(&edgeID, &childID, &execOrder, &stepName, &optional, &alwaysExecuted, &enabled, &timeout, &retryPolicy)
 		if err != nil {
 			return nil, err
 		}
@@ -101,6 +128,14 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 		mapOptional[edgeID] = optional
 		mapAlwaysExecuted[edgeID] = alwaysExecuted
 		mapEnabled[edgeID] = enabled
+		mapTimeout[edgeID] = timeout
+		if retryPolicy.Valid {
+			p := new(sdk.RetryPolicy)
+			if err := json.Unmarshal([]byte(retryPolicy.String), p); err != nil {
+				return nil, fmt.Errorf("cannot unmarshal retry policy of edge %d> %s", edgeID, err)
+			}
+			mapRetryPolicy[edgeID] = p
+		}
 	}
 	rows.Close()
 
@@ -128,6 +163,8 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 		children[i].AlwaysExecuted = mapAlwaysExecuted[edgeIDs[i]]
 		// Get enable flag
 		children[i].Enabled = mapEnabled[edgeIDs[i]]
+		children[i].Timeout = mapTimeout[edgeIDs[i]]
+		children[i].RetryPolicy = mapRetryPolicy[edgeIDs[i]]
 	}
 
 	return children, nil
@@ -8,6 +8,8 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/migrate"
+	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
@@ -135,3 +137,33 @@ func putPostAdminServiceCallHandler(api *API, method string) service.Handler {
 		return service.Write(w, btes, code, "application/json")
 	}
 }
+
+func (api *API) getAdminSecretReencryptionHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		status, err := migrate.LoadReencryptionStatus(api.mustDB())
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, status, http.StatusOK)
+	}
+}
+
+// postAdminSecretReencryptionHandler starts the re-encryption of the stored secrets with the current master key
+func (api *API) postAdminSecretReencryptionHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		owner, err := migrate.StartSecretReencryption(api.mustDB())
+		if err != nil {
+			return sdk.WrapError(err, "postAdminSecretReencryptionHandler> Unable to start re-encryption")
+		}
+		log.Info("postAdminSecretReencryptionHandler> Re-encryption of the secrets with key %s started by %s", secret.CurrentKeyID(), getUser(ctx).Username)
+		sdk.GoRoutine("migrate.ReencryptSecrets", func() {
+			migrate.ReencryptSecrets(api.Router.Background, api.DBConnectionFactory.GetDBMap, owner)
+		})
+
+		status, err := migrate.LoadReencryptionStatus(api.mustDB())
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, status, http.StatusAccepted)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/go-gorp/gorp"
@@ -62,7 +63,9 @@ type Configuration struct {
 		Port int    `toml:"port" default:"8082" json:"port"`
 	} `toml:"grpc" json:"grpc"`
 	Secrets struct {
-		Key string `toml:"key" json:"-"`
+		Key   string            `toml:"key" json:"-"`
+		Keys  map[string]string `toml:"keys" commented:"true" comment:"Master keys encrypting the secrets, by key ID. Keep the previous keys until all the secrets are re-encrypted with the current one" json:"-"`
+		KeyID string            `toml:"keyID" commented:"true" comment:"ID of the master key encrypting the new secrets. The legacy key is used if empty" json:"keyID"`
 	} `toml:"secrets" json:"secrets"`
 	Database database.DBConfiguration `toml:"database" comment:"################################\n Postgresql Database settings \n###############################" json:"database"`
 	Cache    struct {
@@ -90,6 +93,16 @@ type Configuration struct {
 			BindDN   string `toml:"bindDN" default:"" comment:"Define it if ldapsearch need to be authenticated" json:"bindDN"`
 			BindPwd  string `toml:"bindPwd" default:"" comment:"Define it if ldapsearch need to be authenticated" json:"-"`
 		} `toml:"ldap" json:"ldap"`
+		OIDC struct {
+			Enable        bool              `toml:"enable" default:"false" json:"enable"`
+			Issuer        string            `toml:"issuer" comment:"URL of the OpenID Connect provider, its configuration is loaded from <issuer>/.well-known/openid-configuration" json:"issuer"`
+			ClientID      string            `toml:"clientID" json:"clientID"`
+			ClientSecret  string            `toml:"clientSecret" json:"-"`
+			Scopes        string            `toml:"scopes" default:"openid profile email groups" comment:"Space separated scopes requested to the provider" json:"scopes"`
+			UsernameClaim string            `toml:"usernameClaim" default:"preferred_username" comment:"Claim of the ID token used as CDS username" json:"usernameClaim"`
+			GroupsClaim   string            `toml:"groupsClaim" default:"groups" comment:"Claim of the ID token listing the groups of the user" json:"groupsClaim"`
+			Groups        map[string]string `toml:"groups" comment:"Map the groups of the provider to CDS groups, ie. sso-devops = \"devops\". The users are added to and removed from these CDS groups at each login" json:"groups"`
+		} `toml:"oidc" comment:"Login with an OpenID Connect provider, the users are created at their first login" json:"oidc"`
 		Local struct {
 			SignupAllowedDomains string `toml:"signupAllowedDomains" default:"" comment:"Allow signup from selected domains only - comma separated. Example: your-domain.com,another-domain.com" commented:"true" json:"signupAllowedDomains"`
 		} `toml:"local" json:"local"`
@@ -104,7 +117,7 @@ type Configuration struct {
 		From     string `toml:"from" default:"no-reply@cds.local" json:"from"`
 	} `toml:"smtp" comment:"#####################\n# CDS SMTP Settings \n####################" json:"smtp"`
 	Artifact struct {
-		Mode  string `toml:"mode" default:"local" comment:"swift or local" json:"mode"`
+		Mode  string `toml:"mode" default:"local" comment:"swift, s3 or local" json:"mode"`
 		Local struct {
 			BaseDirectory string `toml:"baseDirectory" default:"/tmp/cds/artifacts" json:"baseDirectory"`
 		} `toml:"local"`
@@ -118,7 +131,20 @@ type Configuration struct {
 			ContainerPrefix string `toml:"containerPrefix" comment:"Use if your want to prefix containers for CDS Artifacts" json:"containerPrefix"`
 			DisableTempURL  bool   `toml:"disableTempURL" default:"false" commented:"true" comment:"True if you want to disable Temporary URL in file upload" json:"disableTempURL"`
 		} `toml:"openstack" json:"openstack"`
-	} `toml:"artifact" comment:"Either filesystem local storage or Openstack Swift Storage are supported" json:"artifact"`
+		S3 struct {
+			Endpoint        string `toml:"endpoint" comment:"S3 API endpoint, ie: http://localhost:9000 for a local Minio. Default is AWS endpoint of the region" json:"endpoint"`
+			Region          string `toml:"region" default:"us-east-1" json:"region"`
+			Bucket          string `toml:"bucket" default:"cds" comment:"Bucket name, it will be created if it does not exist" json:"bucket"`
+			AccessKeyID     string `toml:"accessKeyId" json:"-"`
+			SecretAccessKey string `toml:"secretAccessKey" json:"-"`
+			Prefix          string `toml:"prefix" comment:"Use if your want to prefix objects keys for CDS Artifacts" json:"prefix"`
+			ForcePathStyle  bool   `toml:"forcePathStyle" default:"false" comment:"True to use path-style addressing (needed by Minio and most S3 compatible storages)" json:"forcePathStyle"`
+			DisableTempURL  bool   `toml:"disableTempURL" default:"false" commented:"true" comment:"True if you want to disable presigned URL in file upload" json:"disableTempURL"`
+		} `toml:"s3" json:"s3"`
+	} `toml:"artifact" comment:"Either filesystem local storage, Openstack Swift Storage or S3 compatible storage are supported" json:"artifact"`
+	Logs struct {
+		Storage string `toml:"storage" default:"database" comment:"database or objectstore. With objectstore, the step logs of finished jobs are compressed and moved to the artifact storage" json:"storage"`
+	} `toml:"logs" comment:"#####################\n CDS Logs Settings \n####################" json:"logs"`
 	Events struct {
 		Kafka struct {
 			Enabled         bool   `toml:"enabled" json:"enabled"`
@@ -142,6 +168,8 @@ type Configuration struct {
 	} `toml:"schedulers" comment:"###########################\n CDS Schedulers Settings \n##########################" json:"schedulers"`
 	Vault struct {
 		ConfigurationKey string `toml:"configurationKey" json:"-"`
+		Address          string `toml:"address" comment:"Vault address used to resolve the vault variables (example: https://vault.mydomain.net:8200)" json:"address"`
+		Token            string `toml:"token" comment:"Vault token allowed to create tokens with the Vault roles of the projects" json:"-"`
 	} `toml:"vault" json:"vault"`
 	Providers []ProviderConfiguration `toml:"providers" comment:"###########################\n CDS Providers Settings \n##########################" json:"providers"`
 	Services  []ServiceConfiguration  `toml:"services" comment:"###########################\n CDS Services Settings \n##########################" json:"services"`
@@ -304,7 +332,7 @@ func (a *API) CheckConfiguration(config interface{}) error {
 	}
 
 	switch aConfig.Artifact.Mode {
-	case "local", "openstack", "swift":
+	case "local", "openstack", "swift", "s3":
 	default:
 		return fmt.Errorf("Invalid artifact mode")
 	}
@@ -323,6 +351,16 @@ func (a *API) CheckConfiguration(config interface{}) error {
 		}
 	}
 
+	switch aConfig.Logs.Storage {
+	case "", "database", "objectstore":
+	default:
+		return fmt.Errorf("Invalid logs storage")
+	}
+
+	if aConfig.Auth.OIDC.Enable && (aConfig.Auth.OIDC.Issuer == "" || aConfig.Auth.OIDC.ClientID == "") {
+		return fmt.Errorf("Invalid OpenID Connect configuration, issuer and clientID are mandatory")
+	}
+
 	if len(aConfig.Secrets.Key) != 32 {
 		return fmt.Errorf("Invalid secret key. It should be 32 bits (%d)", len(aConfig.Secrets.Key))
 	}
@@ -416,6 +454,18 @@ func getHatchery(c context.Context) *sdk.Service {
 	return u
 }
 
+func getAccessToken(c context.Context) *sdk.AccessToken {
+	i := c.Value(auth.ContextAccessToken)
+	if i == nil {
+		return nil
+	}
+	t, ok := i.(*sdk.AccessToken)
+	if !ok {
+		return nil
+	}
+	return t
+}
+
 func getService(c context.Context) *sdk.Service {
 	i := c.Value(auth.ContextService)
 	if i == nil {
@@ -454,6 +504,14 @@ func (a *API) Serve(ctx context.Context) error {
 
 	//Initialize secret driver
 	secret.Init(a.Config.Secrets.Key)
+	if err := secret.InitKeys(a.Config.Secrets.Keys, a.Config.Secrets.KeyID); err != nil {
+		return fmt.Errorf("Unable to init secret master keys: %v", err)
+	}
+	if a.Config.Vault.Address != "" {
+		if err := secret.InitVault(a.Config.Vault.Address, a.Config.Vault.Token); err != nil {
+			return fmt.Errorf("Unable to init Vault client: %v", err)
+		}
+	}
 
 	//Initialize mail package
 	log.Info("Initializing mail driver...")
@@ -485,6 +543,8 @@ func (a *API) Serve(ctx context.Context) error {
 		objectstoreKind = objectstore.Openstack
 	case "swift":
 		objectstoreKind = objectstore.Swift
+	case "s3":
+		objectstoreKind = objectstore.S3
 	case "filesystem", "local":
 		objectstoreKind = objectstore.Filesystem
 	default:
@@ -504,6 +564,16 @@ func (a *API) Serve(ctx context.Context) error {
 				ContainerPrefix: a.Config.Artifact.Openstack.ContainerPrefix,
 				DisableTempURL:  a.Config.Artifact.Openstack.DisableTempURL,
 			},
+			S3: objectstore.ConfigOptionsS3{
+				Endpoint:        a.Config.Artifact.S3.Endpoint,
+				Region:          a.Config.Artifact.S3.Region,
+				Bucket:          a.Config.Artifact.S3.Bucket,
+				AccessKeyID:     a.Config.Artifact.S3.AccessKeyID,
+				SecretAccessKey: a.Config.Artifact.S3.SecretAccessKey,
+				Prefix:          a.Config.Artifact.S3.Prefix,
+				ForcePathStyle:  a.Config.Artifact.S3.ForcePathStyle,
+				DisableTempURL:  a.Config.Artifact.S3.DisableTempURL,
+			},
 			Filesystem: objectstore.ConfigOptionsFilesystem{
 				Basedir: a.Config.Artifact.Local.BaseDirectory,
 			},
@@ -514,6 +584,11 @@ func (a *API) Serve(ctx context.Context) error {
 		return fmt.Errorf("cannot initialize storage: %v", err)
 	}
 
+	if a.Config.Logs.Storage == "objectstore" {
+		log.Info("Initializing logs storage in objectstore...")
+		workflow.SetLogStore(workflow.NewObjectstoreLogStore(objectstore.Storage()))
+	}
+
 	log.Info("Initializing database connection...")
 	//Intialize database
 	var errDB error
@@ -578,14 +653,14 @@ func (a *API) Serve(ctx context.Context) error {
 	hook.Init(a.Config.URL.API)
 
 	//Intialize notification package
-	notification.Init(a.Config.URL.UI)
+	notification.Init(a.Config.URL.UI, a.DBConnectionFactory.GetDBMap)
 
 	log.Info("Initializing Authentication driver...")
 	// Initialize the auth driver
 	var authMode string
 	var authOptions interface{}
-	switch a.Config.Auth.LDAP.Enable {
-	case true:
+	switch {
+	case a.Config.Auth.LDAP.Enable:
 		authMode = "ldap"
 		authOptions = auth.LDAPConfig{
 			Host:         a.Config.Auth.LDAP.Host,
@@ -597,6 +672,18 @@ func (a *API) Serve(ctx context.Context) error {
 			BindDN:       a.Config.Auth.LDAP.BindDN,
 			BindPwd:      a.Config.Auth.LDAP.BindPwd,
 		}
+	case a.Config.Auth.OIDC.Enable:
+		authMode = "oidc"
+		authOptions = auth.OIDCConfig{
+			Issuer:        a.Config.Auth.OIDC.Issuer,
+			ClientID:      a.Config.Auth.OIDC.ClientID,
+			ClientSecret:  a.Config.Auth.OIDC.ClientSecret,
+			RedirectURL:   a.Config.URL.API + "/login/oidc/callback",
+			Scopes:        strings.Fields(a.Config.Auth.OIDC.Scopes),
+			UsernameClaim: a.Config.Auth.OIDC.UsernameClaim,
+			GroupsClaim:   a.Config.Auth.OIDC.GroupsClaim,
+			Groups:        a.Config.Auth.OIDC.Groups,
+		}
 	default:
 		authMode = "local"
 	}
@@ -627,7 +714,9 @@ func (a *API) Serve(ctx context.Context) error {
 		go event.DequeueEvent(ctx)
 	}
 
-	if err := worker.Initialize(ctx, a.DBConnectionFactory.GetDBMap, a.Cache); err != nil {
+	if err := worker.Initialize(ctx, a.DBConnectionFactory.GetDBMap, a.Cache, func(c context.Context, id string) error {
+		return DisableWorker(c, a.DBConnectionFactory.GetDBMap, a.Cache, id)
+	}); err != nil {
 		log.Error("error while initializing workers routine: %s", err)
 	}
 
@@ -644,8 +733,12 @@ func (a *API) Serve(ctx context.Context) error {
 	sdk.GoRoutine("repositoriesmanager.ReceiveEvents", func() { repositoriesmanager.ReceiveEvents(ctx, a.DBConnectionFactory.GetDBMap, a.Cache) })
 	sdk.GoRoutine("action.RequirementsCacheLoader", func() { action.RequirementsCacheLoader(ctx, 5*time.Second, a.DBConnectionFactory.GetDBMap, a.Cache) })
 	sdk.GoRoutine("hookRecoverer(ctx", func() { hookRecoverer(ctx, a.DBConnectionFactory.GetDBMap, a.Cache) })
+	sdk.GoRoutine("jobTimeoutKiller", func() { jobTimeoutKiller(ctx, a.DBConnectionFactory.GetDBMap, a.Cache) })
+	sdk.GoRoutine("approvalExpirer", func() { approvalExpirer(ctx, a.DBConnectionFactory.GetDBMap, a.Cache) })
+	sdk.GoRoutine("concurrencyReleaser", func() { concurrencyReleaser(ctx, a.DBConnectionFactory.GetDBMap, a.Cache) })
 	sdk.GoRoutine("services.KillDeadServices", func() { services.KillDeadServices(ctx, a.mustDB) })
 	sdk.GoRoutine("migrate.CleanOldWorkflow", func() { migrate.CleanOldWorkflow(ctx, a.Cache, a.DBConnectionFactory.GetDBMap, a.Config.URL.API) })
+	sdk.GoRoutine("migrate.ResumeSecretReencryption", func() { migrate.ResumeSecretReencryption(ctx, a.DBConnectionFactory.GetDBMap) })
 	sdk.GoRoutine("migrate.KeyMigration", func() { migrate.KeyMigration(a.Cache, a.DBConnectionFactory.GetDBMap, &sdk.User{Admin: true}) })
 	sdk.GoRoutine("broadcast.Initialize", func() { broadcast.Initialize(ctx, a.DBConnectionFactory.GetDBMap) })
 	//sdk.GoRoutine("workflow.RestartAwolJobs", func() { workflow.RestartAwolJobs(ctx, a.Cache, a.DBConnectionFactory.GetDBMap) })
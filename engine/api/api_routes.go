@@ -29,6 +29,11 @@ func (api *API) InitRouter() {
 
 	r := api.Router
 	r.Handle("/login", r.POST(api.loginUserHandler, Auth(false)))
+	r.Handle("/login/oidc", r.GET(api.getLoginOIDCHandler, Auth(false)))
+	r.Handle("/login/oidc/config", r.GET(api.getLoginOIDCConfigHandler, Auth(false)))
+	r.Handle("/login/oidc/callback", r.GET(api.getLoginOIDCCallbackHandler, Auth(false)))
+	r.Handle("/login/oidc/device", r.POST(api.postLoginOIDCDeviceHandler, Auth(false)))
+	r.Handle("/login/oidc/device/token", r.POST(api.postLoginOIDCDeviceTokenHandler, Auth(false)))
 
 	// Action
 	r.Handle("/action", r.GET(api.getActionsHandler))
@@ -55,6 +60,7 @@ func (api *API) InitRouter() {
 	// Admin service
 	r.Handle("/admin/service/{name}", r.GET(api.getAdminServiceHandler, NeedAdmin(true)))
 	r.Handle("/admin/services", r.GET(api.getAdminServicesHandler, NeedAdmin(true)))
+	r.Handle("/admin/secret/reencryption", r.GET(api.getAdminSecretReencryptionHandler, NeedAdmin(true)), r.POST(api.postAdminSecretReencryptionHandler, NeedAdmin(true)))
 	r.Handle("/admin/services/call", r.GET(api.getAdminServiceCallHandler, NeedAdmin(true)), r.POST(api.postAdminServiceCallHandler, NeedAdmin(true)), r.PUT(api.putAdminServiceCallHandler, NeedAdmin(true)), r.DELETE(api.deleteAdminServiceCallHandler, NeedAdmin(true)))
 
 	// Download file
@@ -70,6 +76,17 @@ func (api *API) InitRouter() {
 	r.Handle("/group/{permGroupName}/user/{user}/admin", r.POST(api.setUserGroupAdminHandler), r.DELETE(api.removeUserGroupAdminHandler))
 	r.Handle("/group/{permGroupName}/token", r.GET(api.getGroupTokenListHandler), r.POST(api.generateTokenHandler))
 	r.Handle("/group/{permGroupName}/token/{tokenid}", r.DELETE(api.deleteTokenHandler))
+	r.Handle("/group/{permGroupName}/template", r.GET(api.getGroupWorkflowTemplatesHandler), r.POST(api.postGroupWorkflowTemplateHandler))
+	r.Handle("/group/{permGroupName}/template/{templateName}", r.GET(api.getGroupWorkflowTemplateHandler), r.PUT(api.putGroupWorkflowTemplateHandler), r.DELETE(api.deleteGroupWorkflowTemplateHandler))
+	r.Handle("/group/{permGroupName}/template/{templateName}/version", r.GET(api.getGroupWorkflowTemplateVersionsHandler))
+	r.Handle("/group/{permGroupName}/template/{templateName}/instance", r.GET(api.getGroupWorkflowTemplateInstancesHandler))
+
+	// Role
+	r.Handle("/role", r.GET(api.getRolesHandler), r.POST(api.postRoleHandler, NeedAdmin(true)))
+	r.Handle("/role/{name}", r.PUT(api.putRoleHandler, NeedAdmin(true)), r.DELETE(api.deleteRoleHandler, NeedAdmin(true)))
+
+	// Workflow templates
+	r.Handle("/template", r.GET(api.getWorkflowTemplatesHandler))
 
 	// Hatchery
 	r.Handle("/hatchery/count/{workflowNodeRunID}", r.GET(api.hatcheryCountHandler))
@@ -77,6 +94,7 @@ func (api *API) InitRouter() {
 	// Hooks
 	r.Handle("/hook", r.POST(api.receiveHookHandler, Auth(false) /* Public handler called by third parties */))
 	r.Handle("/hook/{uuid}/workflow/{workflowID}/vcsevent/{vcsServer}", r.GET(api.getHookPollingVCSEvents))
+	r.Handle("/hook/{uuid}/changedfiles", r.GET(api.getHookChangedFilesHandler))
 
 	// Platform
 	r.Handle("/platform/models", r.GET(api.getPlatformModelsHandler), r.POST(api.postPlatformModelHandler, NeedAdmin(true)))
@@ -110,21 +128,30 @@ func (api *API) InitRouter() {
 	r.Handle("/project", r.GET(api.getProjectsHandler, AllowProvider(true), EnableTracing()), r.POST(api.addProjectHandler))
 	r.Handle("/project/{permProjectKey}", r.GET(api.getProjectHandler), r.PUT(api.updateProjectHandler), r.DELETE(api.deleteProjectHandler))
 	r.Handle("/project/{permProjectKey}/labels", r.PUT(api.putProjectLabelsHandler))
+	r.Handle("/project/{permProjectKey}/vault/role", r.PUT(api.putProjectVaultRoleHandler, NeedAdmin(true)))
 	r.Handle("/project/{permProjectKey}/group", r.POST(api.addGroupInProjectHandler))
 	r.Handle("/project/{permProjectKey}/group/import", r.POST(api.importGroupsInProjectHandler, DEPRECATED))
 	r.Handle("/project/{permProjectKey}/group/{group}", r.PUT(api.updateGroupRoleOnProjectHandler), r.DELETE(api.deleteGroupFromProjectHandler))
+	r.Handle("/project/{permProjectKey}/role", r.GET(api.getProjectRoleBindingsHandler), r.POST(api.postProjectRoleBindingHandler))
+	r.Handle("/project/{permProjectKey}/role/{id}", r.DELETE(api.deleteProjectRoleBindingHandler))
 	r.Handle("/project/{permProjectKey}/variable", r.GET(api.getVariablesInProjectHandler))
 	r.Handle("/project/{permProjectKey}/encrypt", r.POST(api.postEncryptVariableHandler))
 	r.Handle("/project/{key}/variable/audit", r.GET(api.getVariablesAuditInProjectnHandler))
-	r.Handle("/project/{permProjectKey}/variable/{name}", r.GET(api.getVariableInProjectHandler), r.POST(api.addVariableInProjectHandler), r.PUT(api.updateVariableInProjectHandler), r.DELETE(api.deleteVariableFromProjectHandler))
+	r.Handle("/project/{permProjectKey}/variable/{name}", r.GET(api.getVariableInProjectHandler), r.POST(api.addVariableInProjectHandler, NeedPermission(sdk.PermissionNameManageVariables)), r.PUT(api.updateVariableInProjectHandler, NeedPermission(sdk.PermissionNameManageVariables)), r.DELETE(api.deleteVariableFromProjectHandler, NeedPermission(sdk.PermissionNameManageVariables)))
 	r.Handle("/project/{permProjectKey}/variable/{name}/audit", r.GET(api.getVariableAuditInProjectHandler))
 	r.Handle("/project/{permProjectKey}/applications", r.GET(api.getApplicationsHandler, AllowProvider(true)), r.POST(api.addApplicationHandler))
 	r.Handle("/project/{permProjectKey}/platforms", r.GET(api.getProjectPlatformsHandler), r.POST(api.postProjectPlatformHandler))
 	r.Handle("/project/{permProjectKey}/platforms/{platformName}", r.GET(api.getProjectPlatformHandler, AllowServices(true)), r.PUT(api.putProjectPlatformHandler), r.DELETE(api.deleteProjectPlatformHandler))
 	r.Handle("/project/{permProjectKey}/notifications", r.GET(api.getProjectNotificationsHandler))
 	r.Handle("/project/{permProjectKey}/all/keys", r.GET(api.getAllKeysProjectHandler))
-	r.Handle("/project/{permProjectKey}/keys", r.GET(api.getKeysInProjectHandler), r.POST(api.addKeyInProjectHandler))
-	r.Handle("/project/{permProjectKey}/keys/{name}", r.DELETE(api.deleteKeyInProjectHandler))
+	r.Handle("/project/{permProjectKey}/keys", r.GET(api.getKeysInProjectHandler), r.POST(api.addKeyInProjectHandler, NeedPermission(sdk.PermissionNameManageVariables)))
+	r.Handle("/project/{permProjectKey}/keys/{name}", r.DELETE(api.deleteKeyInProjectHandler, NeedPermission(sdk.PermissionNameManageVariables)))
+	r.Handle("/project/{permProjectKey}/freeze", r.GET(api.getFreezeWindowsHandler), r.POST(api.postFreezeWindowHandler))
+	r.Handle("/project/{permProjectKey}/freeze/audit", r.GET(api.getFreezeWindowAuditsHandler))
+	r.Handle("/project/{permProjectKey}/freeze/{id}", r.PUT(api.putFreezeWindowHandler), r.DELETE(api.deleteFreezeWindowHandler))
+	r.Handle("/project/{permProjectKey}/freeze/{id}/override", r.GET(api.getFreezeWindowOverridesHandler))
+	r.Handle("/project/{permProjectKey}/template/instance", r.GET(api.getProjectWorkflowTemplateInstancesHandler))
+	r.Handle("/project/{permProjectKey}/template/{groupName}/{templateName}/apply", r.POST(api.postProjectWorkflowTemplateApplyHandler))
 	// Import Application
 	r.Handle("/project/{permProjectKey}/import/application", r.POST(api.postApplicationImportHandler))
 	// Export Application
@@ -136,8 +163,8 @@ func (api *API) InitRouter() {
 	// Application
 	r.Handle("/project/{key}/application/{permApplicationName}", r.GET(api.getApplicationHandler), r.PUT(api.updateApplicationHandler), r.DELETE(api.deleteApplicationHandler))
 	r.Handle("/project/{key}/application/{permApplicationName}/metrics/{metricName}", r.GET(api.getApplicationMetricHandler))
-	r.Handle("/project/{key}/application/{permApplicationName}/keys", r.GET(api.getKeysInApplicationHandler), r.POST(api.addKeyInApplicationHandler))
-	r.Handle("/project/{key}/application/{permApplicationName}/keys/{name}", r.DELETE(api.deleteKeyInApplicationHandler))
+	r.Handle("/project/{key}/application/{permApplicationName}/keys", r.GET(api.getKeysInApplicationHandler), r.POST(api.addKeyInApplicationHandler, NeedPermission(sdk.PermissionNameManageVariables)))
+	r.Handle("/project/{key}/application/{permApplicationName}/keys/{name}", r.DELETE(api.deleteKeyInApplicationHandler, NeedPermission(sdk.PermissionNameManageVariables)))
 	r.Handle("/project/{key}/application/{permApplicationName}/branches", r.GET(api.getApplicationBranchHandler))
 	r.Handle("/project/{key}/application/{permApplicationName}/vcsinfos", r.GET(api.getApplicationVCSInfosHandler))
 	r.Handle("/project/{key}/application/{permApplicationName}/remotes", r.GET(api.getApplicationRemoteHandler))
@@ -159,7 +186,7 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{key}/application/{permApplicationName}/tree/status", r.GET(api.getApplicationTreeStatusHandler))
 	r.Handle("/project/{key}/application/{permApplicationName}/variable", r.GET(api.getVariablesInApplicationHandler))
 	r.Handle("/project/{key}/application/{permApplicationName}/variable/audit", r.GET(api.getVariablesAuditInApplicationHandler))
-	r.Handle("/project/{key}/application/{permApplicationName}/variable/{name}", r.GET(api.getVariableInApplicationHandler), r.POST(api.addVariableInApplicationHandler), r.PUT(api.updateVariableInApplicationHandler), r.DELETE(api.deleteVariableFromApplicationHandler))
+	r.Handle("/project/{key}/application/{permApplicationName}/variable/{name}", r.GET(api.getVariableInApplicationHandler), r.POST(api.addVariableInApplicationHandler, NeedPermission(sdk.PermissionNameManageVariables)), r.PUT(api.updateVariableInApplicationHandler, NeedPermission(sdk.PermissionNameManageVariables)), r.DELETE(api.deleteVariableFromApplicationHandler, NeedPermission(sdk.PermissionNameManageVariables)))
 	r.Handle("/project/{key}/application/{permApplicationName}/variable/{name}/audit", r.GET(api.getVariableAuditInApplicationHandler))
 	r.Handle("/project/{key}/application/{permApplicationName}/vulnerability/{id}", r.POST(api.postVulnerabilityHandler))
 	// Application deployment
@@ -224,6 +251,8 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/groups", r.POST(api.postWorkflowGroupHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/groups/{groupName}", r.PUT(api.putWorkflowGroupHandler), r.DELETE(api.deleteWorkflowGroupHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/hooks/{uuid}", r.GET(api.getWorkflowHookHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/notifications/deliveries", r.GET(api.getWorkflowNotificationDeliveriesHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/approvals/audit", r.GET(api.getWorkflowApprovalAuditsHandler))
 	r.Handle("/project/{key}/workflow/{permWorkflowName}/node/{nodeID}/hook/model", r.GET(api.getWorkflowHookModelsHandler))
 
 	// Preview workflows
@@ -252,10 +281,13 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/artifacts", r.GET(api.getWorkflowRunArtifactsHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}", r.GET(api.getWorkflowNodeRunHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/stop", r.POSTEXECUTE(api.stopWorkflowNodeRunHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/approval", r.POSTEXECUTE(api.postWorkflowNodeRunApprovalHandler, NeedPermission(sdk.PermissionNameApprove)))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeID}/history", r.GET(api.getWorkflowNodeRunHistoryHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/{nodeName}/commits", r.GET(api.getWorkflowCommitsHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/job/{runJobId}/log/service", r.GET(api.getWorkflowNodeRunJobServiceLogsHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/job/{runJobId}/step/{stepOrder}", r.GET(api.getWorkflowNodeRunJobStepHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/job/{runJobId}/step/{stepOrder}/lines", r.GET(api.getWorkflowNodeRunJobStepLinesHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/logs/search", r.GET(api.getWorkflowNodeRunLogsSearchHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/artifact/{artifactId}", r.GET(api.getDownloadArtifactHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/node/{nodeID}/triggers/condition", r.GET(api.getWorkflowTriggerConditionHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/release", r.POST(api.releaseApplicationWorkflowHandler))
@@ -271,15 +303,15 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{key}/environment/import/{permEnvironmentName}", r.POST(api.importIntoEnvironmentHandler, DEPRECATED))
 	r.Handle("/project/{key}/environment/{permEnvironmentName}", r.GET(api.getEnvironmentHandler), r.PUT(api.updateEnvironmentHandler), r.DELETE(api.deleteEnvironmentHandler))
 	r.Handle("/project/{key}/environment/{permEnvironmentName}/usage", r.GET(api.getEnvironmentUsageHandler))
-	r.Handle("/project/{key}/environment/{permEnvironmentName}/keys", r.GET(api.getKeysInEnvironmentHandler), r.POST(api.addKeyInEnvironmentHandler))
-	r.Handle("/project/{key}/environment/{permEnvironmentName}/keys/{name}", r.DELETE(api.deleteKeyInEnvironmentHandler))
+	r.Handle("/project/{key}/environment/{permEnvironmentName}/keys", r.GET(api.getKeysInEnvironmentHandler), r.POST(api.addKeyInEnvironmentHandler, NeedPermission(sdk.PermissionNameManageVariables)))
+	r.Handle("/project/{key}/environment/{permEnvironmentName}/keys/{name}", r.DELETE(api.deleteKeyInEnvironmentHandler, NeedPermission(sdk.PermissionNameManageVariables)))
 	r.Handle("/project/{key}/environment/{permEnvironmentName}/clone/{cloneName}", r.POST(api.cloneEnvironmentHandler))
 	r.Handle("/project/{key}/environment/{permEnvironmentName}/group", r.POST(api.addGroupInEnvironmentHandler))
 	r.Handle("/project/{key}/environment/{permEnvironmentName}/groups", r.POST(api.addGroupsInEnvironmentHandler, DEPRECATED))
 	r.Handle("/project/{key}/environment/{permEnvironmentName}/group/import", r.POST(api.importGroupsInEnvironmentHandler, DEPRECATED))
 	r.Handle("/project/{key}/environment/{permEnvironmentName}/group/{group}", r.PUT(api.updateGroupRoleOnEnvironmentHandler), r.DELETE(api.deleteGroupFromEnvironmentHandler))
 	r.Handle("/project/{key}/environment/{permEnvironmentName}/variable", r.GET(api.getVariablesInEnvironmentHandler))
-	r.Handle("/project/{key}/environment/{permEnvironmentName}/variable/{name}", r.GET(api.getVariableInEnvironmentHandler), r.POST(api.addVariableInEnvironmentHandler), r.PUT(api.updateVariableInEnvironmentHandler), r.DELETE(api.deleteVariableFromEnvironmentHandler))
+	r.Handle("/project/{key}/environment/{permEnvironmentName}/variable/{name}", r.GET(api.getVariableInEnvironmentHandler), r.POST(api.addVariableInEnvironmentHandler, NeedPermission(sdk.PermissionNameManageVariables)), r.PUT(api.updateVariableInEnvironmentHandler, NeedPermission(sdk.PermissionNameManageVariables)), r.DELETE(api.deleteVariableFromEnvironmentHandler, NeedPermission(sdk.PermissionNameManageVariables)))
 	r.Handle("/project/{key}/environment/{permEnvironmentName}/variable/{name}/audit", r.GET(api.getVariableAuditInEnvironmentHandler))
 
 	// Import Environment
@@ -372,16 +404,19 @@ func (api *API) InitRouter() {
 	r.Handle("/requirement/types/{type}", r.GET(api.getRequirementTypeValuesHandler))
 
 	// config
-	r.Handle("/config/user", r.GET(api.ConfigUserHandler, Auth(true)))
+	r.Handle("/config/user", r.GET(api.ConfigUserHandler, Auth(true), AllowProjectAccessToken(true)))
 
 	// Users
 	r.Handle("/user", r.GET(api.getUsersHandler))
-	r.Handle("/user/me", r.GET(api.getUserMeHandler))
+	r.Handle("/user/me", r.GET(api.getUserMeHandler, AllowProjectAccessToken(true)))
 	r.Handle("/user/favorite", r.POST(api.postUserFavoriteHandler))
 	r.Handle("/user/timeline", r.GET(api.getTimelineHandler))
 	r.Handle("/user/timeline/filter", r.GET(api.getTimelineFilterHandler), r.POST(api.postTimelineFilterHandler))
 	r.Handle("/user/token", r.GET(api.getUserTokenListHandler))
 	r.Handle("/user/token/{token}", r.GET(api.getUserTokenHandler))
+	r.Handle("/user/accesstoken", r.GET(api.getUserAccessTokensHandler), r.POST(api.postUserAccessTokenHandler))
+	r.Handle("/user/accesstoken/{id}", r.DELETE(api.deleteUserAccessTokenHandler))
+	r.Handle("/user/accesstoken/{id}/audit", r.GET(api.getUserAccessTokenAuditsHandler))
 	r.Handle("/user/signup", r.POST(api.addUserHandler, Auth(false)))
 	r.Handle("/user/import", r.POST(api.importUsersHandler, NeedAdmin(true)))
 	r.Handle("/user/{username}", r.GET(api.getUserHandler, NeedUsernameOrAdmin(true)), r.PUT(api.updateUserHandler, NeedUsernameOrAdmin(true)), r.DELETE(api.deleteUserHandler, NeedUsernameOrAdmin(true)))
@@ -6,6 +6,7 @@ import (
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/role"
 	"github.com/ovh/cds/engine/api/trigger"
 	"github.com/ovh/cds/sdk"
 )
@@ -86,6 +87,16 @@ func DeleteApplication(db gorp.SqlExecutor, applicationID int64) error {
 		return err
 	}
 
+	// Delete role bindings, they are not linked to the application by a foreign key
+	var projectID int64
+	var name string
+	if err := db.QueryRow("SELECT project_id, name FROM application WHERE id = $1", applicationID).Scan(&projectID, &name); err != nil {
+		return sdk.WrapError(err, "DeleteApplication> Cannot load application")
+	}
+	if err := role.DeleteBindingsByObject(db, projectID, sdk.RoleBindingApplication, name); err != nil {
+		return err
+	}
+
 	query = `DELETE FROM application WHERE id=$1`
 	if _, err := db.Exec(query, applicationID); err != nil {
 		return sdk.WrapError(err, "DeleteApplication> Cannot delete application")
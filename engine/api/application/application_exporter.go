@@ -8,6 +8,7 @@ import (
 
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/role"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/exportentities"
 )
@@ -99,6 +100,12 @@ func ExportApplication(db gorp.SqlExecutor, app sdk.Application, f exportentitie
 	if err != nil {
 		return 0, sdk.WrapError(err, "application.Export> Unable to export application")
 	}
+	if withPermissions {
+		eapp.Roles, err = role.ExportBindings(db, app.ProjectID, sdk.RoleBindingApplication, app.Name)
+		if err != nil {
+			return 0, sdk.WrapError(err, "application.Export> Unable to load roles")
+		}
+	}
 
 	// Marshal to the desired format
 	b, err := exportentities.Marshal(eapp, f)
@@ -9,6 +9,7 @@ import (
 
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/keys"
+	"github.com/ovh/cds/engine/api/role"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/exportentities"
 	"github.com/ovh/cds/sdk/log"
@@ -199,5 +200,11 @@ func ParseAndImport(db gorp.SqlExecutor, cache cache.Store, proj *sdk.Project, e
 	close(msgChan)
 	done.Wait()
 
+	if globalError == nil {
+		if err := role.ImportBindings(db, proj.ID, sdk.RoleBindingApplication, app.Name, eapp.Roles); err != nil {
+			return app, msgList, sdk.WrapError(err, "ParseAndImport> Unable to import roles")
+		}
+	}
+
 	return app, msgList, globalError
 }
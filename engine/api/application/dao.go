@@ -12,6 +12,7 @@ import (
 	"github.com/ovh/cds/engine/api/database"
 	"github.com/ovh/cds/engine/api/event"
 	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/role"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -323,6 +324,16 @@ func Update(db gorp.SqlExecutor, store cache.Store, app *sdk.Application, u *sdk
 		return sdk.WrapError(err, "application.Update> application is not valid")
 	}
 
+	// Rename the role bindings, they are matched by name
+	var projectID int64
+	var oldName string
+	if err := db.QueryRow("SELECT project_id, name FROM application WHERE id = $1", app.ID).Scan(&projectID, &oldName); err != nil {
+		return sdk.WrapError(err, "application.Update %s(%d)", app.Name, app.ID)
+	}
+	if err := role.RenameBindingsObject(db, projectID, sdk.RoleBindingApplication, oldName, app.Name); err != nil {
+		return err
+	}
+
 	app.LastModified = time.Now()
 	dbApp := dbApplication(*app)
 	n, err := db.Update(&dbApp)
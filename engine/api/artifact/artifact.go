@@ -420,17 +420,6 @@ func InsertArtifact(db gorp.SqlExecutor, pipelineID, applicationID int64, enviro
 	return nil
 }
 
-// SaveWorkflowFile Insert file in db and write it in data directory
-func SaveWorkflowFile(art *sdk.WorkflowNodeRunArtifact, content io.ReadCloser) error {
-	objectPath, err := objectstore.Store(art, content)
-	if err != nil {
-		return sdk.WrapError(err, "SaveWorkflowFile> Cannot store artifact")
-	}
-	log.Debug("objectpath=%s\n", objectPath)
-	art.ObjectPath = objectPath
-	return nil
-}
-
 // SaveFile Insert file in db and write it in data directory
 func SaveFile(db *gorp.DbMap, p *sdk.Pipeline, a *sdk.Application, art sdk.Artifact, content io.ReadCloser, e *sdk.Environment) error {
 	tx, errB := db.Begin()
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-gorp/gorp"
 
@@ -13,6 +15,7 @@ import (
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/api/sessionstore"
+	"github.com/ovh/cds/engine/api/user"
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/cdsclient"
@@ -28,9 +31,10 @@ const (
 	ContextService
 	ContextUserSession
 	ContextProvider
+	ContextAccessToken
 )
 
-//Driver is an interface to all auth method (local, ldap and beyond...)
+//Driver is an interface to all auth method (local, ldap, oidc and beyond...)
 type Driver interface {
 	Open(options interface{}, store sessionstore.Store) error
 	Store() sessionstore.Store
@@ -52,6 +56,10 @@ func GetDriver(c context.Context, mode string, options interface{}, storeOptions
 		d = &LDAPClient{
 			dbFunc: DBFunc,
 		}
+	case "oidc":
+		d = &OIDCClient{
+			dbFunc: DBFunc,
+		}
 	default:
 		d = &LocalClient{
 			dbFunc: DBFunc,
@@ -185,3 +193,31 @@ func CheckServiceAuth(ctx context.Context, db *gorp.DbMap, store cache.Store, he
 	}
 	return ctx, nil
 }
+
+// HasAccessToken returns true if the request is authenticated with an access token
+func HasAccessToken(headers http.Header) bool {
+	return strings.HasPrefix(headers.Get("Authorization"), "Bearer ")
+}
+
+// CheckAccessTokenAuth checks the access token given as bearer token, the user owning the token is set in the context
+func CheckAccessTokenAuth(ctx context.Context, db gorp.SqlExecutor, headers http.Header) (context.Context, error) {
+	t, err := user.LoadAccessToken(db, strings.TrimPrefix(headers.Get("Authorization"), "Bearer "))
+	if err != nil {
+		return ctx, err
+	}
+	if !t.IsActive(time.Now()) {
+		return ctx, fmt.Errorf("access token %d is revoked or expired", t.ID)
+	}
+
+	u, err := user.LoadUserWithoutAuthByID(db, t.UserID)
+	if err != nil {
+		return ctx, fmt.Errorf("cannot load user of access token %d: %s", t.ID, err)
+	}
+	if err := user.UpdateAccessTokenLastUsed(db, t.ID); err != nil {
+		log.Warning("CheckAccessTokenAuth> %v", err)
+	}
+
+	ctx = context.WithValue(ctx, ContextUser, u)
+	ctx = context.WithValue(ctx, ContextAccessToken, t)
+	return ctx, nil
+}
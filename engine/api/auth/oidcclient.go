@@ -0,0 +1,553 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/sessionstore"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+//OIDCConfig handles all config to connect to an OpenID Connect provider
+type OIDCConfig struct {
+	Issuer        string
+	ClientID      string
+	ClientSecret  string
+	RedirectURL   string
+	Scopes        []string
+	UsernameClaim string
+	GroupsClaim   string
+	// Groups maps the values of the groups claim to CDS group names
+	Groups map[string]string
+}
+
+//OIDCAuthRequest is an authorization code request, kept by the API until the provider redirects the user
+type OIDCAuthRequest struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+//OIDCClient is an auth driver delegating the authentication to an OpenID Connect provider
+type OIDCClient struct {
+	store    sessionstore.Store
+	conf     OIDCConfig
+	provider oidcProvider
+	keys     map[string]*rsa.PublicKey
+	mutex    sync.RWMutex
+	local    *LocalClient
+	dbFunc   func() *gorp.DbMap
+	client   *http.Client
+}
+
+// oidcProvider is the discovery document of the provider
+type oidcProvider struct {
+	Issuer                      string `json:"issuer"`
+	AuthorizationEndpoint       string `json:"authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+	JWKSURI                     string `json:"jwks_uri"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+}
+
+type oidcTokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// oidcClaims are the claims of an ID token
+type oidcClaims map[string]interface{}
+
+//Open fetches the discovery document and the signing keys of the provider
+func (c *OIDCClient) Open(options interface{}, store sessionstore.Store) error {
+	log.Info("Auth> Connecting to session store")
+	c.store = store
+	//OIDC Client needs a local client to check local users and sessions
+	c.local = &LocalClient{
+		dbFunc: c.dbFunc,
+	}
+	c.local.Open(options, store)
+
+	conf, ok := options.(OIDCConfig)
+	if !ok {
+		return fmt.Errorf("invalid OpenID Connect configuration")
+	}
+	c.conf = conf
+	if c.client == nil {
+		c.client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if len(c.conf.Scopes) == 0 {
+		c.conf.Scopes = []string{"openid", "profile", "email"}
+	}
+	if c.conf.UsernameClaim == "" {
+		c.conf.UsernameClaim = "preferred_username"
+	}
+
+	discoveryURL := strings.TrimSuffix(c.conf.Issuer, "/") + "/.well-known/openid-configuration"
+	log.Info("Auth> Loading OpenID Connect provider configuration from %s", discoveryURL)
+	if err := c.getJSON(discoveryURL, &c.provider); err != nil {
+		return sdk.WrapError(err, "OIDCClient.Open> Unable to load provider configuration")
+	}
+	if strings.TrimSuffix(c.provider.Issuer, "/") != strings.TrimSuffix(c.conf.Issuer, "/") {
+		return fmt.Errorf("OIDCClient.Open> issuer %s of the provider does not match %s", c.provider.Issuer, c.conf.Issuer)
+	}
+	return c.loadKeys()
+}
+
+//Store returns store
+func (c *OIDCClient) Store() sessionstore.Store {
+	return c.store
+}
+
+//CheckAuth checks the session, sessions are created by the API once the provider has authenticated the user
+func (c *OIDCClient) CheckAuth(ctx context.Context, w http.ResponseWriter, req *http.Request) (context.Context, error) {
+	return c.local.CheckAuth(ctx, w, req)
+}
+
+//Authentify check username and password of local users, the other users are authenticated by the provider
+func (c *OIDCClient) Authentify(username, password string) (bool, error) {
+	return c.local.Authentify(username, password)
+}
+
+//NewOIDCAuthRequest returns a new authorization code request with its PKCE verifier
+func NewOIDCAuthRequest() (*OIDCAuthRequest, error) {
+	var r OIDCAuthRequest
+	for _, s := range []*string{&r.State, &r.Nonce, &r.Verifier} {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return nil, sdk.WrapError(err, "NewOIDCAuthRequest> Unable to generate random string")
+		}
+		*s = base64.RawURLEncoding.EncodeToString(b)
+	}
+	return &r, nil
+}
+
+//AuthCodeURL returns the URL of the provider where the user is redirected to login
+func (c *OIDCClient) AuthCodeURL(r OIDCAuthRequest) string {
+	challenge := sha256.Sum256([]byte(r.Verifier))
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", c.conf.ClientID)
+	v.Set("redirect_uri", c.conf.RedirectURL)
+	v.Set("scope", strings.Join(c.conf.Scopes, " "))
+	v.Set("state", r.State)
+	v.Set("nonce", r.Nonce)
+	v.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	v.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(c.provider.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return c.provider.AuthorizationEndpoint + sep + v.Encode()
+}
+
+//LoginWithCode exchanges the authorization code, then creates or updates the user from the claims of its ID token
+func (c *OIDCClient) LoginWithCode(db gorp.SqlExecutor, r OIDCAuthRequest, code string) (*sdk.User, error) {
+	v := url.Values{}
+	v.Set("grant_type", "authorization_code")
+	v.Set("code", code)
+	v.Set("redirect_uri", c.conf.RedirectURL)
+	v.Set("code_verifier", r.Verifier)
+	claims, err := c.token(v, r.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	return c.insertOrUpdateUser(db, claims)
+}
+
+//DeviceAuthorization starts a device authorization on the provider, the user enters the returned code on the provider
+func (c *OIDCClient) DeviceAuthorization() (*sdk.UserOIDCDeviceAuthorization, error) {
+	if c.provider.DeviceAuthorizationEndpoint == "" {
+		return nil, sdk.NewError(sdk.ErrOIDCLoginFailed, fmt.Errorf("the provider does not support the device authorization"))
+	}
+	v := url.Values{}
+	v.Set("scope", strings.Join(c.conf.Scopes, " "))
+
+	var res struct {
+		sdk.UserOIDCDeviceAuthorization
+		// Some providers use verification_url
+		VerificationURL string `json:"verification_url"`
+	}
+	if err := c.postForm(c.provider.DeviceAuthorizationEndpoint, v, &res); err != nil {
+		return nil, sdk.WrapError(err, "OIDCClient.DeviceAuthorization> Unable to start device authorization")
+	}
+	if res.DeviceCode == "" {
+		return nil, sdk.NewError(sdk.ErrOIDCLoginFailed, fmt.Errorf("the provider returned no device code"))
+	}
+	if res.VerificationURI == "" {
+		res.VerificationURI = res.VerificationURL
+	}
+	return &res.UserOIDCDeviceAuthorization, nil
+}
+
+//LoginWithDeviceCode polls the provider for the ID token of the device, then creates or updates the user from
+//its claims. It returns ErrOIDCAuthorizationPending while the user has not entered the code.
+func (c *OIDCClient) LoginWithDeviceCode(db gorp.SqlExecutor, deviceCode string) (*sdk.User, error) {
+	v := url.Values{}
+	v.Set("grant_type", "urn:ietf:params:oauth:grant-type:device_code")
+	v.Set("device_code", deviceCode)
+	claims, err := c.token(v, "")
+	if err != nil {
+		return nil, err
+	}
+	return c.insertOrUpdateUser(db, claims)
+}
+
+// token calls the token endpoint of the provider and verifies the returned ID token
+func (c *OIDCClient) token(v url.Values, nonce string) (oidcClaims, error) {
+	var res oidcTokenResponse
+	if err := c.postForm(c.provider.TokenEndpoint, v, &res); err != nil && res.Error == "" {
+		return nil, sdk.WrapError(err, "OIDCClient.token> Unable to get token")
+	}
+	switch res.Error {
+	case "":
+	case "authorization_pending", "slow_down":
+		return nil, sdk.ErrOIDCAuthorizationPending
+	default:
+		return nil, sdk.NewError(sdk.ErrOIDCLoginFailed, fmt.Errorf("%s %s", res.Error, res.ErrorDescription))
+	}
+	if res.IDToken == "" {
+		return nil, sdk.NewError(sdk.ErrOIDCLoginFailed, fmt.Errorf("the provider returned no ID token"))
+	}
+	return c.verifyIDToken(res.IDToken, nonce)
+}
+
+// verifyIDToken checks the signature, the issuer, the audience, the expiration and the nonce of the ID token
+func (c *OIDCClient) verifyIDToken(raw, nonce string) (oidcClaims, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, sdk.NewError(sdk.ErrOIDCLoginFailed, fmt.Errorf("malformed ID token"))
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, sdk.NewError(sdk.ErrOIDCLoginFailed, fmt.Errorf("malformed ID token header: %v", err))
+	}
+	if header.Alg != "RS256" {
+		return nil, sdk.NewError(sdk.ErrOIDCLoginFailed, fmt.Errorf("unsupported ID token algorithm %s", header.Alg))
+	}
+	key, err := c.key(header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, sdk.NewError(sdk.ErrOIDCLoginFailed, fmt.Errorf("malformed ID token signature"))
+	}
+	hashed := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed[:], sig); err != nil {
+		return nil, sdk.NewError(sdk.ErrOIDCLoginFailed, fmt.Errorf("invalid ID token signature"))
+	}
+
+	var claims oidcClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, sdk.NewError(sdk.ErrOIDCLoginFailed, fmt.Errorf("malformed ID token claims: %v", err))
+	}
+	if iss, _ := claims["iss"].(string); iss != c.provider.Issuer {
+		return nil, sdk.NewError(sdk.ErrOIDCLoginFailed, fmt.Errorf("invalid ID token issuer %s", iss))
+	}
+	if !claims.hasAudience(c.conf.ClientID) {
+		return nil, sdk.NewError(sdk.ErrOIDCLoginFailed, fmt.Errorf("invalid ID token audience"))
+	}
+	if exp, _ := claims["exp"].(float64); time.Unix(int64(exp), 0).Before(time.Now().Add(-time.Minute)) {
+		return nil, sdk.NewError(sdk.ErrOIDCLoginFailed, fmt.Errorf("expired ID token"))
+	}
+	if n, _ := claims["nonce"].(string); nonce != "" && n != nonce {
+		return nil, sdk.NewError(sdk.ErrOIDCLoginFailed, fmt.Errorf("invalid ID token nonce"))
+	}
+	return claims, nil
+}
+
+func decodeSegment(s string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func (claims oidcClaims) hasAudience(clientID string) bool {
+	switch aud := claims["aud"].(type) {
+	case string:
+		return aud == clientID
+	case []interface{}:
+		for _, a := range aud {
+			if a == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// stringSlice returns the values of a claim which is a string or a list of strings
+func (claims oidcClaims) stringSlice(name string) []string {
+	switch v := claims[name].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		res := make([]string, 0, len(v))
+		for _, s := range v {
+			if str, ok := s.(string); ok {
+				res = append(res, str)
+			}
+		}
+		return res
+	}
+	return nil
+}
+
+// key returns the signing key of the provider, the keys are reloaded once for an unknown key id
+func (c *OIDCClient) key(kid string) (*rsa.PublicKey, error) {
+	c.mutex.RLock()
+	k, ok := c.keys[kid]
+	c.mutex.RUnlock()
+	if ok {
+		return k, nil
+	}
+	if err := c.loadKeys(); err != nil {
+		return nil, err
+	}
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if k, ok := c.keys[kid]; ok {
+		return k, nil
+	}
+	return nil, sdk.NewError(sdk.ErrOIDCLoginFailed, fmt.Errorf("unknown ID token key %s", kid))
+}
+
+func (c *OIDCClient) loadKeys() error {
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := c.getJSON(c.provider.JWKSURI, &jwks); err != nil {
+		return sdk.WrapError(err, "OIDCClient.loadKeys> Unable to load provider keys")
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil {
+			log.Warning("OIDCClient.loadKeys> Invalid key %s", k.Kid)
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+
+	c.mutex.Lock()
+	c.keys = keys
+	c.mutex.Unlock()
+	return nil
+}
+
+// subject returns the issuer and the subject of the ID token, which identify the user on the provider
+func (claims oidcClaims) subject() (string, error) {
+	iss, _ := claims["iss"].(string)
+	sub, _ := claims["sub"].(string)
+	if iss == "" || sub == "" {
+		return "", sdk.NewError(sdk.ErrOIDCLoginFailed, fmt.Errorf("claim iss or sub is missing in ID token"))
+	}
+	return iss + " " + sub, nil
+}
+
+// insertOrUpdateUser creates the user at its first login, refreshes its data and its groups from the claims. The
+// users are matched on the issuer and the subject of the ID token, the username claim is only used to name the new
+// users: it can not be used to login as an existing user, which is not an OpenID Connect user.
+func (c *OIDCClient) insertOrUpdateUser(db gorp.SqlExecutor, claims oidcClaims) (*sdk.User, error) {
+	subject, err := claims.subject()
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := user.LoadUserAndAuthByOIDCSubject(db, subject)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, sdk.WrapError(err, "OIDCClient.insertOrUpdateUser> Unable to load user %s", subject)
+	}
+
+	newUser := u == nil
+	if newUser {
+		username, _ := claims[c.conf.UsernameClaim].(string)
+		if username == "" {
+			return nil, sdk.NewError(sdk.ErrOIDCLoginFailed, fmt.Errorf("claim %s is missing in ID token", c.conf.UsernameClaim))
+		}
+		existing, err := user.LoadUserAndAuth(db, username)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, sdk.WrapError(err, "OIDCClient.insertOrUpdateUser> Unable to load user %s", username)
+		}
+		if existing != nil {
+			u, err = c.linkExistingUser(db, existing, subject)
+			if err != nil {
+				return nil, err
+			}
+			newUser = false
+		} else {
+			u = &sdk.User{
+				Admin:    false,
+				Username: username,
+				Origin:   "oidc",
+			}
+		}
+	}
+	if u.Origin != "oidc" {
+		return nil, sdk.NewError(sdk.ErrOIDCLoginFailed, fmt.Errorf("user %s is not an OpenID Connect user", u.Username))
+	}
+	if name, _ := claims["name"].(string); name != "" {
+		u.Fullname = name
+	}
+	if email, _ := claims["email"].(string); email != "" {
+		u.Email = email
+	}
+
+	if newUser {
+		a := &sdk.Auth{
+			EmailVerified: true,
+		}
+		if err := user.InsertUser(db, u, a); err != nil {
+			return nil, sdk.WrapError(err, "OIDCClient.insertOrUpdateUser> Unable to insert user %s", u.Username)
+		}
+		if err := user.UpdateOIDCSubject(db, u.ID, subject); err != nil {
+			return nil, sdk.WrapError(err, "OIDCClient.insertOrUpdateUser> Unable to link user %s", u.Username)
+		}
+		u.Auth = *a
+		log.Info("OIDCClient.insertOrUpdateUser> User %s created", u.Username)
+	} else if err := user.UpdateUser(db, *u); err != nil {
+		return nil, sdk.WrapError(err, "OIDCClient.insertOrUpdateUser> Unable to update user %s", u.Username)
+	}
+
+	if c.conf.GroupsClaim != "" {
+		if err := c.syncGroups(db, u, claims.stringSlice(c.conf.GroupsClaim)); err != nil {
+			return nil, err
+		}
+	}
+	return u, nil
+}
+
+// linkExistingUser links an OpenID Connect user created before the users were matched on their subject. The local
+// users, and the users already linked to another subject, are never linked.
+func (c *OIDCClient) linkExistingUser(db gorp.SqlExecutor, u *sdk.User, subject string) (*sdk.User, error) {
+	if u.Origin != "oidc" {
+		return nil, sdk.NewError(sdk.ErrOIDCLoginFailed, fmt.Errorf("user %s is not an OpenID Connect user", u.Username))
+	}
+	linked, err := user.LoadOIDCSubject(db, u.ID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "OIDCClient.linkExistingUser> Unable to load subject of user %s", u.Username)
+	}
+	if linked != "" {
+		return nil, sdk.NewError(sdk.ErrOIDCLoginFailed, fmt.Errorf("user %s is linked to another OpenID Connect account", u.Username))
+	}
+	if err := user.UpdateOIDCSubject(db, u.ID, subject); err != nil {
+		return nil, sdk.WrapError(err, "OIDCClient.linkExistingUser> Unable to link user %s", u.Username)
+	}
+	log.Info("OIDCClient.linkExistingUser> User %s linked to %s", u.Username, subject)
+	return u, nil
+}
+
+// syncGroups adds the user in the CDS groups mapped to its claimed groups, and removes it from the other mapped groups
+func (c *OIDCClient) syncGroups(db gorp.SqlExecutor, u *sdk.User, claimed []string) error {
+	for claim, groupName := range c.conf.Groups {
+		var isClaimed bool
+		for _, g := range claimed {
+			if strings.EqualFold(g, claim) {
+				isClaimed = true
+				break
+			}
+		}
+
+		g, err := group.LoadGroup(db, groupName)
+		if err != nil {
+			log.Warning("OIDCClient.syncGroups> Unable to load group %s: %v", groupName, err)
+			continue
+		}
+		inGroup, err := group.CheckUserInGroup(db, g.ID, u.ID)
+		if err != nil {
+			return sdk.WrapError(err, "OIDCClient.syncGroups> Unable to check user %s in group %s", u.Username, groupName)
+		}
+
+		switch {
+		case isClaimed && !inGroup:
+			if err := group.InsertUserInGroup(db, g.ID, u.ID, false); err != nil {
+				return sdk.WrapError(err, "OIDCClient.syncGroups> Unable to add user %s in group %s", u.Username, groupName)
+			}
+		case !isClaimed && inGroup:
+			if err := group.DeleteUserFromGroup(db, g.ID, u.ID); err != nil {
+				log.Warning("OIDCClient.syncGroups> Unable to remove user %s from group %s: %v", u.Username, groupName, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *OIDCClient) getJSON(u string, v interface{}) error {
+	res, err := c.client.Get(u)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 400 {
+		return fmt.Errorf("HTTP %d on %s", res.StatusCode, u)
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+// postForm calls an endpoint of the provider with the client credentials, the body is decoded even on error
+func (c *OIDCClient) postForm(u string, v url.Values, out interface{}) error {
+	v.Set("client_id", c.conf.ClientID)
+	req, err := http.NewRequest(http.MethodPost, u, strings.NewReader(v.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if c.conf.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(c.conf.ClientID), url.QueryEscape(c.conf.ClientSecret))
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("invalid response from %s: %v", u, err)
+	}
+	if res.StatusCode >= 400 {
+		return fmt.Errorf("HTTP %d on %s", res.StatusCode, u)
+	}
+	return nil
+}
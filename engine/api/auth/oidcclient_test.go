@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// mockOIDCProvider is a minimal OpenID Connect provider issuing ID tokens for a single user
+type mockOIDCProvider struct {
+	*httptest.Server
+	key      *rsa.PrivateKey
+	claims   map[string]interface{}
+	verifier string
+	pending  bool
+}
+
+func newMockOIDCProvider(t *testing.T) *mockOIDCProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	p := &mockOIDCProvider{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(oidcProvider{
+			Issuer:                      p.URL,
+			AuthorizationEndpoint:       p.URL + "/authorize",
+			TokenEndpoint:               p.URL + "/token",
+			JWKSURI:                     p.URL + "/keys",
+			DeviceAuthorizationEndpoint: p.URL + "/device",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		e := big.NewInt(int64(key.E)).Bytes()
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "k1",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(e),
+			}},
+		})
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"device_code":      "dev-code",
+			"user_code":        "ABCD-EFGH",
+			"verification_url": p.URL + "/activate",
+			"expires_in":       600,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch r.FormValue("grant_type") {
+		case "authorization_code":
+			challenge := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+			if base64.RawURLEncoding.EncodeToString(challenge[:]) != p.verifier {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+				return
+			}
+		default:
+			if p.pending {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": "authorization_pending"})
+				return
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": p.sign(t, p.claims)})
+	})
+	p.Server = httptest.NewServer(mux)
+	return p
+}
+
+func (p *mockOIDCProvider) sign(t *testing.T, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "k1"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	hashed := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, hashed[:])
+	assert.NoError(t, err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestOIDCClient(t *testing.T) {
+	p := newMockOIDCProvider(t)
+	defer p.Close()
+
+	c := &OIDCClient{}
+	err := c.Open(OIDCConfig{Issuer: p.URL, ClientID: "cds", ClientSecret: "secret", RedirectURL: "http://cds/login/oidc/callback"}, nil)
+	assert.NoError(t, err)
+
+	r, err := NewOIDCAuthRequest()
+	assert.NoError(t, err)
+	u, err := url.Parse(c.AuthCodeURL(*r))
+	assert.NoError(t, err)
+	assert.Equal(t, p.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	assert.Equal(t, "S256", u.Query().Get("code_challenge_method"))
+	assert.Equal(t, r.State, u.Query().Get("state"))
+	p.verifier = u.Query().Get("code_challenge")
+
+	p.claims = map[string]interface{}{
+		"iss":                p.URL,
+		"aud":                []string{"cds"},
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"nonce":              r.Nonce,
+		"sub":                "alice-id",
+		"preferred_username": "alice",
+		"groups":             []string{"devops", "qa"},
+	}
+	v := url.Values{"grant_type": {"authorization_code"}, "code_verifier": {r.Verifier}}
+	claims, err := c.token(v, r.Nonce)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", claims["preferred_username"])
+	assert.Equal(t, []string{"devops", "qa"}, claims.stringSlice("groups"))
+	subject, err := claims.subject()
+	assert.NoError(t, err)
+	assert.Equal(t, p.URL+" alice-id", subject)
+	_, err = oidcClaims{"iss": p.URL, "preferred_username": "admin"}.subject()
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrOIDCLoginFailed))
+
+	// Invalid PKCE verifier
+	v.Set("code_verifier", "wrong")
+	_, err = c.token(v, r.Nonce)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrOIDCLoginFailed))
+
+	// Invalid nonce, audience, expiration and signature
+	v.Set("code_verifier", r.Verifier)
+	_, err = c.token(v, "other")
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrOIDCLoginFailed))
+	p.claims["aud"] = "other-client"
+	_, err = c.token(v, r.Nonce)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrOIDCLoginFailed))
+	p.claims["aud"] = "cds"
+	p.claims["exp"] = time.Now().Add(-time.Hour).Unix()
+	_, err = c.token(v, r.Nonce)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrOIDCLoginFailed))
+	_, err = c.verifyIDToken(p.sign(t, p.claims)+"x", "")
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrOIDCLoginFailed))
+
+	// Device flow
+	d, err := c.DeviceAuthorization()
+	assert.NoError(t, err)
+	assert.Equal(t, "dev-code", d.DeviceCode)
+	assert.Equal(t, p.URL+"/activate", d.VerificationURI)
+
+	p.claims["exp"] = time.Now().Add(time.Hour).Unix()
+	p.pending = true
+	v = url.Values{"grant_type": {"urn:ietf:params:oauth:grant-type:device_code"}, "device_code": {d.DeviceCode}}
+	_, err = c.token(v, "")
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrOIDCAuthorizationPending))
+	p.pending = false
+	claims, err = c.token(v, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", claims["preferred_username"])
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/ovh/cds/engine/api/database"
 	"github.com/ovh/cds/engine/api/keys"
 	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/role"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -278,6 +279,16 @@ func UpdateEnvironment(db gorp.SqlExecutor, environment *sdk.Environment) error
 		return sdk.NewError(sdk.ErrInvalidName, fmt.Errorf("Invalid environment name. It should match %s", sdk.NamePattern))
 	}
 
+	// Rename the role bindings, they are matched by name
+	var projectID int64
+	var oldName string
+	if err := db.QueryRow("SELECT project_id, name FROM environment WHERE id = $1", environment.ID).Scan(&projectID, &oldName); err != nil {
+		return sdk.WrapError(err, "UpdateEnvironment> Cannot load environment %d", environment.ID)
+	}
+	if err := role.RenameBindingsObject(db, projectID, sdk.RoleBindingEnvironment, oldName, environment.Name); err != nil {
+		return err
+	}
+
 	query := `UPDATE environment SET name=$1 WHERE id=$2`
 	if _, err := db.Exec(query, environment.Name, environment.ID); err != nil {
 		return err
@@ -325,6 +336,16 @@ func DeleteEnvironment(db gorp.SqlExecutor, environmentID int64) error {
 		return sdk.WrapError(err, "DeleteEnvironment> Cannot delete environment application_pipeline_notif")
 	}
 
+	// Delete role bindings, they are not linked to the environment by a foreign key
+	var projectID int64
+	var name string
+	if err := db.QueryRow("SELECT project_id, name FROM environment WHERE id = $1", environmentID).Scan(&projectID, &name); err != nil {
+		return sdk.WrapError(err, "DeleteEnvironment> Cannot load environment %d", environmentID)
+	}
+	if err := role.DeleteBindingsByObject(db, projectID, sdk.RoleBindingEnvironment, name); err != nil {
+		return err
+	}
+
 	// FINALLY delete environment
 	query = `DELETE FROM environment WHERE id=$1`
 	if _, err := db.Exec(query, environmentID); err != nil {
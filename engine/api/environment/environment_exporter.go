@@ -7,6 +7,7 @@ import (
 	"github.com/go-gorp/gorp"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/role"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/exportentities"
 )
@@ -82,6 +83,13 @@ func ExportEnvironment(db gorp.SqlExecutor, env sdk.Environment, f exportentitie
 	}
 
 	e := exportentities.NewEnvironment(env, withPermissions, keys)
+	if withPermissions {
+		roles, err := role.ExportBindings(db, env.ProjectID, sdk.RoleBindingEnvironment, env.Name)
+		if err != nil {
+			return 0, sdk.WrapError(err, "environment.Export> Unable to load roles")
+		}
+		e.Roles = roles
+	}
 	btes, errMarshal := exportentities.Marshal(e, f)
 	if errMarshal != nil {
 		return 0, sdk.WrapError(errMarshal, "environment.Export")
@@ -9,6 +9,7 @@ import (
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/keys"
+	"github.com/ovh/cds/engine/api/role"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/exportentities"
 	"github.com/ovh/cds/sdk/log"
@@ -139,5 +140,11 @@ func ParseAndImport(db gorp.SqlExecutor, cache cache.Store, proj *sdk.Project, e
 	close(msgChan)
 	done.Wait()
 
+	if globalError == nil {
+		if err := role.ImportBindings(db, proj.ID, sdk.RoleBindingEnvironment, env.Name, eenv.Roles); err != nil {
+			return env, msgList, sdk.WrapError(err, "ParseAndImport> Unable to import roles")
+		}
+	}
+
 	return env, msgList, globalError
 }
@@ -0,0 +1,75 @@
+package environment_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/role"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/sdk"
+)
+
+func insertRoleBindingTestData(t *testing.T, key string) (*sdk.Project, *sdk.Environment, *sdk.Group) {
+	db, cache := test.SetupPG(t)
+
+	proj := sdk.Project{Key: key, Name: key}
+	project.Delete(db, cache, proj.Key)
+	test.NoError(t, project.Insert(db, cache, &proj, nil))
+
+	env := sdk.Environment{Name: "testenv", ProjectID: proj.ID}
+	test.NoError(t, environment.InsertEnvironment(db, &env))
+
+	g := sdk.Group{Name: key + "-group"}
+	if oldg, _ := group.LoadGroup(db, g.Name); oldg != nil {
+		group.DeleteGroupAndDependencies(db, oldg)
+	}
+	test.NoError(t, group.InsertGroup(db, &g))
+
+	b := sdk.RoleBinding{
+		RoleName:   sdk.RoleReadExecute,
+		GroupID:    g.ID,
+		ProjectID:  proj.ID,
+		ObjectType: sdk.RoleBindingEnvironment,
+		ObjectName: env.Name,
+	}
+	test.NoError(t, role.InsertBinding(db, &b))
+	return &proj, &env, &g
+}
+
+func TestDeleteEnvironment_RoleBindings(t *testing.T) {
+	db, _ := test.SetupPG(t)
+	proj, env, _ := insertRoleBindingTestData(t, "testdeleteenvroles")
+
+	test.NoError(t, environment.DeleteEnvironment(db, env.ID))
+
+	// an environment created with the same name must not get the role bindings of the deleted one
+	env2 := sdk.Environment{Name: env.Name, ProjectID: proj.ID}
+	test.NoError(t, environment.InsertEnvironment(db, &env2))
+
+	bs, err := role.LoadBindingsByObject(db, proj.ID, sdk.RoleBindingEnvironment, env2.Name)
+	test.NoError(t, err)
+	assert.Len(t, bs, 0)
+}
+
+func TestUpdateEnvironment_RenameRoleBindings(t *testing.T) {
+	db, _ := test.SetupPG(t)
+	proj, env, g := insertRoleBindingTestData(t, "testrenameenvroles")
+
+	env.Name = "testenv-renamed"
+	test.NoError(t, environment.UpdateEnvironment(db, env))
+
+	bs, err := role.LoadBindingsByObject(db, proj.ID, sdk.RoleBindingEnvironment, "testenv")
+	test.NoError(t, err)
+	assert.Len(t, bs, 0)
+
+	bs, err = role.LoadBindingsByObject(db, proj.ID, sdk.RoleBindingEnvironment, env.Name)
+	test.NoError(t, err)
+	if assert.Len(t, bs, 1) {
+		assert.Equal(t, g.Name, bs[0].GroupName)
+		assert.Equal(t, sdk.RoleReadExecute, bs[0].RoleName)
+	}
+}
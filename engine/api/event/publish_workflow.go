@@ -30,7 +30,7 @@ func publishWorkflowEvent(payload interface{}, key, workflowName string, u *sdk.
 // PublishWorkflowAdd publishes an event for the creation of the given Workflow
 func PublishWorkflowAdd(projKey string, w sdk.Workflow, u *sdk.User) {
 	e := sdk.EventWorkflowAdd{
-		Workflow: w,
+		Workflow: w.WithMaskedSecrets(),
 	}
 	publishWorkflowEvent(e, projKey, w.Name, u)
 }
@@ -38,8 +38,8 @@ func PublishWorkflowAdd(projKey string, w sdk.Workflow, u *sdk.User) {
 // PublishWorkflowUpdate publishes an event for the update of the given Workflow
 func PublishWorkflowUpdate(projKey string, w sdk.Workflow, oldw sdk.Workflow, u *sdk.User) {
 	e := sdk.EventWorkflowUpdate{
-		NewWorkflow: w,
-		OldWorkflow: oldw,
+		NewWorkflow: w.WithMaskedSecrets(),
+		OldWorkflow: oldw.WithMaskedSecrets(),
 	}
 	publishWorkflowEvent(e, projKey, w.Name, u)
 }
@@ -47,7 +47,7 @@ func PublishWorkflowUpdate(projKey string, w sdk.Workflow, oldw sdk.Workflow, u
 // PublishWorkflowDelete publishes an event for the deletion of the given Workflow
 func PublishWorkflowDelete(projKey string, w sdk.Workflow, u *sdk.User) {
 	e := sdk.EventWorkflowDelete{
-		Workflow: w,
+		Workflow: w.WithMaskedSecrets(),
 	}
 	publishWorkflowEvent(e, projKey, w.Name, u)
 }
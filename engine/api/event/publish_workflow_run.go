@@ -76,6 +76,12 @@ func PublishWorkflowNodeRun(db gorp.SqlExecutor, nr sdk.WorkflowNodeRun, w sdk.W
 		e.StagesSummary[i] = nr.Stages[i].ToSummary()
 	}
 
+	if nr.Approval != nil {
+		e.ApprovalStatus = nr.Approval.Status
+		e.Approvers = nr.Approval.Approvers()
+		e.RequiredApprovers = nr.Approval.Gate.RequiredApprovers()
+	}
+
 	var pipName string
 	node := w.GetNode(nr.WorkflowNodeID)
 	if node != nil {
@@ -111,5 +117,11 @@ func PublishWorkflowNodeJobRun(db gorp.SqlExecutor, pkey, wname string, jr sdk.W
 	if sdk.StatusIsTerminated(jr.Status) {
 		e.Done = jr.Done.Unix()
 	}
+	if jr.Attempt > 0 {
+		e.Attempt = jr.Attempt + 1
+		if n := len(jr.Job.Attempts); n > 0 {
+			e.LastAttempt = &jr.Job.Attempts[n-1]
+		}
+	}
 	publishRunWorkflow(e, pkey, wname, "", "", "", 0, 0, jr.Status, nil)
 }
@@ -0,0 +1,209 @@
+package freeze
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// Insert inserts a new freeze window
+func Insert(db gorp.SqlExecutor, f *sdk.FreezeWindow, u *sdk.User) error {
+	f.Created = time.Now()
+	if u != nil {
+		f.Author = u.Username
+	}
+	dbf := freezeWindow(*f)
+	if err := db.Insert(&dbf); err != nil {
+		return sdk.WrapError(err, "Insert> Cannot insert freeze window")
+	}
+	*f = sdk.FreezeWindow(dbf)
+	return nil
+}
+
+// Update updates a freeze window
+func Update(db gorp.SqlExecutor, f *sdk.FreezeWindow) error {
+	dbf := freezeWindow(*f)
+	if _, err := db.Update(&dbf); err != nil {
+		return sdk.WrapError(err, "Update> Cannot update freeze window %d", f.ID)
+	}
+	*f = sdk.FreezeWindow(dbf)
+	return nil
+}
+
+// Delete deletes a freeze window and its overrides
+func Delete(db gorp.SqlExecutor, f *sdk.FreezeWindow) error {
+	dbf := freezeWindow(*f)
+	if _, err := db.Delete(&dbf); err != nil {
+		return sdk.WrapError(err, "Delete> Cannot delete freeze window %d", f.ID)
+	}
+	return nil
+}
+
+// LoadByID loads a freeze window of a project
+func LoadByID(db gorp.SqlExecutor, projectID, id int64) (*sdk.FreezeWindow, error) {
+	var dbf freezeWindow
+	if err := db.SelectOne(&dbf, "SELECT * FROM freeze_window WHERE project_id = $1 AND id = $2", projectID, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrFreezeWindowNotFound
+		}
+		return nil, sdk.WrapError(err, "LoadByID> Cannot load freeze window %d", id)
+	}
+	f := sdk.FreezeWindow(dbf)
+	return &f, nil
+}
+
+// LoadAllByProjectID loads all the freeze windows of a project
+func LoadAllByProjectID(db gorp.SqlExecutor, projectID int64) ([]sdk.FreezeWindow, error) {
+	return getAll(db, "SELECT * FROM freeze_window WHERE project_id = $1 ORDER BY id", projectID)
+}
+
+// LoadAllByEnvironmentID loads the freeze windows applying to an environment: the windows of the
+// environment and the windows of its project
+func LoadAllByEnvironmentID(db gorp.SqlExecutor, projectID, environmentID int64) ([]sdk.FreezeWindow, error) {
+	query := "SELECT * FROM freeze_window WHERE project_id = $1 AND (environment_id IS NULL OR environment_id = $2) ORDER BY id"
+	return getAll(db, query, projectID, environmentID)
+}
+
+func getAll(db gorp.SqlExecutor, query string, args ...interface{}) ([]sdk.FreezeWindow, error) {
+	var dbfs []freezeWindow
+	if _, err := db.Select(&dbfs, query, args...); err != nil {
+		return nil, sdk.WrapError(err, "getAll> Cannot load freeze windows")
+	}
+	fs := make([]sdk.FreezeWindow, len(dbfs))
+	for i := range dbfs {
+		fs[i] = sdk.FreezeWindow(dbfs[i])
+	}
+	return fs, nil
+}
+
+// InsertOverride inserts the audit of an override of a freeze window
+func InsertOverride(db gorp.SqlExecutor, o *sdk.FreezeWindowOverride) error {
+	o.Date = time.Now()
+	dbo := freezeWindowOverride(*o)
+	if err := db.Insert(&dbo); err != nil {
+		return sdk.WrapError(err, "InsertOverride> Cannot insert override of freeze window %d", o.FreezeWindowID)
+	}
+	o.ID = dbo.ID
+	return nil
+}
+
+// LoadOverrides loads the overrides of a freeze window, the newest first
+func LoadOverrides(db gorp.SqlExecutor, freezeWindowID int64) ([]sdk.FreezeWindowOverride, error) {
+	var dbos []freezeWindowOverride
+	query := "SELECT * FROM freeze_window_override WHERE freeze_window_id = $1 ORDER BY date DESC"
+	if _, err := db.Select(&dbos, query, freezeWindowID); err != nil {
+		return nil, sdk.WrapError(err, "LoadOverrides> Cannot load overrides of freeze window %d", freezeWindowID)
+	}
+	overrides := make([]sdk.FreezeWindowOverride, len(dbos))
+	for i := range dbos {
+		overrides[i] = sdk.FreezeWindowOverride(dbos[i])
+	}
+	return overrides, nil
+}
+
+// IsOverridden returns true if the freeze window has been overridden for the pipeline of the workflow run since
+// the given date
+func IsOverridden(db gorp.SqlExecutor, freezeWindowID int64, workflowName string, number int64, workflowNodeName string, since time.Time) (bool, error) {
+	query := `
+	SELECT COUNT(1)
+	FROM freeze_window_override
+	WHERE freeze_window_id = $1 AND workflow_name = $2 AND number = $3 AND workflow_node_name = $4 AND date >= $5`
+	count, err := db.SelectInt(query, freezeWindowID, workflowName, number, workflowNodeName, since)
+	if err != nil {
+		return false, sdk.WrapError(err, "IsOverridden> Cannot count overrides of freeze window %d", freezeWindowID)
+	}
+	return count > 0, nil
+}
+
+// InsertAudit records the freeze window before its update or its deletion
+func InsertAudit(db gorp.SqlExecutor, f sdk.FreezeWindow, auditType string, active bool, u *sdk.User) error {
+	a := freezeWindowAudit{
+		ProjectID:      f.ProjectID,
+		FreezeWindowID: f.ID,
+		Type:           auditType,
+		Active:         active,
+		FreezeWindow:   f,
+		Username:       u.Username,
+		Date:           time.Now(),
+	}
+	if err := db.Insert(&a); err != nil {
+		return sdk.WrapError(err, "InsertAudit> Cannot insert audit of freeze window %d", f.ID)
+	}
+	return nil
+}
+
+// LoadAuditsByProjectID loads the audits of the freeze windows of a project, the newest first
+func LoadAuditsByProjectID(db gorp.SqlExecutor, projectID int64) ([]sdk.FreezeWindowAudit, error) {
+	var dbas []freezeWindowAudit
+	query := "SELECT * FROM freeze_window_audit WHERE project_id = $1 ORDER BY date DESC"
+	if _, err := db.Select(&dbas, query, projectID); err != nil {
+		return nil, sdk.WrapError(err, "LoadAuditsByProjectID> Cannot load audits of freeze windows")
+	}
+	audits := make([]sdk.FreezeWindowAudit, len(dbas))
+	for i := range dbas {
+		audits[i] = sdk.FreezeWindowAudit(dbas[i])
+	}
+	return audits, nil
+}
+
+// PostInsert is a db hook
+func (a *freezeWindowAudit) PostInsert(db gorp.SqlExecutor) error {
+	f, err := gorpmapping.JSONToNullString(a.FreezeWindow)
+	if err != nil {
+		return sdk.WrapError(err, "freezeWindowAudit.PostInsert> Cannot marshal freeze window")
+	}
+	if _, err := db.Exec("UPDATE freeze_window_audit SET freeze_window = $2 WHERE id = $1", a.ID, f); err != nil {
+		return sdk.WrapError(err, "freezeWindowAudit.PostInsert> Cannot update audit %d", a.ID)
+	}
+	return nil
+}
+
+// PostGet is a db hook
+func (a *freezeWindowAudit) PostGet(db gorp.SqlExecutor) error {
+	var f sql.NullString
+	if err := db.QueryRow("SELECT freeze_window FROM freeze_window_audit WHERE id = $1", a.ID).Scan(&f); err != nil {
+		return sdk.WrapError(err, "freezeWindowAudit.PostGet> Cannot load audit %d", a.ID)
+	}
+	return sdk.WrapError(gorpmapping.JSONNullString(f, &a.FreezeWindow), "freezeWindowAudit.PostGet> Cannot unmarshal freeze window")
+}
+
+// PostInsert is a db hook
+func (f *freezeWindow) PostInsert(db gorp.SqlExecutor) error {
+	return f.PostUpdate(db)
+}
+
+// PostUpdate is a db hook
+func (f *freezeWindow) PostUpdate(db gorp.SqlExecutor) error {
+	envID := sql.NullInt64{Int64: f.EnvironmentID, Valid: f.EnvironmentID != 0}
+	groups, err := gorpmapping.JSONToNullString(f.OverrideGroups)
+	if err != nil {
+		return sdk.WrapError(err, "freezeWindow.PostUpdate> Cannot marshal override groups")
+	}
+	query := "UPDATE freeze_window SET environment_id = $2, override_groups = $3 WHERE id = $1"
+	if _, err := db.Exec(query, f.ID, envID, groups); err != nil {
+		return sdk.WrapError(err, "freezeWindow.PostUpdate> Cannot update freeze window %d", f.ID)
+	}
+	return f.PostGet(db)
+}
+
+// PostGet is a db hook
+func (f *freezeWindow) PostGet(db gorp.SqlExecutor) error {
+	var envID sql.NullInt64
+	var envName, groups sql.NullString
+	query := `
+	SELECT freeze_window.environment_id, environment.name, freeze_window.override_groups
+	FROM freeze_window
+	LEFT JOIN environment ON environment.id = freeze_window.environment_id
+	WHERE freeze_window.id = $1`
+	if err := db.QueryRow(query, f.ID).Scan(&envID, &envName, &groups); err != nil {
+		return sdk.WrapError(err, "freezeWindow.PostGet> Cannot load freeze window %d", f.ID)
+	}
+	f.EnvironmentID = envID.Int64
+	f.EnvironmentName = envName.String
+	f.OverrideGroups = nil
+	return sdk.WrapError(gorpmapping.JSONNullString(groups, &f.OverrideGroups), "freezeWindow.PostGet> Cannot unmarshal override groups")
+}
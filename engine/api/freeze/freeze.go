@@ -0,0 +1,67 @@
+package freeze
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorhill/cronexpr"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// CheckValidity checks the freeze window and its cron expression
+func CheckValidity(f sdk.FreezeWindow) error {
+	if err := f.IsValid(); err != nil {
+		return err
+	}
+	if f.IsRecurrent() {
+		if _, err := cronexpr.Parse(f.Cron); err != nil {
+			return sdk.NewError(sdk.ErrInvalidFreezeWindow, fmt.Errorf("invalid cron expression %s: %v", f.Cron, err))
+		}
+	}
+	return nil
+}
+
+// IsActive returns true if the freeze window covers the given time. A recurrent window is active if
+// its cron expression matched during the last Duration seconds.
+func IsActive(f sdk.FreezeWindow, t time.Time) (bool, error) {
+	if !f.IsRecurrent() {
+		return !t.Before(f.Start) && t.Before(f.End), nil
+	}
+
+	expr, err := cronexpr.Parse(f.Cron)
+	if err != nil {
+		return false, sdk.WrapError(err, "IsActive> Invalid cron expression %s on freeze window %d", f.Cron, f.ID)
+	}
+	loc, err := time.LoadLocation(f.Timezone)
+	if err != nil {
+		return false, sdk.WrapError(err, "IsActive> Invalid timezone %s on freeze window %d", f.Timezone, f.ID)
+	}
+	t = t.In(loc)
+	// Next returns the first start strictly after the given time
+	start := expr.Next(t.Add(-time.Duration(f.Duration) * time.Second))
+	return !start.IsZero() && !start.After(t), nil
+}
+
+// LoadActiveByEnvironmentID loads the freeze windows applying to the environment which are active now
+func LoadActiveByEnvironmentID(db gorp.SqlExecutor, projectID, environmentID int64) ([]sdk.FreezeWindow, error) {
+	fs, err := LoadAllByEnvironmentID(db, projectID, environmentID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var res []sdk.FreezeWindow
+	for _, f := range fs {
+		active, err := IsActive(f, now)
+		if err != nil {
+			log.Warning("LoadActiveByEnvironmentID> %v", err)
+			continue
+		}
+		if active {
+			res = append(res, f)
+		}
+	}
+	return res, nil
+}
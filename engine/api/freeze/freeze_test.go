@@ -0,0 +1,72 @@
+package freeze
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestIsActive(t *testing.T) {
+	christmas := time.Date(2018, 12, 25, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		window sdk.FreezeWindow
+		t      time.Time
+		active bool
+	}{
+		{
+			name:   "in date range",
+			window: sdk.FreezeWindow{Start: christmas.Add(-24 * time.Hour), End: christmas.Add(24 * time.Hour)},
+			t:      christmas,
+			active: true,
+		},
+		{
+			name:   "after date range",
+			window: sdk.FreezeWindow{Start: christmas.Add(-24 * time.Hour), End: christmas},
+			t:      christmas,
+			active: false,
+		},
+		{
+			name:   "weekend freeze on tuesday",
+			window: sdk.FreezeWindow{Cron: "0 18 * * 5", Duration: 64 * 3600},
+			t:      christmas,
+			active: false,
+		},
+		{
+			name:   "weekend freeze on sunday",
+			window: sdk.FreezeWindow{Cron: "0 18 * * 5", Duration: 64 * 3600},
+			t:      time.Date(2018, 12, 23, 23, 0, 0, 0, time.UTC),
+			active: true,
+		},
+		{
+			name:   "weekend freeze on friday before start in paris",
+			window: sdk.FreezeWindow{Cron: "0 18 * * 5", Duration: 64 * 3600, Timezone: "Europe/Paris"},
+			t:      time.Date(2018, 12, 21, 17, 30, 0, 0, time.UTC),
+			active: true,
+		},
+		{
+			name:   "weekend freeze on friday before start in utc",
+			window: sdk.FreezeWindow{Cron: "0 18 * * 5", Duration: 64 * 3600},
+			t:      time.Date(2018, 12, 21, 17, 30, 0, 0, time.UTC),
+			active: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			active, err := IsActive(tt.window, tt.t)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.active, active)
+		})
+	}
+}
+
+func TestCheckValidity(t *testing.T) {
+	assert.NoError(t, CheckValidity(sdk.FreezeWindow{Reason: "holidays", Cron: "0 18 * * 5", Duration: 3600}))
+	assert.Error(t, CheckValidity(sdk.FreezeWindow{Reason: "holidays", Cron: "every friday", Duration: 3600}))
+	assert.Error(t, CheckValidity(sdk.FreezeWindow{Reason: "holidays", Cron: "0 18 * * 5"}))
+	assert.Error(t, CheckValidity(sdk.FreezeWindow{Reason: "incident", Start: time.Now()}))
+	assert.Error(t, CheckValidity(sdk.FreezeWindow{Start: time.Now(), End: time.Now().Add(time.Hour)}))
+}
@@ -0,0 +1,16 @@
+package freeze
+
+import (
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+type freezeWindow sdk.FreezeWindow
+type freezeWindowOverride sdk.FreezeWindowOverride
+type freezeWindowAudit sdk.FreezeWindowAudit
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(freezeWindow{}, "freeze_window", true, "id"))
+	gorpmapping.Register(gorpmapping.New(freezeWindowOverride{}, "freeze_window_override", true, "id"))
+	gorpmapping.Register(gorpmapping.New(freezeWindowAudit{}, "freeze_window_audit", true, "id"))
+}
@@ -475,3 +475,39 @@ func (api *API) getHookPollingVCSEvents() service.Handler {
 		return service.WriteJSON(w, repoEvents, http.StatusOK)
 	}
 }
+
+func (api *API) getHookChangedFilesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		uuid := mux.Vars(r)["uuid"]
+		base := r.FormValue("base")
+		head := r.FormValue("head")
+
+		h, errL := workflow.LoadHookByUUID(api.mustDB(), uuid)
+		if errL != nil {
+			return sdk.WrapError(errL, "getHookChangedFilesHandler> cannot load hook")
+		}
+		if h == nil {
+			return sdk.ErrNotFound
+		}
+
+		proj, errProj := project.Load(api.mustDB(), api.Cache, h.Config[sdk.HookConfigProject].Value, nil)
+		if errProj != nil {
+			return sdk.WrapError(errProj, "getHookChangedFilesHandler> cannot load project")
+		}
+
+		vcsServer := repositoriesmanager.GetProjectVCSServer(proj, h.Config["vcsServer"].Value)
+		if vcsServer == nil {
+			return sdk.WrapError(sdk.ErrNoReposManagerClientAuth, "getHookChangedFilesHandler> no vcs server %s on project %s", h.Config["vcsServer"].Value, proj.Key)
+		}
+		client, errR := repositoriesmanager.AuthorizedClient(ctx, api.mustDB(), api.Cache, vcsServer)
+		if errR != nil {
+			return sdk.WrapError(errR, "getHookChangedFilesHandler> Unable to get client for %s %s", proj.Key, vcsServer.Name)
+		}
+
+		files, err := client.ChangedFiles(ctx, h.Config["repoFullName"].Value, base, head)
+		if err != nil {
+			return sdk.WrapError(err, "getHookChangedFilesHandler> Unable to get changed files between %s and %s", base, head)
+		}
+		return service.WriteJSON(w, files, http.StatusOK)
+	}
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ovh/cds/engine/api/auth"
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/sessionstore"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// oidcRequestTTL is the time in seconds given to the user to login on the provider
+const oidcRequestTTL = 600
+
+// oidcStateCookie binds the state of the login request to the browser which started it
+const oidcStateCookie = "cds_oidc_state"
+
+func (api *API) oidcDriver() (*auth.OIDCClient, error) {
+	d, ok := api.Router.AuthDriver.(*auth.OIDCClient)
+	if !ok {
+		return nil, sdk.ErrOIDCNotEnabled
+	}
+	return d, nil
+}
+
+// oidcCookiePath returns the path of the login routes as seen by the browser, the API can be served under a prefix
+func (api *API) oidcCookiePath() string {
+	u, err := url.Parse(api.Config.URL.API)
+	if err != nil {
+		return "/login/oidc"
+	}
+	return strings.TrimSuffix(u.Path, "/") + "/login/oidc"
+}
+
+func (api *API) getLoginOIDCConfigHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		_, err := api.oidcDriver()
+		return service.WriteJSON(w, map[string]bool{"enabled": err == nil}, http.StatusOK)
+	}
+}
+
+// getLoginOIDCHandler redirects the user to the provider with a new authorization code request
+func (api *API) getLoginOIDCHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		d, err := api.oidcDriver()
+		if err != nil {
+			return err
+		}
+
+		req, err := auth.NewOIDCAuthRequest()
+		if err != nil {
+			return err
+		}
+		api.Cache.SetWithTTL(cache.Key("oidc", "request", req.State), req, oidcRequestTTL)
+		http.SetCookie(w, &http.Cookie{
+			Name:     oidcStateCookie,
+			Value:    req.State,
+			Path:     api.oidcCookiePath(),
+			MaxAge:   oidcRequestTTL,
+			Secure:   strings.HasPrefix(api.Config.URL.API, "https://"),
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+
+		http.Redirect(w, r, d.AuthCodeURL(*req), http.StatusFound)
+		return nil
+	}
+}
+
+// getLoginOIDCCallbackHandler is called by the provider once the user has logged in. The user is redirected
+// to the UI with its new session, or the session is returned if the UI URL is not set.
+func (api *API) getLoginOIDCCallbackHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		d, err := api.oidcDriver()
+		if err != nil {
+			return err
+		}
+		if e := r.FormValue("error"); e != "" {
+			return sdk.NewError(sdk.ErrOIDCLoginFailed, fmt.Errorf("%s %s", e, r.FormValue("error_description")))
+		}
+
+		state := r.FormValue("state")
+		cookie, err := r.Cookie(oidcStateCookie)
+		if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+			return sdk.NewError(sdk.ErrOIDCLoginFailed, fmt.Errorf("login request not started by this browser"))
+		}
+		http.SetCookie(w, &http.Cookie{Name: oidcStateCookie, Path: api.oidcCookiePath(), MaxAge: -1})
+
+		var req auth.OIDCAuthRequest
+		key := cache.Key("oidc", "request", state)
+		if !api.Cache.Get(key, &req) {
+			return sdk.NewError(sdk.ErrOIDCLoginFailed, fmt.Errorf("unknown or expired login request"))
+		}
+		api.Cache.Delete(key)
+
+		u, err := d.LoginWithCode(api.mustDB(), req, r.FormValue("code"))
+		if err != nil {
+			return sdk.WrapError(err, "getLoginOIDCCallbackHandler> Login failed")
+		}
+		response, err := api.newOIDCSession(w, u, false)
+		if err != nil {
+			return err
+		}
+
+		if api.Config.URL.UI != "" {
+			http.Redirect(w, r, api.Config.URL.UI+"/account/login#session="+response.Token, http.StatusFound)
+			return nil
+		}
+		return service.WriteJSON(w, response, http.StatusOK)
+	}
+}
+
+// postLoginOIDCDeviceHandler starts the login of a device without browser, ie. cdsctl
+func (api *API) postLoginOIDCDeviceHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		d, err := api.oidcDriver()
+		if err != nil {
+			return err
+		}
+		a, err := d.DeviceAuthorization()
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, a, http.StatusOK)
+	}
+}
+
+// postLoginOIDCDeviceTokenHandler returns a persistent session once the user has entered the code of the device
+func (api *API) postLoginOIDCDeviceTokenHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		d, err := api.oidcDriver()
+		if err != nil {
+			return err
+		}
+		var req sdk.UserOIDCDeviceTokenRequest
+		if err := UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+
+		u, err := d.LoginWithDeviceCode(api.mustDB(), req.DeviceCode)
+		if err != nil {
+			return err
+		}
+		response, err := api.newOIDCSession(w, u, true)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, response, http.StatusOK)
+	}
+}
+
+func (api *API) newOIDCSession(w http.ResponseWriter, u *sdk.User, persistent bool) (*sdk.UserAPIResponse, error) {
+	if err := group.CheckUserInDefaultGroup(api.mustDB(), u.ID); err != nil {
+		log.Warning("Auth> Error while check user in default group:%s\n", err)
+	}
+
+	var sessionKey sessionstore.SessionKey
+	var err error
+	if persistent {
+		sessionKey, err = auth.NewPersistentSession(api.mustDB(), api.Router.AuthDriver, u)
+	} else {
+		sessionKey, err = auth.NewSession(api.Router.AuthDriver, u)
+	}
+	if err != nil {
+		return nil, sdk.WrapError(err, "newOIDCSession> Unable to create session for %s", u.Username)
+	}
+	log.Info("Auth> %s logged in with OpenID Connect", u.Username)
+	w.Header().Set(sdk.SessionTokenHeader, string(sessionKey))
+
+	response := &sdk.UserAPIResponse{
+		User:  *u,
+		Token: string(sessionKey),
+	}
+	response.User.Auth = sdk.Auth{}
+	response.User.Permissions = sdk.UserPermissions{}
+	return response, nil
+}
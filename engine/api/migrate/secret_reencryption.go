@@ -0,0 +1,606 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	reencryptionBatchSize = 100
+	// reencryptionTimeout is the delay without heartbeat after which a re-encryption is resumed by another API
+	reencryptionTimeout = 5 * time.Minute
+)
+
+// errReencryptionResumed is returned when the re-encryption has been resumed by another API
+var errReencryptionResumed = errors.New("re-encryption resumed by another API")
+
+// secretColumn is a column storing encrypted secrets. The rows are re-encrypted in the order of their first key, the id of
+// the row by default.
+type secretColumn struct {
+	table  string
+	column string
+	keys   []string
+	// text is set for the text columns, the private keys are stored in text columns
+	text bool
+	// reencrypt is set for the JSON columns storing base64 encoded secrets, it returns the column with its secrets
+	// re-encrypted with the current master key and the number of re-encrypted secrets
+	reencrypt func(data []byte) ([]byte, int, error)
+}
+
+var secretColumns = []secretColumn{
+	{table: "project_variable", column: "cipher_value"},
+	{table: "application_variable", column: "cipher_value"},
+	{table: "environment_variable", column: "cipher_value"},
+	{table: "project_key", column: "private", text: true},
+	{table: "application_key", column: "private", text: true},
+	{table: "environment_key", column: "private", text: true},
+	{table: "project", column: "vcs_servers"},
+	{table: "application", column: "vcs_strategy", reencrypt: reencryptVCSStrategy},
+	{table: "project_platform", column: "config", reencrypt: reencryptPlatformConfig},
+	{table: "platform_model", column: "public_configurations", reencrypt: reencryptPlatformPublicConfigurations},
+	{table: "application_deployment_strategy", column: "config", keys: []string{"application_id", "project_platform_id"}, reencrypt: reencryptPlatformConfig},
+	{table: "project_variable_audit", column: "variable_before", reencrypt: reencryptAuditVariable},
+	{table: "project_variable_audit", column: "variable_after", reencrypt: reencryptAuditVariable},
+	{table: "application_variable_audit", column: "variable_before", reencrypt: reencryptAuditVariable},
+	{table: "application_variable_audit", column: "variable_after", reencrypt: reencryptAuditVariable},
+	{table: "environment_variable_audit", column: "variable_before", reencrypt: reencryptAuditVariable},
+	{table: "environment_variable_audit", column: "variable_after", reencrypt: reencryptAuditVariable},
+	{table: "workflow_node_hook", column: "config", reencrypt: reencryptHookConfig},
+	{table: "workflow_notification", column: "settings", reencrypt: reencryptWebhookNotificationSettings},
+}
+
+func (c secretColumn) name() string {
+	return c.table + "." + c.column
+}
+
+func (c secretColumn) primaryKeys() []string {
+	if len(c.keys) == 0 {
+		return []string{"id"}
+	}
+	return c.keys
+}
+
+func (c secretColumn) prefix(keyID string) interface{} {
+	if c.text {
+		return secret.KeyPrefix(keyID)
+	}
+	return []byte(secret.KeyPrefix(keyID))
+}
+
+// reencryptionColumn is the progress of the re-encryption of a column
+type reencryptionColumn struct {
+	lastKey int64
+	done    bool
+}
+
+// StartSecretReencryption starts the re-encryption of the stored secrets with the current master key, and returns the owner
+// of the re-encryption to give to ReencryptSecrets. An interrupted re-encryption is resumed, a finished one is started again.
+func StartSecretReencryption(db *gorp.DbMap) (string, error) {
+	keyID := secret.CurrentKeyID()
+	tx, err := db.Begin()
+	if err != nil {
+		return "", sdk.WrapError(err, "StartSecretReencryption> Unable to start transaction")
+	}
+	defer tx.Rollback()
+
+	var ended *time.Time
+	var stale bool
+	err = tx.QueryRow(`
+		SELECT ended, heartbeat IS NULL OR heartbeat < now() - $2 * interval '1 second' FROM secret_reencryption
+		WHERE key_id = $1 FOR UPDATE`, keyID, reencryptionTimeout.Seconds()).Scan(&ended, &stale)
+	if err != nil && err != sql.ErrNoRows {
+		return "", sdk.WrapError(err, "StartSecretReencryption> Unable to load re-encryption")
+	}
+
+	owner := sdk.UUID()
+	switch {
+	case err == sql.ErrNoRows:
+		if _, err := tx.Exec("INSERT INTO secret_reencryption (key_id, owner, started, heartbeat) VALUES ($1, $2, now(), now())", keyID, owner); err != nil {
+			return "", sdk.WrapError(err, "StartSecretReencryption> Unable to insert re-encryption")
+		}
+	case ended == nil && !stale:
+		return "", sdk.ErrSecretReencryptionRunning
+	case ended == nil:
+		if _, err := tx.Exec("UPDATE secret_reencryption SET owner = $2, heartbeat = now() WHERE key_id = $1", keyID, owner); err != nil {
+			return "", sdk.WrapError(err, "StartSecretReencryption> Unable to resume re-encryption")
+		}
+	default:
+		if _, err := tx.Exec("DELETE FROM secret_reencryption_column WHERE key_id = $1", keyID); err != nil {
+			return "", sdk.WrapError(err, "StartSecretReencryption> Unable to reset re-encryption")
+		}
+		if _, err := tx.Exec(`
+			UPDATE secret_reencryption SET owner = $2, started = now(), ended = NULL, heartbeat = now(), reencrypted = 0, error = ''
+			WHERE key_id = $1`, keyID, owner); err != nil {
+			return "", sdk.WrapError(err, "StartSecretReencryption> Unable to reset re-encryption")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", sdk.WrapError(err, "StartSecretReencryption> Unable to commit transaction")
+	}
+	return owner, nil
+}
+
+// claimSecretReencryption takes over a re-encryption with the current master key which is not finished and without heartbeat.
+// It returns an empty owner if there is no such re-encryption.
+func claimSecretReencryption(db gorp.SqlExecutor) (string, error) {
+	owner := sdk.UUID()
+	res, err := db.Exec(`
+		UPDATE secret_reencryption SET owner = $2, heartbeat = now()
+		WHERE key_id = $1 AND ended IS NULL AND (heartbeat IS NULL OR heartbeat < now() - $3 * interval '1 second')`,
+		secret.CurrentKeyID(), owner, reencryptionTimeout.Seconds())
+	if err != nil {
+		return "", sdk.WrapError(err, "claimSecretReencryption> Unable to claim re-encryption")
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return "", sdk.WrapError(err, "claimSecretReencryption> Unable to claim re-encryption")
+	}
+	if n == 0 {
+		return "", nil
+	}
+	return owner, nil
+}
+
+// ResumeSecretReencryption resumes the re-encryptions of the secrets with the current master key interrupted by the restart
+// or the failure of an API
+func ResumeSecretReencryption(ctx context.Context, DBFunc func() *gorp.DbMap) {
+	tick := time.NewTicker(time.Minute)
+	defer tick.Stop()
+	for {
+		owner, err := claimSecretReencryption(DBFunc())
+		if err != nil {
+			log.Warning("ResumeSecretReencryption> %v", err)
+		} else if owner != "" {
+			log.Info("ResumeSecretReencryption> Re-encryption of the secrets with key %s resumed", secret.CurrentKeyID())
+			ReencryptSecrets(ctx, DBFunc, owner)
+		}
+
+		select {
+		case <-ctx.Done():
+			if ctx.Err() != nil {
+				log.Error("Exiting ResumeSecretReencryption: %v", ctx.Err())
+			}
+			return
+		case <-tick.C:
+		}
+	}
+}
+
+// ReencryptSecrets re-encrypts the stored secrets with the current master key. The progress of each column is saved with its
+// secrets, so an interrupted re-encryption is resumed where it stopped.
+func ReencryptSecrets(ctx context.Context, DBFunc func() *gorp.DbMap, owner string) {
+	db := DBFunc()
+	keyID := secret.CurrentKeyID()
+
+	progress, err := loadReencryptionColumns(db, keyID)
+	if err != nil {
+		log.Error("ReencryptSecrets> %v", err)
+		endSecretReencryption(db, keyID, owner, err)
+		return
+	}
+
+	for _, c := range secretColumns {
+		p := progress[c.name()]
+		if p.done {
+			continue
+		}
+		last := p.lastKey
+		for {
+			if ctx.Err() != nil {
+				// without heartbeat, the re-encryption is resumed by the next API started
+				if _, err := db.Exec("UPDATE secret_reencryption SET heartbeat = NULL WHERE key_id = $1 AND owner = $2", keyID, owner); err != nil {
+					log.Error("ReencryptSecrets> Unable to release re-encryption: %v", err)
+				}
+				return
+			}
+			next, err := reencryptBatch(db, c, keyID, owner, last)
+			if err == errReencryptionResumed {
+				log.Warning("ReencryptSecrets> Re-encryption of the secrets with key %s resumed by another API", keyID)
+				return
+			}
+			if err != nil {
+				log.Error("ReencryptSecrets> %v", err)
+				endSecretReencryption(db, keyID, owner, err)
+				return
+			}
+			if next == 0 {
+				break
+			}
+			last = next
+		}
+		log.Info("ReencryptSecrets> Secrets of %s re-encrypted with key %s", c.name(), keyID)
+	}
+	endSecretReencryption(db, keyID, owner, nil)
+}
+
+func endSecretReencryption(db gorp.SqlExecutor, keyID, owner string, reencryptionErr error) {
+	var msg string
+	if reencryptionErr != nil {
+		msg = reencryptionErr.Error()
+	}
+	if _, err := db.Exec("UPDATE secret_reencryption SET ended = now(), heartbeat = NULL, error = $3 WHERE key_id = $1 AND owner = $2", keyID, owner, msg); err != nil {
+		log.Error("ReencryptSecrets> Unable to end re-encryption: %v", err)
+	}
+}
+
+func loadReencryptionColumns(db gorp.SqlExecutor, keyID string) (map[string]reencryptionColumn, error) {
+	rows, err := db.Query("SELECT table_name, column_name, last_key, done FROM secret_reencryption_column WHERE key_id = $1", keyID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "loadReencryptionColumns> Unable to load re-encryption progress")
+	}
+	defer rows.Close()
+
+	progress := map[string]reencryptionColumn{}
+	for rows.Next() {
+		var table, column string
+		var p reencryptionColumn
+		if err := rows.Scan(&table, &column, &p.lastKey, &p.done); err != nil {
+			return nil, sdk.WrapError(err, "loadReencryptionColumns> Unable to scan re-encryption progress")
+		}
+		progress[table+"."+column] = p
+	}
+	return progress, nil
+}
+
+// reencryptBatch re-encrypts the secrets of a batch of rows following the given key, and saves the progress of the column in
+// the same transaction. It returns the key of the last row of the batch, or 0 when all the rows of the column are re-encrypted.
+func reencryptBatch(db *gorp.DbMap, c secretColumn, keyID, owner string, from int64) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, sdk.WrapError(err, "reencryptBatch> Unable to start transaction")
+	}
+	defer tx.Rollback()
+
+	keys := c.primaryKeys()
+	query := fmt.Sprintf(`
+		SELECT %[3]s, %[2]s FROM %[1]s
+		WHERE %[2]s IS NOT NULL AND %[4]s IN (
+			SELECT DISTINCT %[4]s FROM %[1]s WHERE %[4]s > $1 AND %[2]s IS NOT NULL ORDER BY %[4]s LIMIT %[5]d
+		)
+		ORDER BY %[3]s FOR UPDATE`, c.table, c.column, strings.Join(keys, ", "), keys[0], reencryptionBatchSize)
+	rows, err := tx.Query(query, from)
+	if err != nil {
+		return 0, sdk.WrapError(err, "reencryptBatch> Unable to load secrets of %s", c.name())
+	}
+	type row struct {
+		keys []interface{}
+		data []byte
+	}
+	var batch []row
+	for rows.Next() {
+		ids := make([]int64, len(keys))
+		dest := make([]interface{}, 0, len(keys)+1)
+		for i := range ids {
+			dest = append(dest, &ids[i])
+		}
+		var r row
+		if err := rows.Scan(append(dest, &r.data)...); err != nil {
+			rows.Close()
+			return 0, sdk.WrapError(err, "reencryptBatch> Unable to scan secret of %s", c.name())
+		}
+		for _, id := range ids {
+			r.keys = append(r.keys, id)
+		}
+		batch = append(batch, r)
+	}
+	rows.Close()
+
+	where := make([]string, len(keys))
+	for i, k := range keys {
+		where[i] = fmt.Sprintf("%s = $%d", k, i+2)
+	}
+	update := fmt.Sprintf("UPDATE %s SET %s = $1 WHERE %s", c.table, c.column, strings.Join(where, " AND "))
+
+	var last, reencrypted int64
+	for _, r := range batch {
+		last = r.keys[0].(int64)
+		if len(r.data) == 0 {
+			continue
+		}
+
+		var data []byte
+		var n int
+		if c.reencrypt != nil {
+			data, n, err = c.reencrypt(r.data)
+		} else {
+			data, n, err = reencryptSecret(r.data)
+		}
+		if err != nil {
+			return 0, sdk.WrapError(err, "reencryptBatch> Unable to re-encrypt secret %v of %s", r.keys, c.name())
+		}
+		if n == 0 {
+			continue
+		}
+
+		var value interface{} = data
+		if c.text || c.reencrypt != nil {
+			value = string(data)
+		}
+		if _, err := tx.Exec(update, append([]interface{}{value}, r.keys...)...); err != nil {
+			return 0, sdk.WrapError(err, "reencryptBatch> Unable to update secret %v of %s", r.keys, c.name())
+		}
+		reencrypted += int64(n)
+	}
+
+	res, err := tx.Exec("UPDATE secret_reencryption SET heartbeat = now(), reencrypted = reencrypted + $3 WHERE key_id = $1 AND owner = $2", keyID, owner, reencrypted)
+	if err != nil {
+		return 0, sdk.WrapError(err, "reencryptBatch> Unable to update re-encryption")
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return 0, sdk.WrapError(err, "reencryptBatch> Unable to update re-encryption")
+	} else if n == 0 {
+		return 0, errReencryptionResumed
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO secret_reencryption_column (key_id, table_name, column_name, last_key, done) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (key_id, table_name, column_name) DO UPDATE SET last_key = $4, done = $5`,
+		keyID, c.table, c.column, last, len(batch) == 0); err != nil {
+		return 0, sdk.WrapError(err, "reencryptBatch> Unable to save progress of %s", c.name())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, sdk.WrapError(err, "reencryptBatch> Unable to commit transaction")
+	}
+	return last, nil
+}
+
+// reencryptSecret re-encrypts a secret which is not encrypted with the current master key
+func reencryptSecret(data []byte) ([]byte, int, error) {
+	if bytes.HasPrefix(data, []byte(secret.KeyPrefix(secret.CurrentKeyID()))) {
+		return data, 0, nil
+	}
+	clear, err := secret.Decrypt(data)
+	if err != nil {
+		return nil, 0, sdk.WrapError(err, "reencryptSecret> Unable to decrypt secret")
+	}
+	ct, err := secret.Encrypt(clear)
+	if err != nil {
+		return nil, 0, sdk.WrapError(err, "reencryptSecret> Unable to encrypt secret")
+	}
+	return ct, 1, nil
+}
+
+// reencryptBase64 re-encrypts a base64 encoded secret
+func reencryptBase64(v string) (string, int, error) {
+	if v == "" {
+		return v, 0, nil
+	}
+	b, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return "", 0, sdk.WrapError(err, "reencryptBase64> Unable to decode secret")
+	}
+	ct, n, err := reencryptSecret(b)
+	if err != nil || n == 0 {
+		return v, n, err
+	}
+	return base64.StdEncoding.EncodeToString(ct), n, nil
+}
+
+// reencryptJSONField re-encrypts the base64 encoded secret stored in a field of a JSON object
+func reencryptJSONField(obj map[string]json.RawMessage, field string) (int, error) {
+	raw, has := obj[field]
+	if !has {
+		return 0, nil
+	}
+	var v string
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return 0, sdk.WrapError(err, "reencryptJSONField> Unable to read %s", field)
+	}
+	v, n, err := reencryptBase64(v)
+	if err != nil || n == 0 {
+		return n, err
+	}
+	if obj[field], err = json.Marshal(v); err != nil {
+		return 0, sdk.WrapError(err, "reencryptJSONField> Unable to write %s", field)
+	}
+	return n, nil
+}
+
+// reencryptVCSStrategy re-encrypts the password of the repository strategy of an application
+func reencryptVCSStrategy(data []byte) ([]byte, int, error) {
+	var strategy map[string]json.RawMessage
+	if err := json.Unmarshal(data, &strategy); err != nil {
+		return nil, 0, sdk.WrapError(err, "reencryptVCSStrategy> Unable to read strategy")
+	}
+	n, err := reencryptJSONField(strategy, "password")
+	if err != nil || n == 0 {
+		return data, n, err
+	}
+	data, err = json.Marshal(strategy)
+	return data, n, sdk.WrapError(err, "reencryptVCSStrategy> Unable to write strategy")
+}
+
+// reencryptAuditVariable re-encrypts the value of the secret variable of an audit
+func reencryptAuditVariable(data []byte) ([]byte, int, error) {
+	var v map[string]json.RawMessage
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, 0, sdk.WrapError(err, "reencryptAuditVariable> Unable to read variable")
+	}
+	var t string
+	if raw, has := v["type"]; has {
+		if err := json.Unmarshal(raw, &t); err != nil {
+			return nil, 0, sdk.WrapError(err, "reencryptAuditVariable> Unable to read variable type")
+		}
+	}
+	if !sdk.NeedPlaceholder(t) {
+		return data, 0, nil
+	}
+	n, err := reencryptJSONField(v, "value")
+	if err != nil || n == 0 {
+		return data, n, err
+	}
+	data, err = json.Marshal(v)
+	return data, n, sdk.WrapError(err, "reencryptAuditVariable> Unable to write variable")
+}
+
+func reencryptPlatformSecrets(cfg sdk.PlatformConfig) (int, error) {
+	var count int
+	for k, v := range cfg {
+		if v.Type != sdk.PlatformConfigTypePassword {
+			continue
+		}
+		s, n, err := reencryptBase64(v.Value)
+		if err != nil {
+			return 0, sdk.WrapError(err, "reencryptPlatformSecrets> Unable to re-encrypt %s", k)
+		}
+		v.Value = s
+		cfg[k] = v
+		count += n
+	}
+	return count, nil
+}
+
+// reencryptPlatformConfig re-encrypts the passwords of the configuration of a platform
+func reencryptPlatformConfig(data []byte) ([]byte, int, error) {
+	var cfg sdk.PlatformConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, 0, sdk.WrapError(err, "reencryptPlatformConfig> Unable to read config")
+	}
+	n, err := reencryptPlatformSecrets(cfg)
+	if err != nil || n == 0 {
+		return data, n, err
+	}
+	data, err = json.Marshal(cfg)
+	return data, n, sdk.WrapError(err, "reencryptPlatformConfig> Unable to write config")
+}
+
+// reencryptPlatformPublicConfigurations re-encrypts the passwords of the public configurations of a platform model
+func reencryptPlatformPublicConfigurations(data []byte) ([]byte, int, error) {
+	var cfgs map[string]sdk.PlatformConfig
+	if err := json.Unmarshal(data, &cfgs); err != nil {
+		return nil, 0, sdk.WrapError(err, "reencryptPlatformPublicConfigurations> Unable to read configurations")
+	}
+	var count int
+	for name, cfg := range cfgs {
+		n, err := reencryptPlatformSecrets(cfg)
+		if err != nil {
+			return nil, 0, sdk.WrapError(err, "reencryptPlatformPublicConfigurations> Unable to re-encrypt %s", name)
+		}
+		count += n
+	}
+	if count == 0 {
+		return data, 0, nil
+	}
+	data, err := json.Marshal(cfgs)
+	return data, count, sdk.WrapError(err, "reencryptPlatformPublicConfigurations> Unable to write configurations")
+}
+
+// reencryptHookConfig re-encrypts the secrets of the configuration of a workflow hook
+func reencryptHookConfig(data []byte) ([]byte, int, error) {
+	var cfg sdk.WorkflowNodeHookConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, 0, sdk.WrapError(err, "reencryptHookConfig> Unable to read config")
+	}
+	var count int
+	for k, v := range cfg {
+		if !cfg.IsSecret(k) {
+			continue
+		}
+		s, n, err := reencryptBase64(v.Value)
+		if err != nil {
+			return nil, 0, sdk.WrapError(err, "reencryptHookConfig> Unable to re-encrypt %s", k)
+		}
+		v.Value = s
+		cfg[k] = v
+		count += n
+	}
+	if count == 0 {
+		return data, 0, nil
+	}
+	data, err := json.Marshal(cfg)
+	return data, count, sdk.WrapError(err, "reencryptHookConfig> Unable to write config")
+}
+
+// reencryptWebhookNotificationSettings re-encrypts the header values and the secret of a webhook notification, the
+// settings of the other notifications have no secret
+func reencryptWebhookNotificationSettings(data []byte) ([]byte, int, error) {
+	var settings map[string]json.RawMessage
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, 0, sdk.WrapError(err, "reencryptWebhookNotificationSettings> Unable to read settings")
+	}
+	count, err := reencryptJSONField(settings, "secret")
+	if err != nil {
+		return nil, 0, err
+	}
+	if raw, has := settings["headers"]; has {
+		var headers map[string]string
+		if err := json.Unmarshal(raw, &headers); err != nil {
+			return nil, 0, sdk.WrapError(err, "reencryptWebhookNotificationSettings> Unable to read headers")
+		}
+		for k, v := range headers {
+			s, n, err := reencryptBase64(v)
+			if err != nil {
+				return nil, 0, sdk.WrapError(err, "reencryptWebhookNotificationSettings> Unable to re-encrypt header %s", k)
+			}
+			headers[k] = s
+			count += n
+		}
+		if settings["headers"], err = json.Marshal(headers); err != nil {
+			return nil, 0, sdk.WrapError(err, "reencryptWebhookNotificationSettings> Unable to write headers")
+		}
+	}
+	if count == 0 {
+		return data, 0, nil
+	}
+	data, err = json.Marshal(settings)
+	return data, count, sdk.WrapError(err, "reencryptWebhookNotificationSettings> Unable to write settings")
+}
+
+// LoadReencryptionStatus returns the status of the re-encryption with the current master key. The secrets stored in columns
+// are counted by master key, the rows of the JSON columns storing secrets are counted as done once they are re-encrypted.
+func LoadReencryptionStatus(db gorp.SqlExecutor) (*sdk.SecretReencryptionStatus, error) {
+	keyID := secret.CurrentKeyID()
+	status := sdk.SecretReencryptionStatus{KeyID: keyID}
+	err := db.QueryRow("SELECT started, ended, reencrypted, error FROM secret_reencryption WHERE key_id = $1", keyID).
+		Scan(&status.Started, &status.Ended, &status.Reencrypted, &status.Error)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, sdk.WrapError(err, "LoadReencryptionStatus> Unable to load re-encryption")
+	}
+	status.Running = status.Started != nil && status.Ended == nil
+
+	progress, err := loadReencryptionColumns(db, keyID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadReencryptionStatus")
+	}
+
+	p := secret.KeyPrefix(keyID)
+	for _, c := range secretColumns {
+		t := sdk.SecretReencryptionTable{Name: c.name()}
+		if c.reencrypt == nil {
+			query := fmt.Sprintf(`
+				SELECT COUNT(*), COUNT(*) FILTER (WHERE substring(%[2]s from 1 for $1) = $2) FROM %[1]s
+				WHERE %[2]s IS NOT NULL AND length(%[2]s) > 0`, c.table, c.column)
+			if err := db.QueryRow(query, len(p), c.prefix(keyID)).Scan(&t.Total, &t.Done); err != nil {
+				return nil, sdk.WrapError(err, "LoadReencryptionStatus> Unable to count secrets of %s", c.name())
+			}
+		} else {
+			cp := progress[c.name()]
+			query := fmt.Sprintf(`
+				SELECT COUNT(*), COUNT(*) FILTER (WHERE %[3]s <= $1) FROM %[1]s
+				WHERE %[2]s IS NOT NULL`, c.table, c.column, c.primaryKeys()[0])
+			if err := db.QueryRow(query, cp.lastKey).Scan(&t.Total, &t.Done); err != nil {
+				return nil, sdk.WrapError(err, "LoadReencryptionStatus> Unable to count rows of %s", c.name())
+			}
+			if cp.done {
+				t.Done = t.Total
+			}
+		}
+		status.Tables = append(status.Tables, t)
+	}
+	return &status, nil
+}
@@ -0,0 +1,110 @@
+package migrate
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/sdk"
+)
+
+func encryptBase64(t *testing.T, v string) string {
+	ct, err := secret.Encrypt([]byte(v))
+	if err != nil {
+		t.Fatalf("Encrypt failed: %s", err)
+	}
+	return base64.StdEncoding.EncodeToString(ct)
+}
+
+func decryptBase64(t *testing.T, v string) string {
+	b, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		t.Fatalf("DecodeString failed: %s", err)
+	}
+	assert.True(t, strings.HasPrefix(string(b), secret.KeyPrefix("k1")), "%s is not encrypted with the current master key", b)
+	clear, err := secret.Decrypt(b)
+	if err != nil {
+		t.Fatalf("Decrypt failed: %s", err)
+	}
+	return string(clear)
+}
+
+func Test_reencryptJSONColumns(t *testing.T) {
+	secret.Init("78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xf")
+	hook, _ := json.Marshal(sdk.WorkflowNodeHookConfig{
+		"token":  {Value: encryptBase64(t, "my-token"), Type: sdk.HookConfigTypePassword},
+		"method": {Value: "POST", Type: sdk.HookConfigTypeString},
+	})
+	platform, _ := json.Marshal(sdk.PlatformConfig{
+		"password": {Value: encryptBase64(t, "my-password"), Type: sdk.PlatformConfigTypePassword},
+		"user":     {Value: "admin", Type: sdk.PlatformConfigTypeString},
+	})
+	variable, _ := json.Marshal(sdk.Variable{Name: "foo", Type: sdk.SecretVariable, Value: encryptBase64(t, "my-secret")})
+	strategy := []byte(`{"connection_type":"https","user":"foo","password":"` + encryptBase64(t, "my-vcs-password") + `"}`)
+	webhook, _ := json.Marshal(sdk.WebhookUserNotificationSettings{
+		URL:     "https://my-server/cds",
+		Headers: map[string]string{"Authorization": encryptBase64(t, "Bearer my-token")},
+		Secret:  encryptBase64(t, "my-webhook-secret"),
+	})
+	jabber, _ := json.Marshal(sdk.JabberEmailUserNotificationSettings{Recipients: []string{"alice"}})
+
+	if err := secret.InitKeys(map[string]string{"k1": "Zf23hwefw34LAQ15ZD5AOABo1Xb239fj"}, "k1"); err != nil {
+		t.Fatalf("InitKeys failed: %s", err)
+	}
+	defer secret.InitKeys(nil, "")
+
+	data, n, err := reencryptHookConfig(hook)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	var cfg sdk.WorkflowNodeHookConfig
+	assert.NoError(t, json.Unmarshal(data, &cfg))
+	assert.Equal(t, "my-token", decryptBase64(t, cfg["token"].Value))
+	assert.Equal(t, "POST", cfg["method"].Value)
+
+	data, n, err = reencryptPlatformConfig(platform)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	var pfCfg sdk.PlatformConfig
+	assert.NoError(t, json.Unmarshal(data, &pfCfg))
+	assert.Equal(t, "my-password", decryptBase64(t, pfCfg["password"].Value))
+	assert.Equal(t, "admin", pfCfg["user"].Value)
+
+	data, n, err = reencryptAuditVariable(variable)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	var v sdk.Variable
+	assert.NoError(t, json.Unmarshal(data, &v))
+	assert.Equal(t, "my-secret", decryptBase64(t, v.Value))
+
+	data, n, err = reencryptVCSStrategy(strategy)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	var s sdk.RepositoryStrategy
+	assert.NoError(t, json.Unmarshal(data, &s))
+	assert.Equal(t, "my-vcs-password", decryptBase64(t, s.Password))
+	assert.Equal(t, "foo", s.User)
+
+	data, n, err = reencryptWebhookNotificationSettings(webhook)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	var ws sdk.WebhookUserNotificationSettings
+	assert.NoError(t, json.Unmarshal(data, &ws))
+	assert.Equal(t, "Bearer my-token", decryptBase64(t, ws.Headers["Authorization"]))
+	assert.Equal(t, "my-webhook-secret", decryptBase64(t, ws.Secret))
+	assert.Equal(t, "https://my-server/cds", ws.URL)
+
+	data, n, err = reencryptWebhookNotificationSettings(jabber)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Equal(t, jabber, data)
+
+	// the secrets already encrypted with the current master key are skipped
+	again, n, err := reencryptVCSStrategy(data)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Equal(t, data, again)
+}
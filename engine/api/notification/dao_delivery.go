@@ -0,0 +1,43 @@
+package notification
+
+import (
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+type delivery sdk.WorkflowNotificationDelivery
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(delivery{}, "workflow_notification_delivery", true, "id"))
+}
+
+// deliveryRetention is the number of days the deliveries are kept
+const deliveryRetention = 7
+
+func insertDelivery(db gorp.SqlExecutor, d *sdk.WorkflowNotificationDelivery) error {
+	dbd := delivery(*d)
+	if err := db.Insert(&dbd); err != nil {
+		return sdk.WrapError(err, "insertDelivery> Unable to insert delivery")
+	}
+	d.ID = dbd.ID
+
+	if _, err := db.Exec("DELETE FROM workflow_notification_delivery WHERE workflow_id = $1 AND created < now() - $2 * interval '1 day'", d.WorkflowID, deliveryRetention); err != nil {
+		return sdk.WrapError(err, "insertDelivery> Unable to purge deliveries")
+	}
+	return nil
+}
+
+// LoadDeliveries returns the last webhook notification deliveries of a workflow
+func LoadDeliveries(db gorp.SqlExecutor, workflowID int64, limit int) ([]sdk.WorkflowNotificationDelivery, error) {
+	dbds := []delivery{}
+	if _, err := db.Select(&dbds, "SELECT * FROM workflow_notification_delivery WHERE workflow_id = $1 ORDER BY created DESC LIMIT $2", workflowID, limit); err != nil {
+		return nil, sdk.WrapError(err, "LoadDeliveries> Unable to load deliveries of workflow %d", workflowID)
+	}
+	ds := make([]sdk.WorkflowNotificationDelivery, len(dbds))
+	for i := range dbds {
+		ds[i] = sdk.WorkflowNotificationDelivery(dbds[i])
+	}
+	return ds, nil
+}
@@ -16,12 +16,14 @@ import (
 )
 
 var (
-	uiURL string
+	uiURL  string
+	dbFunc func() *gorp.DbMap
 )
 
 // Init initializes notification package
-func Init(uiurl string) {
+func Init(uiurl string, DBFunc func() *gorp.DbMap) {
 	uiURL = uiurl
+	dbFunc = DBFunc
 }
 
 // GetUserEvents returns event from user notification
@@ -219,6 +221,34 @@ func GetUserWorkflowEvents(db gorp.SqlExecutor, w sdk.Workflow, previousWR *sdk.
 				//Finally deduplicate everyone
 				removeDuplicates(&jn.Recipients)
 				go SendMailNotif(getWorkflowEvent(jn, params))
+			case sdk.WebhookUserNotification:
+				wn, ok := notif.Settings.(*sdk.WebhookUserNotificationSettings)
+				if !ok {
+					log.Error("notification.GetUserWorkflowEvents[Webhook]> cannot deal with %v", notif)
+					continue
+				}
+				// the secrets are masked in the workflow of the run
+				if wn.IsMasked() {
+					stored, err := LoadWebhookSecrets(db, w.ID, notif.ID, wn.URL)
+					if err != nil {
+						log.Error("notification.GetUserWorkflowEvents[Webhook]> Unable to load secrets of notification %d: %v", notif.ID, err)
+						continue
+					}
+					wn = wn.WithRestoredSecrets(stored)
+				}
+				n, err := getWebhookNotif(wn, params)
+				if err != nil {
+					log.Error("notification.GetUserWorkflowEvents[Webhook]> %v", err)
+					continue
+				}
+				d := sdk.WorkflowNotificationDelivery{
+					WorkflowID:        w.ID,
+					NotificationID:    notif.ID,
+					WorkflowRunNumber: nr.Number,
+					WorkflowNodeRunID: nr.ID,
+					Status:            nr.Status,
+				}
+				go sendWebhookNotif(n, d)
 			}
 		}
 	}
@@ -0,0 +1,133 @@
+package notification
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/interpolate"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// WebhookSignatureHeader contains the HMAC SHA256 of the body when the webhook has a secret
+const WebhookSignatureHeader = "X-Cds-Signature"
+
+var (
+	webhookClient      = &http.Client{Timeout: 10 * time.Second}
+	webhookMaxAttempts = 5
+	webhookBackoff     = 2 * time.Second
+)
+
+type webhookNotif struct {
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    []byte
+}
+
+// getWebhookNotif renders the webhook templates with the parameters of the run,
+// the body is the JSON of the parameters if there is no template
+func getWebhookNotif(notif *sdk.WebhookUserNotificationSettings, params map[string]string) (webhookNotif, error) {
+	n := webhookNotif{
+		Method:  notif.Method,
+		Headers: make(map[string]string, len(notif.Headers)+2),
+	}
+	if n.Method == "" {
+		n.Method = http.MethodPost
+	}
+
+	var err error
+	if n.URL, err = interpolate.Do(notif.URL, params); err != nil {
+		return n, sdk.WrapError(err, "getWebhookNotif> Unable to interpolate url")
+	}
+
+	if notif.Body != "" {
+		body, err := interpolate.Do(notif.Body, params)
+		if err != nil {
+			return n, sdk.WrapError(err, "getWebhookNotif> Unable to interpolate body")
+		}
+		n.Body = []byte(body)
+	} else {
+		if n.Body, err = json.Marshal(params); err != nil {
+			return n, sdk.WrapError(err, "getWebhookNotif> Unable to marshal params")
+		}
+	}
+
+	n.Headers["Content-Type"] = "application/json"
+	for k, v := range notif.Headers {
+		if n.Headers[k], err = interpolate.Do(v, params); err != nil {
+			return n, sdk.WrapError(err, "getWebhookNotif> Unable to interpolate header %s", k)
+		}
+	}
+	if notif.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(notif.Secret))
+		mac.Write(n.Body) // nolint
+		n.Headers[WebhookSignatureHeader] = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+	return n, nil
+}
+
+// sendWebhookNotif sends the request, it retries with an exponential backoff on network errors,
+// 429 and 5xx responses. Each attempt is saved in the delivery log.
+func sendWebhookNotif(notif webhookNotif, d sdk.WorkflowNotificationDelivery) {
+	log.Info("notification.sendWebhookNotif> Send notif %s %s", notif.Method, notif.URL)
+	d.Method = notif.Method
+	d.URL = notif.URL
+	backoff := webhookBackoff
+	for d.Attempt = 1; d.Attempt <= webhookMaxAttempts; d.Attempt++ {
+		start := time.Now()
+		code, err := doWebhookRequest(notif)
+		d.Created = time.Now()
+		d.Duration = int64(d.Created.Sub(start) / time.Millisecond)
+		d.StatusCode = code
+		d.Error = ""
+		if err != nil {
+			d.Error = err.Error()
+		}
+		if dbFunc != nil {
+			if errI := insertDelivery(dbFunc(), &d); errI != nil {
+				log.Error("notification.sendWebhookNotif> Unable to save delivery: %v", errI)
+			}
+		}
+
+		if code != 0 && code != http.StatusTooManyRequests && code < 500 {
+			return
+		}
+		log.Warning("notification.sendWebhookNotif> Attempt %d on %s failed: %d %v", d.Attempt, notif.URL, code, err)
+		if d.Attempt < webhookMaxAttempts {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+}
+
+func doWebhookRequest(notif webhookNotif) (int, error) {
+	req, err := http.NewRequest(notif.Method, notif.URL, bytes.NewReader(notif.Body))
+	if err != nil {
+		return 0, err
+	}
+	for k, v := range notif.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("User-Agent", "CDS/notification")
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Read the body to reuse the connection
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode >= 400 {
+		return resp.StatusCode, fmt.Errorf("HTTP %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,97 @@
+package notification
+
+import (
+	"database/sql"
+	"encoding/base64"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/sdk"
+)
+
+// EncryptWebhookSecrets returns a copy of the settings whose header values and secret are encrypted
+func EncryptWebhookSecrets(s *sdk.WebhookUserNotificationSettings) (*sdk.WebhookUserNotificationSettings, error) {
+	c := *s
+	if s.Headers != nil {
+		c.Headers = make(map[string]string, len(s.Headers))
+		for k, v := range s.Headers {
+			encrypted, err := encryptBase64(v)
+			if err != nil {
+				return nil, sdk.WrapError(err, "EncryptWebhookSecrets> Cannot encrypt header %s", k)
+			}
+			c.Headers[k] = encrypted
+		}
+	}
+	var err error
+	if c.Secret, err = encryptBase64(s.Secret); err != nil {
+		return nil, sdk.WrapError(err, "EncryptWebhookSecrets> Cannot encrypt secret")
+	}
+	return &c, nil
+}
+
+// DecryptWebhookSecrets decrypts the header values and the secret of the settings
+func DecryptWebhookSecrets(s *sdk.WebhookUserNotificationSettings) error {
+	for k, v := range s.Headers {
+		clear, err := decryptBase64(v)
+		if err != nil {
+			return sdk.WrapError(err, "DecryptWebhookSecrets> Cannot decrypt header %s", k)
+		}
+		s.Headers[k] = clear
+	}
+	var err error
+	s.Secret, err = decryptBase64(s.Secret)
+	return sdk.WrapError(err, "DecryptWebhookSecrets> Cannot decrypt secret")
+}
+
+// LoadWebhookSecrets returns the settings of a webhook notification of the workflow with the header values and the secret
+// stored in database. The notification is loaded by id, or by url if the workflow has been updated since the id was loaded.
+func LoadWebhookSecrets(db gorp.SqlExecutor, workflowID, id int64, url string) (*sdk.WebhookUserNotificationSettings, error) {
+	query := `
+	SELECT settings FROM workflow_notification
+	WHERE workflow_id = $1 AND type = $2 AND (id = $3 OR settings->>'url' = $4)
+	ORDER BY id = $3 DESC, id
+	LIMIT 1`
+	var settings sql.NullString
+	if err := db.QueryRow(query, workflowID, string(sdk.WebhookUserNotification), id, url).Scan(&settings); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.WrapError(sdk.ErrNotFound, "LoadWebhookSecrets> Notification %d not found", id)
+		}
+		return nil, sdk.WrapError(err, "LoadWebhookSecrets> Cannot load notification %d", id)
+	}
+	s, err := sdk.ParseWorkflowUserNotificationSettings(sdk.WebhookUserNotification, []byte(settings.String))
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadWebhookSecrets> Cannot parse notification %d", id)
+	}
+	ws := s.(*sdk.WebhookUserNotificationSettings)
+	if err := DecryptWebhookSecrets(ws); err != nil {
+		return nil, err
+	}
+	return ws, nil
+}
+
+func encryptBase64(v string) (string, error) {
+	if v == "" {
+		return v, nil
+	}
+	encrypted, err := secret.Encrypt([]byte(v))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(encrypted), nil
+}
+
+func decryptBase64(v string) (string, error) {
+	if v == "" {
+		return v, nil
+	}
+	b, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return "", err
+	}
+	clear, err := secret.Decrypt(b)
+	if err != nil {
+		return "", err
+	}
+	return string(clear), nil
+}
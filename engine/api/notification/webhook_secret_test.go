@@ -0,0 +1,41 @@
+package notification
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_webhookSecrets(t *testing.T) {
+	secret.Init("78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xf")
+	s := &sdk.WebhookUserNotificationSettings{
+		URL:     "https://chat.local",
+		Headers: map[string]string{"Authorization": "Bearer my-token"},
+		Secret:  "s3cr3t",
+	}
+
+	encrypted, err := EncryptWebhookSecrets(s)
+	test.NoError(t, err)
+	assert.NotEqual(t, "Bearer my-token", encrypted.Headers["Authorization"])
+	assert.NotEqual(t, "s3cr3t", encrypted.Secret)
+	assert.Equal(t, "Bearer my-token", s.Headers["Authorization"], "the settings must not be modified")
+
+	test.NoError(t, DecryptWebhookSecrets(encrypted))
+	assert.Equal(t, s, encrypted)
+
+	masked := s.WithMaskedSecrets()
+	assert.Equal(t, sdk.PasswordPlaceholder, masked.Headers["Authorization"])
+	assert.Equal(t, sdk.PasswordPlaceholder, masked.Secret)
+	assert.True(t, masked.IsMasked())
+	assert.False(t, s.IsMasked())
+
+	masked.Headers["X-Status"] = "{{.cds.status}}"
+	masked.Headers["X-Unknown"] = sdk.PasswordPlaceholder
+	restored := masked.WithRestoredSecrets(s)
+	assert.Equal(t, map[string]string{"Authorization": "Bearer my-token", "X-Status": "{{.cds.status}}"}, restored.Headers)
+	assert.Equal(t, "s3cr3t", restored.Secret)
+}
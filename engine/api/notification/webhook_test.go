@@ -0,0 +1,64 @@
+package notification
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_getWebhookNotif(t *testing.T) {
+	params := map[string]string{"cds.project": "KEY", "cds.status": "Success"}
+	n, err := getWebhookNotif(&sdk.WebhookUserNotificationSettings{
+		URL:     "https://chat.local/hooks/{{.cds.project}}",
+		Headers: map[string]string{"X-Status": "{{.cds.status}}"},
+		Secret:  "s3cr3t",
+		Body:    `{"text": "{{.cds.project}} is {{.cds.status}}"}`,
+	}, params)
+	test.NoError(t, err)
+	assert.Equal(t, http.MethodPost, n.Method)
+	assert.Equal(t, "https://chat.local/hooks/KEY", n.URL)
+	assert.Equal(t, `{"text": "KEY is Success"}`, string(n.Body))
+	assert.Equal(t, "Success", n.Headers["X-Status"])
+	assert.Equal(t, "sha256=128988c49034c3078cf5c4f302db1f26a76dcd31debb0ed417719e74c886dd18", n.Headers[WebhookSignatureHeader])
+
+	n, err = getWebhookNotif(&sdk.WebhookUserNotificationSettings{URL: "https://chat.local", Method: http.MethodPut}, params)
+	test.NoError(t, err)
+	assert.Equal(t, http.MethodPut, n.Method)
+	assert.Equal(t, `{"cds.project":"KEY","cds.status":"Success"}`, string(n.Body))
+}
+
+func Test_sendWebhookNotif(t *testing.T) {
+	webhookBackoff = time.Millisecond
+	var calls int
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer srv.Close()
+
+	sendWebhookNotif(webhookNotif{Method: http.MethodPost, URL: srv.URL, Body: []byte("done")}, sdk.WorkflowNotificationDelivery{})
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, "done", body)
+
+	// Client errors are not retried
+	calls = 0
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNotFound)
+	})
+	sendWebhookNotif(webhookNotif{Method: http.MethodPost, URL: srv.URL}, sdk.WorkflowNotificationDelivery{})
+	assert.Equal(t, 1, calls)
+}
@@ -72,6 +72,7 @@ func FetchTempURL(o Object) (string, error) {
 
 // Driver allows artifact to be stored and retrieve the same way to any backend
 // - Openstack / Swift
+// - S3
 // - Filesystem
 type Driver interface {
 	Status() sdk.MonitoringStatusLine
@@ -106,6 +107,7 @@ const (
 	Openstack Kind = iota
 	Filesystem
 	Swift
+	S3
 )
 
 // Config represents all the configuration for all objecstore drivers
@@ -118,6 +120,7 @@ type Config struct {
 type ConfigOptions struct {
 	Openstack  ConfigOptionsOpenstack
 	Filesystem ConfigOptionsFilesystem
+	S3         ConfigOptionsS3
 }
 
 // ConfigOptionsOpenstack is used by ConfigOptions
@@ -132,6 +135,18 @@ type ConfigOptionsOpenstack struct {
 	DisableTempURL  bool
 }
 
+// ConfigOptionsS3 is used by ConfigOptions
+type ConfigOptionsS3 struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	Prefix          string
+	ForcePathStyle  bool
+	DisableTempURL  bool
+}
+
 // ConfigOptionsFilesystem is used by ConfigOptions
 type ConfigOptionsFilesystem struct {
 	Basedir string
@@ -153,6 +168,19 @@ func New(c context.Context, cfg Config) (Driver, error) {
 			cfg.Options.Openstack.Tenant,
 			cfg.Options.Openstack.Domain,
 			cfg.Options.Openstack.ContainerPrefix)
+	case S3:
+		instance = sdk.ArtifactsStore{
+			Name:                  "S3",
+			Private:               false,
+			TemporaryURLSupported: !cfg.Options.S3.DisableTempURL,
+		}
+		return NewS3Store(cfg.Options.S3.Endpoint,
+			cfg.Options.S3.Region,
+			cfg.Options.S3.Bucket,
+			cfg.Options.S3.AccessKeyID,
+			cfg.Options.S3.SecretAccessKey,
+			cfg.Options.S3.Prefix,
+			cfg.Options.S3.ForcePathStyle)
 	case Filesystem:
 		instance = sdk.ArtifactsStore{
 			Name:                  "Local FS",
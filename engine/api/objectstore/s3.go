@@ -0,0 +1,335 @@
+package objectstore
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3TimeFormat      = "20060102T150405Z"
+	s3DateFormat      = "20060102"
+	s3TempURLExpiry   = time.Hour
+)
+
+// S3Store implements ObjectStore interface with an Amazon S3 compatible API (AWS, Minio, Ceph RGW...)
+type S3Store struct {
+	endpoint       *url.URL
+	region         string
+	bucket         string
+	accessKeyID    string
+	secretKey      string
+	prefix         string
+	forcePathStyle bool
+	client         *http.Client
+}
+
+// NewS3Store create a new ObjectStore with S3 driver and check configuration
+func NewS3Store(endpoint, region, bucket, accessKeyID, secretKey, prefix string, forcePathStyle bool) (Driver, error) {
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, sdk.WrapError(err, "S3> Invalid endpoint %s", endpoint)
+	}
+	if bucket == "" {
+		return nil, fmt.Errorf("S3> Missing bucket name")
+	}
+
+	s := &S3Store{
+		endpoint:       u,
+		region:         region,
+		bucket:         bucket,
+		accessKeyID:    accessKeyID,
+		secretKey:      secretKey,
+		prefix:         prefix,
+		forcePathStyle: forcePathStyle,
+		client:         &http.Client{Timeout: 10 * time.Minute},
+	}
+
+	if err := s.createBucket(); err != nil {
+		return nil, sdk.WrapError(err, "S3> Unable to initialize bucket %s", bucket)
+	}
+	return s, nil
+}
+
+// Status returns the status of the bucket
+func (s *S3Store) Status() sdk.MonitoringStatusLine {
+	res, err := s.do(http.MethodHead, "", nil, -1)
+	if err != nil {
+		return sdk.MonitoringStatusLine{Component: "Object-Store", Value: "S3 KO " + err.Error(), Status: sdk.MonitoringStatusAlert}
+	}
+	res.Body.Close()
+	if res.StatusCode >= 300 {
+		return sdk.MonitoringStatusLine{Component: "Object-Store", Value: fmt.Sprintf("S3 KO (HTTP %d on bucket %s)", res.StatusCode, s.bucket), Status: sdk.MonitoringStatusAlert}
+	}
+	return sdk.MonitoringStatusLine{Component: "Object-Store", Value: fmt.Sprintf("S3 OK (bucket %s)", s.bucket), Status: sdk.MonitoringStatusOK}
+}
+
+// Store stores in the bucket
+func (s *S3Store) Store(o Object, data io.ReadCloser) (string, error) {
+	defer data.Close()
+	key := s.objectKey(o)
+	log.Debug("S3Store> Storing %s/%s", s.bucket, key)
+
+	// S3 needs the content length, the data is buffered in a temporary file
+	tmp, err := ioutil.TempFile("", "cds-s3-")
+	if err != nil {
+		return "", sdk.WrapError(err, "S3Store> Unable to create temporary file")
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, data)
+	if err != nil {
+		return "", sdk.WrapError(err, "S3Store> Unable to buffer object %s", key)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return "", sdk.WrapError(err, "S3Store> Unable to read buffered object %s", key)
+	}
+
+	res, err := s.do(http.MethodPut, key, tmp, size)
+	if err != nil {
+		return "", sdk.WrapError(err, "S3Store> Unable to store object %s", key)
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 300 {
+		return "", sdk.WrapError(s3Error(res), "S3Store> Unable to store object %s", key)
+	}
+
+	return s.bucket + "/" + key, nil
+}
+
+// Fetch an object from the bucket
+func (s *S3Store) Fetch(o Object) (io.ReadCloser, error) {
+	key := s.objectKey(o)
+	log.Debug("S3Store> Fetching %s/%s", s.bucket, key)
+
+	res, err := s.do(http.MethodGet, key, nil, -1)
+	if err != nil {
+		return nil, sdk.WrapError(err, "S3Store> Unable to fetch object %s", key)
+	}
+	if res.StatusCode >= 300 {
+		defer res.Body.Close()
+		return nil, sdk.WrapError(s3Error(res), "S3Store> Unable to fetch object %s", key)
+	}
+	return res.Body, nil
+}
+
+// Delete an object from the bucket
+func (s *S3Store) Delete(o Object) error {
+	key := s.objectKey(o)
+	res, err := s.do(http.MethodDelete, key, nil, -1)
+	if err != nil {
+		return sdk.WrapError(err, "S3Store> Unable to delete object %s", key)
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		log.Info("Delete.S3Store: %s/%s: not found", s.bucket, key)
+		return nil
+	}
+	if res.StatusCode >= 300 {
+		return sdk.WrapError(s3Error(res), "S3Store> Unable to delete object %s", key)
+	}
+	return nil
+}
+
+// StoreURL returns a presigned url to store an object, there is no secret key with S3
+func (s *S3Store) StoreURL(o Object) (string, string, error) {
+	return s.presign(http.MethodPut, s.objectKey(o), time.Now(), s3TempURLExpiry), "", nil
+}
+
+// FetchURL returns a presigned url to fetch an object, there is no secret key with S3
+func (s *S3Store) FetchURL(o Object) (string, string, error) {
+	url := s.presign(http.MethodGet, s.objectKey(o), time.Now(), s3TempURLExpiry)
+	log.Debug("S3Store> Fetch URL: %s", url)
+	return url, "", nil
+}
+
+func (s *S3Store) createBucket() error {
+	res, err := s.do(http.MethodHead, "", nil, -1)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode < 300 {
+		return nil
+	}
+	if res.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("unable to check bucket (HTTP %d)", res.StatusCode)
+	}
+
+	log.Info("S3Store> Creating bucket %s", s.bucket)
+	var body []byte
+	if s.region != "" && s.region != "us-east-1" {
+		body = []byte(fmt.Sprintf(`<CreateBucketConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><LocationConstraint>%s</LocationConstraint></CreateBucketConfiguration>`, s.region))
+	}
+	res, err = s.do(http.MethodPut, "", bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 300 {
+		return s3Error(res)
+	}
+	return nil
+}
+
+func (s *S3Store) objectKey(o Object) string {
+	return strings.TrimPrefix(s.prefix+o.GetPath()+"/"+o.GetName(), "/")
+}
+
+// objectURL returns the url of the object with a path-style or a virtual-hosted style addressing
+func (s *S3Store) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	if s.forcePathStyle {
+		u.Path += "/" + s.bucket
+	} else {
+		u.Host = s.bucket + "." + u.Host
+	}
+	if key != "" || !s.forcePathStyle {
+		u.Path += "/" + key
+	}
+	u.RawPath = s3EscapePath(u.Path)
+	return &u
+}
+
+// do sends a request signed with AWS Signature Version 4, with an unsigned payload
+func (s *S3Store) do(method, key string, body io.Reader, size int64) (*http.Response, error) {
+	u := s.objectURL(key)
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if size >= 0 {
+		req.ContentLength = size
+	}
+
+	now := time.Now().UTC()
+	req.Header.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedPayload)
+
+	signedHeaders := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	canonicalHeaders := "host:" + u.Host + "\n" +
+		"x-amz-content-sha256:" + s3UnsignedPayload + "\n" +
+		"x-amz-date:" + now.Format(s3TimeFormat) + "\n"
+
+	signature := s.signature(method, u, "", canonicalHeaders, strings.Join(signedHeaders, ";"), s3UnsignedPayload, now)
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.accessKeyID, s.scope(now), strings.Join(signedHeaders, ";"), signature))
+
+	return s.client.Do(req)
+}
+
+// presign returns an url signed with AWS Signature Version 4 query parameters
+func (s *S3Store) presign(method, key string, now time.Time, expiry time.Duration) string {
+	now = now.UTC()
+	u := s.objectURL(key)
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", s3Algorithm)
+	query.Set("X-Amz-Credential", s.accessKeyID+"/"+s.scope(now))
+	query.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	query.Set("X-Amz-Expires", fmt.Sprintf("%d", int64(expiry/time.Second)))
+	query.Set("X-Amz-SignedHeaders", "host")
+	canonicalQuery := s3CanonicalQuery(query)
+
+	signature := s.signature(method, u, canonicalQuery, "host:"+u.Host+"\n", "host", s3UnsignedPayload, now)
+	u.RawQuery = canonicalQuery + "&X-Amz-Signature=" + signature
+	return u.String()
+}
+
+func (s *S3Store) scope(t time.Time) string {
+	return t.Format(s3DateFormat) + "/" + s.region + "/s3/aws4_request"
+}
+
+func (s *S3Store) signature(method string, u *url.URL, canonicalQuery, canonicalHeaders, signedHeaders, payloadHash string, t time.Time) string {
+	canonicalRequest := strings.Join([]string{
+		method,
+		s3EscapePath(u.Path),
+		canonicalQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		t.Format(s3TimeFormat),
+		s.scope(t),
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+
+	key := s3HMAC([]byte("AWS4"+s.secretKey), t.Format(s3DateFormat))
+	key = s3HMAC(key, s.region)
+	key = s3HMAC(key, "s3")
+	key = s3HMAC(key, "aws4_request")
+	return hex.EncodeToString(s3HMAC(key, stringToSign))
+}
+
+func s3HMAC(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data)) // nolint
+	return h.Sum(nil)
+}
+
+// s3Escape encodes a string following the AWS URI encoding rules
+func s3Escape(s string, encodeSlash bool) string {
+	var buf bytes.Buffer
+	for _, b := range []byte(s) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9', b == '-', b == '_', b == '.', b == '~':
+			buf.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			buf.WriteByte(b)
+		default:
+			fmt.Fprintf(&buf, "%%%02X", b)
+		}
+	}
+	return buf.String()
+}
+
+func s3EscapePath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	return s3Escape(p, false)
+}
+
+func s3CanonicalQuery(v url.Values) string {
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		for _, value := range v[k] {
+			parts = append(parts, s3Escape(k, true)+"="+s3Escape(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func s3Error(res *http.Response) error {
+	body, _ := ioutil.ReadAll(io.LimitReader(res.Body, 1024))
+	return fmt.Errorf("HTTP %d: %s", res.StatusCode, string(body))
+}
@@ -106,7 +106,7 @@ func (api *API) checkPermission(ctx context.Context, routeVar map[string]string,
 	permissionOk := true
 	for key, value := range routeVar {
 		if permFunc, ok := permissionFunc(api)[key]; ok {
-			permissionOk = permFunc(ctx, value, permission, routeVar)
+			permissionOk = permFunc(ctx, value, permission, routeVar) || checkRolePermission(ctx, key, permission, routeVar)
 			if !permissionOk {
 				return permissionOk
 			}
@@ -115,6 +115,58 @@ func (api *API) checkPermission(ctx context.Context, routeVar map[string]string,
 	return permissionOk
 }
 
+// permissionObjectTypes are the route variables of the objects on which a role can be bound
+var permissionObjectTypes = map[string]string{
+	"permProjectKey":      sdk.RoleBindingProject,
+	"permApplicationName": sdk.RoleBindingApplication,
+	"permPipelineKey":     sdk.RoleBindingPipeline,
+	"permEnvironmentName": sdk.RoleBindingEnvironment,
+	"permWorkflowName":    sdk.RoleBindingWorkflow,
+}
+
+// checkRolePermission checks the named permission equivalent to the permission level, given by the roles bound to the groups of the user
+func checkRolePermission(ctx context.Context, routeKey string, perm int, routeVar map[string]string) bool {
+	objectType, ok := permissionObjectTypes[routeKey]
+	if !ok {
+		return false
+	}
+	return checkObjectPermission(ctx, objectType, routeKey, permission.LevelPermissionName(objectType, perm), routeVar)
+}
+
+func checkObjectPermission(ctx context.Context, objectType, routeKey, perm string, routeVar map[string]string) bool {
+	if objectType == sdk.RoleBindingProject {
+		return permission.HasPermission(getUser(ctx), objectType, routeVar[routeKey], "", perm)
+	}
+	projectKey, ok := routeVar["key"]
+	if !ok {
+		log.Warning("Wrong route configuration. need key parameter")
+		return false
+	}
+	return permission.HasPermission(getUser(ctx), objectType, projectKey, routeVar[routeKey], perm)
+}
+
+// checkNamedPermission checks a named permission on the most specific object of the route
+func (api *API) checkNamedPermission(ctx context.Context, routeVar map[string]string, perm string) bool {
+	for _, g := range getUser(ctx).Groups {
+		if group.SharedInfraGroup != nil && g.Name == group.SharedInfraGroup.Name {
+			return true
+		}
+	}
+
+	for _, routeKey := range []string{"permApplicationName", "permPipelineKey", "permEnvironmentName", "permWorkflowName"} {
+		if _, ok := routeVar[routeKey]; ok {
+			return checkObjectPermission(ctx, permissionObjectTypes[routeKey], routeKey, perm, routeVar)
+		}
+	}
+	for _, routeKey := range []string{"permProjectKey", "key"} {
+		if _, ok := routeVar[routeKey]; ok {
+			return checkObjectPermission(ctx, sdk.RoleBindingProject, routeKey, perm, routeVar)
+		}
+	}
+	log.Warning("checkNamedPermission> No project in route to check permission %s", perm)
+	return false
+}
+
 func (api *API) checkProjectPermissions(ctx context.Context, projectKey string, perm int, routeVar map[string]string) bool {
 	if permission.PermissionReadExecute == perm && getService(ctx) != nil {
 		return true
@@ -140,3 +140,48 @@ func AccessToEnvironment(key, env string, u *sdk.User, access int) bool {
 
 	return u.Permissions.EnvironmentsPerm[sdk.UserPermissionKey(key, env)] >= access
 }
+
+// LevelPermissionName returns the named permission equivalent to a permission level on a type of object
+func LevelPermissionName(objectType string, access int) string {
+	switch {
+	case access >= PermissionReadWriteExecute:
+		return sdk.PermissionNameEdit
+	case access >= PermissionReadExecute && objectType == sdk.RoleBindingEnvironment:
+		return sdk.PermissionNameDeploy
+	case access >= PermissionReadExecute:
+		return sdk.PermissionNameRun
+	default:
+		return sdk.PermissionNameRead
+	}
+}
+
+// HasPermission checks if the user has a named permission on a project, or on an application, a pipeline,
+// an environment or a workflow of the project. The permission is given either by the permission level of
+// the groups of the user, or by a role bound on the object or on the project.
+func HasPermission(u *sdk.User, objectType, key, name, perm string) bool {
+	if u.Admin {
+		return true
+	}
+
+	var level int
+	switch objectType {
+	case sdk.RoleBindingProject:
+		level = u.Permissions.ProjectsPerm[key]
+	case sdk.RoleBindingApplication:
+		level = u.Permissions.ApplicationsPerm[sdk.UserPermissionKey(key, name)]
+	case sdk.RoleBindingPipeline:
+		level = u.Permissions.PipelinesPerm[sdk.UserPermissionKey(key, name)]
+	case sdk.RoleBindingEnvironment:
+		level = u.Permissions.EnvironmentsPerm[sdk.UserPermissionKey(key, name)]
+	case sdk.RoleBindingWorkflow:
+		level = u.Permissions.WorkflowsPerm[sdk.UserPermissionKey(key, name)]
+	}
+	if r := sdk.BuiltinRoleByLevel(level); r != nil && r.Has(perm) {
+		return true
+	}
+
+	if u.Permissions.RolesPerm.Has(sdk.RolePermissionKey(objectType, key, name), perm) {
+		return true
+	}
+	return u.Permissions.RolesPerm.Has(sdk.RolePermissionKey(sdk.RoleBindingProject, key, ""), perm)
+}
@@ -0,0 +1,33 @@
+package permission
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestHasPermission(t *testing.T) {
+	u := &sdk.User{
+		Permissions: sdk.UserPermissions{
+			ProjectsPerm:     map[string]int{"PROJ": PermissionRead},
+			EnvironmentsPerm: sdk.UserPermissionsMap{"PROJ/prod": PermissionReadExecute},
+			WorkflowsPerm:    sdk.UserPermissionsMap{"PROJ/w": PermissionRead},
+			RolesPerm: sdk.UserPermissionsRoles{
+				"project:PROJ":    {sdk.PermissionNameApprove},
+				"workflow:PROJ/w": {sdk.PermissionNameRun},
+			},
+		},
+	}
+
+	assert.True(t, HasPermission(u, sdk.RoleBindingEnvironment, "PROJ", "prod", sdk.PermissionNameDeploy))
+	assert.False(t, HasPermission(u, sdk.RoleBindingEnvironment, "PROJ", "prod", sdk.PermissionNameManageVariables))
+	assert.True(t, HasPermission(u, sdk.RoleBindingWorkflow, "PROJ", "w", sdk.PermissionNameRun))
+	assert.False(t, HasPermission(u, sdk.RoleBindingWorkflow, "PROJ", "w", sdk.PermissionNameEdit))
+	assert.True(t, HasPermission(u, sdk.RoleBindingWorkflow, "PROJ", "w", sdk.PermissionNameApprove))
+	assert.False(t, HasPermission(u, sdk.RoleBindingProject, "OTHER", "", sdk.PermissionNameRead))
+
+	u.Admin = true
+	assert.True(t, HasPermission(u, sdk.RoleBindingProject, "OTHER", "", sdk.PermissionNameEdit))
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/event"
 	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/engine/api/role"
 	"github.com/ovh/cds/engine/api/trigger"
 	"github.com/ovh/cds/sdk"
 )
@@ -249,6 +250,16 @@ func DeletePipeline(db gorp.SqlExecutor, pipelineID int64, userID int64) error {
 		return err
 	}
 
+	// Delete role bindings, they are not linked to the pipeline by a foreign key
+	var projectID int64
+	var name string
+	if err := db.QueryRow("SELECT project_id, name FROM pipeline WHERE id = $1", pipelineID).Scan(&projectID, &name); err != nil {
+		return err
+	}
+	if err := role.DeleteBindingsByObject(db, projectID, sdk.RoleBindingPipeline, name); err != nil {
+		return err
+	}
+
 	// Delete pipeline
 	query = `DELETE FROM pipeline WHERE id = $1`
 	if _, err := db.Exec(query, pipelineID); err != nil {
@@ -425,6 +436,16 @@ func UpdatePipeline(db gorp.SqlExecutor, p *sdk.Pipeline) error {
 		return sdk.NewError(sdk.ErrInvalidName, fmt.Errorf("Invalid pipeline name. It should match %s", sdk.NamePattern))
 	}
 
+	// Rename the role bindings, they are matched by name
+	var projectID int64
+	var oldName string
+	if err := db.QueryRow("SELECT project_id, name FROM pipeline WHERE id = $1", p.ID).Scan(&projectID, &oldName); err != nil {
+		return err
+	}
+	if err := role.RenameBindingsObject(db, projectID, sdk.RoleBindingPipeline, oldName, p.Name); err != nil {
+		return err
+	}
+
 	//Update pipeline
 	query := `UPDATE pipeline SET name=$1, description = $2, type=$3 WHERE id=$4`
 	_, err := db.Exec(query, p.Name, p.Description, p.Type, p.ID)
@@ -1,6 +1,8 @@
 package pipeline
 
 import (
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -71,6 +73,15 @@ func selectAllPipelineActionID(db gorp.SqlExecutor, pipelineStageID int64) ([]in
 
 // InsertJob  Insert a new Job ( pipeline_action + joinedAction )
 func InsertJob(db gorp.SqlExecutor, job *sdk.Job, stageID int64, pip *sdk.Pipeline) error {
+	matrix, err := jobMatrixValue(job)
+	if err != nil {
+		return err
+	}
+	retryPolicy, err := action.RetryPolicyValue(job.RetryPolicy, job.Action.Name)
+	if err != nil {
+		return err
+	}
+
 	// Insert Joined Action
 	job.Action.Type = sdk.JoinedAction
 	log.Debug("InsertJob> Insert Action %s on pipeline %s with %d children", job.Action.Name, pip.Name, len(job.Action.Actions))
@@ -103,8 +114,8 @@ func InsertJob(db gorp.SqlExecutor, job *sdk.Job, stageID int64, pip *sdk.Pipeli
 	job.PipelineStageID = stage.ID
 
 	// Create pipeline action
-	query := `INSERT INTO pipeline_action (pipeline_stage_id, action_id, enabled) VALUES ($1, $2, $3) RETURNING id`
-	return db.QueryRow(query, job.PipelineStageID, job.Action.ID, job.Enabled).Scan(&job.PipelineActionID)
+	query := `INSERT INTO pipeline_action (pipeline_stage_id, action_id, enabled, matrix, timeout, retry_policy) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	return db.QueryRow(query, job.PipelineStageID, job.Action.ID, job.Enabled, matrix, job.Timeout, retryPolicy).Scan(&job.PipelineActionID)
 }
 
 // UpdateJob  updates the job by actionData.PipelineActionID and actionData.ID
@@ -132,14 +143,40 @@ func DeleteJob(db gorp.SqlExecutor, job sdk.Job, userID int64) error {
 
 // UpdatePipelineAction Update an action in a pipeline
 func UpdatePipelineAction(db gorp.SqlExecutor, job sdk.Job) error {
-	query := `UPDATE pipeline_action set action_id=$1, pipeline_stage_id=$2, enabled=$3 WHERE id=$4`
-	if _, err := db.Exec(query, job.Action.ID, job.PipelineStageID, job.Enabled, job.PipelineActionID); err != nil {
+	matrix, err := jobMatrixValue(&job)
+	if err != nil {
+		return err
+	}
+	retryPolicy, err := action.RetryPolicyValue(job.RetryPolicy, job.Action.Name)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE pipeline_action set action_id=$1, pipeline_stage_id=$2, enabled=$3, matrix=$4, timeout=$5, retry_policy=$6 WHERE id=$7`
+	if _, err := db.Exec(query, job.Action.ID, job.PipelineStageID, job.Enabled, matrix, job.Timeout, retryPolicy, job.PipelineActionID); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// jobMatrixValue checks the matrix of the job and returns its database value
+func jobMatrixValue(job *sdk.Job) (sql.NullString, error) {
+	if job.Matrix == nil {
+		return sql.NullString{}, nil
+	}
+	if err := job.Matrix.IsValid(); err != nil {
+		httpErr := sdk.ErrInvalidJobMatrix
+		httpErr.Message = fmt.Sprintf("%s %s: %v", httpErr, job.Action.Name, err)
+		return sql.NullString{}, sdk.NewError(httpErr, err)
+	}
+	b, err := json.Marshal(job.Matrix)
+	if err != nil {
+		return sql.NullString{}, sdk.WrapError(err, "jobMatrixValue> cannot marshal matrix")
+	}
+	return sql.NullString{String: string(b), Valid: true}, nil
+}
+
 // DeletePipelineAction Delete an action in a pipeline
 func DeletePipelineAction(db gorp.SqlExecutor, pipelineActionID int64) error {
 
@@ -107,7 +107,8 @@ func LoadPipelineStage(ctx context.Context, db gorp.SqlExecutor, p *sdk.Pipeline
 	SELECT pipeline_stage_R.id as stage_id, pipeline_stage_R.pipeline_id, pipeline_stage_R.name, pipeline_stage_R.last_modified,
 			pipeline_stage_R.build_order, pipeline_stage_R.enabled, pipeline_stage_R.parameter,
 			pipeline_stage_R.expected_value, pipeline_action_R.id as pipeline_action_id, pipeline_action_R.action_id, pipeline_action_R.action_last_modified,
-			pipeline_action_R.action_args, pipeline_action_R.action_enabled
+			pipeline_action_R.action_args, pipeline_action_R.action_enabled, pipeline_action_R.action_matrix,
+			pipeline_action_R.action_timeout, pipeline_action_R.action_retry_policy
 	FROM (
 		SELECT pipeline_stage.id, pipeline_stage.pipeline_id,
 				pipeline_stage.name, pipeline_stage.last_modified, pipeline_stage.build_order,
@@ -120,7 +121,8 @@ func LoadPipelineStage(ctx context.Context, db gorp.SqlExecutor, p *sdk.Pipeline
 	LEFT OUTER JOIN (
 		SELECT pipeline_action.id, action.id as action_id, action.name as action_name, action.last_modified as action_last_modified,
 				pipeline_action.args as action_args, pipeline_action.enabled as action_enabled,
-				pipeline_action.pipeline_stage_id
+				pipeline_action.matrix as action_matrix, pipeline_action.timeout as action_timeout,
+				pipeline_action.retry_policy as action_retry_policy, pipeline_action.pipeline_stage_id
 		FROM action
 		JOIN pipeline_action ON pipeline_action.action_id = action.id
 	) as pipeline_action_R ON pipeline_action_R.pipeline_stage_id = pipeline_stage_R.id
@@ -141,9 +143,9 @@ func LoadPipelineStage(ctx context.Context, db gorp.SqlExecutor, p *sdk.Pipeline
 	for rows.Next() {
 		var stageID, pipelineID int64
 		var stageBuildOrder int
-		var pipelineActionID, actionID sql.NullInt64
+		var pipelineActionID, actionID, actionTimeout sql.NullInt64
 		var stageName string
-		var stagePrerequisiteParameter, stagePrerequisiteExpectedValue, actionArgs sql.NullString
+		var stagePrerequisiteParameter, stagePrerequisiteExpectedValue, actionArgs, actionMatrix, actionRetryPolicy sql.NullString
 		var stageEnabled, actionEnabled sql.NullBool
 		var stageLastModified, actionLastModified pq.NullTime
 
@@ -151,7 +153,7 @@ func LoadPipelineStage(ctx context.Context, db gorp.SqlExecutor, p *sdk.Pipeline
 			&stageID, &pipelineID, &stageName, &stageLastModified,
 			&stageBuildOrder, &stageEnabled, &stagePrerequisiteParameter,
 			&stagePrerequisiteExpectedValue, &pipelineActionID, &actionID, &actionLastModified,
-			&actionArgs, &actionEnabled)
+			&actionArgs, &actionEnabled, &actionMatrix, &actionTimeout, &actionRetryPolicy)
 		if err != nil {
 			return err
 		}
@@ -199,10 +201,23 @@ func LoadPipelineStage(ctx context.Context, db gorp.SqlExecutor, p *sdk.Pipeline
 					PipelineActionID: pipelineActionID.Int64,
 					LastModified:     actionLastModified.Time.Unix(),
 					Enabled:          actionEnabled.Bool,
+					Timeout:          actionTimeout.Int64,
 					Action: sdk.Action{
 						ID: actionID.Int64,
 					},
 				}
+				if actionMatrix.Valid {
+					j.Matrix = new(sdk.JobMatrix)
+					if err := json.Unmarshal([]byte(actionMatrix.String), j.Matrix); err != nil {
+						return sdk.WrapError(err, "LoadPipelineStage> cannot unmarshal matrix of job %d", pipelineActionID.Int64)
+					}
+				}
+				if actionRetryPolicy.Valid {
+					j.RetryPolicy = new(sdk.RetryPolicy)
+					if err := json.Unmarshal([]byte(actionRetryPolicy.String), j.RetryPolicy); err != nil {
+						return sdk.WrapError(err, "LoadPipelineStage> cannot unmarshal retry policy of job %d", pipelineActionID.Int64)
+					}
+				}
 				mapAllActions[pipelineActionID.Int64] = j
 				mapActionsStages[stageID] = append(mapActionsStages[stageID], *j)
 
@@ -152,6 +152,10 @@ func (api *API) updateProjectHandler() service.Handler {
 			return sdk.WrapError(sdk.ErrInvalidProjectName, "updateProject> Project name must no be empty")
 		}
 
+		if proj.JobTimeout < 0 {
+			return sdk.WrapError(sdk.ErrWrongRequest, "updateProject> Job timeout must not be negative")
+		}
+
 		// Check Request
 		if key != proj.Key {
 			return sdk.WrapError(sdk.ErrWrongRequest, "updateProject> bad Project key %s/%s ", key, proj.Key)
@@ -165,6 +169,8 @@ func (api *API) updateProjectHandler() service.Handler {
 		// Update in DB is made given the primary key
 		proj.ID = p.ID
 		proj.VCSServers = p.VCSServers
+		// The Vault role is only updated by the CDS administrators, with putProjectVaultRoleHandler
+		proj.VaultRole = p.VaultRole
 		if proj.Icon == "" {
 			p.Icon = proj.Icon
 		}
@@ -176,6 +182,32 @@ func (api *API) updateProjectHandler() service.Handler {
 	}
 }
 
+// putProjectVaultRoleHandler updates the Vault role of the project, the role gives access to the secrets of Vault
+// so it is only set by the CDS administrators
+func (api *API) putProjectVaultRoleHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)["permProjectKey"]
+
+		var req sdk.Project
+		if err := UnmarshalBody(r, &req); err != nil {
+			return sdk.WrapError(err, "putProjectVaultRoleHandler> Unmarshall error")
+		}
+
+		p, err := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "putProjectVaultRoleHandler> Cannot load project %s", key)
+		}
+		if err := project.UpdateVaultRole(api.mustDB(), p.ID, req.VaultRole); err != nil {
+			return err
+		}
+
+		newProj := *p
+		newProj.VaultRole = req.VaultRole
+		event.PublishUpdateProject(&newProj, p, getUser(ctx))
+		return service.WriteJSON(w, newProj, http.StatusOK)
+	}
+}
+
 func (api *API) getProjectHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		// Get project name in URL
@@ -362,6 +394,11 @@ func (api *API) addProjectHandler() service.Handler {
 			return sdk.WrapError(sdk.ErrInvalidProjectName, "addProjectHandler> Project name must no be empty")
 		}
 
+		// The Vault role is only set by the CDS administrators
+		if !getUser(ctx).Admin {
+			p.VaultRole = ""
+		}
+
 		// Check that project does not already exists
 		exist, errExist := project.Exist(api.mustDB(), p.Key)
 		if errExist != nil {
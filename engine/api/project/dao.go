@@ -216,6 +216,12 @@ func Update(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, u *sdk.Us
 	return nil
 }
 
+// UpdateVaultRole updates the Vault role used to resolve the vault variables of the project
+func UpdateVaultRole(db gorp.SqlExecutor, projectID int64, role string) error {
+	_, err := db.Exec("UPDATE project SET vault_role = $2, last_modified = $3 WHERE id = $1", projectID, role, time.Now())
+	return sdk.WrapError(err, "UpdateVaultRole> Cannot update vault role of project %d", projectID)
+}
+
 // DeleteByID removes given project from database (project and project_group table)
 // DeleteByID also removes all pipelines inside project (pipeline and pipeline_group table).
 func DeleteByID(db gorp.SqlExecutor, id int64) error {
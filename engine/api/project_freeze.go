@@ -0,0 +1,223 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/freeze"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// checkFreezeWindow checks the freeze window sent by the user and resolves its environment
+func checkFreezeWindow(db gorp.SqlExecutor, p *sdk.Project, f *sdk.FreezeWindow) error {
+	if err := freeze.CheckValidity(*f); err != nil {
+		return err
+	}
+	f.ProjectID = p.ID
+	f.EnvironmentID = 0
+	if f.EnvironmentName != "" {
+		env, err := environment.LoadEnvironmentByName(db, p.Key, f.EnvironmentName)
+		if err != nil {
+			return sdk.WrapError(err, "checkFreezeWindow> Cannot load environment %s", f.EnvironmentName)
+		}
+		f.EnvironmentID = env.ID
+	}
+	return nil
+}
+
+// checkFreezeWindowActive returns true if the freeze window is active, only the CDS administrators can update or
+// delete an active freeze window
+func checkFreezeWindowActive(u *sdk.User, f sdk.FreezeWindow) (bool, error) {
+	active, err := freeze.IsActive(f, time.Now())
+	if err != nil {
+		return false, err
+	}
+	if active && !u.Admin {
+		return true, sdk.ErrFreezeWindowActive
+	}
+	return active, nil
+}
+
+func (api *API) getFreezeWindowsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)["permProjectKey"]
+
+		p, err := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "getFreezeWindowsHandler> Cannot load project %s", key)
+		}
+
+		fs, err := freeze.LoadAllByProjectID(api.mustDB(), p.ID)
+		if err != nil {
+			return sdk.WrapError(err, "getFreezeWindowsHandler> Cannot load freeze windows")
+		}
+		return service.WriteJSON(w, fs, http.StatusOK)
+	}
+}
+
+func (api *API) postFreezeWindowHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)["permProjectKey"]
+
+		p, err := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "postFreezeWindowHandler> Cannot load project %s", key)
+		}
+
+		var f sdk.FreezeWindow
+		if err := UnmarshalBody(r, &f); err != nil {
+			return err
+		}
+		if err := checkFreezeWindow(api.mustDB(), p, &f); err != nil {
+			return err
+		}
+
+		if err := freeze.Insert(api.mustDB(), &f, getUser(ctx)); err != nil {
+			return sdk.WrapError(err, "postFreezeWindowHandler> Cannot insert freeze window")
+		}
+		return service.WriteJSON(w, f, http.StatusOK)
+	}
+}
+
+func (api *API) putFreezeWindowHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)["permProjectKey"]
+		id, err := requestVarInt(r, "id")
+		if err != nil {
+			return err
+		}
+
+		p, err := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "putFreezeWindowHandler> Cannot load project %s", key)
+		}
+
+		old, err := freeze.LoadByID(api.mustDB(), p.ID, id)
+		if err != nil {
+			return sdk.WrapError(err, "putFreezeWindowHandler> Cannot load freeze window %d", id)
+		}
+		active, err := checkFreezeWindowActive(getUser(ctx), *old)
+		if err != nil {
+			return err
+		}
+
+		var f sdk.FreezeWindow
+		if err := UnmarshalBody(r, &f); err != nil {
+			return err
+		}
+		if err := checkFreezeWindow(api.mustDB(), p, &f); err != nil {
+			return err
+		}
+		f.ID = old.ID
+		f.Author = old.Author
+		f.Created = old.Created
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "putFreezeWindowHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		if err := freeze.InsertAudit(tx, *old, sdk.FreezeWindowAuditUpdate, active, getUser(ctx)); err != nil {
+			return err
+		}
+		if err := freeze.Update(tx, &f); err != nil {
+			return sdk.WrapError(err, "putFreezeWindowHandler> Cannot update freeze window %d", id)
+		}
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "putFreezeWindowHandler> Cannot commit transaction")
+		}
+		return service.WriteJSON(w, f, http.StatusOK)
+	}
+}
+
+func (api *API) deleteFreezeWindowHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)["permProjectKey"]
+		id, err := requestVarInt(r, "id")
+		if err != nil {
+			return err
+		}
+
+		p, err := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "deleteFreezeWindowHandler> Cannot load project %s", key)
+		}
+
+		f, err := freeze.LoadByID(api.mustDB(), p.ID, id)
+		if err != nil {
+			return sdk.WrapError(err, "deleteFreezeWindowHandler> Cannot load freeze window %d", id)
+		}
+		active, err := checkFreezeWindowActive(getUser(ctx), *f)
+		if err != nil {
+			return err
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "deleteFreezeWindowHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		if err := freeze.InsertAudit(tx, *f, sdk.FreezeWindowAuditDelete, active, getUser(ctx)); err != nil {
+			return err
+		}
+		if err := freeze.Delete(tx, f); err != nil {
+			return sdk.WrapError(err, "deleteFreezeWindowHandler> Cannot delete freeze window %d", id)
+		}
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "deleteFreezeWindowHandler> Cannot commit transaction")
+		}
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
+
+func (api *API) getFreezeWindowOverridesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)["permProjectKey"]
+		id, err := requestVarInt(r, "id")
+		if err != nil {
+			return err
+		}
+
+		p, err := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "getFreezeWindowOverridesHandler> Cannot load project %s", key)
+		}
+
+		f, err := freeze.LoadByID(api.mustDB(), p.ID, id)
+		if err != nil {
+			return sdk.WrapError(err, "getFreezeWindowOverridesHandler> Cannot load freeze window %d", id)
+		}
+
+		overrides, err := freeze.LoadOverrides(api.mustDB(), f.ID)
+		if err != nil {
+			return sdk.WrapError(err, "getFreezeWindowOverridesHandler> Cannot load overrides")
+		}
+		return service.WriteJSON(w, overrides, http.StatusOK)
+	}
+}
+
+func (api *API) getFreezeWindowAuditsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)["permProjectKey"]
+
+		p, err := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "getFreezeWindowAuditsHandler> Cannot load project %s", key)
+		}
+
+		audits, err := freeze.LoadAuditsByProjectID(api.mustDB(), p.ID)
+		if err != nil {
+			return sdk.WrapError(err, "getFreezeWindowAuditsHandler> Cannot load audits")
+		}
+		return service.WriteJSON(w, audits, http.StatusOK)
+	}
+}
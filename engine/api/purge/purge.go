@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
 
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/project"
@@ -36,6 +37,16 @@ func Initialize(c context.Context, store cache.Store, DBFunc func() *gorp.DbMap)
 			if err := Workflows(c, DBFunc(), store); err != nil {
 				log.Warning("purge> Error on workflows : %v", err)
 			}
+
+			log.Debug("purge> Deleting all unreferenced artifact blobs...")
+			if err := workflow.PurgeArtifactBlobs(DBFunc()); err != nil {
+				log.Warning("purge> Error on artifact blobs : %v", err)
+			}
+
+			log.Debug("purge> Deleting logs of deleted workflow runs...")
+			if err := workflow.PurgeLogs(DBFunc()); err != nil {
+				log.Warning("purge> Error on logs : %v", err)
+			}
 		}
 	}
 }
@@ -106,9 +117,21 @@ func Workflows(ctx context.Context, db *gorp.DbMap, store cache.Store) error {
 
 // deleteWorkflowRunsHistory is useful to delete all the workflow run marked with to delete flag in db
 func deleteWorkflowRunsHistory(db gorp.SqlExecutor) error {
-	query := `DELETE FROM workflow_run WHERE workflow_run.id IN (SELECT id FROM workflow_run WHERE to_delete = true LIMIT 30)`
+	var ids []int64
+	if _, err := db.Select(&ids, "SELECT id FROM workflow_run WHERE to_delete = true LIMIT 30"); err != nil {
+		log.Warning("deleteWorkflowRunsHistory> Unable to load workflow history %s", err)
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := workflow.DeleteRunArtifactObjects(db, ids); err != nil {
+		log.Warning("deleteWorkflowRunsHistory> Unable to delete artifacts %s", err)
+		return err
+	}
 
-	if _, err := db.Exec(query); err != nil {
+	if _, err := db.Exec("DELETE FROM workflow_run WHERE id = ANY($1)", pq.Int64Array(ids)); err != nil {
 		log.Warning("deleteWorkflowRunsHistory> Unable to delete workflow history %s", err)
 		return err
 	}
@@ -328,6 +328,15 @@ func (c *vcsClient) Commits(ctx context.Context, fullname, branch, since, until
 	return commits, nil
 }
 
+func (c *vcsClient) ChangedFiles(ctx context.Context, fullname, base, head string) ([]string, error) {
+	var files []string
+	path := fmt.Sprintf("/vcs/%s/repos/%s/files?base=%s&head=%s", c.name, fullname, url.QueryEscape(base), url.QueryEscape(head))
+	if _, err := c.doJSONRequest(ctx, "GET", path, nil, &files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
 func (c *vcsClient) CommitsBetweenRefs(ctx context.Context, fullname, base, head string) ([]sdk.VCSCommit, error) {
 	var commits []sdk.VCSCommit
 	path := fmt.Sprintf("/vcs/%s/repos/%s/commits?base=%s&head=%s", c.name, fullname, url.QueryEscape(base), url.QueryEscape(head))
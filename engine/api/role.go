@@ -0,0 +1,183 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/role"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func (api *API) getRolesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		roles, err := role.LoadAll(api.mustDB())
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, roles, http.StatusOK)
+	}
+}
+
+func (api *API) postRoleHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var rl sdk.Role
+		if err := UnmarshalBody(r, &rl); err != nil {
+			return err
+		}
+		if err := rl.IsValid(); err != nil {
+			return err
+		}
+		if _, err := role.LoadByName(api.mustDB(), rl.Name); err == nil {
+			return sdk.NewError(sdk.ErrInvalidRole, fmt.Errorf("role %s already exists", rl.Name))
+		}
+
+		if err := role.Insert(api.mustDB(), &rl); err != nil {
+			return err
+		}
+		log.Info("postRoleHandler> Role %s created by %s with permissions %v", rl.Name, getUser(ctx).Username, rl.Permissions)
+		return service.WriteJSON(w, rl, http.StatusCreated)
+	}
+}
+
+func (api *API) putRoleHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var rl sdk.Role
+		if err := UnmarshalBody(r, &rl); err != nil {
+			return err
+		}
+		rl.Name = mux.Vars(r)["name"]
+		if err := rl.IsValid(); err != nil {
+			return err
+		}
+
+		if err := role.Update(api.mustDB(), &rl); err != nil {
+			return err
+		}
+		log.Info("putRoleHandler> Role %s updated by %s with permissions %v", rl.Name, getUser(ctx).Username, rl.Permissions)
+		return service.WriteJSON(w, rl, http.StatusOK)
+	}
+}
+
+func (api *API) deleteRoleHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := mux.Vars(r)["name"]
+		if sdk.BuiltinRole(name) != nil {
+			return sdk.NewError(sdk.ErrInvalidRole, fmt.Errorf("%s is a built-in role", name))
+		}
+		if err := role.Delete(api.mustDB(), name); err != nil {
+			return err
+		}
+		log.Info("deleteRoleHandler> Role %s deleted by %s", name, getUser(ctx).Username)
+		return nil
+	}
+}
+
+func (api *API) getProjectRoleBindingsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)["permProjectKey"]
+		p, err := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "getProjectRoleBindingsHandler> Unable to load project %s", key)
+		}
+
+		bs, err := role.LoadBindingsByProject(api.mustDB(), p.ID)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, bs, http.StatusOK)
+	}
+}
+
+func (api *API) postProjectRoleBindingHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)["permProjectKey"]
+		p, err := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "postProjectRoleBindingHandler> Unable to load project %s", key)
+		}
+
+		var b sdk.RoleBinding
+		if err := UnmarshalBody(r, &b); err != nil {
+			return err
+		}
+		if err := b.IsValid(); err != nil {
+			return err
+		}
+		if err := api.checkRoleBindingObject(p.Key, b); err != nil {
+			return err
+		}
+		g, err := group.LoadGroup(api.mustDB(), b.GroupName)
+		if err != nil {
+			return sdk.WrapError(err, "postProjectRoleBindingHandler> Unable to load group %s", b.GroupName)
+		}
+		b.GroupID = g.ID
+		b.ProjectID = p.ID
+		b.ProjectKey = p.Key
+
+		if err := role.InsertBinding(api.mustDB(), &b); err != nil {
+			return err
+		}
+		log.Info("postProjectRoleBindingHandler> Role %s bound to group %s on %s %s/%s by %s", b.RoleName, b.GroupName, b.ObjectType, p.Key, b.ObjectName, getUser(ctx).Username)
+		return service.WriteJSON(w, b, http.StatusCreated)
+	}
+}
+
+func (api *API) deleteProjectRoleBindingHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)["permProjectKey"]
+		id, err := requestVarInt(r, "id")
+		if err != nil {
+			return err
+		}
+		p, err := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "deleteProjectRoleBindingHandler> Unable to load project %s", key)
+		}
+
+		if err := role.DeleteBinding(api.mustDB(), p.ID, id); err != nil {
+			return err
+		}
+		log.Info("deleteProjectRoleBindingHandler> Role binding %d of project %s deleted by %s", id, p.Key, getUser(ctx).Username)
+		return nil
+	}
+}
+
+// checkRoleBindingObject checks that the object of a role binding exists in the project
+func (api *API) checkRoleBindingObject(key string, b sdk.RoleBinding) error {
+	var exists bool
+	var err error
+	switch b.ObjectType {
+	case sdk.RoleBindingProject:
+		return nil
+	case sdk.RoleBindingApplication:
+		exists, err = application.Exists(api.mustDB(), key, b.ObjectName)
+	case sdk.RoleBindingEnvironment:
+		exists, err = environment.Exists(api.mustDB(), key, b.ObjectName)
+	case sdk.RoleBindingWorkflow:
+		exists, err = workflow.Exists(api.mustDB(), key, b.ObjectName)
+	case sdk.RoleBindingPipeline:
+		_, err = pipeline.LoadPipeline(api.mustDB(), key, b.ObjectName, false)
+		exists = err == nil
+		if sdk.ErrorIs(err, sdk.ErrPipelineNotFound) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return sdk.WrapError(err, "checkRoleBindingObject> Unable to load %s %s", b.ObjectType, b.ObjectName)
+	}
+	if !exists {
+		return sdk.NewError(sdk.ErrInvalidRole, fmt.Errorf("%s %s not found in project %s", b.ObjectType, b.ObjectName, key))
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package role
+
+import (
+	"database/sql"
+
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// LoadAll loads the built-in roles and the roles defined by the administrators
+func LoadAll(db gorp.SqlExecutor) ([]sdk.Role, error) {
+	rows, err := db.Query("SELECT id, name, description, permissions FROM role ORDER BY name")
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadAll> Unable to load roles")
+	}
+	defer rows.Close()
+
+	roles := sdk.BuiltinRoles()
+	for rows.Next() {
+		var r sdk.Role
+		if err := rows.Scan(&r.ID, &r.Name, &r.Description, (*pq.StringArray)(&r.Permissions)); err != nil {
+			return nil, sdk.WrapError(err, "LoadAll> Unable to scan role")
+		}
+		roles = append(roles, r)
+	}
+	return roles, nil
+}
+
+// LoadByName loads a built-in role or a role defined by the administrators
+func LoadByName(db gorp.SqlExecutor, name string) (*sdk.Role, error) {
+	if r := sdk.BuiltinRole(name); r != nil {
+		return r, nil
+	}
+	var r sdk.Role
+	if err := db.QueryRow("SELECT id, name, description, permissions FROM role WHERE name = $1", name).
+		Scan(&r.ID, &r.Name, &r.Description, (*pq.StringArray)(&r.Permissions)); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrRoleNotFound
+		}
+		return nil, sdk.WrapError(err, "LoadByName> Unable to load role %s", name)
+	}
+	return &r, nil
+}
+
+// Insert inserts a role
+func Insert(db gorp.SqlExecutor, r *sdk.Role) error {
+	if err := db.QueryRow("INSERT INTO role (name, description, permissions) VALUES ($1, $2, $3) RETURNING id",
+		r.Name, r.Description, pq.StringArray(r.Permissions)).Scan(&r.ID); err != nil {
+		return sdk.WrapError(err, "Insert> Unable to insert role %s", r.Name)
+	}
+	return nil
+}
+
+// Update updates the description and the permissions of a role
+func Update(db gorp.SqlExecutor, r *sdk.Role) error {
+	res, err := db.Exec("UPDATE role SET description = $2, permissions = $3 WHERE name = $1", r.Name, r.Description, pq.StringArray(r.Permissions))
+	if err != nil {
+		return sdk.WrapError(err, "Update> Unable to update role %s", r.Name)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sdk.ErrRoleNotFound
+	}
+	return nil
+}
+
+// Delete deletes a role which is not bound to any group
+func Delete(db gorp.SqlExecutor, name string) error {
+	n, err := db.SelectInt("SELECT COUNT(*) FROM role_binding WHERE role_name = $1", name)
+	if err != nil {
+		return sdk.WrapError(err, "Delete> Unable to count bindings of role %s", name)
+	}
+	if n > 0 {
+		return sdk.ErrRoleUsed
+	}
+	if _, err := db.Exec("DELETE FROM role WHERE name = $1", name); err != nil {
+		return sdk.WrapError(err, "Delete> Unable to delete role %s", name)
+	}
+	return nil
+}
+
+const bindingQuery = `
+	SELECT role_binding.id, role_binding.role_name, role_binding.group_id, "group".name,
+		role_binding.project_id, project.projectkey, role_binding.object_type, role_binding.object_name
+	FROM role_binding
+	JOIN "group" ON "group".id = role_binding.group_id
+	JOIN project ON project.id = role_binding.project_id`
+
+func loadBindings(db gorp.SqlExecutor, query string, args ...interface{}) ([]sdk.RoleBinding, error) {
+	rows, err := db.Query(bindingQuery+" "+query, args...)
+	if err != nil {
+		return nil, sdk.WrapError(err, "loadBindings> Unable to load role bindings")
+	}
+	defer rows.Close()
+
+	bs := []sdk.RoleBinding{}
+	for rows.Next() {
+		var b sdk.RoleBinding
+		if err := rows.Scan(&b.ID, &b.RoleName, &b.GroupID, &b.GroupName, &b.ProjectID, &b.ProjectKey, &b.ObjectType, &b.ObjectName); err != nil {
+			return nil, sdk.WrapError(err, "loadBindings> Unable to scan role binding")
+		}
+		bs = append(bs, b)
+	}
+	return bs, nil
+}
+
+// LoadBindingsByProject loads the role bindings of a project and of its objects
+func LoadBindingsByProject(db gorp.SqlExecutor, projectID int64) ([]sdk.RoleBinding, error) {
+	return loadBindings(db, "WHERE role_binding.project_id = $1 ORDER BY role_binding.object_type, role_binding.object_name, \"group\".name", projectID)
+}
+
+// LoadBindingsByObject loads the role bindings of an object of a project
+func LoadBindingsByObject(db gorp.SqlExecutor, projectID int64, objectType, objectName string) ([]sdk.RoleBinding, error) {
+	return loadBindings(db, "WHERE role_binding.project_id = $1 AND role_binding.object_type = $2 AND role_binding.object_name = $3 ORDER BY \"group\".name",
+		projectID, objectType, objectName)
+}
+
+// InsertBinding binds a role to a group, the role must exist
+func InsertBinding(db gorp.SqlExecutor, b *sdk.RoleBinding) error {
+	if _, err := LoadByName(db, b.RoleName); err != nil {
+		return sdk.WrapError(err, "InsertBinding> Unable to load role %s", b.RoleName)
+	}
+	query := `
+	INSERT INTO role_binding (role_name, group_id, project_id, object_type, object_name)
+	VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	if err := db.QueryRow(query, b.RoleName, b.GroupID, b.ProjectID, b.ObjectType, b.ObjectName).Scan(&b.ID); err != nil {
+		return sdk.WrapError(err, "InsertBinding> Unable to bind role %s to group %d", b.RoleName, b.GroupID)
+	}
+	return nil
+}
+
+// DeleteBinding deletes a role binding of a project
+func DeleteBinding(db gorp.SqlExecutor, projectID, id int64) error {
+	res, err := db.Exec("DELETE FROM role_binding WHERE project_id = $1 AND id = $2", projectID, id)
+	if err != nil {
+		return sdk.WrapError(err, "DeleteBinding> Unable to delete role binding %d", id)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sdk.ErrRoleNotFound
+	}
+	return nil
+}
+
+// DeleteBindingsByObject deletes the role bindings of an object of a project
+func DeleteBindingsByObject(db gorp.SqlExecutor, projectID int64, objectType, objectName string) error {
+	if _, err := db.Exec("DELETE FROM role_binding WHERE project_id = $1 AND object_type = $2 AND object_name = $3", projectID, objectType, objectName); err != nil {
+		return sdk.WrapError(err, "DeleteBindingsByObject> Unable to delete role bindings of %s %s", objectType, objectName)
+	}
+	return nil
+}
+
+// RenameBindingsObject updates the name of an object of a project in its role bindings, which are matched by name
+func RenameBindingsObject(db gorp.SqlExecutor, projectID int64, objectType, oldName, newName string) error {
+	if oldName == newName {
+		return nil
+	}
+	query := "UPDATE role_binding SET object_name = $4 WHERE project_id = $1 AND object_type = $2 AND object_name = $3"
+	if _, err := db.Exec(query, projectID, objectType, oldName, newName); err != nil {
+		return sdk.WrapError(err, "RenameBindingsObject> Unable to rename role bindings of %s %s", objectType, oldName)
+	}
+	return nil
+}
+
+// LoadPermissionsByGroup loads the named permissions given to a group by its role bindings
+func LoadPermissionsByGroup(db gorp.SqlExecutor, groupID int64) (sdk.UserPermissionsRoles, error) {
+	bs, err := loadBindings(db, "WHERE role_binding.group_id = $1", groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	perms := sdk.UserPermissionsRoles{}
+	roles := map[string]*sdk.Role{}
+	for _, b := range bs {
+		r, ok := roles[b.RoleName]
+		if !ok {
+			r, err = LoadByName(db, b.RoleName)
+			if err != nil {
+				return nil, sdk.WrapError(err, "LoadPermissionsByGroup> Unable to load role %s", b.RoleName)
+			}
+			roles[b.RoleName] = r
+		}
+		k := sdk.RolePermissionKey(b.ObjectType, b.ProjectKey, b.ObjectName)
+		perms.Add(k, r.Permissions...)
+	}
+	return perms, nil
+}
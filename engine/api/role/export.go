@@ -0,0 +1,60 @@
+package role
+
+import (
+	"sort"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/sdk"
+)
+
+// ExportBindings returns the names of the roles bound to each group on an object of a project
+func ExportBindings(db gorp.SqlExecutor, projectID int64, objectType, objectName string) (map[string][]string, error) {
+	bs, err := LoadBindingsByObject(db, projectID, objectType, objectName)
+	if err != nil {
+		return nil, err
+	}
+	if len(bs) == 0 {
+		return nil, nil
+	}
+	roles := make(map[string][]string, len(bs))
+	for _, b := range bs {
+		roles[b.GroupName] = append(roles[b.GroupName], b.RoleName)
+	}
+	for g := range roles {
+		sort.Strings(roles[g])
+	}
+	return roles, nil
+}
+
+// ImportBindings replaces the role bindings of an object of a project by the roles given for each group.
+// Nothing is done if roles is nil, so that the bindings are kept when they are not exported.
+func ImportBindings(db gorp.SqlExecutor, projectID int64, objectType, objectName string, roles map[string][]string) error {
+	if roles == nil {
+		return nil
+	}
+	if err := DeleteBindingsByObject(db, projectID, objectType, objectName); err != nil {
+		return err
+	}
+	for groupName, names := range roles {
+		g, err := group.LoadGroup(db, groupName)
+		if err != nil {
+			return sdk.WrapError(err, "ImportBindings> Unable to load group %s", groupName)
+		}
+		for _, n := range names {
+			b := sdk.RoleBinding{
+				RoleName:   n,
+				GroupID:    g.ID,
+				GroupName:  g.Name,
+				ProjectID:  projectID,
+				ObjectType: objectType,
+				ObjectName: objectName,
+			}
+			if err := InsertBinding(db, &b); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -416,6 +416,14 @@ func NeedAdmin(admin bool) HandlerConfigParam {
 	return f
 }
 
+// NeedPermission checks a named permission on the route instead of the permission level given by the HTTP method
+func NeedPermission(name string) HandlerConfigParam {
+	f := func(rc *service.HandlerConfig) {
+		rc.Options["permission"] = name
+	}
+	return f
+}
+
 // AllowProvider set the route for external providers
 func AllowProvider(need bool) HandlerConfigParam {
 	f := func(rc *service.HandlerConfig) {
@@ -472,6 +480,14 @@ func AllowServices(s bool) HandlerConfigParam {
 	return f
 }
 
+// AllowProjectAccessToken allows the access tokens restricted to a project to use this route, which has no project
+func AllowProjectAccessToken(s bool) HandlerConfigParam {
+	f := func(rc *service.HandlerConfig) {
+		rc.Options["allowProjectAccessToken"] = fmt.Sprintf("%v", s)
+	}
+	return f
+}
+
 // Auth set manually whether authorisation layer should be applied
 // Authorization is enabled by default
 func Auth(v bool) HandlerConfigParam {
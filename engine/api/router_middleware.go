@@ -66,6 +66,13 @@ func (api *API) authMiddleware(ctx context.Context, w http.ResponseWriter, req *
 			}
 		default:
 			var err error
+			if auth.HasAccessToken(headers) {
+				ctx, err = auth.CheckAccessTokenAuth(ctx, api.mustDB(), headers)
+				if err != nil {
+					return ctx, sdk.WrapError(sdk.ErrUnauthorized, "Router> Authorization denied on %s %s for %s access token agent %s : %s", req.Method, req.URL, req.RemoteAddr, getAgent(req), err)
+				}
+				break
+			}
 			ctx, err = api.Router.AuthDriver.CheckAuth(ctx, w, req)
 			if err != nil {
 				return ctx, sdk.WrapError(sdk.ErrUnauthorized, "Router> Authorization denied on %s %s for %s agent %s : %s", req.Method, req.URL, req.RemoteAddr, getAgent(req), err)
@@ -121,6 +128,9 @@ func (api *API) authMiddleware(ctx context.Context, w http.ResponseWriter, req *
 		if err := loadUserPermissions(api.mustDB(), api.Cache, getUser(ctx)); err != nil {
 			return ctx, sdk.WrapError(sdk.ErrUnauthorized, "Router> Unable to load user %d permission: %v", getUser(ctx).ID, err)
 		}
+		if t := getAccessToken(ctx); t != nil {
+			restrictToAccessToken(getUser(ctx), t)
+		}
 	}
 
 	if rc.Options["auth"] != "true" {
@@ -147,12 +157,25 @@ func (api *API) authMiddleware(ctx context.Context, w http.ResponseWriter, req *
 		return ctx, nil
 	}
 
+	if t := getAccessToken(ctx); t != nil {
+		err := checkAccessTokenScope(t, rc, req.Method, mux.Vars(req))
+		api.insertAccessTokenUse(t, getUser(ctx), req, err == nil)
+		if err != nil {
+			return ctx, sdk.WrapError(err, "Router> Access token %d not allowed on %s %s", t.ID, req.Method, req.URL)
+		}
+	}
+
 	if getUser(ctx).Admin {
 		return ctx, nil
 	}
 
 	if rc.Options["needAdmin"] != "true" {
-		permissionOk := api.checkPermission(ctx, mux.Vars(req), getPermissionByMethod(req.Method, rc.Options["isExecution"] == "true"))
+		var permissionOk bool
+		if name, ok := rc.Options["permission"]; ok {
+			permissionOk = api.checkNamedPermission(ctx, mux.Vars(req), name)
+		} else {
+			permissionOk = api.checkPermission(ctx, mux.Vars(req), getPermissionByMethod(req.Method, rc.Options["isExecution"] == "true"))
+		}
 		if !permissionOk {
 			return ctx, sdk.WrapError(sdk.ErrForbidden, "Router> User not authorized")
 		}
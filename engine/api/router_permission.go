@@ -11,6 +11,7 @@ import (
 	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/role"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
 )
@@ -84,6 +85,17 @@ func loadGroupPermissionInUser(db gorp.SqlExecutor, groupID int64, u *sdk.User)
 			u.Permissions.WorkflowsPerm[k] = p.Permission
 		}
 	}
+
+	permRoles, err := role.LoadPermissionsByGroup(db, groupID)
+	if err != nil {
+		return sdk.WrapError(err, "loadUserPermissions> Unable to load role permissions for  %s", u.Username)
+	}
+	if u.Permissions.RolesPerm == nil {
+		u.Permissions.RolesPerm = make(sdk.UserPermissionsRoles, len(permRoles))
+	}
+	for k, perms := range permRoles {
+		u.Permissions.RolesPerm.Add(k, perms...)
+	}
 	return nil
 }
 
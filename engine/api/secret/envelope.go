@@ -0,0 +1,150 @@
+package secret
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Data encrypted with a master key is prefixed by envelopePrefix and the ID of the master key,
+// followed by the data key encrypted with the master key, then by the data encrypted with the data key.
+const (
+	envelopePrefix = "CDSv2:"
+	dataKeySize    = 2 * ckeySize
+	wrappedKeySize = nonceSize + dataKeySize + macSize
+)
+
+var (
+	masterKeys   map[string][]byte
+	currentKeyID string
+	keyIDPattern = regexp.MustCompile("^[a-zA-Z0-9._-]{1,64}$")
+)
+
+// InitKeys sets the master keys used for envelope encryption, by key ID, and the ID of the master key
+// encrypting the new secrets. If currentID is empty, the new secrets are encrypted with the legacy key.
+func InitKeys(keys map[string]string, currentID string) error {
+	mk := make(map[string][]byte, len(keys))
+	for id, k := range keys {
+		if !keyIDPattern.MatchString(id) {
+			return fmt.Errorf("invalid key ID %s, it should match %s", id, keyIDPattern)
+		}
+		if len(k) < ckeySize {
+			return fmt.Errorf("key %s is too short, it should have at least %d characters", id, ckeySize)
+		}
+		h := sha512.Sum512([]byte(k))
+		mk[id] = h[:]
+	}
+	if _, ok := mk[currentID]; currentID != "" && !ok {
+		return fmt.Errorf("unknown key ID %s", currentID)
+	}
+	masterKeys = mk
+	currentKeyID = currentID
+	return nil
+}
+
+// CurrentKeyID returns the ID of the master key encrypting the new secrets, empty if the legacy key is used
+func CurrentKeyID() string {
+	return currentKeyID
+}
+
+// KeyPrefix returns the prefix of the data encrypted with a master key, or with the legacy key if keyID is empty
+func KeyPrefix(keyID string) string {
+	if keyID == "" {
+		return prefix
+	}
+	return envelopePrefix + keyID + ":"
+}
+
+// encryptEnvelope encrypts data with a new data key, encrypted with the current master key
+func encryptEnvelope(data []byte) ([]byte, error) {
+	dek := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return nil, err
+	}
+	wrapped, err := seal(masterKeys[currentKeyID], dek)
+	if err != nil {
+		return nil, err
+	}
+	ct, err := seal(dek, data)
+	if err != nil {
+		return nil, err
+	}
+
+	res := append([]byte(KeyPrefix(currentKeyID)), wrapped...)
+	return append(res, ct...), nil
+}
+
+// decryptEnvelope decrypts the data key with the master key recorded in data, then the data
+func decryptEnvelope(data []byte) ([]byte, error) {
+	data = data[len(envelopePrefix):]
+	i := bytes.IndexByte(data, ':')
+	if i <= 0 {
+		return nil, sdk.ErrInvalidSecretFormat
+	}
+	id := string(data[:i])
+	kek, ok := masterKeys[id]
+	if !ok {
+		log.Error("Missing master key %s", id)
+		return nil, sdk.ErrSecretKeyFetchFailed
+	}
+
+	data = data[i+1:]
+	if len(data) < wrappedKeySize {
+		log.Error("cannot decrypt secret, got invalid data")
+		return nil, sdk.ErrInvalidSecretFormat
+	}
+	dek, err := open(kek, data[:wrappedKeySize])
+	if err != nil {
+		return nil, err
+	}
+	return open(dek, data[wrappedKeySize:])
+}
+
+// seal encrypts data using aes+hmac algorithm, the first half of k is the aes key and the second half the hmac key
+func seal(k, data []byte) ([]byte, error) {
+	nonce := make([]byte, nonceSize)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	c, err := aes.NewCipher(k[:ckeySize])
+	if err != nil {
+		return nil, err
+	}
+	ct := make([]byte, len(data))
+	cipher.NewCTR(c, nonce).XORKeyStream(ct, data)
+
+	ct = append(nonce, ct...)
+	h := hmac.New(sha256.New, k[ckeySize:])
+	h.Write(ct)
+	return h.Sum(ct), nil
+}
+
+// open decrypts data encrypted by seal
+func open(k, data []byte) ([]byte, error) {
+	if len(data) < nonceSize+macSize {
+		return nil, sdk.ErrInvalidSecretFormat
+	}
+	macStart := len(data) - macSize
+	h := hmac.New(sha256.New, k[ckeySize:])
+	h.Write(data[:macStart])
+	if !hmac.Equal(h.Sum(nil), data[macStart:]) {
+		return nil, fmt.Errorf("invalid hmac")
+	}
+	c, err := aes.NewCipher(k[:ckeySize])
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, macStart-nonceSize)
+	cipher.NewCTR(c, data[:nonceSize]).XORKeyStream(out, data[nonceSize:macStart])
+	return out, nil
+}
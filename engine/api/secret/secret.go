@@ -76,7 +76,11 @@ func (secret *Secret) GetFromVault(s string) (string, error) {
 
 // Encrypt data using aes+hmac algorithm
 // Init() must be called before any encryption
+// If a current master key is set by InitKeys(), data is encrypted with a new data key, itself encrypted with the master key
 func Encrypt(data []byte) ([]byte, error) {
+	if currentKeyID != "" {
+		return encryptEnvelope(data)
+	}
 	// Check key is ready
 	if key == nil {
 		log.Error("Missing key, init failed?")
@@ -107,7 +111,11 @@ func Encrypt(data []byte) ([]byte, error) {
 
 // Decrypt data using aes+hmac algorithm
 // Init() must be called before any decryption
+// Data encrypted with a master key is decrypted with the master key recorded in it, set by InitKeys()
 func Decrypt(data []byte) ([]byte, error) {
+	if strings.HasPrefix(string(data), envelopePrefix) {
+		return decryptEnvelope(data)
+	}
 
 	if !strings.HasPrefix(string(data), prefix) {
 		return data, nil
@@ -202,6 +210,12 @@ func DecryptS(ptype string, val sql.NullString, data []byte, clear bool) (string
 func EncryptS(ptype string, value string) (sql.NullString, []byte, error) {
 	var n sql.NullString
 
+	if ptype == sdk.VaultVariable {
+		if _, _, _, err := sdk.ParseVaultReference(value); err != nil {
+			return n, nil, err
+		}
+	}
+
 	if !sdk.NeedPlaceholder(ptype) {
 		n.String = value
 		n.Valid = true
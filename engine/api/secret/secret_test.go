@@ -99,3 +99,58 @@ func TestDecryptS(t *testing.T) {
 	}
 
 }
+
+func TestEncryptEnvelope(t *testing.T) {
+	key = []byte("78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xf")
+	legacy, err := Encrypt([]byte("legacy"))
+	if err != nil {
+		t.Fatalf("Encrypt failed: %s", err)
+	}
+
+	if err := InitKeys(map[string]string{"k1": "78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xf", "k2": "Zf23hwefw34LAQ15ZD5AOABo1Xb239fj"}, "k1"); err != nil {
+		t.Fatalf("InitKeys failed: %s", err)
+	}
+	defer InitKeys(nil, "")
+
+	ct1, err := Encrypt([]byte("Hello world !"))
+	if err != nil {
+		t.Fatalf("Encrypt failed: %s", err)
+	}
+	if !bytes.HasPrefix(ct1, []byte(KeyPrefix("k1"))) {
+		t.Fatalf("Fail: Expected prefix %s, got %s", KeyPrefix("k1"), ct1)
+	}
+
+	// rotate the master key, the data encrypted with the previous keys is still decrypted
+	if err := InitKeys(map[string]string{"k1": "78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xf", "k2": "Zf23hwefw34LAQ15ZD5AOABo1Xb239fj"}, "k2"); err != nil {
+		t.Fatalf("InitKeys failed: %s", err)
+	}
+	for ct, expected := range map[string]string{string(ct1): "Hello world !", string(legacy): "legacy"} {
+		clear, err := Decrypt([]byte(ct))
+		if err != nil {
+			t.Fatalf("Decrypt failed: %s", err)
+		}
+		if string(clear) != expected {
+			t.Fatalf("Fail: Expected '%s', got '%s'", expected, clear)
+		}
+	}
+
+	ct1[len(ct1)-1] ^= 1
+	if _, err := Decrypt(ct1); err == nil {
+		t.Fatalf("Decrypt should have failed on altered data")
+	}
+
+	if err := InitKeys(map[string]string{"k2": "Zf23hwefw34LAQ15ZD5AOABo1Xb239fj"}, "k2"); err != nil {
+		t.Fatalf("InitKeys failed: %s", err)
+	}
+	ct1[len(ct1)-1] ^= 1
+	if _, err := Decrypt(ct1); err == nil {
+		t.Fatalf("Decrypt should have failed without the master key k1")
+	}
+
+	if err := InitKeys(map[string]string{"k:1": "78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xf"}, ""); err == nil {
+		t.Fatalf("InitKeys should have failed on invalid key ID")
+	}
+	if err := InitKeys(map[string]string{"k1": "78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xf"}, "k3"); err == nil {
+		t.Fatalf("InitKeys should have failed on unknown current key ID")
+	}
+}
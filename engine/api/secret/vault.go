@@ -0,0 +1,110 @@
+package secret
+
+import (
+	"fmt"
+
+	vault "github.com/hashicorp/vault/api"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// vaultTokenTTL is the time to live of the tokens created to resolve the vault variables of a job
+const vaultTokenTTL = "5m"
+
+var vaultClient *vault.Client
+
+// InitVault initializes the Vault client used to resolve the vault variables.
+// The token must be allowed to create tokens with the Vault roles of the projects.
+func InitVault(addr, token string) error {
+	c, err := newVaultClient(addr, token)
+	if err != nil {
+		return err
+	}
+	vaultClient = c
+	return nil
+}
+
+func newVaultClient(addr, token string) (*vault.Client, error) {
+	c, err := vault.NewClient(vault.DefaultConfig())
+	if err != nil {
+		return nil, err
+	}
+	if err := c.SetAddress(addr); err != nil {
+		return nil, err
+	}
+	c.SetToken(token)
+	return c, nil
+}
+
+// ResolveVaultVariables replaces the value of the vault variables by the secrets read in Vault, with a short-lived
+// token created from the Vault role of the project. The resolved variables become secret variables.
+func ResolveVaultVariables(vars []sdk.Variable, projectKey, role string) error {
+	var refs []*sdk.Variable
+	for i := range vars {
+		if vars[i].Type == sdk.VaultVariable {
+			refs = append(refs, &vars[i])
+		}
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+
+	if vaultClient == nil {
+		return sdk.NewError(sdk.ErrVaultSecret, fmt.Errorf("Vault is not configured on CDS API"))
+	}
+	if role == "" {
+		return sdk.NewError(sdk.ErrVaultSecret, fmt.Errorf("no Vault role defined on project %s", projectKey))
+	}
+
+	t, err := vaultClient.Auth().Token().CreateWithRole(&vault.TokenCreateRequest{
+		TTL:         vaultTokenTTL,
+		DisplayName: "cds-" + projectKey,
+		Metadata:    map[string]string{"project": projectKey},
+	}, role)
+	if err != nil {
+		return sdk.NewError(sdk.ErrVaultSecret, fmt.Errorf("unable to create a token with role %s: %v", role, err))
+	}
+	if t == nil || t.Auth == nil {
+		return sdk.NewError(sdk.ErrVaultSecret, fmt.Errorf("no token returned for role %s", role))
+	}
+
+	c, err := newVaultClient(vaultClient.Address(), t.Auth.ClientToken)
+	if err != nil {
+		return sdk.WrapError(err, "ResolveVaultVariables> Unable to create Vault client")
+	}
+	defer c.Auth().Token().RevokeSelf("")
+
+	for _, v := range refs {
+		value, err := readVaultSecret(c, v.Value)
+		if err != nil {
+			return sdk.NewError(sdk.ErrVaultSecret, fmt.Errorf("unable to read %s for variable %s: %v", v.Value, v.Name, err))
+		}
+		v.Value = value
+		v.Type = sdk.SecretVariable
+	}
+	return nil
+}
+
+// readVaultSecret reads a field of a secret in a KV v2 engine
+func readVaultSecret(c *vault.Client, ref string) (string, error) {
+	mount, path, field, err := sdk.ParseVaultReference(ref)
+	if err != nil {
+		return "", err
+	}
+	s, err := c.Logical().Read(mount + "/data/" + path)
+	if err != nil {
+		return "", err
+	}
+	if s == nil {
+		return "", fmt.Errorf("no secret found at %s/%s", mount, path)
+	}
+	data, ok := s.Data["data"].(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("%s is not a KV v2 engine", mount)
+	}
+	value, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("no field %s in secret %s/%s", field, mount, path)
+	}
+	return fmt.Sprintf("%v", value), nil
+}
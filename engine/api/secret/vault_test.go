@@ -0,0 +1,42 @@
+package secret
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// TestResolveVaultVariables runs against a Vault dev server: vault server -dev, with VAULT_ADDR and VAULT_TOKEN set
+func TestResolveVaultVariables(t *testing.T) {
+	addr, token := os.Getenv("VAULT_ADDR"), os.Getenv("VAULT_TOKEN")
+	if addr == "" || token == "" {
+		t.Skip("VAULT_ADDR and VAULT_TOKEN are not set")
+	}
+	if !assert.NoError(t, InitVault(addr, token)) {
+		return
+	}
+
+	assert.NoError(t, vaultClient.Sys().PutPolicy("cds-test", `path "secret/data/cds-test/*" { capabilities = ["read"] }`))
+	_, err := vaultClient.Logical().Write("auth/token/roles/cds-test", map[string]interface{}{"allowed_policies": "cds-test"})
+	assert.NoError(t, err)
+	_, err = vaultClient.Logical().Write("secret/data/cds-test/db", map[string]interface{}{"data": map[string]interface{}{"password": "s3cr3t"}})
+	assert.NoError(t, err)
+	_, err = vaultClient.Logical().Write("secret/data/other/db", map[string]interface{}{"data": map[string]interface{}{"password": "other"}})
+	assert.NoError(t, err)
+
+	vars := []sdk.Variable{
+		{Name: "cds.proj.db", Type: sdk.VaultVariable, Value: "secret/cds-test/db#password"},
+		{Name: "cds.proj.user", Type: sdk.StringVariable, Value: "cds"},
+	}
+	assert.NoError(t, ResolveVaultVariables(vars, "PROJ", "cds-test"))
+	assert.Equal(t, "s3cr3t", vars[0].Value)
+	assert.Equal(t, sdk.SecretVariable, vars[0].Type)
+	assert.Equal(t, "cds", vars[1].Value)
+
+	denied := []sdk.Variable{{Name: "cds.proj.db", Type: sdk.VaultVariable, Value: "secret/other/db#password"}}
+	assert.Error(t, ResolveVaultVariables(denied, "PROJ", "cds-test"))
+	assert.Error(t, ResolveVaultVariables(denied, "PROJ", ""))
+}
@@ -251,8 +251,9 @@ func (api *API) postUserFavoriteHandler() service.Handler {
 // AddUser creates a new user and generate verification email
 func (api *API) addUserHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-		//returns forbidden if LDAP mode is activated
-		if _, ldap := api.Router.AuthDriver.(*auth.LDAPClient); ldap {
+		//returns forbidden if LDAP or OpenID Connect mode is activated
+		switch api.Router.AuthDriver.(type) {
+		case *auth.LDAPClient, *auth.OIDCClient:
 			return sdk.ErrForbidden
 		}
 
@@ -327,8 +328,9 @@ func (api *API) addUserHandler() service.Handler {
 
 func (api *API) resetUserHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-		//returns forbidden if LDAP mode is activated
-		if _, ldap := api.Router.AuthDriver.(*auth.LDAPClient); ldap {
+		//returns forbidden if LDAP or OpenID Connect mode is activated
+		switch api.Router.AuthDriver.(type) {
+		case *auth.LDAPClient, *auth.OIDCClient:
 			return sdk.ErrForbidden
 		}
 
@@ -346,6 +348,10 @@ func (api *API) resetUserHandler() service.Handler {
 		if err != nil || userDb.Email != resetUserRequest.User.Email {
 			return sdk.WrapError(sdk.ErrInvalidResetUser, "Cannot load user: %s", err)
 		}
+		// the users of the OpenID Connect provider have no local password
+		if userDb.Origin == "oidc" {
+			return sdk.WrapError(sdk.ErrForbidden, "ResetUser: Cannot reset the password of OpenID Connect user %s", userDb.Username)
+		}
 
 		tokenVerify, hashedToken, err := user.GeneratePassword()
 		if err != nil {
@@ -365,12 +371,15 @@ func (api *API) resetUserHandler() service.Handler {
 	}
 }
 
-//AuthModeHandler returns the auth mode : local ok ldap
+//AuthModeHandler returns the auth mode : local, ldap or oidc
 func (api *API) authModeHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		mode := "local"
-		if _, ldap := api.Router.AuthDriver.(*auth.LDAPClient); ldap {
+		switch api.Router.AuthDriver.(type) {
+		case *auth.LDAPClient:
 			mode = "ldap"
+		case *auth.OIDCClient:
+			mode = "oidc"
 		}
 		res := map[string]string{
 			"auth_mode": mode,
@@ -402,6 +411,11 @@ func (api *API) confirmUserHandler() service.Handler {
 			return sdk.ErrInvalidUsername
 		}
 
+		// the users of the OpenID Connect provider have no local password
+		if u.Origin == "oidc" {
+			return sdk.ErrForbidden
+		}
+
 		// Verify token
 		password, hashedPassword, err := user.Verify(u, token)
 		if err != nil {
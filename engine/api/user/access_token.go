@@ -0,0 +1,145 @@
+package user
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// accessTokenPrefix makes the access tokens recognizable, ie. by secret scanners
+const accessTokenPrefix = "cdsat_"
+
+// accessTokenAuditRetention is the number of days the uses of an access token are kept
+const accessTokenAuditRetention = 90
+
+func hashAccessToken(token string) string {
+	h := sha512.Sum512([]byte(token))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// InsertAccessToken generates the value of a new access token and inserts its hash in database.
+// The value is only set in the returned token.
+func InsertAccessToken(db gorp.SqlExecutor, t *sdk.AccessToken) error {
+	bs := make([]byte, 32)
+	if _, err := rand.Read(bs); err != nil {
+		return sdk.WrapError(err, "InsertAccessToken> Unable to generate token")
+	}
+	t.Token = accessTokenPrefix + hex.EncodeToString(bs)
+	t.Hash = hashAccessToken(t.Token)
+	t.Created = time.Now()
+
+	dbt := accessToken(*t)
+	if err := db.Insert(&dbt); err != nil {
+		return sdk.WrapError(err, "InsertAccessToken> Unable to insert access token %s for user %d", t.Name, t.UserID)
+	}
+	t.ID = dbt.ID
+	return nil
+}
+
+// LoadAccessTokens loads all the access tokens of a user, revoked tokens included
+func LoadAccessTokens(db gorp.SqlExecutor, userID int64) ([]sdk.AccessToken, error) {
+	var dbts []accessToken
+	if _, err := db.Select(&dbts, "SELECT * FROM user_access_token WHERE user_id = $1 ORDER BY created DESC", userID); err != nil {
+		return nil, sdk.WrapError(err, "LoadAccessTokens> Unable to load access tokens of user %d", userID)
+	}
+	ts := make([]sdk.AccessToken, len(dbts))
+	for i := range dbts {
+		ts[i] = sdk.AccessToken(dbts[i])
+	}
+	return ts, nil
+}
+
+// LoadAccessTokenByID loads an access token of a user
+func LoadAccessTokenByID(db gorp.SqlExecutor, userID, id int64) (*sdk.AccessToken, error) {
+	var dbt accessToken
+	if err := db.SelectOne(&dbt, "SELECT * FROM user_access_token WHERE user_id = $1 AND id = $2", userID, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrAccessTokenNotFound
+		}
+		return nil, sdk.WrapError(err, "LoadAccessTokenByID> Unable to load access token %d", id)
+	}
+	t := sdk.AccessToken(dbt)
+	return &t, nil
+}
+
+// LoadAccessToken loads an access token from its value
+func LoadAccessToken(db gorp.SqlExecutor, token string) (*sdk.AccessToken, error) {
+	var dbt accessToken
+	if err := db.SelectOne(&dbt, "SELECT * FROM user_access_token WHERE hash = $1", hashAccessToken(token)); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrAccessTokenNotFound
+		}
+		return nil, sdk.WrapError(err, "LoadAccessToken> Unable to load access token")
+	}
+	t := sdk.AccessToken(dbt)
+	return &t, nil
+}
+
+// RevokeAccessToken revokes an access token, the token is kept for audit
+func RevokeAccessToken(db gorp.SqlExecutor, t *sdk.AccessToken, revokedBy string) error {
+	now := time.Now()
+	t.Revoked = &now
+	t.RevokedBy = revokedBy
+	if _, err := db.Exec("UPDATE user_access_token SET revoked = $2, revoked_by = $3 WHERE id = $1", t.ID, now, revokedBy); err != nil {
+		return sdk.WrapError(err, "RevokeAccessToken> Unable to revoke access token %d", t.ID)
+	}
+	return nil
+}
+
+// UpdateAccessTokenLastUsed sets the last use date of an access token, at most once a minute.
+// The old uses of the access token are purged at the same time.
+func UpdateAccessTokenLastUsed(db gorp.SqlExecutor, id int64) error {
+	query := `
+	UPDATE user_access_token SET last_used = $2
+	WHERE id = $1 AND (last_used IS NULL OR last_used < $2::timestamptz - interval '1 minute')`
+	res, err := db.Exec(query, id, time.Now())
+	if err != nil {
+		return sdk.WrapError(err, "UpdateAccessTokenLastUsed> Unable to update access token %d", id)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return sdk.WrapError(err, "UpdateAccessTokenLastUsed> Unable to update access token %d", id)
+	}
+	if n == 0 {
+		return nil
+	}
+
+	query = `
+	DELETE FROM user_access_token_audit
+	WHERE access_token_id = $1 AND type IN ($2, $3) AND date < now() - $4 * interval '1 day'`
+	if _, err := db.Exec(query, id, sdk.AccessTokenAuditUse, sdk.AccessTokenAuditDenied, accessTokenAuditRetention); err != nil {
+		return sdk.WrapError(err, "UpdateAccessTokenLastUsed> Unable to purge uses of access token %d", id)
+	}
+	return nil
+}
+
+// InsertAccessTokenAudit records an action on an access token
+func InsertAccessTokenAudit(db gorp.SqlExecutor, a *sdk.AccessTokenAudit) error {
+	a.Date = time.Now()
+	dba := accessTokenAudit(*a)
+	if err := db.Insert(&dba); err != nil {
+		return sdk.WrapError(err, "InsertAccessTokenAudit> Unable to insert audit of access token %d", a.AccessTokenID)
+	}
+	a.ID = dba.ID
+	return nil
+}
+
+// LoadAccessTokenAudits loads the last audits of an access token, the newest first
+func LoadAccessTokenAudits(db gorp.SqlExecutor, id int64, limit int) ([]sdk.AccessTokenAudit, error) {
+	var dbas []accessTokenAudit
+	if _, err := db.Select(&dbas, "SELECT * FROM user_access_token_audit WHERE access_token_id = $1 ORDER BY date DESC LIMIT $2", id, limit); err != nil {
+		return nil, sdk.WrapError(err, "LoadAccessTokenAudits> Unable to load audits of access token %d", id)
+	}
+	as := make([]sdk.AccessTokenAudit, len(dbas))
+	for i := range dbas {
+		as[i] = sdk.AccessTokenAudit(dbas[i])
+	}
+	return as, nil
+}
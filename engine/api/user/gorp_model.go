@@ -8,6 +8,12 @@ import (
 
 type persistentSessionToken sdk.UserToken
 
+type accessToken sdk.AccessToken
+
+type accessTokenAudit sdk.AccessTokenAudit
+
 func init() {
 	gorpmapping.Register(gorpmapping.New(persistentSessionToken{}, "user_persistent_session", false, "token"))
+	gorpmapping.Register(gorpmapping.New(accessToken{}, "user_access_token", true, "id"))
+	gorpmapping.Register(gorpmapping.New(accessTokenAudit{}, "user_access_token_audit", true, "id"))
 }
@@ -120,15 +120,39 @@ func LoadUserWithoutAuth(db gorp.SqlExecutor, name string) (*sdk.User, error) {
 
 // LoadUserAndAuth Load user with auth information
 func LoadUserAndAuth(db gorp.SqlExecutor, name string) (*sdk.User, error) {
-	query := `SELECT id, admin, data, auth, origin FROM "user" WHERE username = $1`
+	return loadUserAndAuth(db, `SELECT id, admin, data, auth, origin FROM "user" WHERE username = $1`, name)
+}
+
+// LoadUserAndAuthByOIDCSubject loads the user with auth information linked to the issuer and subject of an
+// OpenID Connect provider
+func LoadUserAndAuthByOIDCSubject(db gorp.SqlExecutor, subject string) (*sdk.User, error) {
+	return loadUserAndAuth(db, `SELECT id, admin, data, auth, origin FROM "user" WHERE oidc_subject = $1`, subject)
+}
+
+// LoadOIDCSubject returns the issuer and subject of the OpenID Connect provider linked to the user, empty if the
+// user is not linked
+func LoadOIDCSubject(db gorp.SqlExecutor, userID int64) (string, error) {
+	var subject sql.NullString
+	if err := db.QueryRow(`SELECT oidc_subject FROM "user" WHERE id = $1`, userID).Scan(&subject); err != nil {
+		return "", err
+	}
+	return subject.String, nil
+}
+
+// UpdateOIDCSubject links the user to the issuer and subject of an OpenID Connect provider
+func UpdateOIDCSubject(db gorp.SqlExecutor, userID int64, subject string) error {
+	_, err := db.Exec(`UPDATE "user" SET oidc_subject = $2 WHERE id = $1`, userID, subject)
+	return err
+}
 
+func loadUserAndAuth(db gorp.SqlExecutor, query string, arg interface{}) (*sdk.User, error) {
 	var jsonUser []byte
 	var jsonAuth []byte
 	var id int64
 	var admin bool
 	var origin string
 
-	if err := db.QueryRow(query, name).Scan(&id, &admin, &jsonUser, &jsonAuth, &origin); err != nil {
+	if err := db.QueryRow(query, arg).Scan(&id, &admin, &jsonUser, &jsonAuth, &origin); err != nil {
 		return nil, err
 	}
 
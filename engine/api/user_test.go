@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/ovh/cds/engine/api/auth"
 	"github.com/ovh/cds/engine/api/bootstrap"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/pipeline"
@@ -620,3 +621,40 @@ func TestTimelineFilter(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, tfUpdated.Projects, 2)
 }
+
+func Test_resetUserHandlerOIDCUser(t *testing.T) {
+	api, _, _ := newTestAPI(t, bootstrap.InitiliazeDB)
+
+	s := sdk.RandomString(10)
+	u := &sdk.User{
+		Username: s,
+		Email:    "no-reply-" + s + "@corp.ovh.com",
+		Origin:   "oidc",
+	}
+	assert.NoError(t, user.InsertUser(api.mustDB(), u, &sdk.Auth{EmailVerified: true}))
+
+	uri := api.Router.GetRoute("POST", api.resetUserHandler, map[string]string{"username": u.Username})
+	test.NotEmpty(t, uri)
+	req := assets.NewAuthentifiedRequest(t, u, "", "POST", uri, sdk.UserAPIRequest{User: sdk.User{Email: u.Email}})
+
+	//Do the request
+	w := httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code)
+}
+
+func Test_addUserHandlerOIDC(t *testing.T) {
+	api, _, _ := newTestAPI(t, bootstrap.InitiliazeDB)
+	api.Router.AuthDriver = &auth.OIDCClient{}
+
+	uri := api.Router.GetRoute("POST", api.addUserHandler, nil)
+	test.NotEmpty(t, uri)
+	s := sdk.RandomString(10)
+	u := &sdk.User{Username: s, Email: "no-reply-" + s + "@corp.ovh.com"}
+	req := assets.NewAuthentifiedRequest(t, u, "", "POST", uri, sdk.UserAPIRequest{User: *u})
+
+	//Do the request
+	w := httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code)
+}
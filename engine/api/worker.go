@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/go-gorp/gorp"
 	"github.com/gorilla/mux"
@@ -90,7 +91,7 @@ func (api *API) disableWorkerHandler() service.Handler {
 			return sdk.WrapError(sdk.ErrForbidden, "Cannot disable a worker with status %s", wor.Status)
 		}
 
-		if err := DisableWorker(api.mustDB(), id); err != nil {
+		if err := DisableWorker(ctx, api.mustDB, api.Cache, id); err != nil {
 			if err == worker.ErrNoWorker || err == sql.ErrNoRows {
 				return sdk.WrapError(sdk.ErrWrongRequest, "disableWorkerHandler> worker %s does not exists", id)
 			}
@@ -110,13 +111,16 @@ func (api *API) refreshWorkerHandler() service.Handler {
 		if err := worker.RefreshWorker(api.mustDB(), getWorker(ctx)); err != nil && (err != sql.ErrNoRows || err != worker.ErrNoWorker) {
 			return sdk.WrapError(err, "refreshWorkerHandler> cannot refresh last beat of %s", getWorker(ctx).ID)
 		}
-		return nil
+		hb := sdk.WorkerHeartbeat{
+			KillJobID: worker.JobToKill(api.Cache, getWorker(ctx).ID),
+		}
+		return service.WriteJSON(w, hb, http.StatusOK)
 	}
 }
 
 func (api *API) unregisterWorkerHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-		if err := DisableWorker(api.mustDB(), getWorker(ctx).ID); err != nil {
+		if err := DisableWorker(ctx, api.mustDB, api.Cache, getWorker(ctx).ID); err != nil {
 			return sdk.WrapError(err, "unregisterWorkerHandler> cannot delete worker %s", getWorker(ctx).ID)
 		}
 		return nil
@@ -173,8 +177,10 @@ func (api *API) workerWaitingHandler() service.Handler {
 // After migration to new CDS Workflow, put DisableWorker into
 // the package workflow
 
-// DisableWorker disable a worker
-func DisableWorker(db *gorp.DbMap, id string) error {
+// DisableWorker disable a worker. The workflow job it was building is restarted,
+// or goes through its retry policy if the policy covers the lost workers.
+func DisableWorker(ctx context.Context, DBFunc func() *gorp.DbMap, store cache.Store, id string) error {
+	db := DBFunc()
 	tx, errb := db.Begin()
 	if errb != nil {
 		return fmt.Errorf("DisableWorker> Cannot start tx: %v", errb)
@@ -185,6 +191,8 @@ func DisableWorker(db *gorp.DbMap, id string) error {
 	var st, name string
 	var jobID sql.NullInt64
 	var jobType sql.NullString
+	var lostJobID int64
+	var lostInfo sdk.SpawnInfo
 	if err := tx.QueryRow(query, id).Scan(&name, &st, &jobID, &jobType); err != nil {
 		log.Debug("DisableWorker[%s]> Cannot lock worker: %v", id, err)
 		return nil
@@ -202,7 +210,14 @@ func DisableWorker(db *gorp.DbMap, id string) error {
 			}
 		case sdk.JobTypeWorkflowNode:
 			wNodeJob, errL := workflow.LoadNodeJobRun(tx, nil, jobID.Int64)
-			if errL == nil && wNodeJob.Retry < 3 {
+			if errL == nil && wNodeJob.Job.RetryPolicy.Covers(sdk.RetryOnWorkerLost, 0) {
+				// the retry policy is applied once the worker is disabled
+				lostJobID = wNodeJob.ID
+				lostInfo = sdk.SpawnInfo{
+					RemoteTime: time.Now(),
+					Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobWorkerLost.ID, Args: []interface{}{name}},
+				}
+			} else if errL == nil && wNodeJob.Retry < 3 {
 				if err := workflow.RestartWorkflowNodeJob(nil, db, *wNodeJob); err != nil {
 					log.Warning("DisableWorker[%s]> Cannot restart workflow node run: %v", name, err)
 				} else {
@@ -221,5 +236,16 @@ func DisableWorker(db *gorp.DbMap, id string) error {
 		return sdk.WrapError(err, "DisableWorker> cannot update worker status")
 	}
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "DisableWorker> cannot commit tx")
+	}
+
+	if lostJobID != 0 {
+		if err := retryOrFailJob(ctx, DBFunc, store, lostJobID, sdk.StatusBuilding, sdk.RetryOnWorkerLost, lostInfo); err != nil {
+			log.Error("DisableWorker[%s]> Cannot retry workflow node job %d: %v", name, lostJobID, err)
+		} else {
+			log.Info("DisableWorker[%s]> Retry policy applied on workflow node job %d after crash", name, lostJobID)
+		}
+	}
+	return nil
 }
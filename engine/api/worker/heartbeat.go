@@ -5,14 +5,18 @@ import (
 	"time"
 
 	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
 
 // WorkerHeartbeatTimeout defines the number of seconds allowed for workers to refresh their beat
 var WorkerHeartbeatTimeout = 300.0
 
-// CheckHeartbeat runs in a goroutine and check last beat from all workers
-func CheckHeartbeat(c context.Context, DBFunc func() *gorp.DbMap) {
+// CheckHeartbeat runs in a goroutine and check last beat from all workers.
+// The dead workers which were building a job are disabled with disableFunc before being deleted, so their job is restarted.
+func CheckHeartbeat(c context.Context, DBFunc func() *gorp.DbMap, disableFunc func(context.Context, string) error) {
 	tick := time.NewTicker(10 * time.Second).C
 
 	for {
@@ -31,6 +35,11 @@ func CheckHeartbeat(c context.Context, DBFunc func() *gorp.DbMap) {
 				}
 				for i := range w {
 					log.Debug("WorkerHeartbeat> Delete worker %s[%s] LastBeat:%v hatchery:%s status:%s", w[i].Name, w[i].ID, w[i].LastBeat, w[i].HatcheryName, w[i].Status)
+					if w[i].Status == sdk.StatusBuilding && disableFunc != nil {
+						if errD := disableFunc(c, w[i].ID); errD != nil {
+							log.Warning("WorkerHeartbeat> Cannot disable worker %s: %v", w[i].ID, errD)
+						}
+					}
 					if errD := DeleteWorker(db, w[i].ID); errD != nil {
 						log.Warning("WorkerHeartbeat> Cannot delete worker %d: %v", w[i].ID, errD)
 						continue
@@ -40,3 +49,18 @@ func CheckHeartbeat(c context.Context, DBFunc func() *gorp.DbMap) {
 		}
 	}
 }
+
+// SetJobToKill asks the worker to stop the given job, the worker receives it with its next heartbeat
+func SetJobToKill(store cache.Store, workerID string, jobID int64) {
+	store.SetWithTTL(cache.Key("worker", "kill", workerID), jobID, int(WorkerHeartbeatTimeout))
+}
+
+// JobToKill returns the job the worker has to stop, 0 if there is none
+func JobToKill(store cache.Store, workerID string) int64 {
+	var jobID int64
+	k := cache.Key("worker", "kill", workerID)
+	if store.Get(k, &jobID) {
+		store.Delete(k)
+	}
+	return jobID
+}
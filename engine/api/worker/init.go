@@ -9,9 +9,9 @@ import (
 	"github.com/ovh/cds/engine/api/cache"
 )
 
-//Initialize init the package
-func Initialize(c context.Context, DBFunc func() *gorp.DbMap, store cache.Store) error {
-	go CheckHeartbeat(c, DBFunc)
+//Initialize init the package, disableFunc is called on the dead workers which were building a job
+func Initialize(c context.Context, DBFunc func() *gorp.DbMap, store cache.Store, disableFunc func(context.Context, string) error) error {
+	go CheckHeartbeat(c, DBFunc, disableFunc)
 	go ModelCapabilititiesCacheLoader(c, 10*time.Second, DBFunc, store)
 	go insertFirstPatterns(DBFunc())
 	return nil
@@ -0,0 +1,229 @@
+package workflow
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/freeze"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// ApproveNodeRun records the decision of the user on the approval gate of the node run. The node run
+// is executed when the gate is approved, and fails when it is rejected.
+func ApproveNodeRun(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, nodeRunID int64, u *sdk.User, req sdk.WorkflowNodeRunApprovalRequest) (*ProcessorReport, *sdk.WorkflowNodeRun, error) {
+	nodeRun, err := LoadAndLockNodeRunByID(ctx, db, nodeRunID, true)
+	if err != nil {
+		return nil, nil, sdk.WrapError(err, "ApproveNodeRun> Unable to load node run %d", nodeRunID)
+	}
+	if nodeRun.Approval == nil || nodeRun.Status != sdk.StatusWaiting.String() {
+		return nil, nil, sdk.ErrApprovalNotPending
+	}
+
+	approver := *u
+	if len(nodeRun.Approval.Gate.Groups) > 0 {
+		groups, err := group.LoadGroupByUser(db, u.ID)
+		if err != nil {
+			return nil, nil, sdk.WrapError(err, "ApproveNodeRun> Unable to load groups of user %s", u.Username)
+		}
+		approver.Groups = groups
+	}
+	if nodeRun.Approval.Gate.ForbidCommitAuthor {
+		// the commit author is matched on the verified email of the user
+		ua, err := user.LoadUserAndAuth(db, u.Username)
+		if err != nil {
+			return nil, nil, sdk.WrapError(err, "ApproveNodeRun> Unable to load user %s", u.Username)
+		}
+		approver.Email = ua.Email
+		approver.Auth = ua.Auth
+	}
+	if err := nodeRun.Approval.Decide(&approver, req.Approved, req.Comment); err != nil {
+		return nil, nil, err
+	}
+
+	wr, err := LoadRunByID(db, nodeRun.WorkflowRunID, LoadRunOptions{})
+	if err != nil {
+		return nil, nil, sdk.WrapError(err, "ApproveNodeRun> Unable to load workflow run %d", nodeRun.WorkflowRunID)
+	}
+	msg := sdk.MsgWorkflowNodeApproved
+	if !req.Approved {
+		msg = sdk.MsgWorkflowNodeRejected
+	}
+	AddWorkflowRunInfo(wr, false, sdk.SpawnMsg{
+		ID:   msg.ID,
+		Args: []interface{}{nodeRun.WorkflowNodeName, u.Username, req.Comment},
+	})
+	if err := InsertApprovalAudit(db, sdk.WorkflowNodeRunApprovalAudit{
+		ProjectID:         proj.ID,
+		WorkflowID:        wr.WorkflowID,
+		WorkflowName:      wr.Workflow.Name,
+		Number:            wr.Number,
+		WorkflowNodeRunID: nodeRun.ID,
+		WorkflowNodeName:  nodeRun.WorkflowNodeName,
+		Username:          u.Username,
+		Approved:          req.Approved,
+		Comment:           req.Comment,
+		Status:            nodeRun.Approval.Status,
+		Date:              time.Now(),
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	report, err := applyApproval(ctx, db, store, proj, wr, nodeRun)
+	if err != nil {
+		return nil, nil, sdk.WrapError(err, "ApproveNodeRun> Unable to apply approval on node run %d", nodeRun.ID)
+	}
+	return report, nodeRun, nil
+}
+
+// InsertApprovalAudit records a decision on an approval gate. The audits are kept when the workflow runs are purged.
+func InsertApprovalAudit(db gorp.SqlExecutor, a sdk.WorkflowNodeRunApprovalAudit) error {
+	audit := approvalAudit(a)
+	if err := db.Insert(&audit); err != nil {
+		return sdk.WrapError(err, "InsertApprovalAudit> Unable to insert audit of node run %d", a.WorkflowNodeRunID)
+	}
+	return nil
+}
+
+// LoadApprovalAudits loads the decisions on the approval gates of a workflow, the newest first
+func LoadApprovalAudits(db gorp.SqlExecutor, workflowID int64) ([]sdk.WorkflowNodeRunApprovalAudit, error) {
+	var dbas []approvalAudit
+	query := "SELECT * FROM workflow_node_run_approval_audit WHERE workflow_id = $1 ORDER BY date DESC"
+	if _, err := db.Select(&dbas, query, workflowID); err != nil {
+		return nil, sdk.WrapError(err, "LoadApprovalAudits> Unable to load approval audits")
+	}
+	audits := make([]sdk.WorkflowNodeRunApprovalAudit, len(dbas))
+	for i := range dbas {
+		audits[i] = sdk.WorkflowNodeRunApprovalAudit(dbas[i])
+	}
+	return audits, nil
+}
+
+// LoadExpiredApprovalNodeRunIDs loads the ids of the waiting node runs whose approval gate expired
+func LoadExpiredApprovalNodeRunIDs(db gorp.SqlExecutor) ([]int64, error) {
+	query := `
+	SELECT id
+	FROM workflow_node_run
+	WHERE status = $1
+	AND approval->>'status' = $2
+	AND (approval->>'expire')::timestamptz < now()
+	`
+	var ids []int64
+	if _, err := db.Select(&ids, query, sdk.StatusWaiting.String(), sdk.ApprovalPending); err != nil {
+		return nil, sdk.WrapError(err, "LoadExpiredApprovalNodeRunIDs> Unable to load node runs")
+	}
+	return ids, nil
+}
+
+// ExpireNodeRunApproval fails the node run whose approval gate expired
+func ExpireNodeRunApproval(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, nodeRunID int64) (*ProcessorReport, error) {
+	nodeRun, err := LoadAndLockNodeRunByID(ctx, db, nodeRunID, false)
+	if err != nil {
+		return nil, sdk.WrapError(err, "ExpireNodeRunApproval> Unable to load node run %d", nodeRunID)
+	}
+	a := nodeRun.Approval
+	if a == nil || a.Status != sdk.ApprovalPending || a.Expire == nil || a.Expire.After(time.Now()) || nodeRun.Status != sdk.StatusWaiting.String() {
+		return nil, nil
+	}
+	a.Status = sdk.ApprovalExpired
+
+	wr, err := LoadRunByID(db, nodeRun.WorkflowRunID, LoadRunOptions{})
+	if err != nil {
+		return nil, sdk.WrapError(err, "ExpireNodeRunApproval> Unable to load workflow run %d", nodeRun.WorkflowRunID)
+	}
+	timeout := time.Duration(a.Gate.Timeout) * time.Second
+	AddWorkflowRunInfo(wr, false, sdk.SpawnMsg{
+		ID:   sdk.MsgWorkflowNodeApprovalExpired.ID,
+		Args: []interface{}{nodeRun.WorkflowNodeName, timeout.String()},
+	})
+
+	return applyApproval(ctx, db, store, proj, wr, nodeRun)
+}
+
+// applyApproval saves the node run after a change of its approval: the node run is executed if the gate
+// is approved and no freeze window started meanwhile, it fails if the gate is rejected or expired
+func applyApproval(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, wr *sdk.WorkflowRun, nodeRun *sdk.WorkflowNodeRun) (*ProcessorReport, error) {
+	report := new(ProcessorReport)
+
+	var node *sdk.WorkflowNode
+	failed := nodeRun.Approval.Status == sdk.ApprovalRejected || nodeRun.Approval.Status == sdk.ApprovalExpired
+	if nodeRun.Approval.Status == sdk.ApprovalApproved {
+		node = wr.Workflow.GetNode(nodeRun.WorkflowNodeID)
+		if node == nil {
+			return nil, sdk.WrapError(sdk.ErrWorkflowNodeNotFound, "applyApproval> Unable to find node %d", nodeRun.WorkflowNodeID)
+		}
+		frozen, err := checkFreezeWindowsOnApproval(db, proj, wr, node, nodeRun)
+		if err != nil {
+			return nil, sdk.WrapError(err, "applyApproval> Unable to check freeze windows")
+		}
+		failed = frozen
+	}
+
+	if failed {
+		for i := range nodeRun.Stages {
+			nodeRun.Stages[i].Status = sdk.StatusSkipped
+		}
+		nodeRun.Status = sdk.StatusFail.String()
+		nodeRun.Done = time.Now()
+	}
+	nodeRun.LastModified = time.Now()
+	if err := UpdateNodeRun(db, nodeRun); err != nil {
+		return nil, sdk.WrapError(err, "applyApproval> Unable to update node run %d", nodeRun.ID)
+	}
+	if err := UpdateWorkflowRun(ctx, db, wr); err != nil {
+		return nil, sdk.WrapError(err, "applyApproval> Unable to update workflow run %d", wr.ID)
+	}
+	report.Add(*nodeRun)
+
+	switch {
+	case failed:
+		updatedWorkflowRun, err := LoadRunByID(db, wr.ID, LoadRunOptions{})
+		if err != nil {
+			return nil, sdk.WrapError(err, "applyApproval> Unable to reload workflow run %d", wr.ID)
+		}
+		r1, _, err := processWorkflowRun(ctx, db, store, proj, updatedWorkflowRun, nil, nil, nil)
+		if err != nil {
+			return nil, sdk.WrapError(err, "applyApproval> Unable to reprocess workflow run %d", wr.ID)
+		}
+		return report.Merge(r1, nil)
+	case nodeRun.Approval.Status == sdk.ApprovalApproved:
+		log.Debug("applyApproval> execute the node run %d because its approval gate has been approved", nodeRun.ID)
+		return report.Merge(executeIfMutexFree(ctx, db, store, proj, wr, node, nodeRun))
+	}
+	return report, nil
+}
+
+// checkFreezeWindowsOnApproval returns true if a freeze window started while the node run was waiting for its
+// approvals. The freeze windows overridden when the node run was processed do not block it.
+func checkFreezeWindowsOnApproval(db gorp.SqlExecutor, proj *sdk.Project, wr *sdk.WorkflowRun, node *sdk.WorkflowNode, nodeRun *sdk.WorkflowNodeRun) (bool, error) {
+	env, has := node.Environment()
+	if !has {
+		return false, nil
+	}
+	windows, err := freeze.LoadActiveByEnvironmentID(db, proj.ID, env.ID)
+	if err != nil {
+		return false, err
+	}
+
+	var frozen bool
+	for _, f := range windows {
+		overridden, err := freeze.IsOverridden(db, f.ID, wr.Workflow.Name, wr.Number, nodeRun.WorkflowNodeName, nodeRun.Start)
+		if err != nil {
+			return false, err
+		}
+		if overridden {
+			continue
+		}
+		AddWorkflowRunInfo(wr, false, sdk.SpawnMsg{
+			ID:   sdk.MsgWorkflowNodeFrozen.ID,
+			Args: []interface{}{nodeRun.WorkflowNodeName, f.Scope(), f.Reason},
+		})
+		frozen = true
+	}
+	return frozen, nil
+}
@@ -0,0 +1,184 @@
+package workflow
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	artifactBlobContainer = "workflow-artifact-blobs"
+	// unreferenced blobs are kept a while, an upload could be referencing them
+	artifactBlobGracePeriod = time.Hour
+)
+
+// StoreArtifact stores the content of an artifact in the objectstore. The content is addressed by its SHA512:
+// if the same content has already been uploaded, it is not stored again and the artifact references the existing blob.
+func StoreArtifact(db gorp.SqlExecutor, art *sdk.WorkflowNodeRunArtifact, content io.ReadSeeker) error {
+	h := sha512.New()
+	size, err := io.Copy(h, content)
+	if err != nil {
+		return sdk.WrapError(err, "StoreArtifact> Unable to compute sha512sum")
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	if art.SHA512sum != "" && art.SHA512sum != sum {
+		log.Warning("StoreArtifact> sha512sum of artifact %s doesn't match the given one", art.Name)
+	}
+	art.SHA512sum = sum
+
+	blob, err := loadArtifactBlob(db, sum)
+	if err != nil {
+		return err
+	}
+	if blob != nil && !blob.Verified {
+		// the blob has been registered from a SHA512 given by a worker, the artifact keeps its own object
+		if _, err := content.Seek(0, io.SeekStart); err != nil {
+			return sdk.WrapError(err, "StoreArtifact> Unable to read artifact content")
+		}
+		objectPath, err := objectstore.Store(art, ioutil.NopCloser(content))
+		if err != nil {
+			return sdk.WrapError(err, "StoreArtifact> Cannot store artifact")
+		}
+		art.ObjectPath = objectPath
+		return nil
+	}
+	if blob != nil {
+		log.Debug("StoreArtifact> Artifact %s is deduplicated with blob %s", art.Name, sum)
+		art.BlobSHA512sum = sum
+		art.ObjectPath = blob.ObjectPath
+		return touchArtifactBlob(db, sum)
+	}
+
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return sdk.WrapError(err, "StoreArtifact> Unable to read artifact content")
+	}
+
+	blob = &sdk.WorkflowArtifactBlob{
+		SHA512sum: sum,
+		Size:      size,
+		Container: artifactBlobContainer,
+		Name:      sum,
+		Verified:  true,
+	}
+	objectPath, err := objectstore.Store(blob, ioutil.NopCloser(content))
+	if err != nil {
+		return sdk.WrapError(err, "StoreArtifact> Cannot store artifact")
+	}
+	blob.ObjectPath = objectPath
+
+	if err := insertArtifactBlob(db, blob); err != nil {
+		// the same content may have been stored concurrently
+		if existing, errL := loadArtifactBlob(db, sum); errL != nil || existing == nil || !existing.Verified {
+			return err
+		}
+	}
+	art.BlobSHA512sum = sum
+	art.ObjectPath = objectPath
+	return nil
+}
+
+// RegisterArtifactBlob references the content of an artifact uploaded with a temporary URL as a blob.
+// The API doesn't read the uploaded content, the SHA512 computed by the worker while uploading is trusted only
+// to reuse a blob already referenced by the project. A blob registered from it is not verified: it is never reused
+// by other projects nor by artifacts uploaded through the API. If the content can't be registered as a blob,
+// the artifact keeps its own object, deleted with its workflow run.
+func RegisterArtifactBlob(db gorp.SqlExecutor, art *sdk.WorkflowNodeRunArtifact) error {
+	if art.SHA512sum == "" {
+		return nil
+	}
+
+	blob, err := loadProjectArtifactBlob(db, art.WorkflowID, art.SHA512sum)
+	if err != nil {
+		return err
+	}
+	if blob != nil {
+		if err := touchArtifactBlob(db, blob.SHA512sum); err != nil {
+			return err
+		}
+		if err := objectstore.Delete(art); err != nil {
+			log.Warning("RegisterArtifactBlob> Unable to delete duplicated artifact %s: %v", art.Name, err)
+		}
+		art.BlobSHA512sum = blob.SHA512sum
+		art.ObjectPath = blob.ObjectPath
+		return nil
+	}
+
+	// the uploaded object becomes the blob
+	blob = &sdk.WorkflowArtifactBlob{
+		SHA512sum:  art.SHA512sum,
+		Size:       art.Size,
+		Container:  art.GetPath(),
+		Name:       art.GetName(),
+		ObjectPath: art.ObjectPath,
+	}
+	if err := insertArtifactBlob(db, blob); err != nil {
+		// the content is already registered by another project or through the API
+		log.Debug("RegisterArtifactBlob> Artifact %s is not deduplicated: %v", art.Name, err)
+		return nil
+	}
+	art.BlobSHA512sum = blob.SHA512sum
+	return nil
+}
+
+// ArtifactObject returns the object to fetch to get the content of the artifact
+func ArtifactObject(db gorp.SqlExecutor, art *sdk.WorkflowNodeRunArtifact) (objectstore.Object, error) {
+	if art.BlobSHA512sum == "" {
+		return art, nil
+	}
+	blob, err := loadArtifactBlob(db, art.BlobSHA512sum)
+	if err != nil {
+		return nil, err
+	}
+	if blob == nil {
+		return nil, sdk.WrapError(sdk.ErrNotFound, "ArtifactObject> Blob %s of artifact %s not found", art.BlobSHA512sum, art.Name)
+	}
+	return blob, nil
+}
+
+// PurgeArtifactBlobs deletes the blobs which are not referenced anymore by an artifact
+func PurgeArtifactBlobs(db gorp.SqlExecutor) error {
+	before := time.Now().Add(-artifactBlobGracePeriod)
+	blobs, err := loadUnreferencedArtifactBlobs(db, before, 100)
+	if err != nil {
+		return err
+	}
+
+	for i := range blobs {
+		deleted, err := deleteArtifactBlob(db, blobs[i].SHA512sum, before)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			continue
+		}
+		log.Debug("PurgeArtifactBlobs> Deleting blob %s", blobs[i].SHA512sum)
+		if err := objectstore.Delete(&blobs[i]); err != nil {
+			log.Error("PurgeArtifactBlobs> Unable to delete object of blob %s: %v", blobs[i].SHA512sum, err)
+		}
+	}
+	return nil
+}
+
+// DeleteRunArtifactObjects deletes the objects of the artifacts of the workflow runs which are not stored as blobs.
+// The blobs are purged once they are not referenced anymore.
+func DeleteRunArtifactObjects(db gorp.SqlExecutor, runIDs []int64) error {
+	arts, err := loadArtifactsWithoutBlob(db, runIDs)
+	if err != nil {
+		return err
+	}
+	for i := range arts {
+		log.Debug("DeleteRunArtifactObjects> Deleting artifact %s of workflow run %d", arts[i].Name, arts[i].WorkflowID)
+		if err := objectstore.Delete(&arts[i]); err != nil {
+			log.Error("DeleteRunArtifactObjects> Unable to delete object of artifact %s: %v", arts[i].Name, err)
+		}
+	}
+	return nil
+}
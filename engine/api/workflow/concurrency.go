@@ -0,0 +1,234 @@
+package workflow
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// concurrencyHolder is the node run which prevents another node run to take a concurrency group
+type concurrencyHolder struct {
+	WorkflowName     string
+	Number           int64
+	WorkflowNodeName string
+}
+
+// lockConcurrencyGroup serializes the processing of a concurrency group until the end of the transaction
+func lockConcurrencyGroup(db gorp.SqlExecutor, key string) error {
+	if _, err := db.Exec("SELECT pg_advisory_xact_lock(hashtext($1))", key); err != nil {
+		return sdk.WrapError(err, "lockConcurrencyGroup> Unable to lock concurrency group %s", key)
+	}
+	return nil
+}
+
+// loadConcurrencyHolder loads the node run running in the concurrency group, or queued in the group before the given node run
+func loadConcurrencyHolder(db gorp.SqlExecutor, key string, nodeRunID int64) (*concurrencyHolder, error) {
+	query := `
+	SELECT workflow.name, workflow_node_run.num, workflow_node_run.workflow_node_name
+	FROM workflow_node_run
+	JOIN workflow ON workflow.id = workflow_node_run.workflow_id
+	WHERE workflow_node_run.concurrency->>'key' = $1
+	AND workflow_node_run.id <> $2
+	AND workflow_node_run.status IN ($3, $4)
+	AND (workflow_node_run.concurrency->>'status' = $5 OR (workflow_node_run.concurrency->>'status' = $6 AND workflow_node_run.id < $2))
+	ORDER BY workflow_node_run.concurrency->>'status' = $5 DESC, workflow_node_run.id
+	LIMIT 1`
+	var h concurrencyHolder
+	if err := db.QueryRow(query, key, nodeRunID, sdk.StatusWaiting.String(), sdk.StatusBuilding.String(), sdk.ConcurrencyRunning, sdk.ConcurrencyQueued).
+		Scan(&h.WorkflowName, &h.Number, &h.WorkflowNodeName); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, sdk.WrapError(err, "loadConcurrencyHolder> Unable to load holder of concurrency group %s", key)
+	}
+	return &h, nil
+}
+
+// executeInConcurrencyGroup executes the node run if its node is not in a concurrency group or if the group is free.
+// Otherwise the node run is queued in the group, it will be executed when the group is released.
+func executeInConcurrencyGroup(ctx context.Context, db gorp.SqlExecutor, store cache.Store, p *sdk.Project, w *sdk.WorkflowRun, n *sdk.WorkflowNode, run *sdk.WorkflowNodeRun) (*ProcessorReport, error) {
+	report := new(ProcessorReport)
+	if n.Context == nil || n.Context.Concurrency == nil {
+		return report.Merge(execute(ctx, db, store, p, run))
+	}
+
+	c := *n.Context.Concurrency
+	key := c.Key(p.Key)
+	if err := lockConcurrencyGroup(db, key); err != nil {
+		return nil, err
+	}
+	holder, err := loadConcurrencyHolder(db, key, run.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	run.Concurrency = &sdk.WorkflowNodeRunConcurrency{Group: c.Group, Key: key, Status: sdk.ConcurrencyRunning}
+	if holder != nil {
+		run.Concurrency.Status = sdk.ConcurrencyQueued
+	}
+	if err := UpdateNodeRun(db, run); err != nil {
+		return nil, sdk.WrapError(err, "executeInConcurrencyGroup> Unable to update node run %d", run.ID)
+	}
+	if holder == nil {
+		return report.Merge(execute(ctx, db, store, p, run))
+	}
+
+	log.Debug("executeInConcurrencyGroup> Noderun %s processed but not executed because of concurrency group %s", n.Name, key)
+	AddWorkflowRunInfo(w, false, sdk.SpawnMsg{
+		ID:   sdk.MsgWorkflowNodeConcurrencyQueued.ID,
+		Args: []interface{}{n.Name, c.Group, holder.WorkflowName, holder.Number, holder.WorkflowNodeName},
+	})
+	if err := UpdateWorkflowRun(ctx, db, w); err != nil {
+		return nil, sdk.WrapError(err, "executeInConcurrencyGroup> Unable to update workflow run %d", w.ID)
+	}
+
+	if c.CancelSuperseded && run.VCSBranch != "" {
+		return report.Merge(cancelSupersededNodeRuns(ctx, db, store, p, run))
+	}
+	return report, nil
+}
+
+// cancelSupersededNodeRuns stops the node runs of the same node and the same branch queued before the given node run
+func cancelSupersededNodeRuns(ctx context.Context, db gorp.SqlExecutor, store cache.Store, p *sdk.Project, run *sdk.WorkflowNodeRun) (*ProcessorReport, error) {
+	query := `
+	SELECT id
+	FROM workflow_node_run
+	WHERE workflow_id = $1
+	AND workflow_node_name = $2
+	AND vcs_branch = $3
+	AND status = $4
+	AND concurrency->>'status' = $5
+	AND id < $6
+	ORDER BY id`
+	var ids []int64
+	if _, err := db.Select(&ids, query, run.WorkflowID, run.WorkflowNodeName, run.VCSBranch, sdk.StatusWaiting.String(), sdk.ConcurrencyQueued, run.ID); err != nil {
+		return nil, sdk.WrapError(err, "cancelSupersededNodeRuns> Unable to load superseded node runs")
+	}
+
+	report := new(ProcessorReport)
+	for _, id := range ids {
+		nodeRun, err := LoadAndLockNodeRunByID(ctx, db, id, true)
+		if err != nil {
+			return nil, sdk.WrapError(err, "cancelSupersededNodeRuns> Unable to load node run %d", id)
+		}
+		stopWorkflowNodeRunStages(nodeRun)
+		nodeRun.Status = sdk.StatusStopped.String()
+		nodeRun.Done = time.Now()
+		if err := UpdateNodeRun(db, nodeRun); err != nil {
+			return nil, sdk.WrapError(err, "cancelSupersededNodeRuns> Unable to update node run %d", id)
+		}
+		report.Add(*nodeRun)
+
+		wr, err := LoadRunByID(db, nodeRun.WorkflowRunID, LoadRunOptions{})
+		if err != nil {
+			return nil, sdk.WrapError(err, "cancelSupersededNodeRuns> Unable to load workflow run %d", nodeRun.WorkflowRunID)
+		}
+		log.Info("cancelSupersededNodeRuns> Node run %d of %s #%d superseded by #%d on branch %s", id, wr.Workflow.Name, wr.Number, run.Number, run.VCSBranch)
+		AddWorkflowRunInfo(wr, false, sdk.SpawnMsg{
+			ID:   sdk.MsgWorkflowNodeConcurrencySuperseded.ID,
+			Args: []interface{}{nodeRun.WorkflowNodeName, run.Number, run.VCSBranch},
+		})
+		if err := UpdateWorkflowRun(ctx, db, wr); err != nil {
+			return nil, sdk.WrapError(err, "cancelSupersededNodeRuns> Unable to update workflow run %d", wr.ID)
+		}
+		r1, _, err := processWorkflowRun(ctx, db, store, p, wr, nil, nil, nil)
+		if err != nil {
+			return nil, sdk.WrapError(err, "cancelSupersededNodeRuns> Unable to reprocess workflow run %d", wr.ID)
+		}
+		_, _ = report.Merge(r1, nil)
+	}
+	return report, nil
+}
+
+// releaseConcurrencyGroup executes the next node run queued in the concurrency group of the terminated node run.
+// The queued node runs of other projects are started by the API, see LoadStartableConcurrencyNodeRunIDs.
+func releaseConcurrencyGroup(ctx context.Context, db gorp.SqlExecutor, store cache.Store, p *sdk.Project, n *sdk.WorkflowNodeRun) (*ProcessorReport, error) {
+	query := `
+	SELECT workflow_node_run.id
+	FROM workflow_node_run
+	JOIN workflow_run ON workflow_run.id = workflow_node_run.workflow_run_id
+	WHERE workflow_node_run.concurrency->>'key' = $1
+	AND workflow_node_run.concurrency->>'status' = $2
+	AND workflow_node_run.status = $3
+	AND workflow_run.project_id = $4
+	ORDER BY workflow_node_run.id
+	LIMIT 1`
+	id, err := db.SelectInt(query, n.Concurrency.Key, sdk.ConcurrencyQueued, sdk.StatusWaiting.String(), p.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, sdk.WrapError(err, "releaseConcurrencyGroup> Unable to load queued node run of concurrency group %s", n.Concurrency.Key)
+	}
+	if id == 0 {
+		return nil, nil
+	}
+	return StartQueuedNodeRun(ctx, db, store, p, id)
+}
+
+// LoadStartableConcurrencyNodeRunIDs loads the ids of the node runs queued in a concurrency group which is now free
+func LoadStartableConcurrencyNodeRunIDs(db gorp.SqlExecutor) ([]int64, error) {
+	query := `
+	SELECT queued.id
+	FROM workflow_node_run queued
+	WHERE queued.status = $1
+	AND queued.concurrency->>'status' = $2
+	AND NOT EXISTS (
+		SELECT 1
+		FROM workflow_node_run other
+		WHERE other.concurrency->>'key' = queued.concurrency->>'key'
+		AND other.id <> queued.id
+		AND other.status IN ($1, $3)
+		AND (other.concurrency->>'status' = $4 OR (other.concurrency->>'status' = $2 AND other.id < queued.id))
+	)`
+	var ids []int64
+	if _, err := db.Select(&ids, query, sdk.StatusWaiting.String(), sdk.ConcurrencyQueued, sdk.StatusBuilding.String(), sdk.ConcurrencyRunning); err != nil {
+		return nil, sdk.WrapError(err, "LoadStartableConcurrencyNodeRunIDs> Unable to load node runs")
+	}
+	return ids, nil
+}
+
+// StartQueuedNodeRun executes a node run queued in a concurrency group, if the group is free
+func StartQueuedNodeRun(ctx context.Context, db gorp.SqlExecutor, store cache.Store, p *sdk.Project, nodeRunID int64) (*ProcessorReport, error) {
+	nodeRun, err := LoadAndLockNodeRunByID(ctx, db, nodeRunID, true)
+	if err != nil {
+		return nil, sdk.WrapError(err, "StartQueuedNodeRun> Unable to load node run %d", nodeRunID)
+	}
+	if nodeRun.Concurrency == nil || nodeRun.Concurrency.Status != sdk.ConcurrencyQueued || nodeRun.Status != sdk.StatusWaiting.String() {
+		return nil, nil
+	}
+
+	if err := lockConcurrencyGroup(db, nodeRun.Concurrency.Key); err != nil {
+		return nil, err
+	}
+	holder, err := loadConcurrencyHolder(db, nodeRun.Concurrency.Key, nodeRun.ID)
+	if err != nil {
+		return nil, err
+	}
+	if holder != nil {
+		return nil, nil
+	}
+
+	nodeRun.Concurrency.Status = sdk.ConcurrencyRunning
+	if err := UpdateNodeRun(db, nodeRun); err != nil {
+		return nil, sdk.WrapError(err, "StartQueuedNodeRun> Unable to update node run %d", nodeRun.ID)
+	}
+
+	wr, err := LoadRunByID(db, nodeRun.WorkflowRunID, LoadRunOptions{})
+	if err != nil {
+		return nil, sdk.WrapError(err, "StartQueuedNodeRun> Unable to load workflow run %d", nodeRun.WorkflowRunID)
+	}
+	AddWorkflowRunInfo(wr, false, sdk.SpawnMsg{
+		ID:   sdk.MsgWorkflowNodeConcurrencyRelease.ID,
+		Args: []interface{}{nodeRun.Concurrency.Group, nodeRun.WorkflowNodeName},
+	})
+	if err := UpdateWorkflowRun(ctx, db, wr); err != nil {
+		return nil, sdk.WrapError(err, "StartQueuedNodeRun> Unable to update workflow run %d", wr.ID)
+	}
+
+	log.Debug("StartQueuedNodeRun> process the node run %d because concurrency group %s has been released", nodeRun.ID, nodeRun.Concurrency.Key)
+	return execute(ctx, db, store, p, nodeRun)
+}
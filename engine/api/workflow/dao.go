@@ -25,6 +25,7 @@ import (
 	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/role"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/exportentities"
 	"github.com/ovh/cds/sdk/log"
@@ -249,6 +250,7 @@ func Load(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj *sdk
 		workflow.metadata,
 		workflow.history_length,
 		workflow.purge_tags,
+		workflow.cancel_superseded_runs,
 		workflow.from_repository,
 		workflow.derived_from_workflow_id,
 		workflow.derived_from_workflow_name,
@@ -501,7 +503,7 @@ func Insert(db gorp.SqlExecutor, store cache.Store, w *sdk.Workflow, p *sdk.Proj
 	}
 
 	w.LastModified = time.Now()
-	if err := db.QueryRow("INSERT INTO workflow (name, description, icon, project_id, history_length, from_repository) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id", w.Name, w.Description, w.Icon, w.ProjectID, w.HistoryLength, w.FromRepository).Scan(&w.ID); err != nil {
+	if err := db.QueryRow("INSERT INTO workflow (name, description, icon, project_id, history_length, cancel_superseded_runs, from_repository) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id", w.Name, w.Description, w.Icon, w.ProjectID, w.HistoryLength, w.CancelSupersededRuns, w.FromRepository).Scan(&w.ID); err != nil {
 		return sdk.WrapError(err, "Insert> Unable to insert workflow %s/%s", w.ProjectKey, w.Name)
 	}
 
@@ -660,6 +662,16 @@ func Update(db gorp.SqlExecutor, store cache.Store, w *sdk.Workflow, oldWorkflow
 		return sdk.WrapError(err, "Update> cannot check pipeline name")
 	}
 
+	// The hooks are loaded with masked secrets, restore them before deleting the old hooks
+	if err := keepHookSecrets(db, oldWorkflow, w); err != nil {
+		return sdk.WrapError(err, "Update> unable to keep hook secrets on workflow(%d)", w.ID)
+	}
+
+	// The webhook notifications are loaded with masked secrets, restore them before deleting the old notifications
+	if err := keepNotificationSecrets(db, oldWorkflow, w); err != nil {
+		return sdk.WrapError(err, "Update> unable to keep notification secrets on workflow(%d)", w.ID)
+	}
+
 	// Delete all OLD JOIN
 	for _, j := range oldWorkflow.Joins {
 		if err := deleteJoin(db, j); err != nil {
@@ -718,6 +730,11 @@ func Update(db gorp.SqlExecutor, store cache.Store, w *sdk.Workflow, oldWorkflow
 		w.Icon = oldWorkflow.Icon
 	}
 
+	// The role bindings are matched by name
+	if err := role.RenameBindingsObject(db, oldWorkflow.ProjectID, sdk.RoleBindingWorkflow, oldWorkflow.Name, w.Name); err != nil {
+		return sdk.WrapError(err, "Update> unable to rename role bindings of workflow(%d)", w.ID)
+	}
+
 	w.LastModified = time.Now()
 	dbw := Workflow(*w)
 	if _, err := db.Update(&dbw); err != nil {
@@ -763,6 +780,11 @@ func Delete(ctx context.Context, db gorp.SqlExecutor, store cache.Store, p *sdk.
 		return sdk.WrapError(err, "Delete> Unable to delete workflow root")
 	}
 
+	// Delete role bindings, they are not linked to the workflow by a foreign key
+	if err := role.DeleteBindingsByObject(db, w.ProjectID, sdk.RoleBindingWorkflow, w.Name); err != nil {
+		return sdk.WrapError(err, "Delete> Unable to delete role bindings")
+	}
+
 	//Delete workflow
 	dbw := Workflow(*w)
 	if _, err := db.Delete(&dbw); err != nil {
@@ -811,6 +833,15 @@ func IsValid(w *sdk.Workflow, proj *sdk.Project) error {
 		}
 	}
 
+	//Check webhook notifications
+	for _, n := range w.Notifications {
+		if s, ok := n.Settings.(*sdk.WebhookUserNotificationSettings); ok {
+			if err := s.IsValid(); err != nil {
+				return err
+			}
+		}
+	}
+
 	//Checks application are in the current project
 	apps := w.InvolvedApplications()
 	for _, appID := range apps {
@@ -884,6 +915,12 @@ func IsValid(w *sdk.Workflow, proj *sdk.Project) error {
 
 // Push push a workflow from cds files
 func Push(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *sdk.Project, tr *tar.Reader, opts *PushOption, u *sdk.User, decryptFunc keys.DecryptFunc) ([]sdk.Message, *sdk.Workflow, error) {
+	return PushWithFunc(ctx, db, store, proj, tr, opts, u, decryptFunc, nil)
+}
+
+// PushWithFunc push a workflow from cds files like Push, f is called with the transaction of the push
+// before committing it so the changes made by f are saved with the workflow
+func PushWithFunc(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *sdk.Project, tr *tar.Reader, opts *PushOption, u *sdk.User, decryptFunc keys.DecryptFunc, f func(tx gorp.SqlExecutor, wf *sdk.Workflow) error) ([]sdk.Message, *sdk.Workflow, error) {
 	ctx, end := observability.Span(ctx, "workflow.Push")
 	defer end()
 
@@ -1062,7 +1099,7 @@ func Push(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *sdk.Proj
 			if len(wf.Root.Hooks) == 0 {
 				wf.Root.Hooks = append(wf.Root.Hooks, sdk.WorkflowNodeHook{
 					WorkflowHookModel: sdk.RepositoryWebHookModel,
-					Config:            sdk.RepositoryWebHookModel.DefaultConfig,
+					Config:            sdk.RepositoryWebHookModel.DefaultConfig.Clone(),
 					UUID:              opts.HookUUID,
 				})
 				if wf.Root.Context.DefaultPayload, err = DefaultPayload(ctx, tx, store, proj, u, wf); err != nil {
@@ -1091,6 +1128,12 @@ func Push(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *sdk.Proj
 
 	allMsg = append(allMsg, msgList...)
 
+	if f != nil {
+		if err := f(tx, wf); err != nil {
+			return nil, nil, sdk.WrapError(err, "Push>")
+		}
+	}
+
 	isDefaultBranch := false
 	if opts != nil {
 		isDefaultBranch = opts.IsDefaultBranch
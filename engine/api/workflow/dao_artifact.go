@@ -1,7 +1,11 @@
 package workflow
 
 import (
+	"database/sql"
+	"time"
+
 	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
 
 	"github.com/ovh/cds/sdk"
 )
@@ -22,7 +26,8 @@ func LoadWorkfowArtifactByHash(db gorp.SqlExecutor, hash string) (*sdk.WorkflowN
 				object_path,
 				created,
 				workflow_run_id,
-				coalesce(sha512sum, '') AS sha512sum
+				coalesce(sha512sum, '') AS sha512sum,
+				coalesce(blob_sha512sum, '') AS blob_sha512sum
 		  FROM workflow_node_run_artifacts
 		  WHERE workflow_node_run_artifacts.download_hash = $1`
 	if err := db.SelectOne(&artGorp, query, hash); err != nil {
@@ -50,7 +55,8 @@ func LoadArtifactByIDs(db gorp.SqlExecutor, workflowID, artifactID int64) (*sdk.
 			workflow_node_run_artifacts.object_path,
 			workflow_node_run_artifacts.created,
 			workflow_node_run_artifacts.workflow_run_id,
-			coalesce(workflow_node_run_artifacts.sha512sum, '') AS sha512sum
+			coalesce(workflow_node_run_artifacts.sha512sum, '') AS sha512sum,
+			coalesce(workflow_node_run_artifacts.blob_sha512sum, '') AS blob_sha512sum
 		FROM workflow_node_run_artifacts
 		JOIN workflow_run ON workflow_run.id = workflow_node_run_artifacts.workflow_run_id
 		WHERE workflow_run.workflow_id = $1 AND workflow_node_run_artifacts.id = $2
@@ -78,7 +84,8 @@ func loadArtifactByNodeRunID(db gorp.SqlExecutor, nodeRunID int64) ([]sdk.Workfl
 			object_path,
 			created,
 			workflow_run_id,
-			coalesce(sha512sum, '') AS sha512sum
+			coalesce(sha512sum, '') AS sha512sum,
+			coalesce(blob_sha512sum, '') AS blob_sha512sum
 		FROM workflow_node_run_artifacts WHERE workflow_node_run_id = $1`, nodeRunID); err != nil {
 		return nil, err
 	}
@@ -90,6 +97,36 @@ func loadArtifactByNodeRunID(db gorp.SqlExecutor, nodeRunID int64) ([]sdk.Workfl
 	return artifacts, nil
 }
 
+// loadArtifactsWithoutBlob loads the artifacts of the workflow runs which have their own object
+func loadArtifactsWithoutBlob(db gorp.SqlExecutor, runIDs []int64) ([]sdk.WorkflowNodeRunArtifact, error) {
+	var artifactsGorp []NodeRunArtifact
+	if _, err := db.Select(&artifactsGorp, `SELECT
+			id,
+			name,
+			tag,
+			coalesce(ref, '') AS ref,
+			workflow_node_run_id,
+			download_hash,
+			size,
+			perm,
+			md5sum,
+			object_path,
+			created,
+			workflow_run_id,
+			coalesce(sha512sum, '') AS sha512sum,
+			'' AS blob_sha512sum
+		FROM workflow_node_run_artifacts
+		WHERE workflow_run_id = ANY($1) AND blob_sha512sum IS NULL AND object_path <> ''`, pq.Int64Array(runIDs)); err != nil {
+		return nil, sdk.WrapError(err, "loadArtifactsWithoutBlob> Unable to load artifacts")
+	}
+
+	artifacts := make([]sdk.WorkflowNodeRunArtifact, len(artifactsGorp))
+	for i := range artifactsGorp {
+		artifacts[i] = sdk.WorkflowNodeRunArtifact(artifactsGorp[i])
+	}
+	return artifacts, nil
+}
+
 // InsertArtifact insert in table workflow_artifacts
 func InsertArtifact(db gorp.SqlExecutor, a *sdk.WorkflowNodeRunArtifact) error {
 	wArtifactDB := NodeRunArtifact(*a)
@@ -99,3 +136,88 @@ func InsertArtifact(db gorp.SqlExecutor, a *sdk.WorkflowNodeRunArtifact) error {
 	a.ID = wArtifactDB.ID
 	return nil
 }
+
+func loadArtifactBlob(db gorp.SqlExecutor, sha512sum string) (*sdk.WorkflowArtifactBlob, error) {
+	var blob artifactBlob
+	if err := db.SelectOne(&blob, "SELECT * FROM workflow_artifact_blob WHERE sha512sum = $1", sha512sum); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, sdk.WrapError(err, "loadArtifactBlob> Unable to load blob %s", sha512sum)
+	}
+	b := sdk.WorkflowArtifactBlob(blob)
+	return &b, nil
+}
+
+// loadProjectArtifactBlob loads the blob only if it is referenced by an artifact of the project of the workflow run
+func loadProjectArtifactBlob(db gorp.SqlExecutor, workflowRunID int64, sha512sum string) (*sdk.WorkflowArtifactBlob, error) {
+	var blob artifactBlob
+	query := `SELECT * FROM workflow_artifact_blob
+		WHERE sha512sum = $1
+		AND EXISTS (
+			SELECT 1 FROM workflow_node_run_artifacts
+			JOIN workflow_run ON workflow_run.id = workflow_node_run_artifacts.workflow_run_id
+			WHERE workflow_node_run_artifacts.blob_sha512sum = workflow_artifact_blob.sha512sum
+			AND workflow_run.project_id = (SELECT project_id FROM workflow_run WHERE id = $2)
+		)`
+	if err := db.SelectOne(&blob, query, sha512sum, workflowRunID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, sdk.WrapError(err, "loadProjectArtifactBlob> Unable to load blob %s", sha512sum)
+	}
+	b := sdk.WorkflowArtifactBlob(blob)
+	return &b, nil
+}
+
+func insertArtifactBlob(db gorp.SqlExecutor, b *sdk.WorkflowArtifactBlob) error {
+	b.Created = time.Now()
+	b.LastUsed = b.Created
+	blob := artifactBlob(*b)
+	if err := db.Insert(&blob); err != nil {
+		return sdk.WrapError(err, "insertArtifactBlob> Unable to insert blob %s", b.SHA512sum)
+	}
+	return nil
+}
+
+// touchArtifactBlob prevents the blob to be purged while a new artifact references it
+func touchArtifactBlob(db gorp.SqlExecutor, sha512sum string) error {
+	if _, err := db.Exec("UPDATE workflow_artifact_blob SET last_used = $2 WHERE sha512sum = $1", sha512sum, time.Now()); err != nil {
+		return sdk.WrapError(err, "touchArtifactBlob> Unable to update blob %s", sha512sum)
+	}
+	return nil
+}
+
+// loadUnreferencedArtifactBlobs returns the blobs referenced by no artifact and unused since given date
+func loadUnreferencedArtifactBlobs(db gorp.SqlExecutor, before time.Time, limit int) ([]sdk.WorkflowArtifactBlob, error) {
+	var blobs []artifactBlob
+	query := `SELECT * FROM workflow_artifact_blob
+		WHERE last_used < $1
+		AND NOT EXISTS (
+			SELECT 1 FROM workflow_node_run_artifacts WHERE workflow_node_run_artifacts.blob_sha512sum = workflow_artifact_blob.sha512sum
+		)
+		LIMIT $2`
+	if _, err := db.Select(&blobs, query, before, limit); err != nil {
+		return nil, sdk.WrapError(err, "loadUnreferencedArtifactBlobs> Unable to load blobs")
+	}
+	res := make([]sdk.WorkflowArtifactBlob, len(blobs))
+	for i := range blobs {
+		res[i] = sdk.WorkflowArtifactBlob(blobs[i])
+	}
+	return res, nil
+}
+
+// deleteArtifactBlob deletes the blob only if it is still unreferenced
+func deleteArtifactBlob(db gorp.SqlExecutor, sha512sum string, before time.Time) (bool, error) {
+	query := `DELETE FROM workflow_artifact_blob
+		WHERE sha512sum = $1 AND last_used < $2
+		AND NOT EXISTS (
+			SELECT 1 FROM workflow_node_run_artifacts WHERE workflow_node_run_artifacts.blob_sha512sum = workflow_artifact_blob.sha512sum
+		)`
+	res, err := db.Exec(query, sha512sum, before)
+	if err != nil {
+		return false, sdk.WrapError(err, "deleteArtifactBlob> Unable to delete blob %s", sha512sum)
+	}
+	n, _ := res.RowsAffected()
+	return n == 1, nil
+}
@@ -2,12 +2,14 @@ package workflow
 
 import (
 	"database/sql"
+	"encoding/base64"
 	"fmt"
 	"time"
 
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/engine/api/sessionstore"
 	"github.com/ovh/cds/sdk"
 )
@@ -61,6 +63,14 @@ func insertHook(db gorp.SqlExecutor, node *sdk.WorkflowNode, hook *sdk.WorkflowN
 	if _, ok := hook.Config[sdk.SchedulerModelPayload]; hook.WorkflowHookModel.Name == sdk.SchedulerModelName && !ok {
 		hook.Config[sdk.SchedulerModelPayload] = sdk.SchedulerModel.DefaultConfig[sdk.SchedulerModelPayload]
 	}
+	// Hooks created before these configuration keys were added to the models get the default values
+	for _, k := range []string{sdk.RepositoryWebHookModelEvents, sdk.WebHookModelConfigSecret, sdk.WebHookModelConfigSignature,
+		sdk.HookConfigBranches, sdk.HookConfigTags, sdk.HookConfigIncludePaths, sdk.HookConfigExcludePaths, sdk.HookModelFilter} {
+		v, inModel := hook.WorkflowHookModel.DefaultConfig[k]
+		if _, ok := hook.Config[k]; inModel && !ok {
+			hook.Config[k] = v
+		}
+	}
 
 	errmu := sdk.MultiError{}
 	// Check configuration of the hook vs the model
@@ -101,7 +111,11 @@ func insertHook(db gorp.SqlExecutor, node *sdk.WorkflowNode, hook *sdk.WorkflowN
 
 //PostInsert is a db hook
 func (r *NodeHook) PostInsert(db gorp.SqlExecutor) error {
-	sConfig, errgo := gorpmapping.JSONToNullString(r.Config)
+	config, err := encryptHookSecrets(db, r.ID, r.Config)
+	if err != nil {
+		return err
+	}
+	sConfig, errgo := gorpmapping.JSONToNullString(config)
 	if errgo != nil {
 		return errgo
 	}
@@ -112,18 +126,123 @@ func (r *NodeHook) PostInsert(db gorp.SqlExecutor) error {
 	return nil
 }
 
-//PostGet is a db hook
-func (r *NodeHook) PostGet(db gorp.SqlExecutor) error {
+// loadHookConfig loads the configuration of a hook as stored in database, with its secrets encrypted
+func loadHookConfig(db gorp.SqlExecutor, id int64) (sdk.WorkflowNodeHookConfig, error) {
 	var res = struct {
 		Config sql.NullString `db:"config"`
 	}{}
-	if err := db.SelectOne(&res, "select config from workflow_node_hook where id = $1", r.ID); err != nil {
-		return err
+	if err := db.SelectOne(&res, "select config from workflow_node_hook where id = $1", id); err != nil && err != sql.ErrNoRows {
+		return nil, err
 	}
 
 	conf := sdk.WorkflowNodeHookConfig{}
-
 	if err := gorpmapping.JSONNullString(res.Config, &conf); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+// encryptHookSecrets returns a copy of the configuration with its secrets encrypted.
+// A masked secret keeps the value stored for the hook.
+func encryptHookSecrets(db gorp.SqlExecutor, id int64, cfg sdk.WorkflowNodeHookConfig) (sdk.WorkflowNodeHookConfig, error) {
+	var stored sdk.WorkflowNodeHookConfig
+	res := cfg.Clone()
+	for k, v := range res {
+		if !cfg.IsSecret(k) || v.Value == "" {
+			continue
+		}
+		if v.Value == sdk.PasswordPlaceholder {
+			if stored == nil {
+				var err error
+				if stored, err = loadHookConfig(db, id); err != nil {
+					return nil, sdk.WrapError(err, "encryptHookSecrets> Cannot load hook %d", id)
+				}
+			}
+			v.Value = stored[k].Value
+		} else {
+			encrypted, err := secret.Encrypt([]byte(v.Value))
+			if err != nil {
+				return nil, sdk.WrapError(err, "encryptHookSecrets> Cannot encrypt %s", k)
+			}
+			v.Value = base64.StdEncoding.EncodeToString(encrypted)
+		}
+		res[k] = v
+	}
+	return res, nil
+}
+
+// decryptHookSecrets decrypts the secrets of the configuration
+func decryptHookSecrets(cfg sdk.WorkflowNodeHookConfig) error {
+	for k, v := range cfg {
+		if !cfg.IsSecret(k) || v.Value == "" {
+			continue
+		}
+		b, err := base64.StdEncoding.DecodeString(v.Value)
+		if err != nil {
+			return sdk.WrapError(err, "decryptHookSecrets> Cannot decode %s", k)
+		}
+		clear, err := secret.Decrypt(b)
+		if err != nil {
+			return sdk.WrapError(err, "decryptHookSecrets> Cannot decrypt %s", k)
+		}
+		v.Value = string(clear)
+		cfg[k] = v
+	}
+	return nil
+}
+
+// restoreHookSecrets sets the secrets of the hook which are masked or missing to the values stored for the hook uuid
+func restoreHookSecrets(db gorp.SqlExecutor, uuid string, h *sdk.WorkflowNodeHook) error {
+	if uuid == "" {
+		return nil
+	}
+	stored, err := LoadHookByUUID(db, uuid)
+	if err != nil {
+		return sdk.WrapError(err, "restoreHookSecrets> Cannot load hook %s", uuid)
+	}
+	if stored == nil {
+		return nil
+	}
+	if h.Config == nil {
+		h.Config = sdk.WorkflowNodeHookConfig{}
+	}
+	for k, v := range stored.Config {
+		if !stored.Config.IsSecret(k) {
+			continue
+		}
+		if nv, ok := h.Config[k]; !ok || nv.Value == sdk.PasswordPlaceholder {
+			h.Config[k] = v
+		}
+	}
+	return nil
+}
+
+// keepHookSecrets restores the secrets of the hooks of w which are masked or missing from the hooks with the same reference in oldW
+func keepHookSecrets(db gorp.SqlExecutor, oldW *sdk.Workflow, w *sdk.Workflow) error {
+	if oldW == nil || oldW.Root == nil || w.Root == nil {
+		return nil
+	}
+	oldHooks := oldW.GetHooks()
+	var err error
+	w.Visit(func(n *sdk.WorkflowNode) {
+		for i := range n.Hooks {
+			for _, o := range oldHooks {
+				if err == nil && o.Ref == n.Hooks[i].Ref {
+					err = restoreHookSecrets(db, o.UUID, &n.Hooks[i])
+				}
+			}
+		}
+	})
+	return err
+}
+
+//PostGet is a db hook
+func (r *NodeHook) PostGet(db gorp.SqlExecutor) error {
+	conf, err := loadHookConfig(db, r.ID)
+	if err != nil {
+		return err
+	}
+	if err := decryptHookSecrets(conf); err != nil {
 		return err
 	}
 
@@ -174,6 +293,8 @@ func loadHooks(db gorp.SqlExecutor, node *sdk.WorkflowNode) ([]sdk.WorkflowNodeH
 		if err := res[i].PostGet(db); err != nil {
 			return nil, sdk.WrapError(err, "loadHooks")
 		}
+		// the secrets are only sent to the hooks µService, never in the workflow
+		res[i].Config.MaskSecrets()
 		res[i].WorkflowNodeID = node.ID
 		nodes = append(nodes, sdk.WorkflowNodeHook(res[i]))
 	}
@@ -15,6 +15,7 @@ import (
 	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/expression"
 	"github.com/ovh/cds/sdk/log"
 )
 
@@ -209,6 +210,8 @@ type sqlContext struct {
 	DefaultPipelineParameters sql.NullString `db:"default_pipeline_parameters"`
 	Conditions                sql.NullString `db:"conditions"`
 	Mutex                     sql.NullBool   `db:"mutex"`
+	Approval                  sql.NullString `db:"approval"`
+	Concurrency               sql.NullString `db:"concurrency"`
 }
 
 // UpdateNodeContext updates the node context in database
@@ -256,12 +259,47 @@ func UpdateNodeContext(db gorp.SqlExecutor, c *sdk.WorkflowNodeContext) error {
 		}
 	}
 
+	if c.Conditions.Expression != "" {
+		if c.Conditions.LuaScript != "" {
+			httpErr := sdk.ErrWorkflowConditionBadExpression
+			httpErr.Message = fmt.Sprintf("%s: an expression cannot be set with a lua script", httpErr)
+			return sdk.NewError(httpErr, fmt.Errorf("expression and lua script both set on node context %d", c.ID))
+		}
+		if err := expression.Check(c.Conditions.Expression); err != nil {
+			httpErr := sdk.ErrWorkflowConditionBadExpression
+			httpErr.Message = fmt.Sprintf("%s: %v", httpErr, err)
+			return sdk.NewError(httpErr, err)
+		}
+	}
+
 	var errC error
 	sqlContext.Conditions, errC = gorpmapping.JSONToNullString(c.Conditions)
 	if errC != nil {
 		return sdk.WrapError(errC, "updateNodeContext> Unable to marshall workflow node context(%d) conditions", c.ID)
 	}
 
+	if c.Approval != nil {
+		if err := c.Approval.IsValid(); err != nil {
+			return err
+		}
+		var errA error
+		sqlContext.Approval, errA = gorpmapping.JSONToNullString(c.Approval)
+		if errA != nil {
+			return sdk.WrapError(errA, "updateNodeContext> Unable to marshall workflow node context(%d) approval", c.ID)
+		}
+	}
+
+	if c.Concurrency != nil {
+		if err := c.Concurrency.IsValid(); err != nil {
+			return err
+		}
+		var errC error
+		sqlContext.Concurrency, errC = gorpmapping.JSONToNullString(c.Concurrency)
+		if errC != nil {
+			return sdk.WrapError(errC, "updateNodeContext> Unable to marshall workflow node context(%d) concurrency", c.ID)
+		}
+	}
+
 	if _, err := db.Update(&sqlContext); err != nil {
 		return sdk.WrapError(err, "updateNodeContext> Unable to update workflow node context(%d)", c.ID)
 	}
@@ -398,7 +436,7 @@ func LoadNodeContext(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project,
 func postLoadNodeContext(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, u *sdk.User, ctx *sdk.WorkflowNodeContext, opts LoadOptions) error {
 	var sqlContext = sqlContext{}
 	if err := db.SelectOne(&sqlContext,
-		"select application_id, environment_id, default_payload, default_pipeline_parameters, conditions, mutex, project_platform_id from workflow_node_context where id = $1", ctx.ID); err != nil {
+		"select application_id, environment_id, default_payload, default_pipeline_parameters, conditions, mutex, approval, concurrency, project_platform_id from workflow_node_context where id = $1", ctx.ID); err != nil {
 		return err
 	}
 	if sqlContext.AppID.Valid {
@@ -424,6 +462,20 @@ func postLoadNodeContext(db gorp.SqlExecutor, store cache.Store, proj *sdk.Proje
 		return sdk.WrapError(err, "postLoadNodeContext> Unable to unmarshall context %d default pipeline parameters", ctx.ID)
 	}
 
+	if sqlContext.Approval.Valid {
+		ctx.Approval = new(sdk.WorkflowNodeApproval)
+		if err := gorpmapping.JSONNullString(sqlContext.Approval, ctx.Approval); err != nil {
+			return sdk.WrapError(err, "postLoadNodeContext> Unable to unmarshall context %d approval", ctx.ID)
+		}
+	}
+
+	if sqlContext.Concurrency.Valid {
+		ctx.Concurrency = new(sdk.WorkflowNodeConcurrency)
+		if err := gorpmapping.JSONNullString(sqlContext.Concurrency, ctx.Concurrency); err != nil {
+			return sdk.WrapError(err, "postLoadNodeContext> Unable to unmarshall context %d concurrency", ctx.ID)
+		}
+	}
+
 	//Load the application in the context
 	if ctx.ApplicationID != 0 {
 		app, err := application.LoadByID(db, store, ctx.ApplicationID, nil, application.LoadOptions.WithVariables, application.LoadOptions.WithDeploymentStrategies)
@@ -45,7 +45,9 @@ workflow_node_run.vcs_branch,
 workflow_node_run.vcs_tag,
 workflow_node_run.vcs_server,
 workflow_node_run.workflow_node_name,
-workflow_node_run.header
+workflow_node_run.header,
+workflow_node_run.approval,
+workflow_node_run.concurrency
 `
 
 const nodeRunTestsField string = ", workflow_node_run.tests"
@@ -310,6 +312,20 @@ func fromDBNodeRun(rr NodeRun, opts LoadRunOptions) (*sdk.WorkflowNodeRun, error
 		}
 	}
 
+	if rr.Approval.Valid {
+		r.Approval = new(sdk.WorkflowNodeRunApproval)
+		if err := gorpmapping.JSONNullString(rr.Approval, r.Approval); err != nil {
+			return nil, sdk.WrapError(err, "fromDBNodeRun>Error loading node run %d: Approval", r.ID)
+		}
+	}
+
+	if rr.Concurrency.Valid {
+		r.Concurrency = new(sdk.WorkflowNodeRunConcurrency)
+		if err := gorpmapping.JSONNullString(rr.Concurrency, r.Concurrency); err != nil {
+			return nil, sdk.WrapError(err, "fromDBNodeRun>Error loading node run %d: Concurrency", r.ID)
+		}
+	}
+
 	if rr.Tests.Valid {
 		r.Tests = new(venom.Tests)
 		if err := gorpmapping.JSONNullString(rr.Tests, r.Tests); err != nil {
@@ -418,6 +434,20 @@ func makeDBNodeRun(n sdk.WorkflowNodeRun) (*NodeRun, error) {
 		}
 		nodeRunDB.Commits = s
 	}
+	if n.Approval != nil {
+		s, err := gorpmapping.JSONToNullString(n.Approval)
+		if err != nil {
+			return nil, sdk.WrapError(err, "makeDBNodeRun> unable to get json from approval")
+		}
+		nodeRunDB.Approval = s
+	}
+	if n.Concurrency != nil {
+		s, err := gorpmapping.JSONToNullString(n.Concurrency)
+		if err != nil {
+			return nil, sdk.WrapError(err, "makeDBNodeRun> unable to get json from concurrency")
+		}
+		nodeRunDB.Concurrency = s
+	}
 	sh, err := gorpmapping.JSONToNullString(n.Header)
 	if err != nil {
 		return nil, sdk.WrapError(err, "makeDBNodeRun> unable to get json from header")
@@ -8,6 +8,7 @@ import (
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/notification"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -35,6 +36,10 @@ func loadNotifications(db gorp.SqlExecutor, w *sdk.Workflow) ([]sdk.WorkflowNoti
 		if errJ != nil {
 			return nil, sdk.WrapError(errJ, "loadNotification> Unable to load notification %d on workflow %d", id, w.ID)
 		}
+		// the secrets of the webhooks are only loaded to send the notifications, never in the workflow
+		if s, ok := n.Settings.(*sdk.WebhookUserNotificationSettings); ok {
+			n.Settings = s.WithMaskedSecrets()
+		}
 		notifications[index] = n
 	}
 
@@ -62,12 +67,46 @@ func loadNotification(db gorp.SqlExecutor, w *sdk.Workflow, id int64) (sdk.Workf
 	return n, nil
 }
 
+// keepNotificationSecrets restores the masked secrets of the webhook notifications of w from the notifications of oldW
+// with the same id, or with the same url if the notification has no id
+func keepNotificationSecrets(db gorp.SqlExecutor, oldW *sdk.Workflow, w *sdk.Workflow) error {
+	if oldW == nil {
+		return nil
+	}
+	for i := range w.Notifications {
+		n := &w.Notifications[i]
+		s, ok := n.Settings.(*sdk.WebhookUserNotificationSettings)
+		if !ok || !s.IsMasked() {
+			continue
+		}
+		for _, o := range oldW.Notifications {
+			oldS, ok := o.Settings.(*sdk.WebhookUserNotificationSettings)
+			if !ok || (n.ID != 0 && n.ID != o.ID) || (n.ID == 0 && oldS.URL != s.URL) {
+				continue
+			}
+			stored, err := notification.LoadWebhookSecrets(db, oldW.ID, o.ID, oldS.URL)
+			if err != nil {
+				return sdk.WrapError(err, "keepNotificationSecrets> Cannot load notification %d", o.ID)
+			}
+			n.Settings = s.WithRestoredSecrets(stored)
+			break
+		}
+	}
+	return nil
+}
+
 func insertNotification(db gorp.SqlExecutor, store cache.Store, w *sdk.Workflow, n *sdk.WorkflowNotification, nodes []sdk.WorkflowNode, u *sdk.User) error {
 	n.WorkflowID = w.ID
 	n.ID = 0
 	n.SourceNodeIDs = nil
 	dbNotif := Notification(*n)
 
+	if s, ok := n.Settings.(*sdk.WebhookUserNotificationSettings); ok {
+		if err := s.IsValid(); err != nil {
+			return sdk.WrapError(err, "insertNotification> Invalid webhook notification")
+		}
+	}
+
 	//Check references to sources
 	if len(n.SourceNodeRefs) == 0 {
 		return sdk.WrapError(sdk.ErrWorkflowNodeRef, "insertNotification> No notification references")
@@ -101,7 +140,14 @@ func insertNotification(db gorp.SqlExecutor, store cache.Store, w *sdk.Workflow,
 
 // PostInsert is a db hook
 func (no *Notification) PostInsert(db gorp.SqlExecutor) error {
-	b, err := json.Marshal(no.Settings)
+	settings := no.Settings
+	if s, ok := settings.(*sdk.WebhookUserNotificationSettings); ok {
+		var err error
+		if settings, err = notification.EncryptWebhookSecrets(s); err != nil {
+			return err
+		}
+	}
+	b, err := json.Marshal(settings)
 	if err != nil {
 		return err
 	}
@@ -123,5 +169,11 @@ func (no *Notification) PostGet(db gorp.SqlExecutor) error {
 
 	var errN error
 	no.Settings, errN = sdk.ParseWorkflowUserNotificationSettings(no.Type, []byte(res.Notification))
-	return sdk.WrapError(errN, "Notification.PostGet > Cannot parse user notification")
+	if errN != nil {
+		return sdk.WrapError(errN, "Notification.PostGet > Cannot parse user notification")
+	}
+	if s, ok := no.Settings.(*sdk.WebhookUserNotificationSettings); ok {
+		return notification.DecryptWebhookSecrets(s)
+	}
+	return nil
 }
@@ -286,6 +286,25 @@ func LoadAndLockRunByJobID(db gorp.SqlExecutor, id int64, loadOpts LoadRunOption
 	return loadRun(db, loadOpts, query, id)
 }
 
+// LoadSupersededRunIDs loads the ids of the runs of the workflow still waiting or building on the branch,
+// which have been started before the given run number
+func LoadSupersededRunIDs(db gorp.SqlExecutor, workflowID, number int64, branch string) ([]int64, error) {
+	query := `
+	SELECT DISTINCT workflow_run.id
+	FROM workflow_run
+	JOIN workflow_node_run ON workflow_node_run.workflow_run_id = workflow_run.id
+	WHERE workflow_run.workflow_id = $1
+	AND workflow_run.num < $2
+	AND workflow_run.status IN ($3, $4)
+	AND workflow_node_run.vcs_branch = $5
+	ORDER BY workflow_run.id`
+	var ids []int64
+	if _, err := db.Select(&ids, query, workflowID, number, sdk.StatusWaiting.String(), sdk.StatusBuilding.String(), branch); err != nil {
+		return nil, sdk.WrapError(err, "LoadSupersededRunIDs> Unable to load runs of workflow %d on branch %s", workflowID, branch)
+	}
+	return ids, nil
+}
+
 //LoadRuns loads all runs
 //It retuns runs, offset, limit count and an error
 func LoadRuns(db gorp.SqlExecutor, projectkey, workflowname string, offset, limit int, tagFilter map[string]string) ([]sdk.WorkflowRun, int, int, int, error) {
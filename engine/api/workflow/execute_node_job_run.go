@@ -266,6 +266,10 @@ func TakeNodeJobRun(ctx context.Context, dbFunc func() *gorp.DbMap, db gorp.SqlE
 	if err := checkStatusWaiting(store, jobID, job.Status); err != nil {
 		return nil, report, err
 	}
+	// a retried job is queued in the future, until the end of the backoff of its retry policy
+	if job.Queued.After(time.Now()) {
+		return nil, report, sdk.WrapError(sdk.ErrJobNotQueuedYet, "TakeNodeJobRun> job %d is queued at %s", jobID, job.Queued)
+	}
 
 	job.Model = workerModel
 	job.Job.WorkerName = workerName
@@ -396,7 +400,7 @@ func LoadNodeJobRunKeys(db gorp.SqlExecutor, store cache.Store, job *sdk.Workflo
 func LoadNodeJobRunSecrets(db gorp.SqlExecutor, store cache.Store, job *sdk.WorkflowNodeJobRun, nodeRun *sdk.WorkflowNodeRun, w *sdk.WorkflowRun, pv []sdk.Variable) ([]sdk.Variable, error) {
 	var secrets []sdk.Variable
 
-	pv = sdk.VariablesFilter(pv, sdk.SecretVariable, sdk.KeyVariable)
+	pv = sdk.VariablesFilter(pv, sdk.SecretVariable, sdk.KeyVariable, sdk.VaultVariable)
 	pv = sdk.VariablesPrefix(pv, "cds.proj.")
 	secrets = append(secrets, pv...)
 
@@ -413,7 +417,7 @@ func LoadNodeJobRunSecrets(db gorp.SqlExecutor, store cache.Store, job *sdk.Work
 		if errA != nil {
 			return nil, sdk.WrapError(errA, "LoadNodeJobRunSecrets> Cannot load application variables")
 		}
-		av = sdk.VariablesFilter(appv, sdk.SecretVariable, sdk.KeyVariable)
+		av = sdk.VariablesFilter(appv, sdk.SecretVariable, sdk.KeyVariable, sdk.VaultVariable)
 		av = sdk.VariablesPrefix(av, "cds.app.")
 
 		if err := application.DecryptVCSStrategyPassword(n.Context.Application); err != nil {
@@ -434,7 +438,7 @@ func LoadNodeJobRunSecrets(db gorp.SqlExecutor, store cache.Store, job *sdk.Work
 		if errE != nil {
 			return nil, sdk.WrapError(errE, "LoadNodeJobRunSecrets> Cannot load environment variables")
 		}
-		ev = sdk.VariablesFilter(envv, sdk.SecretVariable, sdk.KeyVariable)
+		ev = sdk.VariablesFilter(envv, sdk.SecretVariable, sdk.KeyVariable, sdk.VaultVariable)
 		ev = sdk.VariablesPrefix(ev, "cds.env.")
 	}
 	secrets = append(secrets, ev...)
@@ -546,27 +550,7 @@ func AddLog(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, logs *sdk.Log) err
 		logs.PipelineBuildJobID = job.ID
 		logs.PipelineBuildID = job.WorkflowNodeRunID
 	}
-
-	existingLogs, errLog := LoadStepLogs(db, logs.PipelineBuildJobID, logs.StepOrder)
-	if errLog != nil && errLog != sql.ErrNoRows {
-		return sdk.WrapError(errLog, "AddLog> Cannot load existing logs")
-	}
-
-	if existingLogs == nil {
-		if err := insertLog(db, logs); err != nil {
-			return sdk.WrapError(err, "AddLog> Cannot insert log")
-		}
-	} else {
-		logbuf := bytes.NewBufferString(existingLogs.Val)
-		logbuf.WriteString(logs.Val)
-		existingLogs.Val = logbuf.String()
-		existingLogs.LastModified = logs.LastModified
-		existingLogs.Done = logs.Done
-		if err := updateLog(db, existingLogs); err != nil {
-			return sdk.WrapError(err, "AddLog> Cannot update log")
-		}
-	}
-	return nil
+	return logStore.Append(db, logs)
 }
 
 //AddServiceLog adds a service log
@@ -616,12 +600,15 @@ func RestartWorkflowNodeJob(ctx context.Context, db gorp.SqlExecutor, wNodeJob s
 		step.Status = sdk.StatusWaiting.String()
 		step.Done = time.Time{}
 		if l != nil { // log could be nil here
-			l.Done = nil
-			logbuf := bytes.NewBufferString(l.Val)
-			logbuf.WriteString("\n\n\n-=-=-=-=-=- Worker timeout: job replaced in queue -=-=-=-=-=-\n\n\n")
-			l.Val = logbuf.String()
-			if err := updateLog(db, l); err != nil {
-				return sdk.WrapError(errL, "RestartWorkflowNodeJob> error while update step log")
+			restartLog := &sdk.Log{
+				PipelineBuildJobID: l.PipelineBuildJobID,
+				PipelineBuildID:    l.PipelineBuildID,
+				StepOrder:          l.StepOrder,
+				LastModified:       l.LastModified,
+				Val:                "\n\n\n-=-=-=-=-=- Worker timeout: job replaced in queue -=-=-=-=-=-\n\n\n",
+			}
+			if err := logStore.Append(db, restartLog); err != nil {
+				return sdk.WrapError(err, "RestartWorkflowNodeJob> error while update step log")
 			}
 		}
 	}
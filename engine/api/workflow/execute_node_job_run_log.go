@@ -9,8 +9,8 @@ import (
 	"github.com/ovh/cds/sdk"
 )
 
-//LoadStepLogs load logs (workflow_node_run_job_logs) for a job (workflow_node_run_job) for a specific step_order
-func LoadStepLogs(db gorp.SqlExecutor, id int64, order int64) (*sdk.Log, error) {
+//loadStepLogs load logs (workflow_node_run_job_logs) for a job (workflow_node_run_job) for a specific step_order
+func loadStepLogs(db gorp.SqlExecutor, id int64, order int64) (*sdk.Log, error) {
 	query := `
 		SELECT id, workflow_node_run_job_id, workflow_node_run_id, start, last_modified, done, step_order, value
 		FROM workflow_node_run_job_logs
@@ -39,8 +39,8 @@ func LoadStepLogs(db gorp.SqlExecutor, id int64, order int64) (*sdk.Log, error)
 	return logs, nil
 }
 
-//LoadLogs load logs (workflow_node_run_job_logs) for a job (workflow_node_run_job)
-func LoadLogs(db gorp.SqlExecutor, id int64) ([]sdk.Log, error) {
+//loadLogs load logs (workflow_node_run_job_logs) for a job (workflow_node_run_job)
+func loadLogs(db gorp.SqlExecutor, id int64) ([]sdk.Log, error) {
 	query := `
 		SELECT id, workflow_node_run_job_id, workflow_node_run_id, start, last_modified, done, step_order, value
 		FROM workflow_node_run_job_logs
@@ -50,6 +50,7 @@ func LoadLogs(db gorp.SqlExecutor, id int64) ([]sdk.Log, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 	var logs []sdk.Log
 	for rows.Next() {
 		l := &sdk.Log{}
@@ -44,6 +44,8 @@ func syncTakeJobInNodeRun(ctx context.Context, db gorp.SqlExecutor, n *sdk.Workf
 		rj := &stage.RunJobs[i]
 		if rj.ID == j.ID {
 			rj.Status = j.Status
+			rj.Retry = j.Retry
+			rj.Attempt = j.Attempt
 			rj.Start = j.Start
 			rj.Done = j.Done
 			rj.Model = j.Model
@@ -102,6 +104,16 @@ func execute(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj *
 		return nil, nil
 	}
 
+	//The node run cannot start until its approval gate is approved
+	if n.Approval != nil && n.Approval.Status != sdk.ApprovalApproved {
+		return nil, nil
+	}
+
+	//The node run waits for its concurrency group to be released
+	if n.Concurrency != nil && n.Concurrency.Status == sdk.ConcurrencyQueued {
+		return nil, nil
+	}
+
 	var newStatus = n.Status
 
 	//If no stages ==> success
@@ -130,7 +142,7 @@ func execute(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj *
 				//Insert data in workflow_node_run_job
 				log.Debug("workflow.execute> stage %s call addJobsToQueue", stage.Name)
 				var err error
-				report, err = report.Merge(addJobsToQueue(ctx, db, stage, wr, n))
+				report, err = report.Merge(addJobsToQueue(ctx, db, stage, wr, n, proj))
 				if err != nil {
 					return report, err
 				}
@@ -242,6 +254,15 @@ func execute(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj *
 			return nil, sdk.WrapError(err, "workflow.execute> Unable to delete node %d job runs ", n.ID)
 		}
 
+		//Do we release a concurrency group ?
+		if n.Concurrency != nil && n.Concurrency.Status == sdk.ConcurrencyRunning {
+			var err error
+			report, err = report.Merge(releaseConcurrencyGroup(ctx, db, store, proj, n))
+			if err != nil {
+				return nil, sdk.WrapError(err, "workflow.execute> Unable to release concurrency group %s", n.Concurrency.Key)
+			}
+		}
+
 		node := updatedWorkflowRun.Workflow.GetNode(n.WorkflowNodeID)
 		//Do we release a mutex ?
 		//Try to find one node run of the same node from the same workflow at status Waiting
@@ -255,9 +276,11 @@ func execute(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj *
 			where workflow.id = $1
 			and workflow_node_run.workflow_node_name = $2
 			and workflow_node_run.status = $3
+			and (workflow_node_run.approval is null or workflow_node_run.approval->>'status' = $4)
+			and (workflow_node_run.concurrency is null or workflow_node_run.concurrency->>'status' <> $5)
 			order by workflow_node_run.start asc
 			limit 1`
-			waitingRunID, errID := db.SelectInt(mutexQuery, updatedWorkflowRun.WorkflowID, node.Name, string(sdk.StatusWaiting))
+			waitingRunID, errID := db.SelectInt(mutexQuery, updatedWorkflowRun.WorkflowID, node.Name, string(sdk.StatusWaiting), sdk.ApprovalApproved, sdk.ConcurrencyQueued)
 			if errID != nil && errID != sql.ErrNoRows {
 				log.Error("workflow.execute> Unable to load mutex-locked workflow node run ID: %v", errID)
 				return report, nil
@@ -293,7 +316,7 @@ func execute(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj *
 
 			log.Debug("workflow.execute> process the node run %d because mutex has been released", waitingRun.ID)
 			var err error
-			report, err = report.Merge(execute(ctx, db, store, proj, waitingRun))
+			report, err = report.Merge(executeInConcurrencyGroup(ctx, db, store, proj, workflowRun, node, waitingRun))
 			if err != nil {
 				return nil, sdk.WrapError(err, "workflow.execute> Unable to reprocess workflow")
 			}
@@ -304,7 +327,7 @@ func execute(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj *
 	return report, nil
 }
 
-func addJobsToQueue(ctx context.Context, db gorp.SqlExecutor, stage *sdk.Stage, wr *sdk.WorkflowRun, run *sdk.WorkflowNodeRun) (*ProcessorReport, error) {
+func addJobsToQueue(ctx context.Context, db gorp.SqlExecutor, stage *sdk.Stage, wr *sdk.WorkflowRun, run *sdk.WorkflowNodeRun, proj *sdk.Project) (*ProcessorReport, error) {
 	var end func()
 	ctx, end = observability.Span(ctx, "workflow.addJobsToQueue")
 	defer end()
@@ -340,13 +363,15 @@ func addJobsToQueue(ctx context.Context, db gorp.SqlExecutor, stage *sdk.Stage,
 	next()
 
 	skippedOrDisabledJobs := 0
+	jobVariants := stageJobVariants(stage)
 	//Browse the jobs
-	for j := range stage.Jobs {
-		job := &stage.Jobs[j]
+	for j := range jobVariants {
+		job := &jobVariants[j].job
+		variant := jobVariants[j].values
 		errs := sdk.MultiError{}
 		//Process variables for the jobs
 		_, next = observability.Span(ctx, "workflow..getNodeJobRunParameters")
-		jobParams, errParam := getNodeJobRunParameters(db, *job, run, stage)
+		jobParams, errParam := getNodeJobRunParameters(db, *job, run, stage, variant)
 		next()
 
 		if errParam != nil {
@@ -354,7 +379,7 @@ func addJobsToQueue(ctx context.Context, db gorp.SqlExecutor, stage *sdk.Stage,
 		}
 
 		_, next = observability.Span(ctx, "workflow.getNodeJobRunRequirements")
-		jobRequirements, containsService, modelType, errReq := getNodeJobRunRequirements(db, *job, run)
+		jobRequirements, containsService, modelType, errReq := getNodeJobRunRequirements(db, *job, run, variant)
 		next()
 
 		if errReq != nil {
@@ -388,6 +413,13 @@ func addJobsToQueue(ctx context.Context, db gorp.SqlExecutor, stage *sdk.Stage,
 			ModelType:       modelType,
 		}
 		wjob.Job.Job.Action.Requirements = jobRequirements // Set the interpolated requirements on the job run only
+		if wjob.Job.Timeout == 0 && proj != nil {
+			wjob.Job.Timeout = proj.JobTimeout
+		}
+		if variant != nil {
+			wjob.Job.MatrixVariant = jobVariants[j].name
+			wjob.Job.Job.Action.Name = fmt.Sprintf("%s (%s)", job.Action.Name, jobVariants[j].name)
+		}
 
 		if !stage.Enabled || !wjob.Job.Enabled {
 			wjob.Status = sdk.StatusDisabled.String()
@@ -428,13 +460,37 @@ func addJobsToQueue(ctx context.Context, db gorp.SqlExecutor, stage *sdk.Stage,
 		report.Add(wjob)
 	}
 
-	if skippedOrDisabledJobs == len(stage.Jobs) {
+	if skippedOrDisabledJobs == len(jobVariants) {
 		stage.Status = sdk.StatusSkipped
 	}
 
 	return report, nil
 }
 
+// jobVariant is a job of a stage, or one of the variants of a matrix job
+type jobVariant struct {
+	job    sdk.Job
+	values map[string]string
+	name   string
+}
+
+// stageJobVariants returns the jobs of the stage, each matrix job being expanded into its variants
+func stageJobVariants(stage *sdk.Stage) []jobVariant {
+	res := make([]jobVariant, 0, len(stage.Jobs))
+	for _, j := range stage.Jobs {
+		if j.Matrix == nil {
+			res = append(res, jobVariant{job: j})
+			continue
+		}
+		for _, values := range j.Matrix.Variants() {
+			v := jobVariant{job: j, values: values, name: j.Matrix.VariantName(values)}
+			v.job.Action.Requirements = j.Matrix.VariantRequirements(values, j.Action.Requirements)
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
 func getPlatformPluginBinaries(db gorp.SqlExecutor, wr *sdk.WorkflowRun, run *sdk.WorkflowNodeRun) ([]sdk.GRPCPluginBinary, error) {
 	node := wr.Workflow.GetNode(run.WorkflowNodeID)
 	if node == nil {
@@ -512,6 +568,8 @@ func syncStage(db gorp.SqlExecutor, store cache.Store, stage *sdk.Stage) (bool,
 				runJob.Job.StepStatus = runJobDB.Job.StepStatus
 			} else {
 				runJob.Status = runJobDB.Status
+				runJob.Retry = runJobDB.Retry
+				runJob.Attempt = runJobDB.Attempt
 				runJob.Start = runJobDB.Start
 				runJob.Done = runJobDB.Done
 				runJob.Model = runJobDB.Model
@@ -573,7 +631,7 @@ func NodeBuildParametersFromRun(wr sdk.WorkflowRun, id int64) ([]sdk.Parameter,
 	return params, nil
 }
 
-//NodeBuildParametersFromWorkflow returns build_parameters for a node given its id
+// NodeBuildParametersFromWorkflow returns build_parameters for a node given its id
 func NodeBuildParametersFromWorkflow(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, wf *sdk.Workflow, refNode *sdk.WorkflowNode, ancestorsIds []int64) ([]sdk.Parameter, error) {
 
 	res := []sdk.Parameter{}
@@ -771,15 +829,16 @@ func SyncNodeRunRunJob(ctx context.Context, db gorp.SqlExecutor, nodeRun *sdk.Wo
 }
 
 type vcsInfos struct {
-	Repository string
-	Tag        string
-	Branch     string
-	Hash       string
-	Author     string
-	Message    string
-	URL        string
-	HTTPUrl    string
-	Server     string
+	Repository  string
+	Tag         string
+	Branch      string
+	Hash        string
+	Author      string
+	AuthorEmail string
+	Message     string
+	URL         string
+	HTTPUrl     string
+	Server      string
 }
 
 func (i vcsInfos) String() string {
@@ -793,6 +852,7 @@ func getVCSInfos(ctx context.Context, db gorp.SqlExecutor, store cache.Store, vc
 	vcsInfos.Tag = gitValues[tagGitTag]
 	vcsInfos.Hash = gitValues[tagGitHash]
 	vcsInfos.Author = gitValues[tagGitAuthor]
+	vcsInfos.AuthorEmail = gitValues[tagGitAuthorEmail]
 	vcsInfos.Message = gitValues[tagGitMessage]
 	vcsInfos.URL = gitValues[tagGitURL]
 	vcsInfos.HTTPUrl = gitValues[tagGitHTTPURL]
@@ -929,6 +989,7 @@ func getVCSInfos(ctx context.Context, db gorp.SqlExecutor, store cache.Store, vc
 		}
 	}
 	vcsInfos.Author = commit.Author.Name
+	vcsInfos.AuthorEmail = commit.Author.Email
 	vcsInfos.Message = commit.Message
 
 	return vcsInfos, nil
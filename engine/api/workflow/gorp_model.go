@@ -70,6 +70,8 @@ type NodeRun struct {
 	VCSHash            sql.NullString `db:"vcs_hash"`
 	VCSServer          sql.NullString `db:"vcs_server"`
 	Header             sql.NullString `db:"header"`
+	Approval           sql.NullString `db:"approval"`
+	Concurrency        sql.NullString `db:"concurrency"`
 }
 
 // JobRun is a gorp wrapper around sdk.WorkflowNodeJobRun
@@ -81,6 +83,7 @@ type JobRun struct {
 	Parameters             sql.NullString `db:"variables"`
 	Status                 string         `db:"status"`
 	Retry                  int            `db:"retry"`
+	Attempt                int            `db:"attempt"`
 	SpawnAttempts          *pq.Int64Array `db:"spawn_attempts"`
 	Queued                 time.Time      `db:"queued"`
 	Start                  time.Time      `db:"start"`
@@ -92,6 +95,7 @@ type JobRun struct {
 	ContainsService        bool           `db:"contains_service"`
 	ModelType              sql.NullString `db:"model_type"`
 	Header                 sql.NullString `db:"header"`
+	Timeout                int64          `db:"timeout"`
 }
 
 // ToJobRun transform the JobRun with data of the provided sdk.WorkflowNodeJobRun
@@ -109,6 +113,7 @@ func (j *JobRun) ToJobRun(jr *sdk.WorkflowNodeJobRun) (err error) {
 	}
 	j.Status = jr.Status
 	j.Retry = jr.Retry
+	j.Attempt = jr.Attempt
 	array := pq.Int64Array(jr.SpawnAttempts)
 	j.SpawnAttempts = &array
 	j.Queued = jr.Queued
@@ -117,6 +122,7 @@ func (j *JobRun) ToJobRun(jr *sdk.WorkflowNodeJobRun) (err error) {
 	j.Model = jr.Model
 	j.ModelType = sql.NullString{Valid: true, String: string(jr.ModelType)}
 	j.ContainsService = jr.ContainsService
+	j.Timeout = jr.Job.Timeout
 	j.ExecGroups, err = gorpmapping.JSONToNullString(jr.ExecGroups)
 	if err != nil {
 		return sdk.WrapError(err, "column exec_groups")
@@ -140,6 +146,7 @@ func (j JobRun) WorkflowNodeRunJob() (sdk.WorkflowNodeJobRun, error) {
 		WorkflowNodeRunID: j.WorkflowNodeRunID,
 		Status:            j.Status,
 		Retry:             j.Retry,
+		Attempt:           j.Attempt,
 		Queued:            j.Queued,
 		QueuedSeconds:     time.Now().Unix() - j.Queued.Unix(),
 		Start:             j.Start,
@@ -193,6 +200,9 @@ func (j JobRun) WorkflowNodeRunJob() (sdk.WorkflowNodeJobRun, error) {
 // NodeRunArtifact is a gorp wrapper around sdk.WorkflowNodeRunArtifact
 type NodeRunArtifact sdk.WorkflowNodeRunArtifact
 
+// artifactBlob is a gorp wrapper around sdk.WorkflowArtifactBlob
+type artifactBlob sdk.WorkflowArtifactBlob
+
 // RunTag is a gorp wrapper around sdk.WorkflowRunTag
 type RunTag sdk.WorkflowRunTag
 
@@ -204,6 +214,8 @@ type NodeHookModel sdk.WorkflowHookModel
 
 type auditWorkflow sdk.AuditWorklflow
 
+type approvalAudit sdk.WorkflowNodeRunApprovalAudit
+
 func init() {
 	gorpmapping.Register(gorpmapping.New(Workflow{}, "workflow", true, "id"))
 	gorpmapping.Register(gorpmapping.New(Node{}, "workflow_node", true, "id"))
@@ -217,10 +229,13 @@ func init() {
 	gorpmapping.Register(gorpmapping.New(NodeRun{}, "workflow_node_run", true, "id"))
 	gorpmapping.Register(gorpmapping.New(JobRun{}, "workflow_node_run_job", true, "id"))
 	gorpmapping.Register(gorpmapping.New(NodeRunArtifact{}, "workflow_node_run_artifacts", true, "id"))
+	gorpmapping.Register(gorpmapping.New(artifactBlob{}, "workflow_artifact_blob", false, "sha512sum"))
+	gorpmapping.Register(gorpmapping.New(logObject{}, "workflow_node_run_job_logs_object", false, "workflow_node_run_job_logs_id"))
 	gorpmapping.Register(gorpmapping.New(RunTag{}, "workflow_run_tag", false, "workflow_run_id", "tag"))
 	gorpmapping.Register(gorpmapping.New(NodeHookModel{}, "workflow_hook_model", true, "id"))
 	gorpmapping.Register(gorpmapping.New(Notification{}, "workflow_notification", true, "id"))
 	gorpmapping.Register(gorpmapping.New(auditWorkflow{}, "workflow_audit", true, "id"))
+	gorpmapping.Register(gorpmapping.New(approvalAudit{}, "workflow_node_run_approval_audit", true, "id"))
 	gorpmapping.Register(gorpmapping.New(Coverage{}, "workflow_node_run_coverage", false, "workflow_id", "workflow_run_id", "workflow_node_run_id", "repository", "branch"))
 	gorpmapping.Register(gorpmapping.New(dbNodeRunVulenrabilitiesReport{}, "workflow_node_run_vulnerability", true, "id"))
 }
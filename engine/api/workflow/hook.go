@@ -14,6 +14,7 @@ import (
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
 	"github.com/ovh/cds/engine/api/services"
+	"github.com/ovh/cds/engine/api/token"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -133,6 +134,36 @@ func HookRegistration(ctx context.Context, db gorp.SqlExecutor, store cache.Stor
 			}
 		}
 
+		// Generate the secret shared with the repository manager to sign the requests of repository webhooks
+		for i := range hookToUpdate {
+			h := hookToUpdate[i]
+			// the hooks µService needs the secrets in clear
+			if err := restoreHookSecrets(db, h.UUID, &h); err != nil {
+				return sdk.WrapError(err, "HookRegistration> Unable to load hook secrets")
+			}
+			hookToUpdate[i] = h
+			if h.WorkflowHookModel.Name != sdk.RepositoryWebHookModelName || h.Config["vcsServer"].Value == "" {
+				continue
+			}
+			if v, ok := h.Config[sdk.RepositoryWebHookModelSecret]; ok && v.Value != "" {
+				continue
+			}
+			if v, ok := h.Config["webHookID"]; ok && v.Value != "" {
+				// Webhooks created before signature verification have no secret on the repository manager
+				continue
+			}
+			secret, err := token.GenerateToken()
+			if err != nil {
+				return sdk.WrapError(err, "HookRegistration> Unable to generate webhook secret")
+			}
+			h.Config[sdk.RepositoryWebHookModelSecret] = sdk.WorkflowNodeHookConfigValue{
+				Value:        secret,
+				Configurable: false,
+				Type:         sdk.HookConfigTypePassword,
+			}
+			hookToUpdate[i] = h
+		}
+
 		// Create hook on µservice
 		code, errHooks := services.DoJSONRequest(ctx, srvs, http.MethodPost, "/task/bulk", hookToUpdate, &hookToUpdate)
 		if errHooks != nil || code >= 400 {
@@ -228,6 +259,7 @@ func createVCSConfiguration(ctx context.Context, db gorp.SqlExecutor, store cach
 		Method:   "POST",
 		URL:      h.Config["webHookURL"].Value,
 		Workflow: true,
+		Secret:   h.Config[sdk.RepositoryWebHookModelSecret].Value,
 	}
 	if err := client.CreateHook(ctx, h.Config["repoFullName"].Value, &vcsHook); err != nil {
 		return sdk.WrapError(err, "createVCSConfiguration> Cannot create hook on repository: %+v", vcsHook)
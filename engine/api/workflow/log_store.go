@@ -0,0 +1,97 @@
+package workflow
+
+import (
+	"bytes"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// LogStore is the storage backend of the step logs of workflow node job runs
+type LogStore interface {
+	// Append appends a chunk to the log of a step
+	Append(db gorp.SqlExecutor, logs *sdk.Log) error
+	// Archive is called once a job is over and its status is committed, chunks can still be appended to its logs
+	Archive(db gorp.SqlExecutor, jobID int64) error
+	LoadStepLogs(db gorp.SqlExecutor, jobID int64, stepOrder int64) (*sdk.Log, error)
+	LoadLogs(db gorp.SqlExecutor, jobID int64) ([]sdk.Log, error)
+	// Purge deletes the stored logs which don't belong to a job anymore, it is called periodically
+	Purge(db gorp.SqlExecutor) error
+}
+
+// logStore is the current storage backend, logs are stored in database by default
+var logStore LogStore = NewSQLLogStore()
+
+// SetLogStore sets the storage backend of step logs
+func SetLogStore(s LogStore) {
+	logStore = s
+}
+
+// LoadStepLogs load logs for a job (workflow_node_run_job) for a specific step_order
+func LoadStepLogs(db gorp.SqlExecutor, id int64, order int64) (*sdk.Log, error) {
+	return logStore.LoadStepLogs(db, id, order)
+}
+
+// LoadLogs load logs for a job (workflow_node_run_job)
+func LoadLogs(db gorp.SqlExecutor, id int64) ([]sdk.Log, error) {
+	return logStore.LoadLogs(db, id)
+}
+
+// ArchiveLogs lets the storage backend archive the logs of a finished job
+func ArchiveLogs(db gorp.SqlExecutor, jobID int64) error {
+	return logStore.Archive(db, jobID)
+}
+
+// PurgeLogs deletes the stored logs of deleted workflow runs
+func PurgeLogs(db gorp.SqlExecutor) error {
+	return logStore.Purge(db)
+}
+
+// sqlLogStore stores logs in the workflow_node_run_job_logs table
+type sqlLogStore struct{}
+
+// NewSQLLogStore returns a LogStore which keeps logs in database
+func NewSQLLogStore() LogStore {
+	return sqlLogStore{}
+}
+
+func (sqlLogStore) Append(db gorp.SqlExecutor, logs *sdk.Log) error {
+	existingLogs, errLog := loadStepLogs(db, logs.PipelineBuildJobID, logs.StepOrder)
+	if errLog != nil {
+		return sdk.WrapError(errLog, "AddLog> Cannot load existing logs")
+	}
+
+	if existingLogs == nil {
+		if err := insertLog(db, logs); err != nil {
+			return sdk.WrapError(err, "AddLog> Cannot insert log")
+		}
+	} else {
+		logbuf := bytes.NewBufferString(existingLogs.Val)
+		logbuf.WriteString(logs.Val)
+		existingLogs.Val = logbuf.String()
+		existingLogs.LastModified = logs.LastModified
+		existingLogs.Done = logs.Done
+		if err := updateLog(db, existingLogs); err != nil {
+			return sdk.WrapError(err, "AddLog> Cannot update log")
+		}
+	}
+	return nil
+}
+
+func (sqlLogStore) Archive(db gorp.SqlExecutor, jobID int64) error {
+	return nil
+}
+
+func (sqlLogStore) LoadStepLogs(db gorp.SqlExecutor, jobID int64, stepOrder int64) (*sdk.Log, error) {
+	return loadStepLogs(db, jobID, stepOrder)
+}
+
+func (sqlLogStore) LoadLogs(db gorp.SqlExecutor, jobID int64) ([]sdk.Log, error) {
+	return loadLogs(db, jobID)
+}
+
+// Purge has nothing to do, logs are deleted in cascade with workflow runs
+func (sqlLogStore) Purge(db gorp.SqlExecutor) error {
+	return nil
+}
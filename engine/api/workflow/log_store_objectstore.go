@@ -0,0 +1,328 @@
+package workflow
+
+import (
+	"bytes"
+	"compress/gzip"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/lib/pq"
+
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	logObjectContainer = "workflow-logs"
+	// the logs of the finished jobs not modified since logArchiveDelay are archived by Purge
+	logArchiveDelay = 10 * time.Minute
+)
+
+// logObject is the compressed log of a step moved to the objectstore
+type logObject struct {
+	LogID      int64     `db:"workflow_node_run_job_logs_id"`
+	Container  string    `db:"container"`
+	Name       string    `db:"name"`
+	ObjectPath string    `db:"object_path"`
+	Size       int64     `db:"size"`
+	Created    time.Time `db:"created"`
+}
+
+func (o *logObject) GetName() string {
+	return o.Name
+}
+
+func (o *logObject) GetPath() string {
+	return o.Container
+}
+
+// objectstoreLogStore keeps the logs of running jobs in database. When a job is over,
+// the logs of its steps are compressed and moved to the objectstore.
+type objectstoreLogStore struct {
+	sql    sqlLogStore
+	driver objectstore.Driver
+}
+
+// NewObjectstoreLogStore returns a LogStore which moves logs of finished jobs to the given driver
+func NewObjectstoreLogStore(driver objectstore.Driver) LogStore {
+	return &objectstoreLogStore{driver: driver}
+}
+
+func (s *objectstoreLogStore) Append(db gorp.SqlExecutor, logs *sdk.Log) error {
+	existingLogs, err := loadStepLogs(db, logs.PipelineBuildJobID, logs.StepOrder)
+	if err != nil {
+		return sdk.WrapError(err, "AddLog> Cannot load existing logs")
+	}
+	if existingLogs == nil {
+		return s.sql.Append(db, logs)
+	}
+	o, err := loadLogObject(db, existingLogs.Id)
+	if err != nil {
+		return err
+	}
+	if o == nil {
+		return s.sql.Append(db, logs)
+	}
+
+	// the step has already been archived, its log is moved back to the database. If the value has not been
+	// cleared, the archive is not complete and the database holds the whole log.
+	val := existingLogs.Val
+	if val == "" {
+		val, err = s.fetch(o)
+		if err != nil {
+			return err
+		}
+	}
+	existingLogs.Val = val + logs.Val
+	existingLogs.LastModified = logs.LastModified
+	existingLogs.Done = logs.Done
+	if err := updateLog(db, existingLogs); err != nil {
+		return sdk.WrapError(err, "AddLog> Cannot update log")
+	}
+	return s.deleteObject(db, o)
+}
+
+// Archive moves the logs of the job to the objectstore. The value of a log is cleared only if no chunk has been
+// appended meanwhile, otherwise the log stays in database and is archived again by Purge.
+func (s *objectstoreLogStore) Archive(db gorp.SqlExecutor, jobID int64) error {
+	logs, err := loadLogs(db, jobID)
+	if err != nil {
+		return sdk.WrapError(err, "ArchiveLogs> Cannot load logs of job %d", jobID)
+	}
+	for i := range logs {
+		l := &logs[i]
+		if l.Val == "" {
+			continue
+		}
+		lastModified, err := ptypes.Timestamp(l.LastModified)
+		if err != nil {
+			return sdk.WrapError(err, "ArchiveLogs> Invalid last modified date of log %d", l.Id)
+		}
+
+		// an object of a log which has not been cleared is outdated
+		old, err := loadLogObject(db, l.Id)
+		if err != nil {
+			return err
+		}
+		if old != nil {
+			if err := s.deleteObject(db, old); err != nil {
+				return err
+			}
+		}
+
+		buf := new(bytes.Buffer)
+		w := gzip.NewWriter(buf)
+		if _, err := w.Write([]byte(l.Val)); err != nil {
+			return sdk.WrapError(err, "ArchiveLogs> Cannot compress log %d", l.Id)
+		}
+		if err := w.Close(); err != nil {
+			return sdk.WrapError(err, "ArchiveLogs> Cannot compress log %d", l.Id)
+		}
+
+		o := &logObject{
+			LogID:     l.Id,
+			Container: logObjectContainer,
+			Name:      fmt.Sprintf("%d-%d-%d-%d.log.gz", l.PipelineBuildID, l.PipelineBuildJobID, l.StepOrder, lastModified.UnixNano()),
+			Size:      int64(len(l.Val)),
+		}
+		o.ObjectPath, err = s.driver.Store(o, ioutil.NopCloser(buf))
+		if err != nil {
+			return sdk.WrapError(err, "ArchiveLogs> Cannot store log %d", l.Id)
+		}
+		if err := insertLogObject(db, o); err != nil {
+			return err
+		}
+
+		// appending a chunk updates the last modified date and increases the length of the value
+		res, err := db.Exec(`UPDATE workflow_node_run_job_logs SET value = ''
+			WHERE id = $1 AND last_modified = $2 AND octet_length(value) = $3`, l.Id, lastModified, len(l.Val))
+		if err != nil {
+			return sdk.WrapError(err, "ArchiveLogs> Cannot clear log %d", l.Id)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			log.Debug("ArchiveLogs> Log %d of job %d has been updated while archiving it", l.Id, jobID)
+			if err := s.deleteObject(db, o); err != nil {
+				return err
+			}
+			continue
+		}
+		log.Debug("ArchiveLogs> Log %d of job %d moved to %s", l.Id, jobID, o.ObjectPath)
+	}
+	return nil
+}
+
+func (s *objectstoreLogStore) LoadStepLogs(db gorp.SqlExecutor, jobID int64, stepOrder int64) (*sdk.Log, error) {
+	l, err := loadStepLogs(db, jobID, stepOrder)
+	if err != nil || l == nil || l.Val != "" {
+		return l, err
+	}
+	o, err := loadLogObject(db, l.Id)
+	if err != nil {
+		return nil, err
+	}
+	if o != nil {
+		l.Val, err = s.fetch(o)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+func (s *objectstoreLogStore) LoadLogs(db gorp.SqlExecutor, jobID int64) ([]sdk.Log, error) {
+	logs, err := loadLogs(db, jobID)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := loadLogObjectsByJob(db, jobID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range logs {
+		o, ok := objects[logs[i].Id]
+		if !ok || logs[i].Val != "" {
+			continue
+		}
+		logs[i].Val, err = s.fetch(o)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return logs, nil
+}
+
+// Purge deletes the objects of the deleted logs, and archives the logs of the finished jobs still in database
+func (s *objectstoreLogStore) Purge(db gorp.SqlExecutor) error {
+	objects, err := loadOrphanLogObjects(db, 100)
+	if err != nil {
+		return err
+	}
+	for i := range objects {
+		o := &objects[i]
+		log.Debug("PurgeLogs> Deleting log object %s", o.Name)
+		if err := s.driver.Delete(o); err != nil {
+			log.Error("PurgeLogs> Unable to delete log object %s: %v", o.Name, err)
+			continue
+		}
+		if err := deleteLogObject(db, o.LogID); err != nil {
+			return err
+		}
+	}
+
+	jobIDs, err := loadUnarchivedLogJobIDs(db, time.Now().Add(-logArchiveDelay), 100)
+	if err != nil {
+		return err
+	}
+	for _, id := range jobIDs {
+		if err := s.Archive(db, id); err != nil {
+			log.Error("PurgeLogs> Unable to archive logs of job %d: %v", id, err)
+		}
+	}
+	return nil
+}
+
+func (s *objectstoreLogStore) deleteObject(db gorp.SqlExecutor, o *logObject) error {
+	if err := deleteLogObject(db, o.LogID); err != nil {
+		return err
+	}
+	if err := s.driver.Delete(o); err != nil {
+		log.Warning("deleteLogObject> Unable to delete log object %s: %v", o.Name, err)
+	}
+	return nil
+}
+
+func (s *objectstoreLogStore) fetch(o *logObject) (string, error) {
+	f, err := s.driver.Fetch(o)
+	if err != nil {
+		return "", sdk.WrapError(err, "fetchLogObject> Cannot fetch log object %s", o.Name)
+	}
+	defer f.Close()
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		return "", sdk.WrapError(err, "fetchLogObject> Cannot uncompress log object %s", o.Name)
+	}
+	defer r.Close()
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", sdk.WrapError(err, "fetchLogObject> Cannot read log object %s", o.Name)
+	}
+	return string(b), nil
+}
+
+func loadLogObject(db gorp.SqlExecutor, logID int64) (*logObject, error) {
+	var o logObject
+	if err := db.SelectOne(&o, "SELECT * FROM workflow_node_run_job_logs_object WHERE workflow_node_run_job_logs_id = $1", logID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, sdk.WrapError(err, "loadLogObject> Unable to load log object %d", logID)
+	}
+	return &o, nil
+}
+
+func loadLogObjectsByJob(db gorp.SqlExecutor, jobID int64) (map[int64]*logObject, error) {
+	var objects []logObject
+	query := `SELECT workflow_node_run_job_logs_object.* FROM workflow_node_run_job_logs_object
+		JOIN workflow_node_run_job_logs ON workflow_node_run_job_logs.id = workflow_node_run_job_logs_object.workflow_node_run_job_logs_id
+		WHERE workflow_node_run_job_logs.workflow_node_run_job_id = $1`
+	if _, err := db.Select(&objects, query, jobID); err != nil {
+		return nil, sdk.WrapError(err, "loadLogObjectsByJob> Unable to load log objects of job %d", jobID)
+	}
+	res := make(map[int64]*logObject, len(objects))
+	for i := range objects {
+		res[objects[i].LogID] = &objects[i]
+	}
+	return res, nil
+}
+
+// loadOrphanLogObjects loads the objects whose log has been deleted with its workflow run
+func loadOrphanLogObjects(db gorp.SqlExecutor, limit int) ([]logObject, error) {
+	var objects []logObject
+	query := `SELECT * FROM workflow_node_run_job_logs_object
+		WHERE NOT EXISTS (
+			SELECT 1 FROM workflow_node_run_job_logs WHERE workflow_node_run_job_logs.id = workflow_node_run_job_logs_object.workflow_node_run_job_logs_id
+		)
+		LIMIT $1`
+	if _, err := db.Select(&objects, query, limit); err != nil {
+		return nil, sdk.WrapError(err, "loadOrphanLogObjects> Unable to load log objects")
+	}
+	return objects, nil
+}
+
+// loadUnarchivedLogJobIDs loads the finished jobs having logs in database not modified since given date
+func loadUnarchivedLogJobIDs(db gorp.SqlExecutor, before time.Time, limit int) ([]int64, error) {
+	var ids []int64
+	query := `SELECT DISTINCT workflow_node_run_job_id FROM workflow_node_run_job_logs
+		WHERE last_modified < $1 AND octet_length(value) > 0
+		AND NOT EXISTS (
+			SELECT 1 FROM workflow_node_run_job
+			WHERE workflow_node_run_job.id = workflow_node_run_job_logs.workflow_node_run_job_id
+			AND workflow_node_run_job.status = ANY($2)
+		)
+		LIMIT $3`
+	running := pq.StringArray{sdk.StatusWaiting.String(), sdk.StatusBuilding.String()}
+	if _, err := db.Select(&ids, query, before, running, limit); err != nil {
+		return nil, sdk.WrapError(err, "loadUnarchivedLogJobIDs> Unable to load jobs")
+	}
+	return ids, nil
+}
+
+func insertLogObject(db gorp.SqlExecutor, o *logObject) error {
+	o.Created = time.Now()
+	if err := db.Insert(o); err != nil {
+		return sdk.WrapError(err, "insertLogObject> Unable to insert log object %d", o.LogID)
+	}
+	return nil
+}
+
+func deleteLogObject(db gorp.SqlExecutor, logID int64) error {
+	if _, err := db.Exec("DELETE FROM workflow_node_run_job_logs_object WHERE workflow_node_run_job_logs_id = $1", logID); err != nil {
+		return sdk.WrapError(err, "deleteLogObject> Unable to delete log object %d", logID)
+	}
+	return nil
+}
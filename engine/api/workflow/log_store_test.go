@@ -0,0 +1,74 @@
+package workflow_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestObjectstoreLogStore(t *testing.T) {
+	db, _ := test.SetupPG(t, bootstrap.InitiliazeDB)
+
+	dir, err := ioutil.TempDir("", "cds-logs")
+	test.NoError(t, err)
+	defer os.RemoveAll(dir)
+	driver, err := objectstore.NewFilesystemStore(dir)
+	test.NoError(t, err)
+	store := workflow.NewObjectstoreLogStore(driver)
+
+	jobID := time.Now().UnixNano()
+	test.NoError(t, store.Append(db, &sdk.Log{PipelineBuildJobID: jobID, StepOrder: 0, Val: "step 0\n"}))
+	test.NoError(t, store.Append(db, &sdk.Log{PipelineBuildJobID: jobID, StepOrder: 0, Val: "end of step 0\n"}))
+	test.NoError(t, store.Append(db, &sdk.Log{PipelineBuildJobID: jobID, StepOrder: 1, Val: "step 1\n"}))
+
+	test.NoError(t, store.Archive(db, jobID))
+
+	// the value is moved out of the database
+	val, err := db.SelectStr("SELECT value FROM workflow_node_run_job_logs WHERE workflow_node_run_job_id = $1 AND step_order = 0", jobID)
+	test.NoError(t, err)
+	assert.Empty(t, val)
+
+	l, err := store.LoadStepLogs(db, jobID, 0)
+	test.NoError(t, err)
+	assert.Equal(t, "step 0\nend of step 0\n", l.Val)
+
+	logs, err := store.LoadLogs(db, jobID)
+	test.NoError(t, err)
+	if !assert.Len(t, logs, 2) {
+		t.FailNow()
+	}
+	assert.Equal(t, "step 0\nend of step 0\n", logs[0].Val)
+	assert.Equal(t, "step 1\n", logs[1].Val)
+
+	// a late chunk moves the log back to the database
+	test.NoError(t, store.Append(db, &sdk.Log{PipelineBuildJobID: jobID, StepOrder: 1, Val: "late\n"}))
+	l, err = store.LoadStepLogs(db, jobID, 1)
+	test.NoError(t, err)
+	assert.Equal(t, "step 1\nlate\n", l.Val)
+
+	// the log is archived again
+	test.NoError(t, store.Archive(db, jobID))
+	val, err = db.SelectStr("SELECT value FROM workflow_node_run_job_logs WHERE workflow_node_run_job_id = $1 AND step_order = 1", jobID)
+	test.NoError(t, err)
+	assert.Empty(t, val)
+	l, err = store.LoadStepLogs(db, jobID, 1)
+	test.NoError(t, err)
+	assert.Equal(t, "step 1\nlate\n", l.Val)
+
+	// objects of deleted logs are purged
+	_, err = db.Exec("DELETE FROM workflow_node_run_job_logs WHERE workflow_node_run_job_id = $1", jobID)
+	test.NoError(t, err)
+	test.NoError(t, store.Purge(db))
+	n, err := db.SelectInt("SELECT COUNT(*) FROM workflow_node_run_job_logs_object WHERE workflow_node_run_job_logs_id = $1", logs[0].Id)
+	test.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+}
@@ -14,9 +14,14 @@ import (
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/freeze"
+	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/engine/api/user"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/expression"
+	"github.com/ovh/cds/sdk/interpolate"
 	"github.com/ovh/cds/sdk/log"
 	"github.com/ovh/cds/sdk/luascript"
 )
@@ -535,7 +540,7 @@ func processWorkflowNodeRun(ctx context.Context, db gorp.SqlExecutor, store cach
 	currentGitValues := map[string]string{}
 	for _, param := range jobParams {
 		switch param.Name {
-		case tagGitHash, tagGitBranch, tagGitTag, tagGitAuthor, tagGitMessage, tagGitRepository, tagGitURL, tagGitHTTPURL:
+		case tagGitHash, tagGitBranch, tagGitTag, tagGitAuthor, tagGitAuthorEmail, tagGitMessage, tagGitRepository, tagGitURL, tagGitHTTPURL:
 			currentGitValues[param.Name] = param.Value
 		}
 	}
@@ -544,7 +549,7 @@ func processWorkflowNodeRun(ctx context.Context, db gorp.SqlExecutor, store cach
 	previousGitValues := map[string]string{}
 	for _, param := range run.BuildParameters {
 		switch param.Name {
-		case tagGitHash, tagGitBranch, tagGitTag, tagGitAuthor, tagGitMessage, tagGitRepository, tagGitURL, tagGitHTTPURL:
+		case tagGitHash, tagGitBranch, tagGitTag, tagGitAuthor, tagGitAuthorEmail, tagGitMessage, tagGitRepository, tagGitURL, tagGitHTTPURL:
 			previousGitValues[param.Name] = param.Value
 		}
 	}
@@ -650,6 +655,24 @@ func processWorkflowNodeRun(ctx context.Context, db gorp.SqlExecutor, store cach
 		}
 	}
 
+	if run.Status == sdk.StatusWaiting.String() {
+		frozen, err := checkFreezeWindows(db, p, w, n, m)
+		if err != nil {
+			return report, false, sdk.WrapError(err, "processWorkflowNodeRun> Unable to check freeze windows")
+		}
+		if frozen {
+			for i := range run.Stages {
+				run.Stages[i].Status = sdk.StatusSkipped
+			}
+			run.Status = sdk.StatusFail.String()
+			run.Done = time.Now()
+		}
+	}
+
+	if n.Context != nil && n.Context.Approval != nil && run.Status == sdk.StatusWaiting.String() {
+		run.Approval = sdk.NewWorkflowNodeRunApproval(*n.Context.Approval, vcsInfos.AuthorEmail)
+	}
+
 	if err := insertWorkflowNodeRun(db, run); err != nil {
 		return report, true, sdk.WrapError(err, "processWorkflowNodeRun> unable to insert run (node id : %d, node name : %s, subnumber : %d)", run.WorkflowNodeID, run.WorkflowNodeName, run.SubNumber)
 	}
@@ -685,6 +708,35 @@ func processWorkflowNodeRun(ctx context.Context, db gorp.SqlExecutor, store cach
 		return report, true, sdk.WrapError(err, "processWorkflowNodeRun> unable to update workflow run")
 	}
 
+	//The node run waits for its approvals, it will be executed when the gate is approved
+	if run.Approval != nil {
+		log.Debug("processWorkflowNodeRun> Noderun %s processed but not executed because of approval gate", n.Name)
+		AddWorkflowRunInfo(w, false, sdk.SpawnMsg{
+			ID:   sdk.MsgWorkflowNodeApprovalWaiting.ID,
+			Args: []interface{}{n.Name, run.Approval.Gate.RequiredApprovers()},
+		})
+		if err := UpdateWorkflowRun(ctx, db, w); err != nil {
+			return report, true, sdk.WrapError(err, "processWorkflowNodeRun> unable to update workflow run")
+		}
+		return report, true, nil
+	}
+
+	//The node run has not been started, ie. because of a freeze window
+	if sdk.StatusIsTerminated(run.Status) {
+		return report, true, nil
+	}
+
+	r1, err := executeIfMutexFree(ctx, db, store, p, w, n, run)
+	if err != nil {
+		return report, true, err
+	}
+	_, _ = report.Merge(r1, nil)
+	return report, true, nil
+}
+
+// executeIfMutexFree executes the node run, unless the node is one at a time and is building in another run or
+// its concurrency group is taken
+func executeIfMutexFree(ctx context.Context, db gorp.SqlExecutor, store cache.Store, p *sdk.Project, w *sdk.WorkflowRun, n *sdk.WorkflowNode, run *sdk.WorkflowNodeRun) (*ProcessorReport, error) {
 	//Check the context.mutex to know if we are allowed to run it
 	if n.Context.Mutex {
 		//Check if there are builing workflownoderun with the same workflow_node_name for the same workflow
@@ -698,7 +750,7 @@ func processWorkflowNodeRun(ctx context.Context, db gorp.SqlExecutor, store cach
 		and workflow_node_run.status = $4`
 		nbMutex, err := db.SelectInt(mutexQuery, n.WorkflowID, run.ID, n.Name, string(sdk.StatusBuilding))
 		if err != nil {
-			return report, false, sdk.WrapError(err, "processWorkflowNodeRun> unable to check mutexes")
+			return nil, sdk.WrapError(err, "processWorkflowNodeRun> unable to check mutexes")
 		}
 		if nbMutex > 0 {
 			log.Debug("processWorkflowNodeRun> Noderun %s processed but not executed because of mutex", n.Name)
@@ -708,22 +760,114 @@ func processWorkflowNodeRun(ctx context.Context, db gorp.SqlExecutor, store cach
 			})
 
 			if err := UpdateWorkflowRun(ctx, db, w); err != nil {
-				return report, true, sdk.WrapError(err, "processWorkflowNodeRun> unable to update workflow run")
+				return nil, sdk.WrapError(err, "processWorkflowNodeRun> unable to update workflow run")
 			}
 
 			//Mutex is locked. exit without error
-			return report, true, nil
+			return nil, nil
 		}
 		//Mutex is free, continue
 	}
 
 	//Execute the node run !
-	r1, err := execute(ctx, db, store, p, run)
+	r1, err := executeInConcurrencyGroup(ctx, db, store, p, w, n, run)
 	if err != nil {
-		return report, true, sdk.WrapError(err, "processWorkflowNodeRun> unable to execute workflow run")
+		return nil, sdk.WrapError(err, "processWorkflowNodeRun> unable to execute workflow run")
 	}
-	_, _ = report.Merge(r1, nil)
-	return report, true, nil
+	return r1, nil
+}
+
+// checkFreezeWindows returns true if the node run must not be started because of an active freeze window on its
+// environment. The freeze windows are overridden on a manual run if the user asked for it and is allowed to.
+func checkFreezeWindows(db gorp.SqlExecutor, p *sdk.Project, w *sdk.WorkflowRun, n *sdk.WorkflowNode, m *sdk.WorkflowNodeRunManual) (bool, error) {
+	env, has := n.Environment()
+	if !has {
+		return false, nil
+	}
+	windows, err := freeze.LoadActiveByEnvironmentID(db, p.ID, env.ID)
+	if err != nil {
+		return false, err
+	}
+	if len(windows) == 0 {
+		return false, nil
+	}
+
+	var users []sdk.User
+	if m != nil {
+		if m.FreezeOverride {
+			users = append(users, m.User)
+		}
+	} else {
+		// the freeze windows are overridden for all the nodes of a run started with the override
+		for _, t := range w.Tags {
+			if t.Tag != tagFreezeOverride {
+				continue
+			}
+			for _, name := range strings.Split(t.Value, ",") {
+				ou, err := user.LoadUserWithoutAuth(db, name)
+				if err != nil {
+					log.Warning("checkFreezeWindows> Unable to load user %s who overrode the freeze windows: %v", name, err)
+					continue
+				}
+				users = append(users, *ou)
+			}
+		}
+	}
+
+	var u *sdk.User
+	for i := range users {
+		groups, err := group.LoadGroupByUser(db, users[i].ID)
+		if err != nil {
+			return false, sdk.WrapError(err, "checkFreezeWindows> Unable to load groups of user %s", users[i].Username)
+		}
+		users[i].Groups = groups
+		if u == nil || canOverrideFreezeWindows(windows, &users[i]) {
+			u = &users[i]
+		}
+	}
+
+	var frozen bool
+	for _, f := range windows {
+		if u == nil || !f.CanOverride(u) {
+			AddWorkflowRunInfo(w, false, sdk.SpawnMsg{
+				ID:   sdk.MsgWorkflowNodeFrozen.ID,
+				Args: []interface{}{n.Name, f.Scope(), f.Reason},
+			})
+			frozen = true
+		}
+	}
+	if frozen {
+		return true, nil
+	}
+
+	for _, f := range windows {
+		log.Info("checkFreezeWindows> Freeze window %d overridden by %s on %s/%s #%d %s", f.ID, u.Username, p.Key, w.Workflow.Name, w.Number, n.Name)
+		if err := freeze.InsertOverride(db, &sdk.FreezeWindowOverride{
+			FreezeWindowID:   f.ID,
+			WorkflowName:     w.Workflow.Name,
+			Number:           w.Number,
+			WorkflowNodeName: n.Name,
+			Username:         u.Username,
+		}); err != nil {
+			return false, err
+		}
+		AddWorkflowRunInfo(w, false, sdk.SpawnMsg{
+			ID:   sdk.MsgWorkflowNodeFreezeOverridden.ID,
+			Args: []interface{}{f.Scope(), f.Reason, u.Username, n.Name},
+		})
+	}
+	w.Tag(tagFreezeOverride, u.Username)
+	return false, nil
+}
+
+// canOverrideFreezeWindows returns true if the user can override all the freeze windows
+func canOverrideFreezeWindows(windows []sdk.FreezeWindow, u *sdk.User) bool {
+	for _, f := range windows {
+		if !f.CanOverride(u) {
+			return false
+		}
+	}
+	return true
 }
 
 func setValuesGitInBuildParameters(run *sdk.WorkflowNodeRun, vcsInfos vcsInfos) {
@@ -738,6 +882,9 @@ func setValuesGitInBuildParameters(run *sdk.WorkflowNodeRun, vcsInfos vcsInfos)
 	sdk.ParameterAddOrSetValue(&run.BuildParameters, tagGitTag, sdk.StringParameter, run.VCSTag)
 	sdk.ParameterAddOrSetValue(&run.BuildParameters, tagGitHash, sdk.StringParameter, run.VCSHash)
 	sdk.ParameterAddOrSetValue(&run.BuildParameters, tagGitAuthor, sdk.StringParameter, vcsInfos.Author)
+	if vcsInfos.AuthorEmail != "" {
+		sdk.ParameterAddOrSetValue(&run.BuildParameters, tagGitAuthorEmail, sdk.StringParameter, vcsInfos.AuthorEmail)
+	}
 	sdk.ParameterAddOrSetValue(&run.BuildParameters, tagGitMessage, sdk.StringParameter, vcsInfos.Message)
 	sdk.ParameterAddOrSetValue(&run.BuildParameters, tagGitURL, sdk.StringParameter, vcsInfos.URL)
 	sdk.ParameterAddOrSetValue(&run.BuildParameters, tagGitHTTPURL, sdk.StringParameter, vcsInfos.HTTPUrl)
@@ -771,17 +918,39 @@ func checkNodeRunCondition(wr *sdk.WorkflowRun, node sdk.WorkflowNode, params []
 		errc = luacheck.Perform(node.Context.Conditions.LuaScript)
 		conditionsOK = luacheck.Result
 	}
+	// the expression is checked in addition to the plain conditions or to the lua script, on the interpolated parameters
+	if errc == nil && conditionsOK && node.Context.Conditions.Expression != "" {
+		conditionsOK, errc = checkNodeRunExpression(node.Context.Conditions.Expression, params)
+	}
 	if errc != nil {
 		log.Warning("processWorkflowNodeRun> WorkflowCheckConditions error: %s", errc)
 		AddWorkflowRunInfo(wr, true, sdk.SpawnMsg{
 			ID:   sdk.MsgWorkflowError.ID,
-			Args: []interface{}{fmt.Sprintf("Error on LUA Condition: %v", errc)},
+			Args: []interface{}{fmt.Sprintf("Error on run condition: %v", errc)},
 		})
 		return false
 	}
 	return conditionsOK
 }
 
+// checkNodeRunExpression evaluates the expression of the run conditions with the interpolated parameters
+func checkNodeRunExpression(expr string, params []sdk.Parameter) (bool, error) {
+	tmp := sdk.ParametersToMap(params)
+	vars := make(map[string]string, len(tmp))
+	for k, v := range tmp {
+		s, err := interpolate.Do(v, tmp)
+		if err != nil {
+			return false, fmt.Errorf("unable to interpolate %s (%v)", k, err)
+		}
+		vars[k] = s
+	}
+	ok, err := expression.Eval(expr, vars)
+	if err != nil {
+		return false, fmt.Errorf("invalid expression %s (%v)", expr, err)
+	}
+	return ok, nil
+}
+
 // AddWorkflowRunInfo add WorkflowRunInfo on a WorkflowRun
 func AddWorkflowRunInfo(run *sdk.WorkflowRun, isError bool, infos ...sdk.SpawnMsg) {
 	for _, i := range infos {
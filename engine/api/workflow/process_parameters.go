@@ -15,12 +15,16 @@ import (
 	"github.com/ovh/cds/sdk/interpolate"
 )
 
-func getNodeJobRunParameters(db gorp.SqlExecutor, j sdk.Job, run *sdk.WorkflowNodeRun, stage *sdk.Stage) ([]sdk.Parameter, *sdk.MultiError) {
-	params := run.BuildParameters
+func getNodeJobRunParameters(db gorp.SqlExecutor, j sdk.Job, run *sdk.WorkflowNodeRun, stage *sdk.Stage, variant map[string]string) ([]sdk.Parameter, *sdk.MultiError) {
+	params := make([]sdk.Parameter, len(run.BuildParameters))
+	copy(params, run.BuildParameters)
 	tmp := map[string]string{
 		"cds.stage": stage.Name,
 		"cds.job":   j.Action.Name,
 	}
+	for k, v := range variant {
+		tmp["cds.matrix."+k] = v
+	}
 	errm := &sdk.MultiError{}
 
 	for k, v := range tmp {
@@ -13,8 +13,8 @@ import (
 )
 
 // getNodeJobRunRequirements returns requirements list interpolated, and true or false if at least
-// one requirement is of type "Service"
-func getNodeJobRunRequirements(db gorp.SqlExecutor, j sdk.Job, run *sdk.WorkflowNodeRun) (sdk.RequirementList, bool, string, *sdk.MultiError) {
+// one requirement is of type "Service". Values of the matrix variant can be used as {{.cds.matrix.<name>}}.
+func getNodeJobRunRequirements(db gorp.SqlExecutor, j sdk.Job, run *sdk.WorkflowNodeRun, variant map[string]string) (sdk.RequirementList, bool, string, *sdk.MultiError) {
 	requirements := sdk.RequirementList{}
 	tmp := map[string]string{}
 	errm := &sdk.MultiError{}
@@ -24,6 +24,9 @@ func getNodeJobRunRequirements(db gorp.SqlExecutor, j sdk.Job, run *sdk.Workflow
 	for _, v := range run.BuildParameters {
 		tmp[v.Name] = v.Value
 	}
+	for k, v := range variant {
+		tmp["cds.matrix."+k] = v
+	}
 
 	for _, v := range j.Action.Requirements {
 		name, errName := interpolate.Do(v.Name, tmp)
@@ -61,3 +61,18 @@ func TestGetWorkflowRunStatus(t *testing.T) {
 		assert.Equal(t, tc.status, status)
 	}
 }
+
+func TestCheckNodeRunExpression(t *testing.T) {
+	params := []sdk.Parameter{
+		{Name: "git.branch", Type: sdk.StringParameter, Value: "master"},
+		{Name: "cds.env.target", Type: sdk.StringParameter, Value: "{{.git.branch}}"},
+	}
+
+	ok, err := checkNodeRunExpression(`cds.env.target == "master"`, params)
+	assert.NoError(t, err)
+	assert.True(t, ok, "the expression should be evaluated on the interpolated parameters")
+
+	ok, err = checkNodeRunExpression(`cds.env.target == "{{.git.branch}}"`, params)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,154 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// RetryNodeJobRun queues a new attempt of a job which failed for the given reason if its retry policy allows it.
+// The previous attempt is kept in the job, the new one is queued after the backoff of the policy.
+// It returns false if the job has not been queued again.
+func RetryNodeJobRun(ctx context.Context, db gorp.SqlExecutor, store cache.Store, job *sdk.WorkflowNodeJobRun, reason string) (*ProcessorReport, bool, error) {
+	var end func()
+	ctx, end = observability.Span(ctx, "workflow.RetryNodeJobRun",
+		observability.Tag(observability.TagWorkflowNodeJobRun, job.ID),
+	)
+	defer end()
+
+	policy := job.Job.RetryPolicy
+	attempt := job.Attempt + 1
+	exitCode := failedStepExitCode(job.Job.StepStatus)
+	if !policy.ShouldRetry(attempt, reason, exitCode) {
+		return nil, false, nil
+	}
+
+	now := time.Now()
+	delay := policy.Delay(attempt)
+	job.Job.Attempts = append(job.Job.Attempts, sdk.JobAttempt{
+		Attempt:    attempt,
+		Reason:     reason,
+		ExitCode:   exitCode,
+		WorkerName: job.Job.WorkerName,
+		Model:      job.Model,
+		Start:      job.Start,
+		Done:       now,
+		StepStatus: job.Job.StepStatus,
+	})
+
+	for _, step := range job.Job.StepStatus {
+		l, err := LoadStepLogs(db, job.ID, int64(step.StepOrder))
+		if err != nil {
+			return nil, false, sdk.WrapError(err, "RetryNodeJobRun> Cannot load step logs")
+		}
+		if l == nil {
+			continue
+		}
+		retryLog := &sdk.Log{
+			PipelineBuildJobID: l.PipelineBuildJobID,
+			PipelineBuildID:    l.PipelineBuildID,
+			StepOrder:          l.StepOrder,
+			LastModified:       l.LastModified,
+			Val:                fmt.Sprintf("\n\n\n-=-=-=-=-=- Attempt %d failed (%s): job replaced in queue -=-=-=-=-=-\n\n\n", attempt, reason),
+		}
+		if err := logStore.Append(db, retryLog); err != nil {
+			return nil, false, sdk.WrapError(err, "RetryNodeJobRun> Cannot update step log")
+		}
+	}
+
+	job.Attempt = attempt
+	job.Status = sdk.StatusWaiting.String()
+	job.Queued = now.Add(delay)
+	job.Start = time.Time{}
+	job.Done = time.Time{}
+	job.Model = ""
+	job.SpawnAttempts = nil
+	job.Job.StepStatus = nil
+	job.Job.Reason = ""
+	job.Job.WorkerName = ""
+	job.Job.WorkerID = ""
+
+	infos := []sdk.SpawnInfo{{
+		RemoteTime: now,
+		Message: sdk.SpawnMsg{
+			ID:   sdk.MsgSpawnInfoJobRetry.ID,
+			Args: []interface{}{attempt, reason, attempt + 1, policy.MaxAttempts, delay.String()},
+		},
+	}}
+	if err := AddSpawnInfosNodeJobRun(db, job.ID, PrepareSpawnInfos(infos)); err != nil {
+		return nil, false, sdk.WrapError(err, "RetryNodeJobRun> Cannot save spawn info job %d", job.ID)
+	}
+
+	if err := UpdateNodeJobRun(ctx, db, job); err != nil {
+		return nil, false, sdk.WrapError(err, "RetryNodeJobRun> Cannot update node job run %d", job.ID)
+	}
+	if _, err := db.Exec("UPDATE workflow_node_run_job SET worker_id = NULL WHERE id = $1", job.ID); err != nil {
+		return nil, false, sdk.WrapError(err, "RetryNodeJobRun> Cannot reset worker of node job run %d", job.ID)
+	}
+	_ = FreeNodeJobRun(store, job.ID)
+
+	report := new(ProcessorReport)
+	report.Add(*job)
+
+	nodeRun, err := LoadAndLockNodeRunByID(ctx, db, job.WorkflowNodeRunID, true)
+	if err != nil {
+		return nil, false, sdk.WrapError(err, "RetryNodeJobRun> Cannot load node run %d", job.WorkflowNodeRunID)
+	}
+	spawnInfos, err := loadNodeRunJobInfo(db, job.ID)
+	if err != nil {
+		return nil, false, sdk.WrapError(err, "RetryNodeJobRun> Cannot load spawn infos of job %d", job.ID)
+	}
+	for i := range nodeRun.Stages {
+		for j := range nodeRun.Stages[i].RunJobs {
+			rj := &nodeRun.Stages[i].RunJobs[j]
+			if rj.ID == job.ID {
+				rj.Status = job.Status
+				rj.Attempt = job.Attempt
+				rj.Queued = job.Queued
+				rj.Start = job.Start
+				rj.Done = job.Done
+				rj.Model = job.Model
+				rj.Job = job.Job
+				rj.SpawnInfos = spawnInfos
+			}
+		}
+	}
+	if err := UpdateNodeRun(db, nodeRun); err != nil {
+		return nil, false, sdk.WrapError(err, "RetryNodeJobRun> Cannot update node run %d", nodeRun.ID)
+	}
+	report.Add(*nodeRun)
+
+	log.Info("RetryNodeJobRun> Job %d queued again for attempt %d/%d in %s", job.ID, attempt+1, policy.MaxAttempts, delay)
+	return report, true, nil
+}
+
+// failedStepExitCode returns the exit code of the last failed step
+func failedStepExitCode(steps []sdk.StepStatus) int {
+	for i := len(steps) - 1; i >= 0; i-- {
+		if steps[i].Status == sdk.StatusFail.String() && steps[i].ExitCode != 0 {
+			return steps[i].ExitCode
+		}
+	}
+	return 0
+}
+
+// RetryOrFailNodeJobRun applies the retry policy of a job which failed for the given reason: a new attempt
+// is queued if the policy allows it, else the job is set to fail with the given spawn info.
+func RetryOrFailNodeJobRun(ctx context.Context, dbFunc func() *gorp.DbMap, db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, job *sdk.WorkflowNodeJobRun, reason string, info sdk.SpawnInfo) (*ProcessorReport, error) {
+	report, retried, err := RetryNodeJobRun(ctx, db, store, job, reason)
+	if err != nil || retried {
+		return report, err
+	}
+
+	if err := AddSpawnInfosNodeJobRun(db, job.ID, PrepareSpawnInfos([]sdk.SpawnInfo{info})); err != nil {
+		return nil, sdk.WrapError(err, "RetryOrFailNodeJobRun> Cannot save spawn info job %d", job.ID)
+	}
+	return UpdateNodeJobRunStatus(ctx, dbFunc, db, store, proj, job, sdk.StatusFail)
+}
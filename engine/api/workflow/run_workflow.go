@@ -12,19 +12,22 @@ import (
 )
 
 const (
-	tagTriggeredBy   = "triggered_by"
-	tagEnvironment   = "environment"
-	tagGitHash       = "git.hash"
-	tagGitRepository = "git.repository"
-	tagGitBranch     = "git.branch"
-	tagGitTag        = "git.tag"
-	tagGitAuthor     = "git.author"
-	tagGitMessage    = "git.message"
-	tagGitURL        = "git.url"
-	tagGitHTTPURL    = "git.http_url"
+	tagTriggeredBy    = "triggered_by"
+	tagEnvironment    = "environment"
+	tagGitHash        = "git.hash"
+	tagGitRepository  = "git.repository"
+	tagGitBranch      = "git.branch"
+	tagGitTag         = "git.tag"
+	tagGitAuthor      = "git.author"
+	tagGitAuthorEmail = "git.author.email"
+	tagGitMessage     = "git.message"
+	tagGitURL         = "git.url"
+	tagGitHTTPURL     = "git.http_url"
+	// tagFreezeOverride lists the users who overrode the freeze windows of the run
+	tagFreezeOverride = "freeze.override"
 )
 
-//RunFromHook is the entry point to trigger a workflow from a hook
+// RunFromHook is the entry point to trigger a workflow from a hook
 func RunFromHook(ctx context.Context, dbCopy *gorp.DbMap, db gorp.SqlExecutor, store cache.Store, p *sdk.Project, w *sdk.Workflow, e *sdk.WorkflowNodeRunHookEvent, asCodeMsg []sdk.Message) (*sdk.WorkflowRun, *ProcessorReport, error) {
 	var end func()
 	ctx, end = observability.Span(ctx, "workflow.RunFromHook")
@@ -122,7 +125,7 @@ func RunFromHook(ctx context.Context, dbCopy *gorp.DbMap, db gorp.SqlExecutor, s
 	return run, report, nil
 }
 
-//ManualRunFromNode is the entry point to trigger manually a piece of an existing run workflow
+// ManualRunFromNode is the entry point to trigger manually a piece of an existing run workflow
 func ManualRunFromNode(ctx context.Context, db gorp.SqlExecutor, store cache.Store, p *sdk.Project, w *sdk.Workflow, number int64, e *sdk.WorkflowNodeRunManual, nodeID int64) (*sdk.WorkflowRun, *ProcessorReport, error) {
 	report := new(ProcessorReport)
 
@@ -151,7 +154,7 @@ func ManualRunFromNode(ctx context.Context, db gorp.SqlExecutor, store cache.Sto
 	return lastWorkflowRun, report, nil
 }
 
-//ManualRun is the entry point to trigger a workflow manually
+// ManualRun is the entry point to trigger a workflow manually
 func ManualRun(ctx context.Context, db gorp.SqlExecutor, store cache.Store, p *sdk.Project, w *sdk.Workflow, e *sdk.WorkflowNodeRunManual, asCodeInfos []sdk.Message) (*sdk.WorkflowRun, *ProcessorReport, error) {
 	report := new(ProcessorReport)
 	number, err := nextRunNumber(db, w)
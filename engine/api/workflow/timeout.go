@@ -0,0 +1,75 @@
+package workflow
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// LoadTimedOutNodeJobRuns loads the building workflow_node_run_job which exceeded their timeout
+func LoadTimedOutNodeJobRuns(db gorp.SqlExecutor) ([]sdk.WorkflowNodeJobRun, error) {
+	query := `
+	SELECT workflow_node_run_job.*
+	FROM workflow_node_run_job
+	WHERE status = $1
+	AND timeout > 0
+	AND start + timeout * INTERVAL '1 second' < now()
+	`
+	jobRuns := []JobRun{}
+	if _, err := db.Select(&jobRuns, query, sdk.StatusBuilding.String()); err != nil {
+		return nil, sdk.WrapError(err, "LoadTimedOutNodeJobRuns> Unable to load jobs")
+	}
+
+	jobs := make([]sdk.WorkflowNodeJobRun, 0, len(jobRuns))
+	for i := range jobRuns {
+		j, err := jobRuns[i].WorkflowNodeRunJob()
+		if err != nil {
+			log.Error("LoadTimedOutNodeJobRuns> Error> %v", err)
+			continue
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, nil
+}
+
+// StopTimedOutNodeJobRun sets a job which exceeded its timeout to fail, or queues a new attempt if its retry
+// policy allows it. The worker running the job is asked to stop it through its heartbeat with the returned worker ID.
+func StopTimedOutNodeJobRun(ctx context.Context, dbFunc func() *gorp.DbMap, db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, id int64) (*ProcessorReport, string, error) {
+	job, err := LoadAndLockNodeJobRunNoWait(ctx, db, store, id)
+	if err != nil {
+		return nil, "", sdk.WrapError(err, "StopTimedOutNodeJobRun> Unable to load node run job %d", id)
+	}
+	if job.Status != sdk.StatusBuilding.String() {
+		return nil, "", nil
+	}
+
+	// the worker is reset if the job is queued again
+	workerID := job.Job.WorkerID
+	timeout := time.Duration(job.Job.Timeout) * time.Second
+	infos := []sdk.SpawnInfo{{
+		RemoteTime: time.Now(),
+		Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobTimeout.ID, Args: []interface{}{timeout.String()}},
+	}}
+	if err := AddSpawnInfosNodeJobRun(db, job.ID, PrepareSpawnInfos(infos)); err != nil {
+		return nil, "", sdk.WrapError(err, "StopTimedOutNodeJobRun> Cannot save spawn info job %d", job.ID)
+	}
+
+	report, retried, err := RetryNodeJobRun(ctx, db, store, job, sdk.RetryOnFailure)
+	if err != nil {
+		return nil, "", sdk.WrapError(err, "StopTimedOutNodeJobRun> Cannot retry node job run %d", job.ID)
+	}
+	if retried {
+		return report, workerID, nil
+	}
+
+	report, err = UpdateNodeJobRunStatus(ctx, dbFunc, db, store, proj, job, sdk.StatusFail)
+	if err != nil {
+		return nil, "", sdk.WrapError(err, "StopTimedOutNodeJobRun> Cannot update node job run %d", job.ID)
+	}
+	return report, workerID, nil
+}
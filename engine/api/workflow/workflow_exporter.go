@@ -14,6 +14,7 @@ import (
 	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/role"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/exportentities"
 )
@@ -28,14 +29,20 @@ func Export(ctx context.Context, db gorp.SqlExecutor, cache cache.Store, proj *s
 		return 0, sdk.WrapError(errload, "workflow.Export> Cannot load workflow %s", name)
 	}
 
-	return exportWorkflow(*wf, f, withPermissions, w)
+	return exportWorkflow(db, *wf, f, withPermissions, w)
 }
 
-func exportWorkflow(wf sdk.Workflow, f exportentities.Format, withPermissions bool, w io.Writer) (int, error) {
+func exportWorkflow(db gorp.SqlExecutor, wf sdk.Workflow, f exportentities.Format, withPermissions bool, w io.Writer) (int, error) {
 	e, err := exportentities.NewWorkflow(wf, withPermissions)
 	if err != nil {
 		return 0, err
 	}
+	if withPermissions {
+		e.Roles, err = role.ExportBindings(db, wf.ProjectID, sdk.RoleBindingWorkflow, wf.Name)
+		if err != nil {
+			return 0, sdk.WrapError(err, "workflow.Export> Unable to load roles")
+		}
+	}
 
 	// Useful to not display history_length in yaml or json if it's his default value
 	if e.HistoryLength == sdk.DefaultHistoryLength {
@@ -99,7 +106,7 @@ func Pull(ctx context.Context, db gorp.SqlExecutor, cache cache.Store, proj *sdk
 	tw := tar.NewWriter(w)
 
 	buffw := new(bytes.Buffer)
-	size, errw := exportWorkflow(*wf, f, withPermissions, buffw)
+	size, errw := exportWorkflow(db, *wf, f, withPermissions, buffw)
 	if errw != nil {
 		tw.Close()
 		return sdk.WrapError(errw, "workflow.Pull> Unable to export workflow")
@@ -2,12 +2,14 @@ package workflow
 
 import (
 	"context"
+	"fmt"
 	"sync"
 
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/engine/api/role"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/exportentities"
 	"github.com/ovh/cds/sdk/log"
@@ -42,7 +44,10 @@ func Parse(proj *sdk.Project, ew *exportentities.Workflow) (*sdk.Workflow, error
 	//Parse workflow
 	w, errW := ew.GetWorkflow()
 	if errW != nil {
-		return nil, sdk.NewError(sdk.ErrWrongRequest, errW)
+		// keep the validation details, they locate the error in the workflow file
+		httpErr := sdk.ErrWrongRequest
+		httpErr.Message = fmt.Sprintf("%s: %v", httpErr, errW)
+		return nil, sdk.NewError(httpErr, errW)
 	}
 	w.ProjectID = proj.ID
 	w.ProjectKey = proj.Key
@@ -87,5 +92,11 @@ func ParseAndImport(ctx context.Context, db gorp.SqlExecutor, store cache.Store,
 	close(msgChan)
 	done.Wait()
 
+	if globalError == nil && !opts.DryRun {
+		if err := role.ImportBindings(db, proj.ID, sdk.RoleBindingWorkflow, w.Name, ew.Roles); err != nil {
+			return w, msgList, sdk.WrapError(err, "ParseAndImport> Unable to import roles")
+		}
+	}
+
 	return w, msgList, globalError
 }
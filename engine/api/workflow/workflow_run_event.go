@@ -65,6 +65,13 @@ func SendEvent(db gorp.SqlExecutor, key string, report *ProcessorReport) {
 			continue
 		}
 		event.PublishWorkflowNodeJobRun(db, key, wr.Workflow.Name, jobrun)
+
+		// the report is committed, the logs of the job over can be archived
+		if sdk.StatusIsTerminated(jobrun.Status) {
+			if err := ArchiveLogs(db, jobrun.ID); err != nil {
+				log.Error("SendEvent.workflow> Unable to archive logs of job %d: %v", jobrun.ID, err)
+			}
+		}
 	}
 }
 
@@ -109,7 +109,11 @@ func (api *API) releaseApplicationWorkflowHandler() service.Handler {
 		}
 
 		for _, a := range artifactToUpload {
-			f, err := objectstore.Fetch(&a)
+			o, err := workflow.ArtifactObject(api.mustDB(), &a)
+			if err != nil {
+				return sdk.WrapError(err, "releaseApplicationWorkflowHandler> Cannot load artifact object")
+			}
+			f, err := objectstore.Fetch(o)
 			if err != nil {
 				return sdk.WrapError(err, "releaseApplicationWorkflowHandler> Cannot fetch artifact")
 			}
@@ -85,10 +85,11 @@ func (api *API) getWorkflowHookModelsHandler() service.Handler {
 		}
 
 		hasKafka := false
+		hookPlatforms := map[string]bool{}
 		for _, platform := range p.Platforms {
 			if platform.Model.Hook {
 				hasKafka = true
-				break
+				hookPlatforms[platform.Model.Name] = true
 			}
 		}
 
@@ -108,6 +109,14 @@ func (api *API) getWorkflowHookModelsHandler() service.Handler {
 				if hasKafka {
 					models = append(models, m[i])
 				}
+			case sdk.NATSHookModelName:
+				if hookPlatforms[sdk.NATSPlatformModel] {
+					models = append(models, m[i])
+				}
+			case sdk.MQTTHookModelName:
+				if hookPlatforms[sdk.MQTTPlatformModel] {
+					models = append(models, m[i])
+				}
 			default:
 				models = append(models, m[i])
 			}
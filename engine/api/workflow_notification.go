@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/notification"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// getWorkflowNotificationDeliveriesHandler returns the last deliveries of the webhook notifications of a workflow
+func (api *API) getWorkflowNotificationDeliveriesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		limit, err := FormInt(r, "limit")
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowNotificationDeliveriesHandler> Invalid limit")
+		}
+		if limit <= 0 || limit > 500 {
+			limit = 100
+		}
+
+		proj, err := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowNotificationDeliveriesHandler> Cannot load project %s", key)
+		}
+
+		wf, err := workflow.Load(ctx, api.mustDB(), api.Cache, proj, name, getUser(ctx), workflow.LoadOptions{WithoutNode: true})
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowNotificationDeliveriesHandler> Cannot load workflow %s/%s", key, name)
+		}
+
+		deliveries, err := notification.LoadDeliveries(api.mustDB(), wf.ID, limit)
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowNotificationDeliveriesHandler> Cannot load deliveries")
+		}
+		return service.WriteJSON(w, deliveries, http.StatusOK)
+	}
+}
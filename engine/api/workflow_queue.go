@@ -10,6 +10,7 @@ import (
 	"github.com/go-gorp/gorp"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/ovh/venom"
+	"github.com/pkg/errors"
 	"github.com/sguiheux/go-coverage"
 
 	"github.com/ovh/cds/engine/api/cache"
@@ -21,6 +22,7 @@ import (
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/engine/api/workflow"
@@ -96,18 +98,33 @@ func (api *API) postTakeWorkflowJobHandler() service.Handler {
 
 		pbji := &sdk.WorkflowNodeJobRunData{}
 		report, errT := takeJob(ctx, api.mustDB, api.Cache, p, getWorker(ctx), id, takeForm, workerModel, pbji)
+		// the report is not empty if the job failed while being taken
+		if report != nil {
+			workflow.ResyncNodeRunsWithCommits(ctx, api.mustDB(), api.Cache, p, report)
+			go workflow.SendEvent(api.mustDB(), p.Key, report)
+		}
 		if errT != nil {
 			return sdk.WrapError(errT, "postTakeWorkflowJobHandler> Cannot takeJob nodeJobRunID:%d", id)
 		}
 
-		workflow.ResyncNodeRunsWithCommits(ctx, api.mustDB(), api.Cache, p, report)
-		go workflow.SendEvent(api.mustDB(), p.Key, report)
-
 		return service.WriteJSON(w, pbji, http.StatusOK)
 	}
 }
 
 func takeJob(ctx context.Context, dbFunc func() *gorp.DbMap, store cache.Store, p *sdk.Project, wr *sdk.Worker, id int64, takeForm *sdk.WorkerTakeForm, workerModel string, wnjri *sdk.WorkflowNodeJobRunData) (*workflow.ProcessorReport, error) {
+	// Resolve the Vault variables before starting the transaction, to not hold it during the calls to Vault
+	secrets, errSecret := loadNodeJobRunSecrets(dbFunc(), store, p, id)
+	if errSecret != nil {
+		return nil, sdk.WrapError(errSecret, "takeJob> Cannot load secrets")
+	}
+	if err := secret.ResolveVaultVariables(secrets, p.Key, p.VaultRole); err != nil {
+		report, errF := failJobOnVaultError(ctx, dbFunc, store, p, id, err)
+		if errF != nil {
+			log.Error("takeJob> Cannot fail job %d: %v", id, errF)
+		}
+		return report, sdk.WrapError(err, "takeJob> Cannot resolve vault variables")
+	}
+
 	// Start a tx
 	tx, errBegin := dbFunc().Begin()
 	if errBegin != nil {
@@ -164,17 +181,6 @@ func takeJob(ctx context.Context, dbFunc func() *gorp.DbMap, store cache.Store,
 		return nil, sdk.WrapError(err, "takeJob> Unable to load workflow run")
 	}
 
-	//Load the secrets
-	pv, err := project.GetAllVariableInProject(tx, p.ID, project.WithClearPassword())
-	if err != nil {
-		return nil, sdk.WrapError(err, "takeJob> Cannot load project variable")
-	}
-
-	secrets, errSecret := workflow.LoadNodeJobRunSecrets(tx, store, job, noderun, workflowRun, pv)
-	if errSecret != nil {
-		return nil, sdk.WrapError(errSecret, "takeJob> Cannot load secrets")
-	}
-
 	//Feed the worker
 	wnjri.NodeJobRun = *job
 	wnjri.Number = noderun.Number
@@ -195,6 +201,68 @@ func takeJob(ctx context.Context, dbFunc func() *gorp.DbMap, store cache.Store,
 	return report, nil
 }
 
+// loadNodeJobRunSecrets loads the secrets of a job, without resolving its Vault variables
+func loadNodeJobRunSecrets(db gorp.SqlExecutor, store cache.Store, p *sdk.Project, id int64) ([]sdk.Variable, error) {
+	job, err := workflow.LoadNodeJobRun(db, store, id)
+	if err != nil {
+		return nil, sdk.WrapError(err, "loadNodeJobRunSecrets> Cannot load job %d", id)
+	}
+	// do not call Vault for a job that can not be taken
+	if job.Status != sdk.StatusWaiting.String() {
+		return nil, sdk.WrapError(sdk.ErrAlreadyTaken, "loadNodeJobRunSecrets> job %d is not waiting status. Current status:%s", id, job.Status)
+	}
+	noderun, err := workflow.LoadNodeRunByID(db, job.WorkflowNodeRunID, workflow.LoadRunOptions{})
+	if err != nil {
+		return nil, sdk.WrapError(err, "loadNodeJobRunSecrets> Cannot get node run")
+	}
+	workflowRun, err := workflow.LoadRunByID(db, noderun.WorkflowRunID, workflow.LoadRunOptions{})
+	if err != nil {
+		return nil, sdk.WrapError(err, "loadNodeJobRunSecrets> Unable to load workflow run")
+	}
+	pv, err := project.GetAllVariableInProject(db, p.ID, project.WithClearPassword())
+	if err != nil {
+		return nil, sdk.WrapError(err, "loadNodeJobRunSecrets> Cannot load project variable")
+	}
+	return workflow.LoadNodeJobRunSecrets(db, store, job, noderun, workflowRun, pv)
+}
+
+// failJobOnVaultError fails a waiting job whose Vault variables can not be resolved, with a spawn info giving the reason.
+// Otherwise the job would go back to the queue and be taken again by the workers, forever.
+func failJobOnVaultError(ctx context.Context, dbFunc func() *gorp.DbMap, store cache.Store, p *sdk.Project, id int64, errVault error) (*workflow.ProcessorReport, error) {
+	tx, errBegin := dbFunc().Begin()
+	if errBegin != nil {
+		return nil, sdk.WrapError(errBegin, "failJobOnVaultError> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	job, errl := workflow.LoadAndLockNodeJobRunNoWait(ctx, tx, store, id)
+	if errl != nil {
+		return nil, sdk.WrapError(errl, "failJobOnVaultError> Cannot load node job run %d", id)
+	}
+	// the job has been taken or stopped in the meantime
+	if job.Status != sdk.StatusWaiting.String() {
+		return nil, nil
+	}
+
+	infos := []sdk.SpawnInfo{{
+		RemoteTime: time.Now(),
+		Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoVaultSecretError.ID, Args: []interface{}{errors.Cause(errVault).Error()}},
+	}}
+	if err := workflow.AddSpawnInfosNodeJobRun(tx, id, infos); err != nil {
+		return nil, sdk.WrapError(err, "failJobOnVaultError> Cannot save spawn info on node job run %d", id)
+	}
+
+	report, err := workflow.UpdateNodeJobRunStatus(ctx, dbFunc, tx, store, p, job, sdk.StatusFail)
+	if err != nil {
+		return nil, sdk.WrapError(err, "failJobOnVaultError> Cannot update node job run %d", id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, sdk.WrapError(err, "failJobOnVaultError> Cannot commit transaction")
+	}
+	return report, nil
+}
+
 func (api *API) postBookWorkflowJobHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		id, errc := requestVarInt(r, "id")
@@ -376,6 +444,12 @@ func (api *API) postSpawnInfosWorkflowJobHandler() service.AsynchronousHandler {
 			return sdk.WrapError(err, "postSpawnInfosWorkflowJobHandler> Cannot commit tx")
 		}
 
+		for _, info := range s {
+			if info.Message.ID == sdk.MsgSpawnInfoHatcheryErrorSpawn.ID {
+				return retrySpawnFailedJob(ctx, api.DBConnectionFactory.GetDBMap, api.Cache, id)
+			}
+		}
+
 		return nil
 	}
 }
@@ -468,6 +542,18 @@ func postJobResult(ctx context.Context, dbFunc func(context.Context) *gorp.DbMap
 		observability.Tag(observability.TagWorkflowNodeRun, job.WorkflowNodeRunID),
 		observability.Tag(observability.TagJob, job.Job.Action.Name))
 
+	// the job may have been queued again by its retry policy, the result of the previous attempt is ignored
+	if job.Status == sdk.StatusWaiting.String() || (job.Job.WorkerID != "" && job.Job.WorkerID != wr.ID) {
+		log.Info("postJobResult> Ignoring result of worker %s, job %d is not run by this worker anymore", wr.Name, job.ID)
+		if err := worker.UpdateWorkerStatus(tx, wr.ID, sdk.StatusWaiting); err != nil {
+			return nil, sdk.WrapError(err, "postJobResult> Cannot update worker %s status", wr.ID)
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, sdk.WrapError(err, "postJobResult> Cannot commit tx")
+		}
+		return new(workflow.ProcessorReport), nil
+	}
+
 	remoteTime, errt := ptypes.Timestamp(res.RemoteTime)
 	if errt != nil {
 		return nil, sdk.WrapError(errt, "postJobResult> Cannot parse remote time")
@@ -487,9 +573,21 @@ func postJobResult(ctx context.Context, dbFunc func(context.Context) *gorp.DbMap
 	newDBFunc := func() *gorp.DbMap {
 		return dbFunc(context.Background())
 	}
-	report, err := workflow.UpdateNodeJobRunStatus(ctx, newDBFunc, tx, store, proj, job, sdk.Status(res.Status))
-	if err != nil {
-		return nil, sdk.WrapError(err, "postJobResult> Cannot update NodeJobRun %d status", job.ID)
+	var report *workflow.ProcessorReport
+	var retried bool
+	if res.Status == sdk.StatusFail.String() {
+		var err error
+		report, retried, err = workflow.RetryNodeJobRun(ctx, tx, store, job, sdk.RetryOnFailure)
+		if err != nil {
+			return nil, sdk.WrapError(err, "postJobResult> Cannot retry NodeJobRun %d", job.ID)
+		}
+	}
+	if !retried {
+		var err error
+		report, err = workflow.UpdateNodeJobRunStatus(ctx, newDBFunc, tx, store, proj, job, sdk.Status(res.Status))
+		if err != nil {
+			return nil, sdk.WrapError(err, "postJobResult> Cannot update NodeJobRun %d status", job.ID)
+		}
 	}
 
 	//Update worker status
@@ -619,7 +717,17 @@ func (api *API) postWorkflowJobStepStatusHandler() service.Handler {
 		for i := range nodeJobRun.Job.StepStatus {
 			jobStep := &nodeJobRun.Job.StepStatus[i]
 			if step.StepOrder == jobStep.StepOrder {
+				// A new attempt of the step keeps the previous one in its history
+				if step.Attempt > jobStep.Attempt {
+					previous := *jobStep
+					previous.Attempts = nil
+					jobStep.Attempts = append(jobStep.Attempts, previous)
+					jobStep.Attempt = step.Attempt
+					jobStep.Start = step.Start
+					jobStep.Done = time.Time{}
+				}
 				jobStep.Status = step.Status
+				jobStep.ExitCode = step.ExitCode
 				if sdk.StatusIsTerminated(step.Status) {
 					jobStep.Done = step.Done
 				}
@@ -10,7 +10,6 @@ import (
 
 	"github.com/gorilla/mux"
 
-	"github.com/ovh/cds/engine/api/artifact"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/objectstore"
 	"github.com/ovh/cds/engine/api/workflow"
@@ -117,16 +116,16 @@ func (api *API) postWorkflowJobArtifactHandler() service.Handler {
 
 			}
 
-			if err := artifact.SaveWorkflowFile(&art, file); err != nil {
+			if err := workflow.StoreArtifact(api.mustDB(), &art, file); err != nil {
 				file.Close()
 				return sdk.WrapError(err, "postWorkflowJobArtifactHandler> Cannot save artifact in store")
 			}
 			file.Close()
 		}
 
+		// the stored blob will be purged if it's not referenced
 		nodeRun.Artifacts = append(nodeRun.Artifacts, art)
 		if err := workflow.InsertArtifact(api.mustDB(), &art); err != nil {
-			_ = objectstore.Delete(&art)
 			return sdk.WrapError(err, "postWorkflowJobArtifactHandler> Cannot update workflow node run")
 		}
 		return nil
@@ -225,9 +224,15 @@ func (api *API) postWorkflowJobArtifactWithTempURLCallbackHandler() service.Hand
 			return sdk.WrapError(errR, "Cannot load node run")
 		}
 
+		if err := workflow.RegisterArtifactBlob(api.mustDB(), &art); err != nil {
+			log.Warning("postWorkflowJobArtifactWithTempURLCallbackHandler> Unable to deduplicate artifact %s: %v", art.Name, err)
+		}
+
 		nodeRun.Artifacts = append(nodeRun.Artifacts, art)
 		if err := workflow.InsertArtifact(api.mustDB(), &art); err != nil {
-			_ = objectstore.Delete(&art)
+			if art.BlobSHA512sum == "" {
+				_ = objectstore.Delete(&art)
+			}
 			return sdk.WrapError(err, "postWorkflowJobArtifactWithTempURLCallbackHandler> Cannot update workflow node run")
 		}
 
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+// retrySpawnFailedJob applies the retry policy of a job for which a hatchery failed to spawn a worker
+func retrySpawnFailedJob(ctx context.Context, DBFunc func() *gorp.DbMap, store cache.Store, id int64) error {
+	job, err := workflow.LoadNodeJobRun(DBFunc(), store, id)
+	if err != nil {
+		return sdk.WrapError(err, "retrySpawnFailedJob> Cannot load node job run %d", id)
+	}
+	// without retry policy on spawn errors, hatcheries keep trying to spawn a worker
+	if !job.Job.RetryPolicy.Covers(sdk.RetryOnSpawnError, 0) {
+		return nil
+	}
+	info := sdk.SpawnInfo{
+		RemoteTime: time.Now(),
+		Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobSpawnFailed.ID, Args: []interface{}{job.Attempt + 1}},
+	}
+	return retryOrFailJob(ctx, DBFunc, store, id, sdk.StatusWaiting, sdk.RetryOnSpawnError, info)
+}
+
+func retryOrFailJob(ctx context.Context, DBFunc func() *gorp.DbMap, store cache.Store, id int64, status sdk.Status, reason string, info sdk.SpawnInfo) error {
+	db := DBFunc()
+	proj, err := project.LoadProjectByNodeJobRunID(ctx, db, store, id, nil, project.LoadOptions.WithVariables)
+	if err != nil {
+		return sdk.WrapError(err, "retryOrFailJob> Cannot load project from job %d", id)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WrapError(err, "retryOrFailJob> Cannot begin tx")
+	}
+	defer tx.Rollback()
+
+	job, err := workflow.LoadAndLockNodeJobRunNoWait(ctx, tx, store, id)
+	if err != nil {
+		return sdk.WrapError(err, "retryOrFailJob> Unable to load node run job %d", id)
+	}
+	if job.Status != status.String() {
+		return nil
+	}
+
+	report, err := workflow.RetryOrFailNodeJobRun(ctx, DBFunc, tx, store, proj, job, reason, info)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "retryOrFailJob> Cannot commit tx")
+	}
+	if report == nil {
+		return nil
+	}
+
+	workflow.ResyncNodeRunsWithCommits(ctx, db, store, proj, report)
+	go workflow.SendEvent(db, proj.Key, report)
+	return nil
+}
@@ -691,6 +691,23 @@ func Test_postWorkflowJobArtifactHandler(t *testing.T) {
 
 	assert.NotNil(t, updatedNodeRun.Artifacts)
 	assert.Equal(t, 1, len(updatedNodeRun.Artifacts))
+	assert.NotEmpty(t, updatedNodeRun.Artifacts[0].BlobSHA512sum)
+
+	// Upload the same content with another tag, it must reference the same blob
+	vars = map[string]string{
+		"ref":    base64.RawURLEncoding.EncodeToString([]byte("other")),
+		"permID": fmt.Sprintf("%d", ctx.job.ID),
+	}
+	uri = router.GetRoute("POST", api.postWorkflowJobArtifactHandler, vars)
+	req = assets.NewAuthentifiedMultipartRequestFromWorker(t, ctx.worker, "POST", uri, path.Join(os.TempDir(), "myartifact"), "myartifact", params)
+	rec = httptest.NewRecorder()
+	router.Mux.ServeHTTP(rec, req)
+	assert.Equal(t, 204, rec.Code)
+
+	updatedNodeRun, errN2 = workflow.LoadNodeRunByID(api.mustDB(), wNodeJobRun.WorkflowNodeRunID, workflow.LoadRunOptions{WithArtifacts: true})
+	test.NoError(t, errN2)
+	assert.Equal(t, 2, len(updatedNodeRun.Artifacts))
+	assert.Equal(t, updatedNodeRun.Artifacts[0].BlobSHA512sum, updatedNodeRun.Artifacts[1].BlobSHA512sum)
 
 	//Prepare request
 	vars = map[string]string{
@@ -707,7 +724,7 @@ func Test_postWorkflowJobArtifactHandler(t *testing.T) {
 
 	var arts []sdk.WorkflowNodeRunArtifact
 	test.NoError(t, json.Unmarshal(rec.Body.Bytes(), &arts))
-	assert.Equal(t, 1, len(arts))
+	assert.Equal(t, 2, len(arts))
 	assert.Equal(t, "myartifact", arts[0].Name)
 
 	// Download artifact
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/worker"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// jobTimeoutKiller is the go-routine which fails the jobs exceeding their timeout
+func jobTimeoutKiller(c context.Context, DBFunc func() *gorp.DbMap, store cache.Store) {
+	tick := time.NewTicker(30 * time.Second).C
+	for {
+		select {
+		case <-c.Done():
+			if c.Err() != nil {
+				log.Error("Exiting jobTimeoutKiller: %v", c.Err())
+			}
+			return
+		case <-tick:
+			jobs, err := workflow.LoadTimedOutNodeJobRuns(DBFunc())
+			if err != nil {
+				log.Warning("jobTimeoutKiller> %v", err)
+				continue
+			}
+			for _, j := range jobs {
+				if err := stopTimedOutJob(c, DBFunc, store, j.ID); err != nil {
+					log.Error("jobTimeoutKiller> Unable to stop job %d: %v", j.ID, err)
+				}
+			}
+		}
+	}
+}
+
+func stopTimedOutJob(ctx context.Context, DBFunc func() *gorp.DbMap, store cache.Store, id int64) error {
+	db := DBFunc()
+	proj, err := project.LoadProjectByNodeJobRunID(ctx, db, store, id, nil, project.LoadOptions.WithVariables)
+	if err != nil {
+		return sdk.WrapError(err, "stopTimedOutJob> Cannot load project from job %d", id)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WrapError(err, "stopTimedOutJob> Cannot begin tx")
+	}
+	defer tx.Rollback()
+
+	report, workerID, err := workflow.StopTimedOutNodeJobRun(ctx, DBFunc, tx, store, proj, id)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "stopTimedOutJob> Cannot commit tx")
+	}
+	if report == nil {
+		// the job is over
+		return nil
+	}
+
+	if workerID != "" {
+		worker.SetJobToKill(store, workerID, id)
+	}
+	log.Info("stopTimedOutJob> Job %d has been stopped after its timeout", id)
+
+	workflow.ResyncNodeRunsWithCommits(ctx, db, store, proj, report)
+	go workflow.SendEvent(db, proj.Key, report)
+	return nil
+}
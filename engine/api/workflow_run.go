@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -324,7 +325,8 @@ func (api *API) stopWorkflowRunHandler() service.Handler {
 			return sdk.WrapError(errP, "stopWorkflowRunHandler> Unable to load project")
 		}
 
-		report, err := stopWorkflowRun(ctx, api.mustDB, api.Cache, proj, run, getUser(ctx))
+		spwnMsg := sdk.SpawnMsg{ID: sdk.MsgWorkflowNodeStop.ID, Args: []interface{}{getUser(ctx).Username}}
+		report, err := stopWorkflowRun(ctx, api.mustDB, api.Cache, proj, run, spwnMsg)
 		if err != nil {
 			return sdk.WrapError(err, "stopWorkflowRun> Unable to stop workflow")
 		}
@@ -347,7 +349,7 @@ func (api *API) stopWorkflowRunHandler() service.Handler {
 	}
 }
 
-func stopWorkflowRun(ctx context.Context, dbFunc func() *gorp.DbMap, store cache.Store, p *sdk.Project, run *sdk.WorkflowRun, u *sdk.User) (*workflow.ProcessorReport, error) {
+func stopWorkflowRun(ctx context.Context, dbFunc func() *gorp.DbMap, store cache.Store, p *sdk.Project, run *sdk.WorkflowRun, spwnMsg sdk.SpawnMsg) (*workflow.ProcessorReport, error) {
 	report := new(workflow.ProcessorReport)
 
 	tx, errTx := dbFunc().Begin()
@@ -356,8 +358,6 @@ func stopWorkflowRun(ctx context.Context, dbFunc func() *gorp.DbMap, store cache
 	}
 	defer tx.Rollback() //nolint
 
-	spwnMsg := sdk.SpawnMsg{ID: sdk.MsgWorkflowNodeStop.ID, Args: []interface{}{u.Username}}
-
 	stopInfos := sdk.SpawnInfo{
 		APITime:    time.Now(),
 		RemoteTime: time.Now(),
@@ -397,6 +397,30 @@ func stopWorkflowRun(ctx context.Context, dbFunc func() *gorp.DbMap, store cache
 	return report, nil
 }
 
+// cancelSupersededRuns stops the runs of the workflow still waiting or building on the branch of the given run
+func cancelSupersededRuns(ctx context.Context, dbFunc func() *gorp.DbMap, store cache.Store, p *sdk.Project, wr *sdk.WorkflowRun, branch string) (*workflow.ProcessorReport, error) {
+	report := new(workflow.ProcessorReport)
+
+	ids, err := workflow.LoadSupersededRunIDs(dbFunc(), wr.WorkflowID, wr.Number, branch)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		run, err := workflow.LoadRunByID(dbFunc(), id, workflow.LoadRunOptions{})
+		if err != nil {
+			return nil, sdk.WrapError(err, "cancelSupersededRuns> Unable to load workflow run %d", id)
+		}
+		log.Info("cancelSupersededRuns> Stopping %s/%s #%d superseded by #%d on branch %s", p.Key, wr.Workflow.Name, run.Number, wr.Number, branch)
+		spwnMsg := sdk.SpawnMsg{ID: sdk.MsgWorkflowRunSuperseded.ID, Args: []interface{}{wr.Number, branch}}
+		r1, err := stopWorkflowRun(ctx, dbFunc, store, p, run, spwnMsg)
+		if err != nil {
+			return nil, sdk.WrapError(err, "cancelSupersededRuns> Unable to stop workflow run %d", id)
+		}
+		_, _ = report.Merge(r1, nil)
+	}
+	return report, nil
+}
+
 func (api *API) getWorkflowNodeRunHistoryHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		vars := mux.Vars(r)
@@ -752,7 +776,7 @@ func startWorkflowRun(ctx context.Context, db *gorp.DbMap, store cache.Store, p
 
 	//Run from hook
 	if opts.Hook != nil {
-		_, r1, err := workflow.RunFromHook(ctx, db, tx, store, p, wf, opts.Hook, asCodeInfos)
+		wr, r1, err := workflow.RunFromHook(ctx, db, tx, store, p, wf, opts.Hook, asCodeInfos)
 		if err != nil {
 			return nil, sdk.WrapError(err, "startWorkflowRun> Unable to run workflow from hook")
 		}
@@ -761,8 +785,20 @@ func startWorkflowRun(ctx context.Context, db *gorp.DbMap, store cache.Store, p
 		if err := tx.Commit(); err != nil {
 			return nil, sdk.WrapError(err, "startWorkflowRun> Unable to commit transaction")
 		}
+		_, _ = report.Merge(r1, nil)
 
-		return report.Merge(r1, nil)
+		//Stop the previous runs of the branch, only when the hook triggered a new run
+		branch := opts.Hook.Payload["git.branch"]
+		h, ok := wf.GetHooks()[opts.Hook.WorkflowNodeHookUUID]
+		if wf.CancelSupersededRuns && branch != "" && ok && h.WorkflowNodeID == wf.RootID && wr.Status != sdk.StatusNeverBuilt.String() {
+			dbFunc := func() *gorp.DbMap { return db }
+			r2, err := cancelSupersededRuns(ctx, dbFunc, store, p, wr, branch)
+			if err != nil {
+				log.Error("startWorkflowRun> Unable to cancel superseded runs of %s/%s on branch %s: %v", p.Key, wf.Name, branch, err)
+			}
+			_, _ = report.Merge(r2, nil)
+		}
+		return report, nil
 	}
 
 	//Default manual run
@@ -790,6 +826,9 @@ func startWorkflowRun(ctx context.Context, db *gorp.DbMap, store cache.Store, p
 	} else {
 		fromNodes = append(fromNodes, wf.Root)
 	}
+	if err := checkDeployPermission(p, u, fromNodes); err != nil {
+		return nil, err
+	}
 
 	//Run all the node asynchronously in a goroutines
 	var wg = &sync.WaitGroup{}
@@ -804,6 +843,7 @@ func startWorkflowRun(ctx context.Context, db *gorp.DbMap, store cache.Store, p
 				PipelineParameters: opts.Manual.PipelineParameters,
 				User:               opts.Manual.User,
 				Payload:            opts.Manual.Payload,
+				FreezeOverride:     opts.Manual.FreezeOverride,
 			}
 		}
 		if opts.Hook != nil {
@@ -848,6 +888,24 @@ func startWorkflowRun(ctx context.Context, db *gorp.DbMap, store cache.Store, p
 
 }
 
+// checkDeployPermission checks that the user can deploy on the environments of the nodes run manually.
+// The environments on which the groups of the user have no permission are not checked, the workflow permission applies.
+func checkDeployPermission(p *sdk.Project, u *sdk.User, nodes []*sdk.WorkflowNode) error {
+	for _, n := range nodes {
+		if n.Context == nil || n.Context.Environment == nil || n.Context.Environment.Name == "" || n.Context.Environment.Name == sdk.DefaultEnv.Name {
+			continue
+		}
+		env := n.Context.Environment.Name
+		if u.Permissions.EnvironmentsPerm[sdk.UserPermissionKey(p.Key, env)] == 0 && len(u.Permissions.RolesPerm[sdk.RolePermissionKey(sdk.RoleBindingEnvironment, p.Key, env)]) == 0 {
+			continue
+		}
+		if !permission.HasPermission(u, sdk.RoleBindingEnvironment, p.Key, env, sdk.PermissionNameDeploy) {
+			return sdk.WrapError(sdk.ErrForbidden, "checkDeployPermission> %s is not allowed to deploy on environment %s", u.Username, env)
+		}
+	}
+	return nil
+}
+
 func runFromNode(ctx context.Context, db *gorp.DbMap, store cache.Store, opts sdk.WorkflowRunPostHandlerOption, p *sdk.Project, wf *sdk.Workflow, lastRun *sdk.WorkflowRun, u *sdk.User, fromNode *sdk.WorkflowNode) (*workflow.ProcessorReport, error) {
 	var end func()
 	ctx, end = observability.Span(ctx, "runFromNode")
@@ -909,7 +967,12 @@ func (api *API) downloadworkflowArtifactDirectHandler() service.Handler {
 		w.Header().Add("Content-Type", "application/octet-stream")
 		w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", art.Name))
 
-		f, err := objectstore.Fetch(art)
+		o, err := workflow.ArtifactObject(api.mustDB(), art)
+		if err != nil {
+			return sdk.WrapError(err, "downloadArtifactDirectHandler> Cannot load artifact object")
+		}
+
+		f, err := objectstore.Fetch(o)
 		if err != nil {
 			return sdk.WrapError(err, "downloadArtifactDirectHandler> Cannot fetch artifact")
 		}
@@ -958,9 +1021,13 @@ func (api *API) getDownloadArtifactHandler() service.Handler {
 		w.Header().Add("Content-Type", "application/octet-stream")
 		w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", art.Name))
 
-		f, err := objectstore.Fetch(art)
+		o, err := workflow.ArtifactObject(api.mustDB(), art)
+		if err != nil {
+			return sdk.WrapError(err, "getDownloadArtifactHandler> Cannot load artifact object")
+		}
+
+		f, err := objectstore.Fetch(o)
 		if err != nil {
-			_ = f.Close()
 			return sdk.WrapError(err, "getDownloadArtifactHandler> Cannot fetch artifact")
 		}
 
@@ -1004,14 +1071,19 @@ func (api *API) getWorkflowRunArtifactsHandler() service.Handler {
 
 			wg := &sync.WaitGroup{}
 			for i := range runs[0].Artifacts {
+				o, err := workflow.ArtifactObject(api.mustDB(), &runs[0].Artifacts[i])
+				if err != nil {
+					log.Warning("getWorkflowRunArtifactsHandler> %v", err)
+					continue
+				}
 				wg.Add(1)
-				go func(a *sdk.WorkflowNodeRunArtifact) {
+				go func(a *sdk.WorkflowNodeRunArtifact, o objectstore.Object) {
 					defer wg.Done()
-					url, _ := objectstore.FetchTempURL(a)
+					url, _ := objectstore.FetchTempURL(o)
 					if url != "" {
 						a.TempURL = url
 					}
-				}(&runs[0].Artifacts[i])
+				}(&runs[0].Artifacts[i], o)
 			}
 			wg.Wait()
 			arts = append(arts, runs[0].Artifacts...)
@@ -1108,6 +1180,168 @@ func (api *API) getWorkflowNodeRunJobStepHandler() service.Handler {
 	}
 }
 
+const (
+	defaultLogLinesLimit  = 1000
+	defaultLogSearchLimit = 100
+	maxLogSearchLimit     = 1000
+)
+
+func (api *API) getWorkflowNodeRunJobStepLinesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		projectKey := vars["key"]
+		workflowName := vars["permWorkflowName"]
+		number, errN := requestVarInt(r, "number")
+		if errN != nil {
+			return sdk.WrapError(errN, "getWorkflowNodeRunJobStepLinesHandler> Number: invalid number")
+		}
+		nodeRunID, errNI := requestVarInt(r, "nodeRunID")
+		if errNI != nil {
+			return sdk.WrapError(errNI, "getWorkflowNodeRunJobStepLinesHandler> id: invalid number")
+		}
+		runJobID, errJ := requestVarInt(r, "runJobId")
+		if errJ != nil {
+			return sdk.WrapError(errJ, "getWorkflowNodeRunJobStepLinesHandler> runJobId: invalid number")
+		}
+		stepOrder, errS := requestVarInt(r, "stepOrder")
+		if errS != nil {
+			return sdk.WrapError(errS, "getWorkflowNodeRunJobStepLinesHandler> stepOrder: invalid number")
+		}
+
+		// head and tail are shortcuts for offset and limit
+		var offset, limit int64 = 0, defaultLogLinesLimit
+		head, errH := FormInt(r, "head")
+		if errH != nil {
+			return sdk.WrapError(errH, "getWorkflowNodeRunJobStepLinesHandler> head: invalid number")
+		}
+		tail, errT := FormInt(r, "tail")
+		if errT != nil {
+			return sdk.WrapError(errT, "getWorkflowNodeRunJobStepLinesHandler> tail: invalid number")
+		}
+		o, errO := FormInt(r, "offset")
+		if errO != nil {
+			return sdk.WrapError(errO, "getWorkflowNodeRunJobStepLinesHandler> offset: invalid number")
+		}
+		l, errL := FormInt(r, "limit")
+		if errL != nil {
+			return sdk.WrapError(errL, "getWorkflowNodeRunJobStepLinesHandler> limit: invalid number")
+		}
+		switch {
+		case head < 0 || tail < 0 || l < 0:
+			return sdk.WrapError(sdk.ErrWrongRequest, "getWorkflowNodeRunJobStepLinesHandler> head, tail and limit must be positive")
+		case head > 0 && tail > 0:
+			return sdk.WrapError(sdk.ErrWrongRequest, "getWorkflowNodeRunJobStepLinesHandler> head and tail cannot be used together")
+		case head > 0:
+			limit = int64(head)
+		case tail > 0:
+			offset, limit = -int64(tail), int64(tail)
+		default:
+			offset = int64(o)
+			if l > 0 {
+				limit = int64(l)
+			}
+		}
+
+		nodeRun, errNR := workflow.LoadNodeRun(api.mustDB(), projectKey, workflowName, number, nodeRunID, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+		if errNR != nil {
+			return sdk.WrapError(errNR, "getWorkflowNodeRunJobStepLinesHandler> Cannot find nodeRun %d/%d for workflow %s in project %s", nodeRunID, number, workflowName, projectKey)
+		}
+		if !nodeRunHasStep(nodeRun, runJobID, stepOrder) {
+			return sdk.WrapError(sdk.ErrStepNotFound, "getWorkflowNodeRunJobStepLinesHandler> Cannot find step %d on job %d in nodeRun %d/%d for workflow %s in project %s",
+				stepOrder, runJobID, nodeRunID, number, workflowName, projectKey)
+		}
+
+		logs, err := workflow.LoadStepLogs(api.mustDB(), runJobID, stepOrder)
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowNodeRunJobStepLinesHandler> Cannot load log for runJob %d on step %d", runJobID, stepOrder)
+		}
+		if logs == nil {
+			logs = &sdk.Log{PipelineBuildJobID: runJobID, StepOrder: stepOrder}
+		}
+
+		return service.WriteJSON(w, logs.Lines(offset, limit), http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowNodeRunLogsSearchHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		projectKey := vars["key"]
+		workflowName := vars["permWorkflowName"]
+		number, errN := requestVarInt(r, "number")
+		if errN != nil {
+			return sdk.WrapError(errN, "getWorkflowNodeRunLogsSearchHandler> Number: invalid number")
+		}
+		nodeRunID, errNI := requestVarInt(r, "nodeRunID")
+		if errNI != nil {
+			return sdk.WrapError(errNI, "getWorkflowNodeRunLogsSearchHandler> id: invalid number")
+		}
+
+		q := FormString(r, "q")
+		if q == "" {
+			return sdk.WrapError(sdk.ErrWrongRequest, "getWorkflowNodeRunLogsSearchHandler> Missing regular expression")
+		}
+		re, errRe := regexp.Compile(q)
+		if errRe != nil {
+			httpErr := sdk.ErrWrongRequest
+			httpErr.Message = fmt.Sprintf("Invalid regular expression: %v", errRe)
+			return sdk.NewError(httpErr, errRe)
+		}
+		limit, errL := FormInt(r, "limit")
+		if errL != nil {
+			return sdk.WrapError(errL, "getWorkflowNodeRunLogsSearchHandler> limit: invalid number")
+		}
+		if limit <= 0 {
+			limit = defaultLogSearchLimit
+		}
+		if limit > maxLogSearchLimit {
+			limit = maxLogSearchLimit
+		}
+
+		nodeRun, errNR := workflow.LoadNodeRun(api.mustDB(), projectKey, workflowName, number, nodeRunID, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+		if errNR != nil {
+			return sdk.WrapError(errNR, "getWorkflowNodeRunLogsSearchHandler> Cannot find nodeRun %d/%d for workflow %s in project %s", nodeRunID, number, workflowName, projectKey)
+		}
+
+		res := []sdk.LogLine{}
+	stageLoop:
+		for _, s := range nodeRun.Stages {
+			for _, rj := range s.RunJobs {
+				logs, err := workflow.LoadLogs(api.mustDB(), rj.ID)
+				if err != nil {
+					return sdk.WrapError(err, "getWorkflowNodeRunLogsSearchHandler> Cannot load logs for runJob %d", rj.ID)
+				}
+				for i := range logs {
+					res = append(res, logs[i].Search(re, limit-len(res))...)
+					if len(res) >= limit {
+						break stageLoop
+					}
+				}
+			}
+		}
+
+		return service.WriteJSON(w, res, http.StatusOK)
+	}
+}
+
+// nodeRunHasStep checks that the step belongs to a job of the node run
+func nodeRunHasStep(nodeRun *sdk.WorkflowNodeRun, runJobID, stepOrder int64) bool {
+	for _, s := range nodeRun.Stages {
+		for _, rj := range s.RunJobs {
+			if rj.ID != runJobID {
+				continue
+			}
+			for _, ss := range rj.Job.StepStatus {
+				if int64(ss.StepOrder) == stepOrder {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return false
+}
+
 func (api *API) getWorkflowRunTagsHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		vars := mux.Vars(r)
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func (api *API) postWorkflowNodeRunApprovalHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+		number, err := requestVarInt(r, "number")
+		if err != nil {
+			return err
+		}
+		id, err := requestVarInt(r, "nodeRunID")
+		if err != nil {
+			return err
+		}
+
+		var req sdk.WorkflowNodeRunApprovalRequest
+		if err := UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+
+		p, err := project.Load(api.mustDB(), api.Cache, key, getUser(ctx), project.LoadOptions.WithVariables)
+		if err != nil {
+			return sdk.WrapError(err, "postWorkflowNodeRunApprovalHandler> Cannot load project")
+		}
+
+		// Check that the node run belongs to the workflow run
+		if _, err := workflow.LoadNodeRun(api.mustDB(), key, name, number, id, workflow.LoadRunOptions{DisableDetailledNodeRun: true}); err != nil {
+			return sdk.WrapError(err, "postWorkflowNodeRunApprovalHandler> Unable to load node run")
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "postWorkflowNodeRunApprovalHandler> Cannot begin tx")
+		}
+		defer tx.Rollback()
+
+		report, nodeRun, err := workflow.ApproveNodeRun(ctx, tx, api.Cache, p, id, getUser(ctx), req)
+		if err != nil {
+			return sdk.WrapError(err, "postWorkflowNodeRunApprovalHandler> Cannot approve node run %d", id)
+		}
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "postWorkflowNodeRunApprovalHandler> Cannot commit tx")
+		}
+
+		workflow.ResyncNodeRunsWithCommits(ctx, api.mustDB(), api.Cache, p, report)
+		go workflow.SendEvent(api.mustDB(), p.Key, report)
+
+		return service.WriteJSON(w, nodeRun, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowApprovalAuditsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		proj, err := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowApprovalAuditsHandler> Cannot load project %s", key)
+		}
+
+		wf, err := workflow.Load(ctx, api.mustDB(), api.Cache, proj, name, getUser(ctx), workflow.LoadOptions{WithoutNode: true})
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowApprovalAuditsHandler> Cannot load workflow %s/%s", key, name)
+		}
+
+		audits, err := workflow.LoadApprovalAudits(api.mustDB(), wf.ID)
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowApprovalAuditsHandler> Cannot load approval audits")
+		}
+		return service.WriteJSON(w, audits, http.StatusOK)
+	}
+}
+
+// approvalExpirer is the go-routine which fails the node runs whose approval gate expired
+func approvalExpirer(c context.Context, DBFunc func() *gorp.DbMap, store cache.Store) {
+	tick := time.NewTicker(30 * time.Second).C
+	for {
+		select {
+		case <-c.Done():
+			if c.Err() != nil {
+				log.Error("Exiting approvalExpirer: %v", c.Err())
+			}
+			return
+		case <-tick:
+			ids, err := workflow.LoadExpiredApprovalNodeRunIDs(DBFunc())
+			if err != nil {
+				log.Warning("approvalExpirer> %v", err)
+				continue
+			}
+			for _, id := range ids {
+				if err := expireApproval(c, DBFunc, store, id); err != nil {
+					log.Error("approvalExpirer> Unable to expire approval of node run %d: %v", id, err)
+				}
+			}
+		}
+	}
+}
+
+func expireApproval(ctx context.Context, DBFunc func() *gorp.DbMap, store cache.Store, id int64) error {
+	db := DBFunc()
+	proj, err := project.LoadProjectByNodeRunID(ctx, db, store, id, nil, project.LoadOptions.WithVariables)
+	if err != nil {
+		return sdk.WrapError(err, "expireApproval> Cannot load project from node run %d", id)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WrapError(err, "expireApproval> Cannot begin tx")
+	}
+	defer tx.Rollback()
+
+	report, err := workflow.ExpireNodeRunApproval(ctx, tx, store, proj, id)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "expireApproval> Cannot commit tx")
+	}
+	if report == nil {
+		return nil
+	}
+	log.Info("expireApproval> Approval of node run %d has expired", id)
+
+	workflow.ResyncNodeRunsWithCommits(ctx, db, store, proj, report)
+	go workflow.SendEvent(db, proj.Key, report)
+	return nil
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// concurrencyReleaser is the go-routine which starts the node runs queued in a concurrency group which is free.
+// The groups shared between projects, or whose holder has been stopped, are released here.
+func concurrencyReleaser(c context.Context, DBFunc func() *gorp.DbMap, store cache.Store) {
+	tick := time.NewTicker(10 * time.Second).C
+	for {
+		select {
+		case <-c.Done():
+			if c.Err() != nil {
+				log.Error("Exiting concurrencyReleaser: %v", c.Err())
+			}
+			return
+		case <-tick:
+			ids, err := workflow.LoadStartableConcurrencyNodeRunIDs(DBFunc())
+			if err != nil {
+				log.Warning("concurrencyReleaser> %v", err)
+				continue
+			}
+			for _, id := range ids {
+				if err := startQueuedNodeRun(c, DBFunc, store, id); err != nil {
+					log.Error("concurrencyReleaser> Unable to start node run %d: %v", id, err)
+				}
+			}
+		}
+	}
+}
+
+func startQueuedNodeRun(ctx context.Context, DBFunc func() *gorp.DbMap, store cache.Store, id int64) error {
+	db := DBFunc()
+	proj, err := project.LoadProjectByNodeRunID(ctx, db, store, id, nil, project.LoadOptions.WithVariables)
+	if err != nil {
+		return sdk.WrapError(err, "startQueuedNodeRun> Cannot load project from node run %d", id)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WrapError(err, "startQueuedNodeRun> Cannot begin tx")
+	}
+	defer tx.Rollback()
+
+	report, err := workflow.StartQueuedNodeRun(ctx, tx, store, proj, id)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "startQueuedNodeRun> Cannot commit tx")
+	}
+	if report == nil {
+		return nil
+	}
+
+	workflow.ResyncNodeRunsWithCommits(ctx, db, store, proj, report)
+	go workflow.SendEvent(db, proj.Key, report)
+	return nil
+}
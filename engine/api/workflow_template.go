@@ -0,0 +1,383 @@
+package api
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/api/workflowtemplate"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+// loadWorkflowTemplate loads a template of a group, the group must be one of the user's groups or the shared infra group
+func (api *API) loadWorkflowTemplate(ctx context.Context, groupName, templateName string) (*sdk.WorkflowTemplate, error) {
+	g, err := group.LoadGroup(api.mustDB(), groupName)
+	if err != nil {
+		return nil, sdk.WrapError(err, "loadWorkflowTemplate> Cannot load group %s", groupName)
+	}
+
+	u := getUser(ctx)
+	allowed := u.Admin || g.Name == sdk.SharedInfraGroupName
+	for _, ug := range u.Groups {
+		if ug.ID == g.ID {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, sdk.WrapError(sdk.ErrWorkflowTemplateNotFound, "loadWorkflowTemplate> User %s is not a member of group %s", u.Username, groupName)
+	}
+
+	t, err := workflowtemplate.LoadByGroupIDAndName(api.mustDB(), g.ID, templateName)
+	if err != nil {
+		return nil, sdk.WrapError(err, "loadWorkflowTemplate> Cannot load template %s/%s", groupName, templateName)
+	}
+	t.Group = g
+	return t, nil
+}
+
+func (api *API) getWorkflowTemplatesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		u := getUser(ctx)
+		groups := map[int64]sdk.Group{}
+		for _, g := range u.Groups {
+			groups[g.ID] = g
+		}
+		if group.SharedInfraGroup != nil {
+			groups[group.SharedInfraGroup.ID] = *group.SharedInfraGroup
+		}
+		ids := make([]int64, 0, len(groups))
+		for id := range groups {
+			ids = append(ids, id)
+		}
+
+		ts, err := workflowtemplate.LoadAllByGroupIDs(api.mustDB(), ids)
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowTemplatesHandler> Cannot load templates")
+		}
+		for i := range ts {
+			g := groups[ts[i].GroupID]
+			ts[i].Group = &sdk.Group{ID: g.ID, Name: g.Name}
+		}
+		return service.WriteJSON(w, ts, http.StatusOK)
+	}
+}
+
+func (api *API) getGroupWorkflowTemplatesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		groupName := mux.Vars(r)["permGroupName"]
+
+		g, err := group.LoadGroup(api.mustDB(), groupName)
+		if err != nil {
+			return sdk.WrapError(err, "getGroupWorkflowTemplatesHandler> Cannot load group %s", groupName)
+		}
+		ts, err := workflowtemplate.LoadAllByGroupIDs(api.mustDB(), []int64{g.ID})
+		if err != nil {
+			return sdk.WrapError(err, "getGroupWorkflowTemplatesHandler> Cannot load templates")
+		}
+		return service.WriteJSON(w, ts, http.StatusOK)
+	}
+}
+
+func (api *API) postGroupWorkflowTemplateHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		groupName := mux.Vars(r)["permGroupName"]
+
+		var t sdk.WorkflowTemplate
+		if err := UnmarshalBody(r, &t); err != nil {
+			return sdk.WrapError(err, "postGroupWorkflowTemplateHandler> Cannot unmarshal body")
+		}
+		if err := t.IsValid(); err != nil {
+			return sdk.WrapError(err, "postGroupWorkflowTemplateHandler> Invalid template")
+		}
+		if err := workflowtemplate.CheckSyntax(t); err != nil {
+			return sdk.WrapError(err, "postGroupWorkflowTemplateHandler> Invalid template")
+		}
+
+		g, err := group.LoadGroup(api.mustDB(), groupName)
+		if err != nil {
+			return sdk.WrapError(err, "postGroupWorkflowTemplateHandler> Cannot load group %s", groupName)
+		}
+		if _, err := workflowtemplate.LoadByGroupIDAndName(api.mustDB(), g.ID, t.Name); err == nil {
+			return sdk.WrapError(sdk.ErrAlreadyExist, "postGroupWorkflowTemplateHandler> Template %s already exists in group %s", t.Name, groupName)
+		} else if !sdk.ErrorIs(err, sdk.ErrWorkflowTemplateNotFound) {
+			return sdk.WrapError(err, "postGroupWorkflowTemplateHandler> Cannot check template %s", t.Name)
+		}
+		t.GroupID = g.ID
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "postGroupWorkflowTemplateHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		if err := workflowtemplate.Insert(tx, &t, getUser(ctx)); err != nil {
+			return sdk.WrapError(err, "postGroupWorkflowTemplateHandler> Cannot insert template")
+		}
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "postGroupWorkflowTemplateHandler> Cannot commit transaction")
+		}
+
+		t.Group = g
+		return service.WriteJSON(w, t, http.StatusOK)
+	}
+}
+
+func (api *API) getGroupWorkflowTemplateHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		t, err := api.loadWorkflowTemplate(ctx, vars["permGroupName"], vars["templateName"])
+		if err != nil {
+			return sdk.WrapError(err, "getGroupWorkflowTemplateHandler>")
+		}
+		return service.WriteJSON(w, t, http.StatusOK)
+	}
+}
+
+func (api *API) putGroupWorkflowTemplateHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		old, err := api.loadWorkflowTemplate(ctx, vars["permGroupName"], vars["templateName"])
+		if err != nil {
+			return sdk.WrapError(err, "putGroupWorkflowTemplateHandler>")
+		}
+
+		var t sdk.WorkflowTemplate
+		if err := UnmarshalBody(r, &t); err != nil {
+			return sdk.WrapError(err, "putGroupWorkflowTemplateHandler> Cannot unmarshal body")
+		}
+		if err := t.IsValid(); err != nil {
+			return sdk.WrapError(err, "putGroupWorkflowTemplateHandler> Invalid template")
+		}
+		if err := workflowtemplate.CheckSyntax(t); err != nil {
+			return sdk.WrapError(err, "putGroupWorkflowTemplateHandler> Invalid template")
+		}
+		if t.Name != old.Name {
+			if _, err := workflowtemplate.LoadByGroupIDAndName(api.mustDB(), old.GroupID, t.Name); err == nil {
+				return sdk.WrapError(sdk.ErrAlreadyExist, "putGroupWorkflowTemplateHandler> Template %s already exists", t.Name)
+			}
+		}
+		t.ID = old.ID
+		t.GroupID = old.GroupID
+		t.Version = old.Version
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "putGroupWorkflowTemplateHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		if err := workflowtemplate.Update(tx, &t, getUser(ctx)); err != nil {
+			return sdk.WrapError(err, "putGroupWorkflowTemplateHandler> Cannot update template")
+		}
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "putGroupWorkflowTemplateHandler> Cannot commit transaction")
+		}
+
+		t.Group = old.Group
+		return service.WriteJSON(w, t, http.StatusOK)
+	}
+}
+
+func (api *API) deleteGroupWorkflowTemplateHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		t, err := api.loadWorkflowTemplate(ctx, vars["permGroupName"], vars["templateName"])
+		if err != nil {
+			return sdk.WrapError(err, "deleteGroupWorkflowTemplateHandler>")
+		}
+		if err := workflowtemplate.Delete(api.mustDB(), t); err != nil {
+			return sdk.WrapError(err, "deleteGroupWorkflowTemplateHandler> Cannot delete template")
+		}
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
+
+func (api *API) getGroupWorkflowTemplateVersionsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		t, err := api.loadWorkflowTemplate(ctx, vars["permGroupName"], vars["templateName"])
+		if err != nil {
+			return sdk.WrapError(err, "getGroupWorkflowTemplateVersionsHandler>")
+		}
+		vs, err := workflowtemplate.LoadVersions(api.mustDB(), t.ID)
+		if err != nil {
+			return sdk.WrapError(err, "getGroupWorkflowTemplateVersionsHandler> Cannot load versions")
+		}
+		return service.WriteJSON(w, vs, http.StatusOK)
+	}
+}
+
+func (api *API) getGroupWorkflowTemplateInstancesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		t, err := api.loadWorkflowTemplate(ctx, vars["permGroupName"], vars["templateName"])
+		if err != nil {
+			return sdk.WrapError(err, "getGroupWorkflowTemplateInstancesHandler>")
+		}
+		is, err := workflowtemplate.LoadInstancesByTemplateID(api.mustDB(), t.ID)
+		if err != nil {
+			return sdk.WrapError(err, "getGroupWorkflowTemplateInstancesHandler> Cannot load instances")
+		}
+		for i := range is {
+			is[i].Drift = &sdk.WorkflowTemplateDrift{Outdated: is[i].WorkflowTemplateVersion < t.Version}
+		}
+		return service.WriteJSON(w, is, http.StatusOK)
+	}
+}
+
+// exportTemplateWorkflow returns the yaml export of a workflow, used to detect its changes since a template was applied
+func (api *API) exportTemplateWorkflow(ctx context.Context, db gorp.SqlExecutor, proj *sdk.Project, name string) (string, error) {
+	buf := new(bytes.Buffer)
+	if _, err := workflow.Export(ctx, db, api.Cache, proj, name, exportentities.FormatYAML, false, getUser(ctx), buf); err != nil {
+		return "", sdk.WrapError(err, "exportTemplateWorkflow> Cannot export workflow %s", name)
+	}
+	return buf.String(), nil
+}
+
+func (api *API) getProjectWorkflowTemplateInstancesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)["permProjectKey"]
+
+		proj, err := project.Load(api.mustDB(), api.Cache, key, getUser(ctx), project.LoadOptions.WithPlatforms)
+		if err != nil {
+			return sdk.WrapError(err, "getProjectWorkflowTemplateInstancesHandler> Cannot load project %s", key)
+		}
+		is, err := workflowtemplate.LoadInstancesByProjectID(api.mustDB(), proj.ID)
+		if err != nil {
+			return sdk.WrapError(err, "getProjectWorkflowTemplateInstancesHandler> Cannot load instances")
+		}
+
+		templates := map[int64]*sdk.WorkflowTemplate{}
+		for i := range is {
+			t, ok := templates[is[i].WorkflowTemplateID]
+			if !ok {
+				t, err = workflowtemplate.LoadByID(api.mustDB(), is[i].WorkflowTemplateID)
+				if err != nil {
+					return sdk.WrapError(err, "getProjectWorkflowTemplateInstancesHandler> Cannot load template %d", is[i].WorkflowTemplateID)
+				}
+				if t.Group, err = group.LoadGroupByID(api.mustDB(), t.GroupID); err != nil {
+					return sdk.WrapError(err, "getProjectWorkflowTemplateInstancesHandler> Cannot load group %d", t.GroupID)
+				}
+				templates[t.ID] = t
+			}
+			is[i].Template = t
+
+			drift := &sdk.WorkflowTemplateDrift{Outdated: is[i].WorkflowTemplateVersion < t.Version}
+			if is[i].WorkflowID != nil {
+				export, err := api.exportTemplateWorkflow(ctx, api.mustDB(), proj, is[i].Request.WorkflowName)
+				if err != nil {
+					return sdk.WrapError(err, "getProjectWorkflowTemplateInstancesHandler>")
+				}
+				drift.Modified = export != is[i].WorkflowExport
+			}
+			is[i].Drift = drift
+		}
+
+		return service.WriteJSON(w, is, http.StatusOK)
+	}
+}
+
+func (api *API) postProjectWorkflowTemplateApplyHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+
+		t, err := api.loadWorkflowTemplate(ctx, vars["groupName"], vars["templateName"])
+		if err != nil {
+			return sdk.WrapError(err, "postProjectWorkflowTemplateApplyHandler>")
+		}
+
+		var req sdk.WorkflowTemplateRequest
+		if err := UnmarshalBody(r, &req); err != nil {
+			return sdk.WrapError(err, "postProjectWorkflowTemplateApplyHandler> Cannot unmarshal body")
+		}
+
+		proj, err := project.Load(api.mustDB(), api.Cache, key, getUser(ctx),
+			project.LoadOptions.WithGroups,
+			project.LoadOptions.WithApplications,
+			project.LoadOptions.WithEnvironments,
+			project.LoadOptions.WithPipelines,
+			project.LoadOptions.WithApplicationWithDeploymentStrategies,
+			project.LoadOptions.WithPlatforms)
+		if err != nil {
+			return sdk.WrapError(err, "postProjectWorkflowTemplateApplyHandler> Cannot load project %s", key)
+		}
+
+		// An existing workflow can only be replaced by the template it was generated from
+		var instance *sdk.WorkflowTemplateInstance
+		exists, err := workflow.Exists(api.mustDB(), proj.Key, req.WorkflowName)
+		if err != nil {
+			return sdk.WrapError(err, "postProjectWorkflowTemplateApplyHandler> Cannot check workflow %s", req.WorkflowName)
+		}
+		if exists {
+			wf, err := workflow.Load(ctx, api.mustDB(), api.Cache, proj, req.WorkflowName, getUser(ctx), workflow.LoadOptions{})
+			if err != nil {
+				return sdk.WrapError(err, "postProjectWorkflowTemplateApplyHandler> Cannot load workflow %s", req.WorkflowName)
+			}
+			instance, err = workflowtemplate.LoadInstanceByWorkflowID(api.mustDB(), wf.ID)
+			if err != nil {
+				return sdk.WrapError(err, "postProjectWorkflowTemplateApplyHandler>")
+			}
+			if instance == nil || instance.WorkflowTemplateID != t.ID {
+				return sdk.NewError(sdk.ErrAlreadyExist, fmt.Errorf("workflow %s was not generated from template %s/%s", req.WorkflowName, t.Group.Name, t.Name))
+			}
+			// Re-applying a template keeps the previous values of the parameters if none are given
+			if req.Parameters == nil {
+				req.Parameters = instance.Request.Parameters
+			}
+		}
+
+		res, err := workflowtemplate.Execute(*t, req)
+		if err != nil {
+			return sdk.WrapError(err, "postProjectWorkflowTemplateApplyHandler> Cannot execute template")
+		}
+		buf := new(bytes.Buffer)
+		if err := workflowtemplate.Tar(res, buf); err != nil {
+			return sdk.WrapError(err, "postProjectWorkflowTemplateApplyHandler>")
+		}
+
+		// the instance is saved in the transaction of the push, a workflow is never generated without its instance
+		saveInstance := func(tx gorp.SqlExecutor, wf *sdk.Workflow) error {
+			export, err := api.exportTemplateWorkflow(ctx, tx, proj, wf.Name)
+			if err != nil {
+				return err
+			}
+			if instance == nil {
+				instance = &sdk.WorkflowTemplateInstance{
+					WorkflowTemplateID: t.ID,
+					ProjectID:          proj.ID,
+					WorkflowID:         &wf.ID,
+				}
+			}
+			instance.WorkflowTemplateVersion = t.Version
+			instance.WorkflowExport = export
+			instance.Request = req
+			if instance.ID == 0 {
+				err = workflowtemplate.InsertInstance(tx, instance)
+			} else {
+				err = workflowtemplate.UpdateInstance(tx, instance)
+			}
+			return sdk.WrapError(err, "Cannot save instance")
+		}
+
+		allMsg, wf, err := workflow.PushWithFunc(ctx, api.mustDB(), api.Cache, proj, tar.NewReader(buf), nil, getUser(ctx), project.DecryptWithBuiltinKey, saveInstance)
+		if err != nil {
+			return sdk.WrapError(err, "postProjectWorkflowTemplateApplyHandler> Cannot push workflow")
+		}
+
+		w.Header().Add(sdk.ResponseWorkflowIDHeader, fmt.Sprintf("%d", wf.ID))
+		w.Header().Add(sdk.ResponseWorkflowNameHeader, wf.Name)
+		return service.WriteJSON(w, translate(r, allMsg), http.StatusOK)
+	}
+}
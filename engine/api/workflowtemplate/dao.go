@@ -0,0 +1,178 @@
+package workflowtemplate
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// Insert inserts a new workflow template and its first version
+func Insert(db gorp.SqlExecutor, t *sdk.WorkflowTemplate, u *sdk.User) error {
+	t.Version = 1
+	t.LastModified = time.Now()
+	dbt := workflowTemplate(*t)
+	if err := db.Insert(&dbt); err != nil {
+		return sdk.WrapError(err, "Insert> Cannot insert workflow template %s", t.Name)
+	}
+	t.ID = dbt.ID
+	return insertVersion(db, *t, u)
+}
+
+// Update updates a workflow template and stores it as a new version
+func Update(db gorp.SqlExecutor, t *sdk.WorkflowTemplate, u *sdk.User) error {
+	t.Version++
+	t.LastModified = time.Now()
+	dbt := workflowTemplate(*t)
+	if _, err := db.Update(&dbt); err != nil {
+		return sdk.WrapError(err, "Update> Cannot update workflow template %d", t.ID)
+	}
+	return insertVersion(db, *t, u)
+}
+
+// Delete deletes a workflow template, its versions and its instances
+func Delete(db gorp.SqlExecutor, t *sdk.WorkflowTemplate) error {
+	dbt := workflowTemplate(*t)
+	if _, err := db.Delete(&dbt); err != nil {
+		return sdk.WrapError(err, "Delete> Cannot delete workflow template %d", t.ID)
+	}
+	return nil
+}
+
+func insertVersion(db gorp.SqlExecutor, t sdk.WorkflowTemplate, u *sdk.User) error {
+	t.Group = nil
+	v := workflowTemplateVersion{
+		WorkflowTemplateID: t.ID,
+		Version:            t.Version,
+		Created:            t.LastModified,
+		Template:           t,
+	}
+	if u != nil {
+		v.Author = u.Username
+	}
+	if err := db.Insert(&v); err != nil {
+		return sdk.WrapError(err, "insertVersion> Cannot insert version %d of workflow template %d", t.Version, t.ID)
+	}
+	return nil
+}
+
+func get(db gorp.SqlExecutor, query string, args ...interface{}) (*sdk.WorkflowTemplate, error) {
+	var dbt workflowTemplate
+	if err := db.SelectOne(&dbt, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrWorkflowTemplateNotFound
+		}
+		return nil, sdk.WrapError(err, "get> Cannot load workflow template")
+	}
+	t := sdk.WorkflowTemplate(dbt)
+	return &t, nil
+}
+
+func getAll(db gorp.SqlExecutor, query string, args ...interface{}) ([]sdk.WorkflowTemplate, error) {
+	var dbts []workflowTemplate
+	if _, err := db.Select(&dbts, query, args...); err != nil {
+		return nil, sdk.WrapError(err, "getAll> Cannot load workflow templates")
+	}
+	ts := make([]sdk.WorkflowTemplate, len(dbts))
+	for i := range dbts {
+		ts[i] = sdk.WorkflowTemplate(dbts[i])
+	}
+	return ts, nil
+}
+
+// LoadByID loads a workflow template by its id
+func LoadByID(db gorp.SqlExecutor, id int64) (*sdk.WorkflowTemplate, error) {
+	return get(db, "SELECT * FROM workflow_template WHERE id = $1", id)
+}
+
+// LoadByGroupIDAndName loads a workflow template of a group by its name
+func LoadByGroupIDAndName(db gorp.SqlExecutor, groupID int64, name string) (*sdk.WorkflowTemplate, error) {
+	return get(db, "SELECT * FROM workflow_template WHERE group_id = $1 AND name = $2", groupID, name)
+}
+
+// LoadAllByGroupIDs loads the workflow templates of the given groups
+func LoadAllByGroupIDs(db gorp.SqlExecutor, groupIDs []int64) ([]sdk.WorkflowTemplate, error) {
+	return getAll(db, "SELECT * FROM workflow_template WHERE group_id = ANY($1) ORDER BY name", pq.Int64Array(groupIDs))
+}
+
+// LoadVersions loads the versions of a workflow template, the newest first
+func LoadVersions(db gorp.SqlExecutor, templateID int64) ([]sdk.WorkflowTemplateVersion, error) {
+	var dbvs []workflowTemplateVersion
+	query := "SELECT * FROM workflow_template_version WHERE workflow_template_id = $1 ORDER BY version DESC"
+	if _, err := db.Select(&dbvs, query, templateID); err != nil {
+		return nil, sdk.WrapError(err, "LoadVersions> Cannot load versions of workflow template %d", templateID)
+	}
+	vs := make([]sdk.WorkflowTemplateVersion, len(dbvs))
+	for i := range dbvs {
+		vs[i] = sdk.WorkflowTemplateVersion(dbvs[i])
+	}
+	return vs, nil
+}
+
+// PostInsert is a db hook
+func (t *workflowTemplate) PostInsert(db gorp.SqlExecutor) error {
+	return t.PostUpdate(db)
+}
+
+// PostUpdate is a db hook
+func (t *workflowTemplate) PostUpdate(db gorp.SqlExecutor) error {
+	c, err := gorpmapping.JSONToNullString(content{
+		Parameters:   t.Parameters,
+		Workflow:     t.Workflow,
+		Pipelines:    t.Pipelines,
+		Applications: t.Applications,
+		Environments: t.Environments,
+	})
+	if err != nil {
+		return sdk.WrapError(err, "workflowTemplate.PostUpdate> Cannot marshal content")
+	}
+	if _, err := db.Exec("UPDATE workflow_template SET content = $2 WHERE id = $1", t.ID, c); err != nil {
+		return sdk.WrapError(err, "workflowTemplate.PostUpdate> Cannot update content")
+	}
+	return nil
+}
+
+// PostGet is a db hook
+func (t *workflowTemplate) PostGet(db gorp.SqlExecutor) error {
+	s, err := db.SelectNullStr("SELECT content FROM workflow_template WHERE id = $1", t.ID)
+	if err != nil {
+		return sdk.WrapError(err, "workflowTemplate.PostGet> Cannot load content")
+	}
+	var c content
+	if err := gorpmapping.JSONNullString(s, &c); err != nil {
+		return sdk.WrapError(err, "workflowTemplate.PostGet> Cannot unmarshal content")
+	}
+	t.Parameters = c.Parameters
+	t.Workflow = c.Workflow
+	t.Pipelines = c.Pipelines
+	t.Applications = c.Applications
+	t.Environments = c.Environments
+	return nil
+}
+
+// PostInsert is a db hook
+func (v *workflowTemplateVersion) PostInsert(db gorp.SqlExecutor) error {
+	t, err := gorpmapping.JSONToNullString(v.Template)
+	if err != nil {
+		return sdk.WrapError(err, "workflowTemplateVersion.PostInsert> Cannot marshal template")
+	}
+	query := "UPDATE workflow_template_version SET template = $3 WHERE workflow_template_id = $1 AND version = $2"
+	if _, err := db.Exec(query, v.WorkflowTemplateID, v.Version, t); err != nil {
+		return sdk.WrapError(err, "workflowTemplateVersion.PostInsert> Cannot update template")
+	}
+	return nil
+}
+
+// PostGet is a db hook
+func (v *workflowTemplateVersion) PostGet(db gorp.SqlExecutor) error {
+	query := "SELECT template FROM workflow_template_version WHERE workflow_template_id = $1 AND version = $2"
+	s, err := db.SelectNullStr(query, v.WorkflowTemplateID, v.Version)
+	if err != nil {
+		return sdk.WrapError(err, "workflowTemplateVersion.PostGet> Cannot load template")
+	}
+	return sdk.WrapError(gorpmapping.JSONNullString(s, &v.Template), "workflowTemplateVersion.PostGet> Cannot unmarshal template")
+}
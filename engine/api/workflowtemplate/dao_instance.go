@@ -0,0 +1,103 @@
+package workflowtemplate
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// InsertInstance inserts a new workflow template instance
+func InsertInstance(db gorp.SqlExecutor, i *sdk.WorkflowTemplateInstance) error {
+	i.Created = time.Now()
+	i.LastModified = i.Created
+	dbi := workflowTemplateInstance(*i)
+	if err := db.Insert(&dbi); err != nil {
+		return sdk.WrapError(err, "InsertInstance> Cannot insert workflow template instance")
+	}
+	i.ID = dbi.ID
+	return nil
+}
+
+// UpdateInstance updates a workflow template instance
+func UpdateInstance(db gorp.SqlExecutor, i *sdk.WorkflowTemplateInstance) error {
+	i.LastModified = time.Now()
+	dbi := workflowTemplateInstance(*i)
+	if _, err := db.Update(&dbi); err != nil {
+		return sdk.WrapError(err, "UpdateInstance> Cannot update workflow template instance %d", i.ID)
+	}
+	return nil
+}
+
+// DeleteInstance deletes a workflow template instance
+func DeleteInstance(db gorp.SqlExecutor, i *sdk.WorkflowTemplateInstance) error {
+	dbi := workflowTemplateInstance(*i)
+	if _, err := db.Delete(&dbi); err != nil {
+		return sdk.WrapError(err, "DeleteInstance> Cannot delete workflow template instance %d", i.ID)
+	}
+	return nil
+}
+
+func getAllInstances(db gorp.SqlExecutor, query string, args ...interface{}) ([]sdk.WorkflowTemplateInstance, error) {
+	var dbis []workflowTemplateInstance
+	if _, err := db.Select(&dbis, query, args...); err != nil {
+		return nil, sdk.WrapError(err, "getAllInstances> Cannot load workflow template instances")
+	}
+	is := make([]sdk.WorkflowTemplateInstance, len(dbis))
+	for i := range dbis {
+		is[i] = sdk.WorkflowTemplateInstance(dbis[i])
+	}
+	return is, nil
+}
+
+// LoadInstanceByWorkflowID loads the template instance of a workflow, nil if the workflow was not generated from a template
+func LoadInstanceByWorkflowID(db gorp.SqlExecutor, workflowID int64) (*sdk.WorkflowTemplateInstance, error) {
+	var dbi workflowTemplateInstance
+	if err := db.SelectOne(&dbi, "SELECT * FROM workflow_template_instance WHERE workflow_id = $1", workflowID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, sdk.WrapError(err, "LoadInstanceByWorkflowID> Cannot load workflow template instance of workflow %d", workflowID)
+	}
+	i := sdk.WorkflowTemplateInstance(dbi)
+	return &i, nil
+}
+
+// LoadInstancesByProjectID loads the workflow template instances of a project
+func LoadInstancesByProjectID(db gorp.SqlExecutor, projectID int64) ([]sdk.WorkflowTemplateInstance, error) {
+	return getAllInstances(db, "SELECT * FROM workflow_template_instance WHERE project_id = $1 ORDER BY id", projectID)
+}
+
+// LoadInstancesByTemplateID loads the instances of a workflow template
+func LoadInstancesByTemplateID(db gorp.SqlExecutor, templateID int64) ([]sdk.WorkflowTemplateInstance, error) {
+	return getAllInstances(db, "SELECT * FROM workflow_template_instance WHERE workflow_template_id = $1 ORDER BY id", templateID)
+}
+
+// PostInsert is a db hook
+func (i *workflowTemplateInstance) PostInsert(db gorp.SqlExecutor) error {
+	return i.PostUpdate(db)
+}
+
+// PostUpdate is a db hook
+func (i *workflowTemplateInstance) PostUpdate(db gorp.SqlExecutor) error {
+	r, err := gorpmapping.JSONToNullString(i.Request)
+	if err != nil {
+		return sdk.WrapError(err, "workflowTemplateInstance.PostUpdate> Cannot marshal request")
+	}
+	if _, err := db.Exec("UPDATE workflow_template_instance SET request = $2 WHERE id = $1", i.ID, r); err != nil {
+		return sdk.WrapError(err, "workflowTemplateInstance.PostUpdate> Cannot update request")
+	}
+	return nil
+}
+
+// PostGet is a db hook
+func (i *workflowTemplateInstance) PostGet(db gorp.SqlExecutor) error {
+	s, err := db.SelectNullStr("SELECT request FROM workflow_template_instance WHERE id = $1", i.ID)
+	if err != nil {
+		return sdk.WrapError(err, "workflowTemplateInstance.PostGet> Cannot load request")
+	}
+	return sdk.WrapError(gorpmapping.JSONNullString(s, &i.Request), "workflowTemplateInstance.PostGet> Cannot unmarshal request")
+}
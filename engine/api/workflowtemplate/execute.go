@@ -0,0 +1,161 @@
+package workflowtemplate
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+// Templates use their own delimiters to keep the {{.cds.xxx}} variables of the rendered entities
+const (
+	leftDelim  = "[["
+	rightDelim = "]]"
+)
+
+func parse(name, text string) (*template.Template, error) {
+	return template.New(name).Delims(leftDelim, rightDelim).Option("missingkey=error").Parse(text)
+}
+
+// CheckSyntax checks that all the contents of the template can be parsed
+func CheckSyntax(t sdk.WorkflowTemplate) error {
+	contents := map[string]string{"workflow": t.Workflow}
+	for i, p := range t.Pipelines {
+		contents[fmt.Sprintf("pipeline %d", i+1)] = p
+	}
+	for i, a := range t.Applications {
+		contents[fmt.Sprintf("application %d", i+1)] = a
+	}
+	for i, e := range t.Environments {
+		contents[fmt.Sprintf("environment %d", i+1)] = e
+	}
+	for name, text := range contents {
+		if _, err := parse(name, text); err != nil {
+			return sdk.NewError(sdk.ErrInvalidWorkflowTemplate, fmt.Errorf("invalid %s: %v", name, err))
+		}
+	}
+	return nil
+}
+
+func execute(name, text string, data interface{}) (string, error) {
+	tmpl, err := parse(name, text)
+	if err != nil {
+		return "", sdk.NewError(sdk.ErrInvalidWorkflowTemplate, fmt.Errorf("invalid %s: %v", name, err))
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", sdk.NewError(sdk.ErrInvalidWorkflowTemplate, fmt.Errorf("cannot render %s: %v", name, err))
+	}
+	return buf.String(), nil
+}
+
+// Execute renders the template with the values of the request. The parameters are available as [[.params.key]]
+// and the name of the workflow as [[.name]]. The rendered entities are checked against the exportentities formats.
+func Execute(t sdk.WorkflowTemplate, req sdk.WorkflowTemplateRequest) (sdk.WorkflowTemplateResult, error) {
+	var res sdk.WorkflowTemplateResult
+	if !sdk.NamePatternRegex.MatchString(req.WorkflowName) {
+		return res, sdk.NewError(sdk.ErrInvalidWorkflowTemplateParameters, fmt.Errorf("invalid workflow name %s", req.WorkflowName))
+	}
+
+	params, err := t.ParametersValues(req.Parameters)
+	if err != nil {
+		return res, err
+	}
+	data := map[string]interface{}{
+		"name":   req.WorkflowName,
+		"params": params,
+	}
+
+	res.Workflow, err = execute("workflow", t.Workflow, data)
+	if err != nil {
+		return res, err
+	}
+	var w exportentities.Workflow
+	if err := yaml.Unmarshal([]byte(res.Workflow), &w); err != nil {
+		return res, sdk.NewError(sdk.ErrInvalidWorkflowTemplate, fmt.Errorf("invalid workflow: %v", err))
+	}
+	if w.Name != req.WorkflowName {
+		return res, sdk.NewError(sdk.ErrInvalidWorkflowTemplate, fmt.Errorf("the name of the workflow must be [[.name]]"))
+	}
+
+	for i, text := range t.Pipelines {
+		name := fmt.Sprintf("pipeline %d", i+1)
+		p, err := execute(name, text, data)
+		if err != nil {
+			return res, err
+		}
+		if err := yaml.Unmarshal([]byte(p), &exportentities.PipelineV1{}); err != nil {
+			return res, sdk.NewError(sdk.ErrInvalidWorkflowTemplate, fmt.Errorf("invalid %s: %v", name, err))
+		}
+		res.Pipelines = append(res.Pipelines, p)
+	}
+
+	for i, text := range t.Applications {
+		name := fmt.Sprintf("application %d", i+1)
+		a, err := execute(name, text, data)
+		if err != nil {
+			return res, err
+		}
+		if err := yaml.Unmarshal([]byte(a), &exportentities.Application{}); err != nil {
+			return res, sdk.NewError(sdk.ErrInvalidWorkflowTemplate, fmt.Errorf("invalid %s: %v", name, err))
+		}
+		res.Applications = append(res.Applications, a)
+	}
+
+	for i, text := range t.Environments {
+		name := fmt.Sprintf("environment %d", i+1)
+		e, err := execute(name, text, data)
+		if err != nil {
+			return res, err
+		}
+		if err := yaml.Unmarshal([]byte(e), &exportentities.Environment{}); err != nil {
+			return res, sdk.NewError(sdk.ErrInvalidWorkflowTemplate, fmt.Errorf("invalid %s: %v", name, err))
+		}
+		res.Environments = append(res.Environments, e)
+	}
+
+	return res, nil
+}
+
+type file struct {
+	name    string
+	content string
+}
+
+// Tar writes the rendered entities as a tar archive, the format read by workflow.Push
+func Tar(res sdk.WorkflowTemplateResult, w io.Writer) error {
+	files := []file{{name: "workflow.yml", content: res.Workflow}}
+	for i, p := range res.Pipelines {
+		files = append(files, file{name: fmt.Sprintf("%d.pip.yml", i), content: p})
+	}
+	for i, a := range res.Applications {
+		files = append(files, file{name: fmt.Sprintf("%d.app.yml", i), content: a})
+	}
+	for i, e := range res.Environments {
+		files = append(files, file{name: fmt.Sprintf("%d.env.yml", i), content: e})
+	}
+
+	tw := tar.NewWriter(w)
+	for _, f := range files {
+		hdr := &tar.Header{
+			Name: f.name,
+			Mode: 0644,
+			Size: int64(len(f.content)),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			tw.Close()
+			return sdk.WrapError(err, "Tar> Unable to write header %s", f.name)
+		}
+		if _, err := io.WriteString(tw, f.content); err != nil {
+			tw.Close()
+			return sdk.WrapError(err, "Tar> Unable to write file %s", f.name)
+		}
+	}
+	return sdk.WrapError(tw.Close(), "Tar> Unable to close tar")
+}
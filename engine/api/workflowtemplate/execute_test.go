@@ -0,0 +1,93 @@
+package workflowtemplate
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/sdk"
+)
+
+func testTemplate() sdk.WorkflowTemplate {
+	return sdk.WorkflowTemplate{
+		Name: "my-template",
+		Parameters: []sdk.WorkflowTemplateParameter{
+			{Key: "repo", Type: sdk.TemplateParameterTypeString, Required: true},
+			{Key: "withDeploy", Type: sdk.TemplateParameterTypeBoolean},
+		},
+		Workflow: `name: [[.name]]
+version: v1.0
+workflow:
+  build:
+    pipeline: build-[[.name]]
+[[- if .params.withDeploy]]
+  deploy:
+    depends_on:
+    - build
+    pipeline: deploy-[[.name]]
+[[- end]]`,
+		Pipelines: []string{`version: v1.0
+name: build-[[.name]]
+steps:
+- script:
+  - git clone [[.params.repo]]
+  - echo {{.cds.version}}`},
+	}
+}
+
+func TestExecute(t *testing.T) {
+	res, err := Execute(testTemplate(), sdk.WorkflowTemplateRequest{
+		WorkflowName: "my-workflow",
+		Parameters:   map[string]string{"repo": "https://github.com/ovh/cds"},
+	})
+	test.NoError(t, err)
+	assert.NotContains(t, res.Workflow, "deploy")
+	assert.Contains(t, res.Workflow, "pipeline: build-my-workflow")
+	if !assert.Len(t, res.Pipelines, 1) {
+		return
+	}
+	assert.Contains(t, res.Pipelines[0], "git clone https://github.com/ovh/cds")
+	assert.Contains(t, res.Pipelines[0], "echo {{.cds.version}}")
+
+	res, err = Execute(testTemplate(), sdk.WorkflowTemplateRequest{
+		WorkflowName: "my-workflow",
+		Parameters:   map[string]string{"repo": "https://github.com/ovh/cds", "withDeploy": "true"},
+	})
+	test.NoError(t, err)
+	assert.Contains(t, res.Workflow, "pipeline: deploy-my-workflow")
+
+	var buf bytes.Buffer
+	assert.NoError(t, Tar(res, &buf))
+}
+
+func TestExecuteErrors(t *testing.T) {
+	_, err := Execute(testTemplate(), sdk.WorkflowTemplateRequest{WorkflowName: "my-workflow"})
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrInvalidWorkflowTemplateParameters), "missing required parameter")
+
+	_, err = Execute(testTemplate(), sdk.WorkflowTemplateRequest{
+		WorkflowName: "my-workflow",
+		Parameters:   map[string]string{"repo": "r", "unknown": "value"},
+	})
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrInvalidWorkflowTemplateParameters), "unknown parameter")
+
+	for _, v := range []string{"r\n  - script: rm -rf /", "r: s", "r #c", "'r", "r\"", "*r", "- r", "r:"} {
+		_, err = Execute(testTemplate(), sdk.WorkflowTemplateRequest{
+			WorkflowName: "my-workflow",
+			Parameters:   map[string]string{"repo": v},
+		})
+		assert.True(t, sdk.ErrorIs(err, sdk.ErrInvalidWorkflowTemplateParameters), "unsafe string parameter %q", v)
+	}
+
+	tmpl := testTemplate()
+	tmpl.Workflow = "name: other\nversion: v1.0\npipeline: build-[[.name]]"
+	_, err = Execute(tmpl, sdk.WorkflowTemplateRequest{
+		WorkflowName: "my-workflow",
+		Parameters:   map[string]string{"repo": "r"},
+	})
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrInvalidWorkflowTemplate), "workflow name not rendered from [[.name]]")
+
+	tmpl.Workflow = "name: [[.name]\n"
+	assert.True(t, sdk.ErrorIs(CheckSyntax(tmpl), sdk.ErrInvalidWorkflowTemplate), "invalid syntax")
+}
@@ -0,0 +1,25 @@
+package workflowtemplate
+
+import (
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+type workflowTemplate sdk.WorkflowTemplate
+type workflowTemplateVersion sdk.WorkflowTemplateVersion
+type workflowTemplateInstance sdk.WorkflowTemplateInstance
+
+// content is the part of a template stored as json
+type content struct {
+	Parameters   []sdk.WorkflowTemplateParameter `json:"parameters"`
+	Workflow     string                          `json:"workflow"`
+	Pipelines    []string                        `json:"pipelines,omitempty"`
+	Applications []string                        `json:"applications,omitempty"`
+	Environments []string                        `json:"environments,omitempty"`
+}
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(workflowTemplate{}, "workflow_template", true, "id"))
+	gorpmapping.Register(gorpmapping.New(workflowTemplateVersion{}, "workflow_template_version", false, "workflow_template_id", "version"))
+	gorpmapping.Register(gorpmapping.New(workflowTemplateInstance{}, "workflow_template_instance", true, "id"))
+}
@@ -0,0 +1,196 @@
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/fsamin/go-dump"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// brokerMessage is a message received from a message broker
+type brokerMessage struct {
+	// Topic is the topic, the subject or the routing key of the message
+	Topic   string
+	Headers map[string][]string
+	Body    []byte
+}
+
+// brokerConsumer consumes the messages of a message broker for a hook task
+type brokerConsumer interface {
+	// Consume starts consuming in background, handle is called for each message
+	// and handleError for each error which does not stop the consumer
+	Consume(handle func(brokerMessage), handleError func(error)) error
+	Close() error
+}
+
+// brokerConsumerFactory creates a consumer from the configuration of a task and of its platform
+type brokerConsumerFactory func(t *sdk.Task, pf sdk.ProjectPlatform) (brokerConsumer, error)
+
+var brokerConsumers = map[string]brokerConsumerFactory{
+	TypeKafka:    newKafkaConsumer,
+	TypeRabbitMQ: newRabbitMQConsumer,
+	TypeNATS:     newNATSConsumer,
+	TypeMQTT:     newMQTTConsumer,
+}
+
+// startBrokerHook starts the consumer of a task if it is not already running.
+// The task is stopped if the consumer cannot be started.
+func (s *Service) startBrokerHook(t *sdk.Task) error {
+	s.consumersMutex.Lock()
+	defer s.consumersMutex.Unlock()
+	if _, ok := s.consumers[t.UUID]; ok {
+		return nil
+	}
+
+	consumer, err := s.consumeBroker(t)
+	if err != nil {
+		t.Stopped = true
+		s.Dao.SaveTask(t)
+		return err
+	}
+	if s.consumers == nil {
+		s.consumers = map[string]brokerConsumer{}
+	}
+	s.consumers[t.UUID] = consumer
+	return nil
+}
+
+// consumeBroker creates the consumer of a task and starts consuming.
+// The messages matching the filter of the task are enqueued as task executions.
+func (s *Service) consumeBroker(t *sdk.Task) (brokerConsumer, error) {
+	filter, err := parseMessageFilter(t.Config[sdk.HookModelFilter].Value)
+	if err != nil {
+		return nil, sdk.WrapError(err, "consumeBroker> Invalid filter on task %s", t.UUID)
+	}
+
+	projectKey := t.Config[sdk.HookConfigProject].Value
+	platformName := t.Config[sdk.HookModelPlatform].Value
+	pf, err := s.Client.ProjectPlatformGet(projectKey, platformName, true)
+	if err != nil {
+		return nil, sdk.WrapError(err, "consumeBroker> Cannot get %s configuration for %s/%s", t.Type, projectKey, platformName)
+	}
+
+	consumer, err := brokerConsumers[t.Type](t, pf)
+	if err != nil {
+		return nil, sdk.WrapError(err, "consumeBroker> Cannot create %s consumer for %s/%s", t.Type, projectKey, platformName)
+	}
+
+	handle := func(m brokerMessage) {
+		if !filter.match(m) {
+			log.Debug("Hooks> consumeBroker> message on %s filtered on task %s", m.Topic, t.UUID)
+			return
+		}
+		exec := newBrokerTaskExecution(t, m)
+		s.Dao.SaveTaskExecution(&exec)
+		s.Dao.EnqueueTaskExecution(&exec)
+	}
+	handleError := func(err error) {
+		s.saveBrokerError(t, err)
+	}
+	if err := consumer.Consume(handle, handleError); err != nil {
+		_ = consumer.Close()
+		return nil, sdk.WrapError(err, "consumeBroker> Cannot consume %s messages for %s/%s", t.Type, projectKey, platformName)
+	}
+	return consumer, nil
+}
+
+// stopBrokerHook closes the consumer of a task, if any
+func (s *Service) stopBrokerHook(t *sdk.Task) {
+	s.consumersMutex.Lock()
+	c, ok := s.consumers[t.UUID]
+	delete(s.consumers, t.UUID)
+	s.consumersMutex.Unlock()
+	if !ok {
+		return
+	}
+	if err := c.Close(); err != nil {
+		log.Warning("Hooks> stopBrokerHook> Unable to close %s consumer of task %s: %v", t.Type, t.UUID, err)
+	}
+}
+
+func (s *Service) saveBrokerError(t *sdk.Task, err error) {
+	exec := &sdk.TaskExecution{
+		Timestamp:           time.Now().UnixNano(),
+		Type:                t.Type,
+		UUID:                t.UUID,
+		Config:              t.Config,
+		Status:              TaskExecutionDone,
+		LastError:           err.Error(),
+		NbErrors:            1,
+		ProcessingTimestamp: time.Now().UnixNano(),
+	}
+	s.Dao.SaveTaskExecution(exec)
+}
+
+func newBrokerTaskExecution(t *sdk.Task, m brokerMessage) sdk.TaskExecution {
+	exec := sdk.TaskExecution{
+		ProcessingTimestamp: time.Now().UnixNano(),
+		Status:              TaskExecutionDoing,
+		Config:              t.Config,
+		Type:                t.Type,
+		UUID:                t.UUID,
+		Timestamp:           time.Now().UnixNano(),
+	}
+	switch t.Type {
+	case TypeKafka:
+		exec.Kafka = &sdk.KafkaTaskExecution{Message: m.Body}
+	case TypeRabbitMQ:
+		exec.RabbitMQ = &sdk.RabbitMQTaskExecution{Message: m.Body}
+	case TypeNATS:
+		exec.NATS = &sdk.NATSTaskExecution{Subject: m.Topic, Headers: m.Headers, Message: m.Body}
+	case TypeMQTT:
+		exec.MQTT = &sdk.MQTTTaskExecution{Topic: m.Topic, Message: m.Body}
+	}
+	return exec
+}
+
+// doBrokerTaskExecution dumps the JSON message in the payload of the event, with the extra values
+func (s *Service) doBrokerTaskExecution(t *sdk.TaskExecution, message []byte, extra map[string]string) (*sdk.WorkflowNodeRunHookEvent, error) {
+	log.Debug("Hooks> Processing %s %s", t.Type, t.UUID)
+
+	// Prepare a struct to send to CDS API
+	h := sdk.WorkflowNodeRunHookEvent{
+		WorkflowNodeHookUUID: t.UUID,
+		Payload:              map[string]string{},
+	}
+
+	var bodyJSON interface{}
+
+	//Try to parse the body as an array
+	bodyJSONArray := []interface{}{}
+	if err := json.Unmarshal(message, &bodyJSONArray); err != nil {
+		//Try to parse the body as a map
+		bodyJSONMap := map[string]interface{}{}
+		if err2 := json.Unmarshal(message, &bodyJSONMap); err2 == nil {
+			bodyJSON = bodyJSONMap
+		}
+	} else {
+		bodyJSON = bodyJSONArray
+	}
+
+	//Go Dump
+	e := dump.NewDefaultEncoder(new(bytes.Buffer))
+	e.Formatters = []dump.KeyFormatterFunc{dump.WithDefaultLowerCaseFormatter()}
+	e.ExtraFields.DetailedMap = false
+	e.ExtraFields.DetailedStruct = false
+	e.ExtraFields.DeepJSON = true
+	e.ExtraFields.Len = false
+	e.ExtraFields.Type = false
+	m, err := e.ToStringMap(bodyJSON)
+	if err != nil {
+		return nil, sdk.WrapError(err, "Hooks.doBrokerTaskExecution> Unable to dump body %s", message)
+	}
+	if m == nil {
+		m = map[string]string{}
+	}
+	for k, v := range extra {
+		m[k] = v
+	}
+	h.Payload = m
+
+	return &h, nil
+}
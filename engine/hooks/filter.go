@@ -0,0 +1,140 @@
+package hooks
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// checkPushEventFilters checks the branches, tags and paths filters of a hook against a push event.
+// It returns the reason why the event is skipped, or an empty string if the workflow has to be triggered.
+// The changed files are only loaded if the hook has paths filters, they are not checked for tags.
+func checkPushEventFilters(cfg sdk.WorkflowNodeHookConfig, branch, tag string, changedFiles func() ([]string, error)) string {
+	branches := cfg[sdk.HookConfigBranches].Value
+	tags := cfg[sdk.HookConfigTags].Value
+	if branches != "" || tags != "" {
+		switch {
+		case tag != "" && tags == "":
+			return fmt.Sprintf("tag %s: only branches matching %s trigger the workflow", tag, branches)
+		case tag != "" && !matchRefPatterns(tags, tag):
+			return fmt.Sprintf("tag %s does not match %s", tag, tags)
+		case tag == "" && branches == "":
+			return fmt.Sprintf("branch %s: only tags matching %s trigger the workflow", branch, tags)
+		case tag == "" && !matchRefPatterns(branches, branch):
+			return fmt.Sprintf("branch %s does not match %s", branch, branches)
+		}
+	}
+
+	include := cfg[sdk.HookConfigIncludePaths].Value
+	exclude := cfg[sdk.HookConfigExcludePaths].Value
+	if tag != "" || (include == "" && exclude == "") {
+		return ""
+	}
+	files, err := changedFiles()
+	if err != nil {
+		log.Error("checkPushEventFilters> unable to get changed files, paths filters are ignored: %v", err)
+		return ""
+	}
+	if matchPaths(include, exclude, files) {
+		return ""
+	}
+	return fmt.Sprintf("none of the %d changed files match the paths filters", len(files))
+}
+
+// matchPaths returns true if one of the files matches the include patterns and none of the exclude patterns.
+// Without include patterns, all files are included.
+func matchPaths(include, exclude string, files []string) bool {
+	for _, f := range files {
+		if include != "" && !matchAnyPattern(include, f) {
+			continue
+		}
+		if exclude != "" && matchAnyPattern(exclude, f) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// matchRefPatterns checks a branch or a tag name against a comma separated list of glob patterns.
+// Patterns prefixed by ! exclude the matching names.
+func matchRefPatterns(patterns, name string) bool {
+	var included, hasInclude bool
+	for _, p := range splitPatterns(patterns) {
+		if strings.HasPrefix(p, "!") {
+			if globMatch(strings.TrimPrefix(p, "!"), name) {
+				return false
+			}
+			continue
+		}
+		hasInclude = true
+		if globMatch(p, name) {
+			included = true
+		}
+	}
+	return included || !hasInclude
+}
+
+func matchAnyPattern(patterns, name string) bool {
+	for _, p := range splitPatterns(patterns) {
+		if globMatch(p, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitPatterns(patterns string) []string {
+	var res []string
+	for _, p := range strings.Split(patterns, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// globMatch reports whether the name matches the pattern: * matches any sequence of characters except /,
+// ** matches any sequence of characters, ? matches one character except /.
+// A pattern ending with / matches everything in the directory.
+func globMatch(pattern, name string) bool {
+	re, err := regexp.Compile(globToRegexp(pattern))
+	if err != nil {
+		return false
+	}
+	return re.MatchString(name)
+}
+
+func globToRegexp(pattern string) string {
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+	p := []rune(pattern)
+	var b bytes.Buffer
+	b.WriteString("^")
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; {
+		case c == '*' && i+1 < len(p) && p[i+1] == '*':
+			i++
+			// **/ matches zero or more directories
+			if i+1 < len(p) && p[i+1] == '/' {
+				i++
+				b.WriteString("(.*/)?")
+			} else {
+				b.WriteString(".*")
+			}
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
@@ -0,0 +1,95 @@
+package hooks
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func Test_globMatch(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		match         bool
+	}{
+		{"*.md", "README.md", true},
+		{"*.md", "docs/README.md", false},
+		{"**.md", "docs/README.md", true},
+		{"docs/**", "docs/content/index.md", true},
+		{"docs/", "docs/content/index.md", true},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "engine/api/main.go", true},
+		{"engine/*/main.go", "engine/api/main.go", true},
+		{"engine/*/main.go", "engine/api/test/main.go", false},
+		{"release/v?", "release/v1", true},
+		{"release/v?", "release/v10", false},
+		{"release/*", "master", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.match, globMatch(tt.pattern, tt.name), "%s on %s", tt.pattern, tt.name)
+	}
+}
+
+func Test_checkPushEventFilters(t *testing.T) {
+	files := func(f ...string) func() ([]string, error) {
+		return func() ([]string, error) { return f, nil }
+	}
+	cfg := sdk.WorkflowNodeHookConfig{
+		sdk.HookConfigBranches:     {Value: "master, release/*, !release/old"},
+		sdk.HookConfigIncludePaths: {Value: "engine/**, sdk/**"},
+		sdk.HookConfigExcludePaths: {Value: "**.md"},
+	}
+
+	assert.Empty(t, checkPushEventFilters(cfg, "master", "", files("README.md", "engine/api/main.go")))
+	assert.NotEmpty(t, checkPushEventFilters(cfg, "master", "", files("README.md", "engine/README.md", "ui/main.ts")))
+	assert.NotEmpty(t, checkPushEventFilters(cfg, "master", "", files()))
+	assert.Empty(t, checkPushEventFilters(cfg, "release/1.0", "", files("sdk/hooks.go")))
+	assert.NotEmpty(t, checkPushEventFilters(cfg, "release/old", "", files("sdk/hooks.go")))
+	assert.NotEmpty(t, checkPushEventFilters(cfg, "feat/foo", "", files("sdk/hooks.go")))
+	// Without tags filter, tags are skipped if branches are filtered
+	assert.NotEmpty(t, checkPushEventFilters(cfg, "", "v1.0", files()))
+	// Errors while loading the changed files do not prevent the workflow from running
+	assert.Empty(t, checkPushEventFilters(cfg, "master", "", func() ([]string, error) { return nil, fmt.Errorf("error") }))
+
+	cfg = sdk.WorkflowNodeHookConfig{
+		sdk.HookConfigTags:         {Value: "v*"},
+		sdk.HookConfigIncludePaths: {Value: "engine/**"},
+	}
+	assert.Empty(t, checkPushEventFilters(cfg, "", "v1.0", files()))
+	assert.NotEmpty(t, checkPushEventFilters(cfg, "", "test", files()))
+	assert.NotEmpty(t, checkPushEventFilters(cfg, "master", "", files("engine/api/main.go")))
+}
+
+func Test_doWebHookExecutionGithubPathsFilter(t *testing.T) {
+	log.SetLogger(t)
+	s := Service{}
+	task := &sdk.TaskExecution{
+		UUID: sdk.RandomString(10),
+		Type: TypeRepoManagerWebHook,
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.RepositoryWebHookModelEvents: {Value: sdk.RepositoryWebHookEventPush},
+			sdk.HookConfigIncludePaths:       {Value: "engine/**"},
+		},
+		WebHook: &sdk.WebHookExecution{
+			RequestBody: []byte(githubPushEvent),
+			RequestHeader: map[string][]string{
+				GithubHeader: {"push"},
+			},
+		},
+	}
+	h, err := s.doWebHookExecution(task)
+	test.NoError(t, err)
+	assert.Nil(t, h)
+	assert.NotEmpty(t, task.SkipReason)
+
+	task.SkipReason = ""
+	task.Config[sdk.HookConfigIncludePaths] = sdk.WorkflowNodeHookConfigValue{Value: "*.md"}
+	h, err = s.doWebHookExecution(task)
+	test.NoError(t, err)
+	test.NotNil(t, h)
+	assert.Empty(t, task.SkipReason)
+}
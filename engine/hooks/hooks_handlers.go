@@ -44,6 +44,11 @@ func (s *Service) webhookHandler() service.Handler {
 			return sdk.WrapError(err, "Hooks> webhookHandler> unable to read request")
 		}
 
+		//Check the signature
+		if err := checkWebHookSignature(webHook, r.Header, req); err != nil {
+			return sdk.WrapError(sdk.ErrUnauthorized, "Hooks> webhookHandler> Invalid request on webhook %s: %v", uuid, err)
+		}
+
 		//Prepare a web hook execution
 		exec := &sdk.TaskExecution{
 			Timestamp: time.Now().UnixNano(),
@@ -419,6 +424,11 @@ func (s *Service) deleteTask(ctx context.Context, h *sdk.WorkflowNodeHook) error
 		return sdk.WrapError(err, "Hooks> deleteTask> Unable to parse hook")
 	}
 
+	// Stop the consumer of the broker hooks
+	if t.Type == TypeKafka || t.Type == TypeRabbitMQ || t.Type == TypeNATS || t.Type == TypeMQTT {
+		s.stopBrokerHook(t)
+	}
+
 	//Save the task
 	s.Dao.DeleteTask(t)
 
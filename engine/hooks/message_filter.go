@@ -0,0 +1,241 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// messageFilter selects the messages consumed by the broker hooks. It is a list of conditions
+// joined by && and ||, && having the precedence. A condition is an operand, which is true if it exists
+// and is not empty, false or null, or an operand compared to a value with ==, != or =~ (regular expression).
+// The operands are:
+//   - topic: the topic, the subject or the routing key of the message
+//   - header.name: a header of the message, the name is case insensitive
+//   - a JSONPath on the body of the message: $.event.type, $.commits[0].id
+//
+// The values are quoted strings, numbers, true, false or null.
+type messageFilter [][]messageCondition
+
+type messageCondition struct {
+	operand string
+	op      string
+	value   string
+	re      *regexp.Regexp
+}
+
+// parseMessageFilter parses a filter, an empty filter matches all the messages
+func parseMessageFilter(s string) (messageFilter, error) {
+	tokens, err := tokenizeMessageFilter(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var filter messageFilter
+	var and []messageCondition
+	for i := 0; i < len(tokens); {
+		if tokens[i].op {
+			return nil, fmt.Errorf("invalid filter %s: unexpected %s", s, tokens[i].value)
+		}
+		c := messageCondition{operand: tokens[i].value}
+		if !strings.HasPrefix(c.operand, "$") && c.operand != "topic" && !strings.HasPrefix(c.operand, "header.") {
+			return nil, fmt.Errorf("invalid filter %s: unknown operand %s", s, c.operand)
+		}
+		i++
+
+		if i < len(tokens) && tokens[i].op && (tokens[i].value == "==" || tokens[i].value == "!=" || tokens[i].value == "=~") {
+			c.op = tokens[i].value
+			i++
+			if i >= len(tokens) || tokens[i].op {
+				return nil, fmt.Errorf("invalid filter %s: missing value after %s", s, c.op)
+			}
+			c.value = tokens[i].value
+			if !tokens[i].quoted {
+				c.value = normalizeFilterValue(c.value)
+			}
+			if c.op == "=~" {
+				if c.re, err = regexp.Compile(c.value); err != nil {
+					return nil, fmt.Errorf("invalid filter %s: %v", s, err)
+				}
+			}
+			i++
+		}
+		and = append(and, c)
+
+		if i < len(tokens) {
+			switch {
+			case tokens[i].op && tokens[i].value == "&&":
+			case tokens[i].op && tokens[i].value == "||":
+				filter = append(filter, and)
+				and = nil
+			default:
+				return nil, fmt.Errorf("invalid filter %s: expected && or || instead of %s", s, tokens[i].value)
+			}
+			i++
+			if i == len(tokens) {
+				return nil, fmt.Errorf("invalid filter %s: missing condition", s)
+			}
+		}
+	}
+	if and != nil {
+		filter = append(filter, and)
+	}
+	return filter, nil
+}
+
+type filterToken struct {
+	value  string
+	op     bool
+	quoted bool
+}
+
+func tokenizeMessageFilter(s string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '"' || c == '\'':
+			j := strings.IndexByte(s[i+1:], c)
+			if j < 0 {
+				return nil, fmt.Errorf("invalid filter %s: unterminated string", s)
+			}
+			tokens = append(tokens, filterToken{value: s[i+1 : i+1+j], quoted: true})
+			i += j + 2
+		case strings.HasPrefix(s[i:], "=="), strings.HasPrefix(s[i:], "!="), strings.HasPrefix(s[i:], "=~"),
+			strings.HasPrefix(s[i:], "&&"), strings.HasPrefix(s[i:], "||"):
+			tokens = append(tokens, filterToken{value: s[i : i+2], op: true})
+			i += 2
+		default:
+			j := i
+			for j < len(s) && !strings.ContainsRune(" \t\n\"'=!&|", rune(s[j])) {
+				j++
+			}
+			if j == i {
+				return nil, fmt.Errorf("invalid filter %s: unexpected %c", s, c)
+			}
+			tokens = append(tokens, filterToken{value: s[i:j]})
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+// normalizeFilterValue formats the unquoted numbers as the values read in the messages
+func normalizeFilterValue(v string) string {
+	if f, err := strconv.ParseFloat(v, 64); err == nil {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return v
+}
+
+func (f messageFilter) match(m brokerMessage) bool {
+	if len(f) == 0 {
+		return true
+	}
+	var body interface{}
+	var bodyParsed bool
+	for _, and := range f {
+		matched := true
+		for _, c := range and {
+			var v string
+			var ok bool
+			switch {
+			case c.operand == "topic":
+				v, ok = m.Topic, true
+			case strings.HasPrefix(c.operand, "header."):
+				v, ok = messageHeader(m.Headers, strings.TrimPrefix(c.operand, "header."))
+			default:
+				if !bodyParsed {
+					bodyParsed = true
+					if err := json.Unmarshal(m.Body, &body); err != nil {
+						body = nil
+					}
+				}
+				v, ok = jsonPathValue(body, c.operand)
+			}
+			if !c.eval(v, ok) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (c messageCondition) eval(v string, exists bool) bool {
+	switch c.op {
+	case "==":
+		return exists && v == c.value
+	case "!=":
+		return !exists || v != c.value
+	case "=~":
+		return exists && c.re.MatchString(v)
+	}
+	return exists && v != "" && v != "false" && v != "null"
+}
+
+func messageHeader(headers map[string][]string, name string) (string, bool) {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) && len(v) > 0 {
+			return v[0], true
+		}
+	}
+	return "", false
+}
+
+var jsonPathIndexRegexp = regexp.MustCompile(`^([^\[]*)((?:\[\d+\])*)$`)
+
+// jsonPathValue returns the value of a JSONPath, such as $.a.b[0].c, formatted as a string
+func jsonPathValue(body interface{}, path string) (string, bool) {
+	path = strings.TrimPrefix(path, "$")
+	v := body
+	for _, part := range strings.Split(path, ".") {
+		if part == "" {
+			continue
+		}
+		sub := jsonPathIndexRegexp.FindStringSubmatch(part)
+		if sub == nil {
+			return "", false
+		}
+		if sub[1] != "" {
+			obj, ok := v.(map[string]interface{})
+			if !ok {
+				return "", false
+			}
+			if v, ok = obj[sub[1]]; !ok {
+				return "", false
+			}
+		}
+		for _, idx := range strings.Split(strings.Trim(sub[2], "[]"), "][") {
+			if idx == "" {
+				continue
+			}
+			i, _ := strconv.Atoi(idx)
+			array, ok := v.([]interface{})
+			if !ok || i >= len(array) {
+				return "", false
+			}
+			v = array[i]
+		}
+	}
+
+	switch t := v.(type) {
+	case string:
+		return t, true
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(t), true
+	case nil:
+		return "null", true
+	default:
+		b, _ := json.Marshal(t)
+		return string(b), true
+	}
+}
@@ -0,0 +1,60 @@
+package hooks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_messageFilter(t *testing.T) {
+	m := brokerMessage{
+		Topic:   "events.deploy",
+		Headers: map[string][]string{"X-Event": {"release.v1"}},
+		Body:    []byte(`{"event": {"type": "deploy", "env": "prod", "count": 2, "draft": false}, "commits": [{"id": "abc"}]}`),
+	}
+
+	tests := []struct {
+		filter string
+		match  bool
+	}{
+		{"", true},
+		{`$.event.type == "deploy"`, true},
+		{`$.event.type == "deploy" && $.event.env != "prod"`, false},
+		{`$.event.type == "build" || $.event.env == 'prod'`, true},
+		{`$.event.count == 2`, true},
+		{`$.event.count == 2.0`, true},
+		{`$.commits[0].id == "abc"`, true},
+		{`$.commits[1].id`, false},
+		{`$.event.draft`, false},
+		{`$.event.missing != "x"`, true},
+		{`header.x-event =~ "^release\."`, true},
+		{`header.X-Other`, false},
+		{`topic == "events.deploy"`, true},
+		{`topic =~ "^builds\." || $.event.type == "build" && $.event.env == "prod"`, false},
+	}
+	for _, tt := range tests {
+		f, err := parseMessageFilter(tt.filter)
+		test.NoError(t, err)
+		assert.Equal(t, tt.match, f.match(m), tt.filter)
+	}
+
+	for _, invalid := range []string{`$.a ==`, `$.a == "b" &&`, `body.a`, `$.a =~ "("`, `$.a "b"`, `$.a == "b`} {
+		_, err := parseMessageFilter(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func Test_doBrokerTaskExecution(t *testing.T) {
+	s := Service{}
+	task := &sdk.Task{UUID: sdk.RandomString(10), Type: TypeNATS}
+	exec := newBrokerTaskExecution(task, brokerMessage{Topic: "events.deploy", Body: []byte(`{"env": "prod"}`)})
+	test.NotNil(t, exec.NATS)
+
+	h, err := s.doBrokerTaskExecution(&exec, exec.NATS.Message, map[string]string{"nats.subject": exec.NATS.Subject})
+	test.NoError(t, err)
+	assert.Equal(t, "prod", h.Payload["env"])
+	assert.Equal(t, "events.deploy", h.Payload["nats.subject"])
+}
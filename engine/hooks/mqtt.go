@@ -0,0 +1,75 @@
+package hooks
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/ovh/cds/sdk"
+)
+
+type mqttConsumer struct {
+	opts   *mqtt.ClientOptions
+	client mqtt.Client
+	topic  string
+	qos    byte
+}
+
+func newMQTTConsumer(t *sdk.Task, pf sdk.ProjectPlatform) (brokerConsumer, error) {
+	qos, err := strconv.Atoi(t.Config[sdk.MQTTHookModelQoS].Value)
+	if err != nil || qos < 0 || qos > 2 {
+		return nil, fmt.Errorf("newMQTTConsumer> Invalid qos %s, it must be 0, 1 or 2", t.Config[sdk.MQTTHookModelQoS].Value)
+	}
+
+	// The session is kept by the broker to receive the messages sent while the hooks service is disconnected
+	clientID := fmt.Sprintf("cds.hooks.%s", t.UUID)
+	opts := mqtt.NewClientOptions().
+		AddBroker(pf.Config["broker url"].Value).
+		SetClientID(clientID).
+		SetCleanSession(false).
+		SetAutoReconnect(true).
+		SetConnectTimeout(30 * time.Second)
+	if username := pf.Config["username"].Value; username != "" {
+		opts.SetUsername(username).SetPassword(pf.Config["password"].Value)
+	}
+
+	vClientID := t.Config[sdk.MQTTHookModelClientID]
+	vClientID.Value = clientID
+	t.Config[sdk.MQTTHookModelClientID] = vClientID
+
+	return &mqttConsumer{
+		opts:  opts,
+		topic: t.Config[sdk.MQTTHookModelTopic].Value,
+		qos:   byte(qos),
+	}, nil
+}
+
+func (c *mqttConsumer) Consume(handle func(brokerMessage), handleError func(error)) error {
+	onMessage := func(_ mqtt.Client, msg mqtt.Message) {
+		handle(brokerMessage{Topic: msg.Topic(), Body: msg.Payload()})
+	}
+	// Subscribe on each connection, as the broker may have lost the session
+	c.opts.SetOnConnectHandler(func(client mqtt.Client) {
+		if token := client.Subscribe(c.topic, c.qos, onMessage); token.Wait() && token.Error() != nil {
+			handleError(fmt.Errorf("Subscribe %s: %v", c.topic, token.Error()))
+		}
+	})
+	c.opts.SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+		handleError(fmt.Errorf("Connection lost: %v", err))
+	})
+
+	c.client = mqtt.NewClient(c.opts)
+	if token := c.client.Connect(); token.Wait() && token.Error() != nil {
+		return fmt.Errorf("Connect: %v", token.Error())
+	}
+	return nil
+}
+
+func (c *mqttConsumer) Close() error {
+	if c.client != nil && c.client.IsConnected() {
+		c.client.Disconnect(250)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package hooks
+
+import (
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/ovh/cds/sdk"
+)
+
+type natsConsumer struct {
+	conn    *nats.Conn
+	subject string
+	queue   string
+	errors  chan error
+	done    chan struct{}
+}
+
+func newNATSConsumer(t *sdk.Task, pf sdk.ProjectPlatform) (brokerConsumer, error) {
+	c := &natsConsumer{
+		subject: t.Config[sdk.NATSHookModelSubject].Value,
+		// All the hooks services share the messages of a task
+		queue:  fmt.Sprintf("cds.hooks.%s", t.UUID),
+		errors: make(chan error, 10),
+		done:   make(chan struct{}),
+	}
+
+	opts := []nats.Option{
+		nats.Name(c.queue),
+		nats.MaxReconnects(-1),
+		nats.ErrorHandler(func(_ *nats.Conn, _ *nats.Subscription, err error) {
+			c.sendError(err)
+		}),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				c.sendError(err)
+			}
+		}),
+	}
+	if username := pf.Config["username"].Value; username != "" {
+		opts = append(opts, nats.UserInfo(username, pf.Config["password"].Value))
+	}
+
+	var err error
+	c.conn, err = nats.Connect(pf.Config["url"].Value, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("newNATSConsumer> Error connecting to %s: %v", pf.Config["url"].Value, err)
+	}
+
+	vQueue := t.Config[sdk.NATSHookModelQueueGroup]
+	vQueue.Value = c.queue
+	t.Config[sdk.NATSHookModelQueueGroup] = vQueue
+
+	return c, nil
+}
+
+// sendError does not block the nats client if the errors are not consumed
+func (c *natsConsumer) sendError(err error) {
+	select {
+	case c.errors <- err:
+	default:
+	}
+}
+
+func (c *natsConsumer) Consume(handle func(brokerMessage), handleError func(error)) error {
+	go func() {
+		for {
+			select {
+			case err := <-c.errors:
+				handleError(err)
+			case <-c.done:
+				return
+			}
+		}
+	}()
+
+	if _, err := c.conn.QueueSubscribe(c.subject, c.queue, func(msg *nats.Msg) {
+		handle(brokerMessage{Topic: msg.Subject, Headers: msg.Header, Body: msg.Data})
+	}); err != nil {
+		return fmt.Errorf("Subscribe %s: %v", c.subject, err)
+	}
+	return nil
+}
+
+func (c *natsConsumer) Close() error {
+	c.conn.Close()
+	close(c.done)
+	return nil
+}
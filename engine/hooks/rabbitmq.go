@@ -1,15 +1,8 @@
 package hooks
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
-	"os"
-	"os/signal"
-	"syscall"
-	"time"
 
-	"github.com/fsamin/go-dump"
 	"github.com/streadway/amqp"
 
 	"github.com/ovh/cds/sdk"
@@ -19,19 +12,13 @@ import (
 type rabbitMQConsumer struct {
 	conn    *amqp.Connection
 	channel *amqp.Channel
+	queue   string
 	tag     string
 	done    chan error
+	started bool
 }
 
-func (s *Service) startRabbitMQHook(t *sdk.Task) error {
-	projectKey := t.Config[sdk.HookConfigProject].Value
-	platformName := t.Config[sdk.HookModelPlatform].Value
-	pf, err := s.Client.ProjectPlatformGet(projectKey, platformName, true)
-	if err != nil {
-		_ = s.stopTask(t)
-		return sdk.WrapError(err, "startTask> Cannot get rabbitMQ configuration for %s/%s", projectKey, platformName)
-	}
-
+func newRabbitMQConsumer(t *sdk.Task, pf sdk.ProjectPlatform) (brokerConsumer, error) {
 	password := pf.Config["password"].Value
 	username := pf.Config["username"].Value
 	uri := fmt.Sprintf("amqp://%s:%s@%s", username, password, pf.Config["uri"].Value)
@@ -45,97 +32,46 @@ func (s *Service) startRabbitMQHook(t *sdk.Task) error {
 		t.Config[sdk.RabbitMQHookModelConsumerTag].Value,
 	)
 	if err != nil {
-		_ = s.stopTask(t)
-		return fmt.Errorf("startRabbitMQHook>Error creating consumer: (%s %s %+v): %v", pf.Config["uri"].Value, username, t.Config, err)
+		return nil, fmt.Errorf("newRabbitMQConsumer> Error creating consumer: (%s %s %+v): %v", pf.Config["uri"].Value, username, t.Config, err)
 	}
+	return consumer, nil
+}
 
-	deliveries, errConsume := consumer.channel.Consume(
-		t.Config[sdk.RabbitMQHookModelQueue].Value, // name
-		consumer.tag,                               // consumerTag,
-		false,                                      // noAck
-		false,                                      // exclusive
-		false,                                      // noLocal
-		false,                                      // noWait
-		nil,                                        // arguments
+func (c *rabbitMQConsumer) Consume(handle func(brokerMessage), handleError func(error)) error {
+	deliveries, errConsume := c.channel.Consume(
+		c.queue, // name
+		c.tag,   // consumerTag,
+		false,   // noAck
+		false,   // exclusive
+		false,   // noLocal
+		false,   // noWait
+		nil,     // arguments
 	)
 	if errConsume != nil {
-		_ = s.stopTask(t)
-		return fmt.Errorf("startRabbitMQHook> Queue Consume: %s", errConsume)
+		return fmt.Errorf("Queue Consume: %s", errConsume)
 	}
-
-	sigs := make(chan os.Signal, 1)
-	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
-	go func() {
-		<-sigs
-		log.Info("RabbitMQ> shutdown")
-		_ = consumer.Shutdown()
-	}()
+	c.started = true
 
 	go func() {
 		for d := range deliveries {
 			_ = d.Ack(false)
-			exec := sdk.TaskExecution{
-				ProcessingTimestamp: time.Now().UnixNano(),
-				Status:              TaskExecutionDoing,
-				Config:              t.Config,
-				Type:                TypeRabbitMQ,
-				UUID:                t.UUID,
-				Timestamp:           time.Now().UnixNano(),
-				RabbitMQ:            &sdk.RabbitMQTaskExecution{Message: d.Body},
+			headers := make(map[string][]string, len(d.Headers))
+			for k, v := range d.Headers {
+				headers[k] = []string{fmt.Sprint(v)}
 			}
-			s.Dao.SaveTaskExecution(&exec)
-			s.Dao.EnqueueTaskExecution(&exec)
+			handle(brokerMessage{Topic: d.RoutingKey, Headers: headers, Body: d.Body})
 		}
-		consumer.done <- nil
+		c.done <- nil
 	}()
 
 	return nil
 }
 
-func (s *Service) doRabbitMQTaskExecution(t *sdk.TaskExecution) (*sdk.WorkflowNodeRunHookEvent, error) {
-	log.Debug("Hooks> Processing rabbitMQ %s %s", t.UUID, t.Type)
-
-	// Prepare a struct to send to CDS API
-	h := sdk.WorkflowNodeRunHookEvent{
-		WorkflowNodeHookUUID: t.UUID,
-		Payload:              map[string]string{},
-	}
-
-	var bodyJSON interface{}
-
-	//Try to parse the body as an array
-	bodyJSONArray := []interface{}{}
-	if err := json.Unmarshal(t.RabbitMQ.Message, &bodyJSONArray); err != nil {
-		//Try to parse the body as a map
-		bodyJSONMap := map[string]interface{}{}
-		if err2 := json.Unmarshal(t.RabbitMQ.Message, &bodyJSONMap); err2 == nil {
-			bodyJSON = bodyJSONMap
-		}
-	} else {
-		bodyJSON = bodyJSONArray
-	}
-
-	//Go Dump
-	e := dump.NewDefaultEncoder(new(bytes.Buffer))
-	e.Formatters = []dump.KeyFormatterFunc{dump.WithDefaultLowerCaseFormatter()}
-	e.ExtraFields.DetailedMap = false
-	e.ExtraFields.DetailedStruct = false
-	e.ExtraFields.DeepJSON = true
-	e.ExtraFields.Len = false
-	e.ExtraFields.Type = false
-	m, err := e.ToStringMap(bodyJSON)
-	if err != nil {
-		return nil, sdk.WrapError(err, "Hooks.doRabbitMQTaskExecution> Unable to dump body %s", t.WebHook.RequestBody)
-	}
-	h.Payload = m
-
-	return &h, nil
-}
-
 func newConsumer(amqpURI, exchange, exchangeType, queueName, key, ctag string) (*rabbitMQConsumer, error) {
 	c := &rabbitMQConsumer{
 		conn:    nil,
 		channel: nil,
+		queue:   queueName,
 		tag:     ctag,
 		done:    make(chan error),
 	}
@@ -193,7 +129,7 @@ func newConsumer(amqpURI, exchange, exchangeType, queueName, key, ctag string) (
 	return c, nil
 }
 
-func (c *rabbitMQConsumer) Shutdown() error {
+func (c *rabbitMQConsumer) Close() error {
 	// will close() the deliveries channel
 	if err := c.channel.Cancel(c.tag, true); err != nil {
 		return fmt.Errorf("Consumer cancel failed: %s", err)
@@ -203,6 +139,9 @@ func (c *rabbitMQConsumer) Shutdown() error {
 		return fmt.Errorf("AMQP connection close error: %s", err)
 	}
 
+	if !c.started {
+		return nil
+	}
 	log.Info("RabbitMQ> Shutdown> Wait for handle to exit...")
 	// wait for handle() to exit
 	return <-c.done
@@ -203,6 +203,7 @@ func (s *Service) dequeueTaskExecutions(c context.Context) error {
 		}
 		t.ProcessingTimestamp = time.Now().UnixNano()
 		t.LastError = ""
+		t.SkipReason = ""
 		t.Status = TaskExecutionDoing
 		s.Dao.SaveTaskExecution(&t)
 
@@ -0,0 +1,82 @@
+package hooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Headers used by the repository managers to sign webhooks requests
+const (
+	GithubSignatureHeader    = "X-Hub-Signature"
+	GithubSignature256Header = "X-Hub-Signature-256"
+	GitlabTokenHeader        = "X-Gitlab-Token"
+)
+
+// checkWebHookSignature checks the request against the secret of the webhook, if any.
+// Repository webhooks are signed by Github and Bitbucket, Gitlab sends the secret as a token.
+// Generic webhooks are signed with the secret in the configured header.
+func checkWebHookSignature(t *sdk.Task, header http.Header, body []byte) error {
+	switch t.Type {
+	case TypeRepoManagerWebHook:
+		secret := t.Config[sdk.RepositoryWebHookModelSecret].Value
+		if secret == "" {
+			return nil
+		}
+		if token := header.Get(GitlabTokenHeader); token != "" {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+				return fmt.Errorf("invalid %s", GitlabTokenHeader)
+			}
+			return nil
+		}
+		if s := header.Get(GithubSignature256Header); s != "" {
+			return checkHMACSignature(s, secret, body)
+		}
+		return checkHMACSignature(header.Get(GithubSignatureHeader), secret, body)
+	case TypeWebHook:
+		secret := t.Config[sdk.WebHookModelConfigSecret].Value
+		if secret == "" {
+			return nil
+		}
+		h := t.Config[sdk.WebHookModelConfigSignature].Value
+		if h == "" {
+			h = GithubSignature256Header
+		}
+		return checkHMACSignature(header.Get(h), secret, body)
+	}
+	return nil
+}
+
+// checkHMACSignature checks an hexadecimal HMAC of the body. The signature can be prefixed by
+// the hash algorithm, sha1= or sha256=, sha256 is used without prefix.
+func checkHMACSignature(signature, secret string, body []byte) error {
+	if signature == "" {
+		return fmt.Errorf("missing signature")
+	}
+	hashFunc := sha256.New
+	switch {
+	case strings.HasPrefix(signature, "sha1="):
+		hashFunc = sha1.New
+		signature = strings.TrimPrefix(signature, "sha1=")
+	case strings.HasPrefix(signature, "sha256="):
+		signature = strings.TrimPrefix(signature, "sha256=")
+	}
+
+	sig, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %v", err)
+	}
+	mac := hmac.New(hashFunc, []byte(secret))
+	mac.Write(body) // nolint
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
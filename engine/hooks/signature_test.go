@@ -0,0 +1,48 @@
+package hooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_checkWebHookSignature(t *testing.T) {
+	body := []byte(`{"ref": "refs/heads/master"}`)
+	mac := hmac.New(sha256.New, []byte("my-secret"))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	repoTask := &sdk.Task{
+		Type: TypeRepoManagerWebHook,
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.RepositoryWebHookModelSecret: {Value: "my-secret"},
+		},
+	}
+	assert.NoError(t, checkWebHookSignature(repoTask, http.Header{GithubSignature256Header: {"sha256=" + signature}}, body))
+	assert.NoError(t, checkWebHookSignature(repoTask, http.Header{GithubSignatureHeader: {"sha256=" + signature}}, body))
+	assert.NoError(t, checkWebHookSignature(repoTask, http.Header{GitlabTokenHeader: {"my-secret"}}, body))
+	assert.Error(t, checkWebHookSignature(repoTask, http.Header{GitlabTokenHeader: {"other-secret"}}, body))
+	assert.Error(t, checkWebHookSignature(repoTask, http.Header{GithubSignature256Header: {"sha256=" + signature}}, []byte("{}")))
+	assert.Error(t, checkWebHookSignature(repoTask, http.Header{GithubSignatureHeader: {"sha1=" + signature}}, body))
+	assert.Error(t, checkWebHookSignature(repoTask, http.Header{}, body))
+
+	// Webhooks without secret are not checked
+	repoTask.Config = sdk.WorkflowNodeHookConfig{}
+	assert.NoError(t, checkWebHookSignature(repoTask, http.Header{}, body))
+
+	webHookTask := &sdk.Task{
+		Type: TypeWebHook,
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.WebHookModelConfigSecret:    {Value: "my-secret"},
+			sdk.WebHookModelConfigSignature: {Value: "X-My-Signature"},
+		},
+	}
+	assert.NoError(t, checkWebHookSignature(webHookTask, http.Header{"X-My-Signature": {signature}}, body))
+	assert.Error(t, checkWebHookSignature(webHookTask, http.Header{GithubSignature256Header: {signature}}, body))
+}
@@ -28,6 +28,8 @@ const (
 	TypeRepoPoller         = "RepoPoller"
 	TypeKafka              = "Kafka"
 	TypeRabbitMQ           = "RabbitMQ"
+	TypeNATS               = "NATS"
+	TypeMQTT               = "MQTT"
 
 	GithubHeader    = "X-Github-Event"
 	GitlabHeader    = "X-Gitlab-Event"
@@ -125,6 +127,18 @@ func (s *Service) hookToTask(h *sdk.WorkflowNodeHook) (*sdk.Task, error) {
 			Type:   TypeRabbitMQ,
 			Config: h.Config,
 		}, nil
+	case sdk.NATSHookModelName:
+		return &sdk.Task{
+			UUID:   h.UUID,
+			Type:   TypeNATS,
+			Config: h.Config,
+		}, nil
+	case sdk.MQTTHookModelName:
+		return &sdk.Task{
+			UUID:   h.UUID,
+			Type:   TypeMQTT,
+			Config: h.Config,
+		}, nil
 	case sdk.WebHookModelName:
 		h.Config["webHookURL"] = sdk.WorkflowNodeHookConfigValue{
 			Value:        fmt.Sprintf("%s/webhook/%s", s.Cfg.URLPublic, h.UUID),
@@ -210,10 +224,8 @@ func (s *Service) startTask(ctx context.Context, t *sdk.Task) error {
 		return nil
 	case TypeScheduler, TypeRepoPoller:
 		return s.prepareNextScheduledTaskExecution(t)
-	case TypeKafka:
-		return s.startKafkaHook(t)
-	case TypeRabbitMQ:
-		return s.startRabbitMQHook(t)
+	case TypeKafka, TypeRabbitMQ, TypeNATS, TypeMQTT:
+		return s.startBrokerHook(t)
 	default:
 		return fmt.Errorf("Unsupported task type %s", t.Type)
 	}
@@ -295,7 +307,11 @@ func (s *Service) stopTask(t *sdk.Task) error {
 	s.Dao.SaveTask(t)
 
 	switch t.Type {
-	case TypeWebHook, TypeScheduler, TypeRepoManagerWebHook, TypeRepoPoller, TypeKafka:
+	case TypeWebHook, TypeScheduler, TypeRepoManagerWebHook, TypeRepoPoller:
+		log.Debug("Hooks> Tasks %s has been stopped", t.UUID)
+		return nil
+	case TypeKafka, TypeRabbitMQ, TypeNATS, TypeMQTT:
+		s.stopBrokerHook(t)
 		log.Debug("Hooks> Tasks %s has been stopped", t.UUID)
 		return nil
 	default:
@@ -321,9 +337,13 @@ func (s *Service) doTask(ctx context.Context, t *sdk.Task, e *sdk.TaskExecution)
 		//Populate next execution
 		hs, err = s.doPollerTaskExecution(t, e)
 	case e.Kafka != nil:
-		h, err = s.doKafkaTaskExecution(e)
+		h, err = s.doBrokerTaskExecution(e, e.Kafka.Message, nil)
 	case e.RabbitMQ != nil:
-		h, err = s.doRabbitMQTaskExecution(e)
+		h, err = s.doBrokerTaskExecution(e, e.RabbitMQ.Message, nil)
+	case e.NATS != nil:
+		h, err = s.doBrokerTaskExecution(e, e.NATS.Message, map[string]string{"nats.subject": e.NATS.Subject})
+	case e.MQTT != nil:
+		h, err = s.doBrokerTaskExecution(e, e.MQTT.Message, map[string]string{"mqtt.topic": e.MQTT.Topic})
 	default:
 		err = fmt.Errorf("Unsupported task type %s", e.Type)
 	}
@@ -410,27 +430,30 @@ func (s *Service) doPollerTaskExecution(task *sdk.Task, taskExec *sdk.TaskExecut
 	}
 
 	var hookEvents []sdk.WorkflowNodeRunHookEvent
-	if len(events.PushEvents) > 0 || len(events.PullRequestEvents) > 0 {
-		i := 0
-		hookEvents = make([]sdk.WorkflowNodeRunHookEvent, len(events.PushEvents)+len(events.PullRequestEvents))
-		for _, pushEvent := range events.PushEvents {
-			payload := fillPayload(pushEvent)
-			hookEvents[i] = sdk.WorkflowNodeRunHookEvent{
-				WorkflowNodeHookUUID: task.UUID,
-				Payload:              sdk.ParametersMapMerge(payloadValues, payload),
-			}
-			i++
+	var skipReasons []string
+	for _, pushEvent := range events.PushEvents {
+		payload := fillPayload(pushEvent)
+		// The poller only gets the last commit of each branch
+		changedFiles := s.hookChangedFiles(taskExec.UUID, "", pushEvent.Commit.Hash)
+		if reason := checkPushEventFilters(task.Config, payload["git.branch"], payload["git.tag"], changedFiles); reason != "" {
+			log.Debug("Hooks> doPollerTaskExecution> push event skipped on %s: %s", taskExec.UUID, reason)
+			skipReasons = append(skipReasons, reason)
+			continue
 		}
+		hookEvents = append(hookEvents, sdk.WorkflowNodeRunHookEvent{
+			WorkflowNodeHookUUID: task.UUID,
+			Payload:              sdk.ParametersMapMerge(payloadValues, payload),
+		})
+	}
 
-		for _, pullRequestEvent := range events.PullRequestEvents {
-			payload := fillPayload(pullRequestEvent.Head)
-			hookEvents[i] = sdk.WorkflowNodeRunHookEvent{
-				WorkflowNodeHookUUID: task.UUID,
-				Payload:              sdk.ParametersMapMerge(payloadValues, payload),
-			}
-			i++
-		}
+	for _, pullRequestEvent := range events.PullRequestEvents {
+		payload := fillPayload(pullRequestEvent.Head)
+		hookEvents = append(hookEvents, sdk.WorkflowNodeRunHookEvent{
+			WorkflowNodeHookUUID: task.UUID,
+			Payload:              sdk.ParametersMapMerge(payloadValues, payload),
+		})
 	}
+	taskExec.SkipReason = strings.Join(skipReasons, ", ")
 
 	nextExec := fmt.Sprint(time.Now().Add(interval).Unix())
 	taskExec.Config["next_execution"] = sdk.WorkflowNodeHookConfigValue{
@@ -492,7 +515,7 @@ func (s *Service) doWebHookExecution(t *sdk.TaskExecution) (*sdk.WorkflowNodeRun
 	log.Debug("Hooks> Processing webhook %s %s", t.UUID, t.Type)
 
 	if t.Type == TypeRepoManagerWebHook {
-		return executeRepositoryWebHook(t)
+		return s.executeRepositoryWebHook(t)
 	}
 	return executeWebHook(t)
 }
@@ -508,13 +531,39 @@ func getRepositoryHeader(whe *sdk.WebHookExecution) string {
 	return ""
 }
 
-func executeRepositoryWebHook(t *sdk.TaskExecution) (*sdk.WorkflowNodeRunHookEvent, error) {
-	// Prepare a struct to send to CDS API
-	h := sdk.WorkflowNodeRunHookEvent{
-		WorkflowNodeHookUUID: t.UUID,
+func getPullRequestHeader(whe *sdk.WebHookExecution) string {
+	if v, ok := whe.RequestHeader[GithubHeader]; ok && v[0] == "pull_request" {
+		return GithubHeader
+	} else if v, ok := whe.RequestHeader[GitlabHeader]; ok && v[0] == "Merge Request Hook" {
+		return GitlabHeader
+	} else if v, ok := whe.RequestHeader[BitbucketHeader]; ok && strings.HasPrefix(v[0], "pr:") {
+		return BitbucketHeader
+	}
+	return ""
+}
+
+// repositoryWebHookEventEnabled checks if the kind of event is in the events configuration of the hook.
+// Hooks without this configuration are triggered by push events only.
+func repositoryWebHookEventEnabled(t *sdk.TaskExecution, kind string) bool {
+	events := sdk.RepositoryWebHookEventPush
+	if c, ok := t.Config[sdk.RepositoryWebHookModelEvents]; ok {
+		events = c.Value
+	}
+	for _, e := range strings.Split(events, ",") {
+		if strings.TrimSpace(e) == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) executeRepositoryWebHook(t *sdk.TaskExecution) (*sdk.WorkflowNodeRunHookEvent, error) {
+	if header := getPullRequestHeader(t.WebHook); header != "" {
+		return executeRepositoryPullRequestWebHook(t, header)
 	}
 
 	payload := make(map[string]interface{})
+	var changedFiles func() ([]string, error)
 	switch getRepositoryHeader(t.WebHook) {
 	case GithubHeader:
 		var pushEvent GithubPushEvent
@@ -542,6 +591,7 @@ func executeRepositoryWebHook(t *sdk.TaskExecution) (*sdk.WorkflowNodeRunHookEve
 		if len(pushEvent.Commits) > 0 {
 			payload["git.message"] = pushEvent.Commits[0].Message
 		}
+		changedFiles = func() ([]string, error) { return pushEvent.ChangedFiles(), nil }
 	case GitlabHeader:
 		var pushEvent GitlabPushEvent
 		if err := json.Unmarshal(t.WebHook.RequestBody, &pushEvent); err != nil {
@@ -569,6 +619,12 @@ func executeRepositoryWebHook(t *sdk.TaskExecution) (*sdk.WorkflowNodeRunHookEve
 		if len(pushEvent.Commits) > 0 {
 			payload["git.message"] = pushEvent.Commits[0].Message
 		}
+		// Gitlab sends at most 20 commits in a push event
+		if pushEvent.TotalCommitsCount > len(pushEvent.Commits) {
+			changedFiles = s.hookChangedFiles(t.UUID, pushEvent.Before, pushEvent.After)
+		} else {
+			changedFiles = func() ([]string, error) { return pushEvent.ChangedFiles(), nil }
+		}
 	case BitbucketHeader:
 		var pushEvent BitbucketPushEvent
 		if err := json.Unmarshal(t.WebHook.RequestBody, &pushEvent); err != nil {
@@ -593,11 +649,151 @@ func executeRepositoryWebHook(t *sdk.TaskExecution) (*sdk.WorkflowNodeRunHookEve
 		payload["cds.triggered_by.username"] = pushEvent.Actor.Name
 		payload["cds.triggered_by.fullname"] = pushEvent.Actor.DisplayName
 		payload["cds.triggered_by.email"] = pushEvent.Actor.EmailAddress
+		changedFiles = s.hookChangedFiles(t.UUID, pushEvent.Changes[0].FromHash, pushEvent.Changes[0].ToHash)
 	default:
 		log.Warning("executeRepositoryWebHook> Repository manager not found. Cannot read %s", string(t.WebHook.RequestBody))
 		return nil, fmt.Errorf("Repository manager not found. Cannot read request body")
 	}
 
+	if !repositoryWebHookEventEnabled(t, sdk.RepositoryWebHookEventPush) {
+		log.Debug("executeRepositoryWebHook> push events are not enabled on hook %s", t.UUID)
+		t.SkipReason = "push events are not enabled"
+		return nil, nil
+	}
+	branch, _ := payload["git.branch"].(string)
+	tag, _ := payload["git.tag"].(string)
+	if reason := checkPushEventFilters(t.Config, branch, tag, changedFiles); reason != "" {
+		log.Debug("executeRepositoryWebHook> hook %s skipped: %s", t.UUID, reason)
+		t.SkipReason = reason
+		return nil, nil
+	}
+	return repositoryWebHookEvent(t, payload)
+}
+
+// hookChangedFiles returns a function loading the files changed between two commits from the repository manager.
+// Without previous commit, as on the creation of a branch, the files of the last commit are returned.
+func (s *Service) hookChangedFiles(uuid, before, after string) func() ([]string, error) {
+	return func() ([]string, error) {
+		if strings.Trim(before, "0") == "" {
+			before = ""
+		}
+		return s.Client.HookChangedFiles(uuid, before, after)
+	}
+}
+
+func executeRepositoryPullRequestWebHook(t *sdk.TaskExecution, header string) (*sdk.WorkflowNodeRunHookEvent, error) {
+	var kind string
+	payload := make(map[string]interface{})
+	switch header {
+	case GithubHeader:
+		var prEvent GithubPullRequestEvent
+		if err := json.Unmarshal(t.WebHook.RequestBody, &prEvent); err != nil {
+			return nil, sdk.WrapError(err, "executeRepositoryPullRequestWebHook> unable to read github request: %s", string(t.WebHook.RequestBody))
+		}
+		switch prEvent.Action {
+		case "opened":
+			kind = sdk.RepositoryWebHookEventPullRequestOpened
+		case "synchronize":
+			kind = sdk.RepositoryWebHookEventPullRequestSynchronize
+		case "reopened":
+			kind = sdk.RepositoryWebHookEventPullRequestReopened
+		case "closed":
+			kind = sdk.RepositoryWebHookEventPullRequestClosed
+		}
+		pr := prEvent.PullRequest
+		payload["git.pr.id"] = prEvent.Number
+		payload["git.pr.title"] = pr.Title
+		payload["git.pr.author"] = pr.User.Login
+		payload["git.pr.source.branch"] = pr.Head.Ref
+		payload["git.pr.source.repository"] = pr.Head.Repo.FullName
+		payload["git.pr.target.branch"] = pr.Base.Ref
+		payload["git.pr.merged"] = pr.Merged
+		payload["git.branch"] = pr.Head.Ref
+		payload["git.hash"] = pr.Head.Sha
+		payload["git.repository"] = prEvent.Repository.FullName
+		payload["git.author"] = pr.User.Login
+		payload["cds.triggered_by.username"] = prEvent.Sender.Login
+	case GitlabHeader:
+		var mrEvent GitlabMergeRequestEvent
+		if err := json.Unmarshal(t.WebHook.RequestBody, &mrEvent); err != nil {
+			return nil, sdk.WrapError(err, "executeRepositoryPullRequestWebHook> unable to read gitlab request: %s", string(t.WebHook.RequestBody))
+		}
+		mr := mrEvent.ObjectAttributes
+		switch mr.Action {
+		case "open":
+			kind = sdk.RepositoryWebHookEventPullRequestOpened
+		case "update":
+			// Updates without oldrev are changes of the title, the labels...
+			if mr.OldRev != "" {
+				kind = sdk.RepositoryWebHookEventPullRequestSynchronize
+			}
+		case "reopen":
+			kind = sdk.RepositoryWebHookEventPullRequestReopened
+		case "close", "merge":
+			kind = sdk.RepositoryWebHookEventPullRequestClosed
+		}
+		payload["git.pr.id"] = mr.IID
+		payload["git.pr.title"] = mr.Title
+		payload["git.pr.author"] = mrEvent.User.Username
+		payload["git.pr.source.branch"] = mr.SourceBranch
+		payload["git.pr.source.repository"] = mr.Source.PathWithNamespace
+		payload["git.pr.target.branch"] = mr.TargetBranch
+		payload["git.pr.merged"] = mr.Action == "merge"
+		payload["git.branch"] = mr.SourceBranch
+		payload["git.hash"] = mr.LastCommit.ID
+		payload["git.message"] = mr.LastCommit.Message
+		payload["git.repository"] = mrEvent.Project.PathWithNamespace
+		payload["git.author"] = mrEvent.User.Username
+		payload["git.author.email"] = mrEvent.User.Email
+		payload["cds.triggered_by.username"] = mrEvent.User.Username
+		payload["cds.triggered_by.fullname"] = mrEvent.User.Name
+		payload["cds.triggered_by.email"] = mrEvent.User.Email
+	case BitbucketHeader:
+		var prEvent BitbucketPullRequestEvent
+		if err := json.Unmarshal(t.WebHook.RequestBody, &prEvent); err != nil {
+			return nil, sdk.WrapError(err, "executeRepositoryPullRequestWebHook> unable to read bitbucket request: %s", string(t.WebHook.RequestBody))
+		}
+		switch prEvent.EventKey {
+		case "pr:opened":
+			kind = sdk.RepositoryWebHookEventPullRequestOpened
+		case "pr:from_ref_updated":
+			kind = sdk.RepositoryWebHookEventPullRequestSynchronize
+		case "pr:merged", "pr:declined", "pr:deleted":
+			kind = sdk.RepositoryWebHookEventPullRequestClosed
+		}
+		pr := prEvent.PullRequest
+		payload["git.pr.id"] = pr.ID
+		payload["git.pr.title"] = pr.Title
+		payload["git.pr.author"] = pr.Author.User.Name
+		payload["git.pr.source.branch"] = pr.FromRef.DisplayID
+		payload["git.pr.source.repository"] = pr.FromRef.fullname()
+		payload["git.pr.target.branch"] = pr.ToRef.DisplayID
+		payload["git.pr.merged"] = prEvent.EventKey == "pr:merged"
+		payload["git.branch"] = pr.FromRef.DisplayID
+		payload["git.hash"] = pr.FromRef.LatestCommit
+		payload["git.repository"] = pr.ToRef.fullname()
+		payload["git.author"] = pr.Author.User.Name
+		payload["git.author.email"] = pr.Author.User.EmailAddress
+		payload["cds.triggered_by.username"] = prEvent.Actor.Name
+		payload["cds.triggered_by.fullname"] = prEvent.Actor.DisplayName
+		payload["cds.triggered_by.email"] = prEvent.Actor.EmailAddress
+	}
+
+	if kind == "" || !repositoryWebHookEventEnabled(t, kind) {
+		log.Debug("executeRepositoryPullRequestWebHook> event %s is not enabled on hook %s", kind, t.UUID)
+		t.SkipReason = fmt.Sprintf("pull request event %s is not enabled", kind)
+		return nil, nil
+	}
+	payload["git.pr.event"] = kind
+	return repositoryWebHookEvent(t, payload)
+}
+
+func repositoryWebHookEvent(t *sdk.TaskExecution, payload map[string]interface{}) (*sdk.WorkflowNodeRunHookEvent, error) {
+	// Prepare a struct to send to CDS API
+	h := sdk.WorkflowNodeRunHookEvent{
+		WorkflowNodeHookUUID: t.UUID,
+	}
+
 	d := dump.NewDefaultEncoder(&bytes.Buffer{})
 	d.ExtraFields.Type = false
 	d.ExtraFields.Len = false
@@ -606,7 +802,7 @@ func executeRepositoryWebHook(t *sdk.TaskExecution) (*sdk.WorkflowNodeRunHookEve
 	d.Formatters = []dump.KeyFormatterFunc{dump.WithDefaultLowerCaseFormatter()}
 	payloadValues, errDump := d.ToStringMap(payload)
 	if errDump != nil {
-		return nil, sdk.WrapError(errDump, "executeRepositoryWebHook> Cannot dump payload %+v ", payload)
+		return nil, sdk.WrapError(errDump, "repositoryWebHookEvent> Cannot dump payload %+v ", payload)
 	}
 	h.Payload = payloadValues
 	return &h, nil
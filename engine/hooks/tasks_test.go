@@ -125,6 +125,83 @@ func Test_doWebHookExecutionBitbucket(t *testing.T) {
 	assert.Equal(t, "9f4fac7ec5642099982a86f584f2c4a362adb670", h.Payload["git.hash"])
 }
 
+func Test_doWebHookExecutionGithubPullRequest(t *testing.T) {
+	log.SetLogger(t)
+	s := Service{}
+	task := &sdk.TaskExecution{
+		UUID: sdk.RandomString(10),
+		Type: TypeRepoManagerWebHook,
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.RepositoryWebHookModelEvents: {Value: "push, pull_request:opened,pull_request:synchronize"},
+		},
+		WebHook: &sdk.WebHookExecution{
+			RequestBody: []byte(githubPullRequestEvent),
+			RequestHeader: map[string][]string{
+				GithubHeader: {"pull_request"},
+			},
+		},
+	}
+	h, err := s.doWebHookExecution(task)
+	test.NoError(t, err)
+	test.NotNil(t, h)
+
+	assert.Equal(t, "1", h.Payload["git.pr.id"])
+	assert.Equal(t, "Update the README with new information", h.Payload["git.pr.title"])
+	assert.Equal(t, "pull_request:opened", h.Payload["git.pr.event"])
+	assert.Equal(t, "changes", h.Payload["git.pr.source.branch"])
+	assert.Equal(t, "contributor/public-repo", h.Payload["git.pr.source.repository"])
+	assert.Equal(t, "master", h.Payload["git.pr.target.branch"])
+	assert.Equal(t, "contributor", h.Payload["git.pr.author"])
+	assert.Equal(t, "changes", h.Payload["git.branch"])
+	assert.Equal(t, "0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c", h.Payload["git.hash"])
+	assert.Equal(t, "baxterthehacker/public-repo", h.Payload["git.repository"])
+
+	// Without events configuration, only push events trigger the hook
+	task.Config = nil
+	h, err = s.doWebHookExecution(task)
+	test.NoError(t, err)
+	assert.Nil(t, h)
+}
+
+func Test_doWebHookExecutionGitlabMergeRequest(t *testing.T) {
+	log.SetLogger(t)
+	s := Service{}
+	task := &sdk.TaskExecution{
+		UUID: sdk.RandomString(10),
+		Type: TypeRepoManagerWebHook,
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.RepositoryWebHookModelEvents: {Value: "pull_request:synchronize"},
+		},
+		WebHook: &sdk.WebHookExecution{
+			RequestBody: []byte(gitlabMergeRequestEvent),
+			RequestHeader: map[string][]string{
+				GitlabHeader: {"Merge Request Hook"},
+			},
+		},
+	}
+	h, err := s.doWebHookExecution(task)
+	test.NoError(t, err)
+	test.NotNil(t, h)
+
+	assert.Equal(t, "1", h.Payload["git.pr.id"])
+	assert.Equal(t, "pull_request:synchronize", h.Payload["git.pr.event"])
+	assert.Equal(t, "ms-viewport", h.Payload["git.pr.source.branch"])
+	assert.Equal(t, "awesome_space/awesome_project", h.Payload["git.pr.source.repository"])
+	assert.Equal(t, "master", h.Payload["git.pr.target.branch"])
+	assert.Equal(t, "da1560886d4f094c3e6c9ef40349f7d38b5d27d7", h.Payload["git.hash"])
+
+	// Push events are not enabled
+	task.WebHook = &sdk.WebHookExecution{
+		RequestBody: []byte(gitlabPushEvent),
+		RequestHeader: map[string][]string{
+			GitlabHeader: {"Push Hook"},
+		},
+	}
+	h, err = s.doWebHookExecution(task)
+	test.NoError(t, err)
+	assert.Nil(t, h)
+}
+
 var bitbucketPushEvent = `
 	{
     "eventKey": "repo:refs_changed",
@@ -581,3 +658,75 @@ var githubTagEvent = `
   }
 }
 `
+
+var githubPullRequestEvent = `
+{
+  "action": "opened",
+  "number": 1,
+  "pull_request": {
+    "number": 1,
+    "state": "open",
+    "title": "Update the README with new information",
+    "user": {
+      "login": "contributor"
+    },
+    "merged": false,
+    "head": {
+      "label": "contributor:changes",
+      "ref": "changes",
+      "sha": "0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c",
+      "repo": {
+        "full_name": "contributor/public-repo"
+      }
+    },
+    "base": {
+      "label": "baxterthehacker:master",
+      "ref": "master",
+      "sha": "9049f1265b7d61be4a8904a9a27120d2064dab3b",
+      "repo": {
+        "full_name": "baxterthehacker/public-repo"
+      }
+    }
+  },
+  "repository": {
+    "full_name": "baxterthehacker/public-repo"
+  },
+  "sender": {
+    "login": "contributor"
+  }
+}
+`
+
+var gitlabMergeRequestEvent = `
+{
+  "object_kind": "merge_request",
+  "user": {
+    "name": "Administrator",
+    "username": "root",
+    "email": "admin@example.com"
+  },
+  "project": {
+    "path_with_namespace": "gitlabhq/gitlab-test"
+  },
+  "object_attributes": {
+    "iid": 1,
+    "title": "MS-Viewport",
+    "state": "opened",
+    "action": "update",
+    "oldrev": "b83d6e391c22777fca1ed3012fce84f633d7fed0",
+    "source_branch": "ms-viewport",
+    "target_branch": "master",
+    "source": {
+      "path_with_namespace": "awesome_space/awesome_project"
+    },
+    "last_commit": {
+      "id": "da1560886d4f094c3e6c9ef40349f7d38b5d27d7",
+      "message": "fixed readme",
+      "author": {
+        "name": "GitLab dev user",
+        "email": "gitlabdev@dv6700.(none)"
+      }
+    }
+  }
+}
+`
@@ -1,5 +1,7 @@
 package hooks
 
+import "fmt"
+
 // BitbucketPushEvent represents payload send by github on a push event
 type BitbucketPushEvent struct {
 	EventKey string `json:"eventKey"`
@@ -42,3 +44,41 @@ type BitbucketPushEvent struct {
 		Type     string `json:"type"`
 	} `json:"changes"`
 }
+
+// BitbucketPullRequestEvent represents payload send by bitbucket on a pull request event
+type BitbucketPullRequestEvent struct {
+	EventKey    string        `json:"eventKey"`
+	Actor       BitbucketUser `json:"actor"`
+	PullRequest struct {
+		ID     int    `json:"id"`
+		Title  string `json:"title"`
+		Author struct {
+			User BitbucketUser `json:"user"`
+		} `json:"author"`
+		FromRef BitbucketPullRequestRef `json:"fromRef"`
+		ToRef   BitbucketPullRequestRef `json:"toRef"`
+	} `json:"pullRequest"`
+}
+
+// BitbucketUser represents a bitbucket user in events
+type BitbucketUser struct {
+	Name         string `json:"name"`
+	EmailAddress string `json:"emailAddress"`
+	DisplayName  string `json:"displayName"`
+}
+
+// BitbucketPullRequestRef represents the source or the target of a bitbucket pull request
+type BitbucketPullRequestRef struct {
+	DisplayID    string `json:"displayId"`
+	LatestCommit string `json:"latestCommit"`
+	Repository   struct {
+		Slug    string `json:"slug"`
+		Project struct {
+			Key string `json:"key"`
+		} `json:"project"`
+	} `json:"repository"`
+}
+
+func (r BitbucketPullRequestRef) fullname() string {
+	return fmt.Sprintf("%s/%s", r.Repository.Project.Key, r.Repository.Slug)
+}
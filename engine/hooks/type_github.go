@@ -32,9 +32,9 @@ type GithubPushEvent struct {
 			Email    string `json:"email"`
 			Username string `json:"username"`
 		} `json:"committer"`
-		Added    []interface{} `json:"added"`
-		Removed  []interface{} `json:"removed"`
-		Modified []string      `json:"modified"`
+		Added    []string `json:"added"`
+		Removed  []string `json:"removed"`
+		Modified []string `json:"modified"`
 	} `json:"commits"`
 	HeadCommit struct {
 		ID        string `json:"id"`
@@ -53,9 +53,9 @@ type GithubPushEvent struct {
 			Email    string `json:"email"`
 			Username string `json:"username"`
 		} `json:"committer"`
-		Added    []interface{} `json:"added"`
-		Removed  []interface{} `json:"removed"`
-		Modified []string      `json:"modified"`
+		Added    []string `json:"added"`
+		Removed  []string `json:"removed"`
+		Modified []string `json:"modified"`
 	} `json:"head_commit"`
 	Repository struct {
 		ID       int    `json:"id"`
@@ -174,3 +174,44 @@ func (g *GithubPushEvent) GetCommits() []sdk.VCSCommit {
 	}
 	return commits
 }
+
+// ChangedFiles returns the files added, modified or removed by the commits of the push event
+func (g *GithubPushEvent) ChangedFiles() []string {
+	var files []string
+	for _, c := range g.Commits {
+		files = append(files, c.Added...)
+		files = append(files, c.Modified...)
+		files = append(files, c.Removed...)
+	}
+	return files
+}
+
+// GithubPullRequestEvent represents payload send by github on a pull request event
+type GithubPullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Title  string `json:"title"`
+		Merged bool   `json:"merged"`
+		User   struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		Head GithubPullRequestRef `json:"head"`
+		Base GithubPullRequestRef `json:"base"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Sender struct {
+		Login string `json:"login"`
+	} `json:"sender"`
+}
+
+// GithubPullRequestRef represents the head or the base of a github pull request
+type GithubPullRequestRef struct {
+	Ref  string `json:"ref"`
+	Sha  string `json:"sha"`
+	Repo struct {
+		FullName string `json:"full_name"`
+	} `json:"repo"`
+}
@@ -1,6 +1,8 @@
 package hooks
 
 import (
+	"sync"
+
 	"github.com/ovh/cds/engine/api"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/service"
@@ -20,6 +22,9 @@ type Service struct {
 	Router *api.Router
 	Cache  cache.Store
 	Dao    dao
+	// consumers of the broker hooks by task UUID
+	consumers      map[string]brokerConsumer
+	consumersMutex sync.Mutex
 }
 
 // Configuration is the hooks configuration structure
@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS workflow_artifact_blob (
+    sha512sum VARCHAR(128) PRIMARY KEY,
+    size BIGINT,
+    container TEXT,
+    name TEXT,
+    object_path TEXT,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    last_used TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+ALTER TABLE workflow_node_run_artifacts ADD COLUMN blob_sha512sum VARCHAR(128);
+SELECT create_index('workflow_node_run_artifacts', 'IDX_WORKFLOW_NODE_RUN_ARTIFACTS_BLOB', 'blob_sha512sum');
+
+-- +migrate Down
+DROP INDEX IDX_WORKFLOW_NODE_RUN_ARTIFACTS_BLOB;
+ALTER TABLE workflow_node_run_artifacts DROP COLUMN blob_sha512sum;
+DROP TABLE workflow_artifact_blob;
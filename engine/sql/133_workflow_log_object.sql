@@ -0,0 +1,12 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS workflow_node_run_job_logs_object (
+    workflow_node_run_job_logs_id BIGINT PRIMARY KEY,
+    container TEXT,
+    name TEXT,
+    object_path TEXT,
+    size BIGINT,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+-- +migrate Down
+DROP TABLE workflow_node_run_job_logs_object;
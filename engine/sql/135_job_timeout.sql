@@ -0,0 +1,11 @@
+-- +migrate Up
+ALTER TABLE pipeline_action ADD COLUMN timeout BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE action_edge ADD COLUMN timeout BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE project ADD COLUMN job_timeout BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE workflow_node_run_job ADD COLUMN timeout BIGINT NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE pipeline_action DROP COLUMN timeout;
+ALTER TABLE action_edge DROP COLUMN timeout;
+ALTER TABLE project DROP COLUMN job_timeout;
+ALTER TABLE workflow_node_run_job DROP COLUMN timeout;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE pipeline_action ADD COLUMN retry_policy JSONB;
+ALTER TABLE action_edge ADD COLUMN retry_policy JSONB;
+
+-- +migrate Down
+ALTER TABLE pipeline_action DROP COLUMN retry_policy;
+ALTER TABLE action_edge DROP COLUMN retry_policy;
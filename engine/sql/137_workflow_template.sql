@@ -0,0 +1,43 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS workflow_template (
+  id BIGSERIAL PRIMARY KEY,
+  group_id BIGINT NOT NULL,
+  name VARCHAR(256) NOT NULL,
+  description TEXT,
+  version BIGINT NOT NULL DEFAULT 1,
+  last_modified TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+  content JSONB
+);
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_TEMPLATE_GROUP', 'workflow_template', 'group', 'group_id', 'id');
+SELECT create_unique_index('workflow_template', 'IDX_WORKFLOW_TEMPLATE_GROUP_ID_NAME', 'group_id,name');
+
+CREATE TABLE IF NOT EXISTS workflow_template_version (
+  workflow_template_id BIGINT,
+  version BIGINT,
+  author VARCHAR(256),
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+  template JSONB,
+  PRIMARY KEY(workflow_template_id, version)
+);
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_TEMPLATE_VERSION_TEMPLATE', 'workflow_template_version', 'workflow_template', 'workflow_template_id', 'id');
+
+CREATE TABLE IF NOT EXISTS workflow_template_instance (
+  id BIGSERIAL PRIMARY KEY,
+  workflow_template_id BIGINT NOT NULL,
+  project_id BIGINT NOT NULL,
+  workflow_id BIGINT,
+  workflow_template_version BIGINT NOT NULL,
+  workflow_export TEXT,
+  request JSONB,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+  last_modified TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_TEMPLATE_INSTANCE_TEMPLATE', 'workflow_template_instance', 'workflow_template', 'workflow_template_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_TEMPLATE_INSTANCE_PROJECT', 'workflow_template_instance', 'project', 'project_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_TEMPLATE_INSTANCE_WORKFLOW', 'workflow_template_instance', 'workflow', 'workflow_id', 'id');
+SELECT create_unique_index('workflow_template_instance', 'IDX_WORKFLOW_TEMPLATE_INSTANCE_WORKFLOW_ID', 'workflow_id');
+
+-- +migrate Down
+DROP TABLE workflow_template_instance;
+DROP TABLE workflow_template_version;
+DROP TABLE workflow_template;
@@ -0,0 +1,21 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS workflow_notification_delivery (
+  id BIGSERIAL PRIMARY KEY,
+  workflow_id BIGINT NOT NULL,
+  workflow_notification_id BIGINT NOT NULL,
+  workflow_run_number BIGINT NOT NULL DEFAULT 0,
+  workflow_node_run_id BIGINT NOT NULL DEFAULT 0,
+  status VARCHAR(50) NOT NULL DEFAULT '',
+  method VARCHAR(10) NOT NULL DEFAULT '',
+  url TEXT NOT NULL DEFAULT '',
+  attempt INT NOT NULL DEFAULT 1,
+  status_code INT NOT NULL DEFAULT 0,
+  error TEXT NOT NULL DEFAULT '',
+  duration BIGINT NOT NULL DEFAULT 0,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NOTIFICATION_DELIVERY_WORKFLOW', 'workflow_notification_delivery', 'workflow', 'workflow_id', 'id');
+SELECT create_index('workflow_notification_delivery', 'IDX_WORKFLOW_NOTIFICATION_DELIVERY_CREATED', 'workflow_id,created');
+
+-- +migrate Down
+DROP TABLE workflow_notification_delivery;
@@ -0,0 +1,32 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS freeze_window (
+  id BIGSERIAL PRIMARY KEY,
+  project_id BIGINT NOT NULL,
+  environment_id BIGINT,
+  reason TEXT NOT NULL DEFAULT '',
+  start_date TIMESTAMP WITH TIME ZONE,
+  end_date TIMESTAMP WITH TIME ZONE,
+  cron VARCHAR(256) NOT NULL DEFAULT '',
+  duration BIGINT NOT NULL DEFAULT 0,
+  timezone VARCHAR(256) NOT NULL DEFAULT '',
+  override_groups JSONB,
+  author VARCHAR(256),
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_FREEZE_WINDOW_PROJECT', 'freeze_window', 'project', 'project_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_FREEZE_WINDOW_ENVIRONMENT', 'freeze_window', 'environment', 'environment_id', 'id');
+
+CREATE TABLE IF NOT EXISTS freeze_window_override (
+  id BIGSERIAL PRIMARY KEY,
+  freeze_window_id BIGINT NOT NULL,
+  workflow_name VARCHAR(256) NOT NULL DEFAULT '',
+  number BIGINT NOT NULL DEFAULT 0,
+  workflow_node_name VARCHAR(256) NOT NULL DEFAULT '',
+  username VARCHAR(256) NOT NULL DEFAULT '',
+  date TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_FREEZE_WINDOW_OVERRIDE_FREEZE_WINDOW', 'freeze_window_override', 'freeze_window', 'freeze_window_id', 'id');
+
+-- +migrate Down
+DROP TABLE freeze_window_override;
+DROP TABLE freeze_window;
@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE workflow_node_context ADD COLUMN concurrency JSONB;
+ALTER TABLE workflow_node_run ADD COLUMN concurrency JSONB;
+SELECT create_index('workflow_node_run', 'IDX_WORKFLOW_NODE_RUN_CONCURRENCY_KEY', '(concurrency->>''key'')');
+
+-- +migrate Down
+DROP INDEX IDX_WORKFLOW_NODE_RUN_CONCURRENCY_KEY;
+ALTER TABLE workflow_node_context DROP COLUMN concurrency;
+ALTER TABLE workflow_node_run DROP COLUMN concurrency;
@@ -0,0 +1,21 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "user_access_token" (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL,
+  name VARCHAR(256) NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  scope VARCHAR(16) NOT NULL,
+  project_key VARCHAR(256) NOT NULL DEFAULT '',
+  hash VARCHAR(128) NOT NULL,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+  expire TIMESTAMP WITH TIME ZONE,
+  last_used TIMESTAMP WITH TIME ZONE,
+  revoked TIMESTAMP WITH TIME ZONE,
+  revoked_by VARCHAR(256) NOT NULL DEFAULT ''
+);
+
+SELECT create_foreign_key_idx_cascade('FK_USER_ACCESS_TOKEN_USER', 'user_access_token', 'user', 'user_id', 'id');
+SELECT create_unique_index('user_access_token', 'IDX_USER_ACCESS_TOKEN_HASH', 'hash');
+
+-- +migrate Down
+DROP TABLE user_access_token;
@@ -0,0 +1,24 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "role" (
+  id BIGSERIAL PRIMARY KEY,
+  name VARCHAR(64) NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  permissions TEXT[] NOT NULL DEFAULT '{}'
+);
+SELECT create_unique_index('role', 'IDX_ROLE_NAME', 'name');
+
+CREATE TABLE IF NOT EXISTS "role_binding" (
+  id BIGSERIAL PRIMARY KEY,
+  role_name VARCHAR(64) NOT NULL,
+  group_id BIGINT NOT NULL,
+  project_id BIGINT NOT NULL,
+  object_type VARCHAR(32) NOT NULL,
+  object_name VARCHAR(256) NOT NULL DEFAULT ''
+);
+SELECT create_foreign_key_idx_cascade('FK_ROLE_BINDING_GROUP', 'role_binding', 'group', 'group_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_ROLE_BINDING_PROJECT', 'role_binding', 'project', 'project_id', 'id');
+SELECT create_unique_index('role_binding', 'IDX_ROLE_BINDING_UNIQ', 'group_id,project_id,object_type,object_name,role_name');
+
+-- +migrate Down
+DROP TABLE role_binding;
+DROP TABLE role;
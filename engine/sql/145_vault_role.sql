@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE project ADD COLUMN vault_role TEXT NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE project DROP COLUMN vault_role;
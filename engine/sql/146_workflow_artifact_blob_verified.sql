@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE workflow_artifact_blob ADD COLUMN verified BOOLEAN NOT NULL DEFAULT true;
+
+-- +migrate Down
+ALTER TABLE workflow_artifact_blob DROP COLUMN verified;
@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS freeze_window_audit (
+  id BIGSERIAL PRIMARY KEY,
+  project_id BIGINT NOT NULL,
+  freeze_window_id BIGINT NOT NULL,
+  type VARCHAR(64) NOT NULL DEFAULT '',
+  active BOOLEAN NOT NULL DEFAULT false,
+  freeze_window JSONB,
+  username VARCHAR(256) NOT NULL DEFAULT '',
+  date TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_FREEZE_WINDOW_AUDIT_PROJECT', 'freeze_window_audit', 'project', 'project_id', 'id');
+
+-- +migrate Down
+DROP TABLE freeze_window_audit;
@@ -0,0 +1,6 @@
+-- +migrate Up
+ALTER TABLE "user" ADD COLUMN oidc_subject TEXT;
+SELECT create_unique_index('user', 'IDX_USER_OIDC_SUBJECT', 'oidc_subject');
+
+-- +migrate Down
+ALTER TABLE "user" DROP COLUMN oidc_subject;
@@ -0,0 +1,24 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS secret_reencryption (
+  key_id VARCHAR(256) PRIMARY KEY,
+  owner VARCHAR(64) NOT NULL DEFAULT '',
+  started TIMESTAMP WITH TIME ZONE,
+  ended TIMESTAMP WITH TIME ZONE,
+  heartbeat TIMESTAMP WITH TIME ZONE,
+  reencrypted BIGINT NOT NULL DEFAULT 0,
+  error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS secret_reencryption_column (
+  key_id VARCHAR(256) NOT NULL,
+  table_name VARCHAR(256) NOT NULL,
+  column_name VARCHAR(256) NOT NULL,
+  last_key BIGINT NOT NULL DEFAULT 0,
+  done BOOLEAN NOT NULL DEFAULT false,
+  PRIMARY KEY (key_id, table_name, column_name)
+);
+SELECT create_foreign_key_idx_cascade('FK_SECRET_REENCRYPTION_COLUMN', 'secret_reencryption_column', 'secret_reencryption', 'key_id', 'key_id');
+
+-- +migrate Down
+DROP TABLE secret_reencryption_column;
+DROP TABLE secret_reencryption;
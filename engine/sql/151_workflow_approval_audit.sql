@@ -0,0 +1,20 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS workflow_node_run_approval_audit (
+  id BIGSERIAL PRIMARY KEY,
+  project_id BIGINT NOT NULL,
+  workflow_id BIGINT NOT NULL,
+  workflow_name VARCHAR(256) NOT NULL DEFAULT '',
+  num BIGINT NOT NULL,
+  workflow_node_run_id BIGINT NOT NULL,
+  workflow_node_name VARCHAR(256) NOT NULL DEFAULT '',
+  username VARCHAR(256) NOT NULL DEFAULT '',
+  approved BOOLEAN NOT NULL DEFAULT false,
+  comment TEXT NOT NULL DEFAULT '',
+  status VARCHAR(64) NOT NULL DEFAULT '',
+  date TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_APPROVAL_AUDIT_PROJECT', 'workflow_node_run_approval_audit', 'project', 'project_id', 'id');
+SELECT create_index('workflow_node_run_approval_audit', 'IDX_WORKFLOW_NODE_RUN_APPROVAL_AUDIT_WORKFLOW', 'workflow_id');
+
+-- +migrate Down
+DROP TABLE workflow_node_run_approval_audit;
@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS user_access_token_audit (
+  id BIGSERIAL PRIMARY KEY,
+  access_token_id BIGINT NOT NULL,
+  type VARCHAR(16) NOT NULL DEFAULT '',
+  username VARCHAR(256) NOT NULL DEFAULT '',
+  method VARCHAR(16) NOT NULL DEFAULT '',
+  url TEXT NOT NULL DEFAULT '',
+  remote_addr VARCHAR(256) NOT NULL DEFAULT '',
+  date TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_USER_ACCESS_TOKEN_AUDIT_ACCESS_TOKEN', 'user_access_token_audit', 'user_access_token', 'access_token_id', 'id');
+SELECT create_index('user_access_token_audit', 'IDX_USER_ACCESS_TOKEN_AUDIT_DATE', 'access_token_id,date');
+
+-- +migrate Down
+DROP TABLE user_access_token_audit;
@@ -218,3 +218,42 @@ func (b *bitbucketClient) CommitsBetweenRefs(ctx context.Context, repo, base, he
 	}
 	return commits, nil
 }
+
+func (b *bitbucketClient) ChangedFiles(ctx context.Context, repo, base, head string) ([]string, error) {
+	project, slug, err := getRepo(repo)
+	if err != nil {
+		return nil, sdk.WrapError(err, "vcs> bitbucket> ChangedFiles>")
+	}
+
+	var files []string
+	var filesKey = cache.Key("vcs", "bitbucket", b.consumer.URL, repo, "changes", "since@"+base, "until@"+head)
+	if b.consumer.cache.Get(filesKey, &files) {
+		return files, nil
+	}
+
+	response := ChangesResponse{}
+	path := fmt.Sprintf("/projects/%s/repos/%s/commits/%s/changes", project, slug, head)
+	params := url.Values{}
+	if base != "" {
+		params.Add("since", base)
+	}
+	for {
+		if response.NextPageStart != 0 {
+			params.Set("start", fmt.Sprintf("%d", response.NextPageStart))
+		}
+		if err := b.do(ctx, "GET", "core", path, params, nil, &response, nil); err != nil {
+			return nil, sdk.WrapError(err, "vcs> bitbucket> ChangedFiles> Unable to get changes %s", path)
+		}
+		for _, v := range response.Values {
+			files = append(files, v.Path.ToString)
+			if v.SrcPath != nil && v.SrcPath.ToString != "" {
+				files = append(files, v.SrcPath.ToString)
+			}
+		}
+		if response.IsLastPage {
+			break
+		}
+	}
+	b.consumer.cache.SetWithTTL(filesKey, files, 3*60*60) //3 hours
+	return files, nil
+}
@@ -102,11 +102,14 @@ func (b *bitbucketClient) CreateHook(ctx context.Context, repo string, hook *sdk
 	url := fmt.Sprintf("/projects/%s/repos/%s/webhooks", project, slug)
 	request := WebHook{
 		URL:           hook.URL,
-		Events:        []string{"repo:refs_changed"},
+		Events:        []string{"repo:refs_changed", "pr:opened", "pr:from_ref_updated", "pr:merged", "pr:declined"},
 		Active:        true,
 		Name:          repo,
 		Configuration: make(map[string]string),
 	}
+	if hook.Secret != "" {
+		request.Configuration["secret"] = hook.Secret
+	}
 
 	values, err := json.Marshal(&request)
 	if err != nil {
@@ -333,3 +333,16 @@ type PullRequestResponse struct {
 	NextPageStart int           `json:"nextPageStart"`
 	IsLastPage    bool          `json:"isLastPage"`
 }
+
+type ChangesResponse struct {
+	Values []struct {
+		Path struct {
+			ToString string `json:"toString"`
+		} `json:"path"`
+		SrcPath *struct {
+			ToString string `json:"toString"`
+		} `json:"srcPath,omitempty"`
+	} `json:"values"`
+	NextPageStart int  `json:"nextPageStart"`
+	IsLastPage    bool `json:"isLastPage"`
+}
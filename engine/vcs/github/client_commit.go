@@ -302,3 +302,42 @@ func (g *githubClient) CommitsBetweenRefs(ctx context.Context, repo, base, head
 
 	return commits, nil
 }
+
+// ChangedFiles returns the files changed between two commits, or by the head commit if there is no base
+func (g *githubClient) ChangedFiles(ctx context.Context, repo, base, head string) ([]string, error) {
+	url := "/repos/" + repo + "/commits/" + head
+	if base != "" {
+		url = fmt.Sprintf("/repos/%s/compare/%s...%s", repo, base, head)
+	}
+	status, body, _, err := g.get(url)
+	if err != nil {
+		log.Warning("githubClient.ChangedFiles> Error %s", err)
+		return nil, err
+	}
+	if status >= 400 {
+		return nil, sdk.NewError(sdk.ErrRepoNotFound, errorAPI(body))
+	}
+
+	var files []string
+	//Github may return 304 status because we are using conditional request with ETag based headers
+	if status == http.StatusNotModified {
+		//If repo isn't updated, lets get them from cache
+		g.Cache.Get(cache.Key("vcs", "github", "files", g.OAuthToken, url), &files)
+		return files, nil
+	}
+
+	var changes ChangedFiles
+	if err := json.Unmarshal(body, &changes); err != nil {
+		log.Warning("githubClient.ChangedFiles> Unable to parse github files: %s", err)
+		return nil, err
+	}
+	for _, f := range changes.Files {
+		files = append(files, f.Filename)
+		if f.PreviousFilename != "" {
+			files = append(files, f.PreviousFilename)
+		}
+	}
+	//Put the body on cache for one hour and one minute
+	g.Cache.SetWithTTL(cache.Key("vcs", "github", "files", g.OAuthToken, url), &files, 61*60)
+	return files, nil
+}
@@ -24,10 +24,11 @@ func (g *githubClient) CreateHook(ctx context.Context, repo string, hook *sdk.VC
 	r := WebhookCreate{
 		Name:   "web",
 		Active: true,
-		Events: []string{"push"},
+		Events: []string{"push", "pull_request"},
 		Config: WebHookConfig{
 			URL:         hook.URL,
 			ContentType: "json",
+			Secret:      hook.Secret,
 		},
 	}
 	b, err := json.Marshal(r)
@@ -56,6 +56,7 @@ type WebhookCreate struct {
 type WebHookConfig struct {
 	URL         string `json:"url"`
 	ContentType string `json:"content_type"`
+	Secret      string `json:"secret,omitempty"`
 }
 
 // User represents a GitHub user.
@@ -709,6 +710,14 @@ type DiffCommits struct {
 	} `json:"files"`
 }
 
+// ChangedFiles represents the files of a commit or of a comparison between two commits
+type ChangedFiles struct {
+	Files []struct {
+		Filename         string `json:"filename"`
+		PreviousFilename string `json:"previous_filename"`
+	} `json:"files"`
+}
+
 type Ref struct {
 	Ref    string `json:"ref"`
 	NodeID string `json:"node_id"`
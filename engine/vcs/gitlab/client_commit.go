@@ -103,3 +103,34 @@ func (c *gitlabClient) CommitsBetweenRefs(ctx context.Context, repo, base, head
 
 	return vcscommits, nil
 }
+
+func (c *gitlabClient) ChangedFiles(ctx context.Context, repo, base, head string) ([]string, error) {
+	var diffs []*gitlab.Diff
+	if base == "" {
+		d, _, err := c.client.Commits.GetCommitDiff(repo, head)
+		if err != nil {
+			return nil, err
+		}
+		diffs = d
+	} else {
+		compare, _, err := c.client.Repositories.Compare(repo, &gitlab.CompareOptions{
+			From: &base,
+			To:   &head,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if compare != nil {
+			diffs = compare.Diffs
+		}
+	}
+
+	var files []string
+	for _, d := range diffs {
+		files = append(files, d.NewPath)
+		if d.RenamedFile {
+			files = append(files, d.OldPath)
+		}
+	}
+	return files, nil
+}
@@ -39,10 +39,13 @@ func (c *gitlabClient) CreateHook(ctx context.Context, repo string, hook *sdk.VC
 	opt := gitlab.AddProjectHookOptions{
 		URL:                   &url,
 		PushEvents:            &t,
-		MergeRequestsEvents:   &f,
+		MergeRequestsEvents:   &t,
 		TagPushEvents:         &f,
 		EnableSSLVerification: &f,
 	}
+	if hook.Secret != "" {
+		opt.Token = &hook.Secret
+	}
 
 	log.Debug("GitlabClient.CreateHook: %s %s\n", repo, *opt.URL)
 	ph, resp, err := c.client.Projects.AddProjectHook(repo, &opt)
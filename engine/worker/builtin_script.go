@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"path"
 	"strings"
+	"syscall"
 
 	"github.com/kardianos/osext"
 
@@ -245,6 +246,11 @@ func runScriptAction(w *currentWorker) BuiltInAction {
 				res.Reason = fmt.Sprintf("%s\n", err)
 				sendLog(res.Reason)
 				res.Status = sdk.StatusFail.String()
+				if exitErr, ok := err.(*exec.ExitError); ok {
+					if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+						res.ExitCode = int32(status.ExitStatus())
+					}
+				}
 				chanRes <- res
 			}
 
@@ -198,12 +198,15 @@ func runCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
 				case <-ctx.Done():
 					return
 				case <-refreshTick.C:
-					if err := w.client.WorkerRefresh(ctx); err != nil {
+					hb, err := w.client.WorkerRefresh(ctx)
+					if err != nil {
 						log.Error("Heartbeat failed: %v", err)
 						nbErrors++
 						if nbErrors == 5 {
 							errs <- err
 						}
+					} else if hb.KillJobID != 0 {
+						w.killJob(hb.KillJobID)
 					}
 					nbErrors = 0
 				case <-registerTick.C:
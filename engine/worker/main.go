@@ -2,6 +2,8 @@ package main
 
 import (
 	"container/list"
+	"context"
+	"sync"
 
 	"google.golang.org/grpc"
 
@@ -46,6 +48,12 @@ type currentWorker struct {
 		secrets          []sdk.Variable
 		workingDirectory string
 	}
+	// jobCancel stops the running job when the API asks it through the heartbeat
+	jobCancel struct {
+		sync.Mutex
+		jobID  int64
+		cancel context.CancelFunc
+	}
 	status struct {
 		Name   string `json:"name"`
 		Status string `json:"status"`
@@ -201,6 +201,23 @@ func (w *currentWorker) runJob(ctx context.Context, a *sdk.Action, buildID int64
 	return r
 }
 
+// startStep runs a step, the step fails if it exceeds its timeout
+func (w *currentWorker) startStep(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, secrets []sdk.Variable, stepOrder int, stepName string) sdk.Result {
+	if a.Timeout <= 0 {
+		return w.startAction(ctx, a, buildID, params, secrets, stepOrder, stepName)
+	}
+
+	timeout := time.Duration(a.Timeout) * time.Second
+	ctxStep, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	r := w.startAction(ctxStep, a, buildID, params, secrets, stepOrder, stepName)
+	if ctxStep.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		r.Status = sdk.StatusFail.String()
+		r.Reason = fmt.Sprintf("Step timed out after %s", timeout)
+	}
+	return r
+}
+
 func (w *currentWorker) runSteps(ctx context.Context, steps []sdk.Action, a *sdk.Action, buildID int64, params *[]sdk.Parameter, secrets []sdk.Variable, stepOrder int, stepName string, stepBaseCount int) (sdk.Result, int) {
 	log.Info("runSteps> start run %d stepOrder:%d len(steps):%d context=%p", buildID, stepOrder, len(steps), ctx)
 	defer func() {
@@ -246,12 +263,38 @@ func (w *currentWorker) runSteps(ctx context.Context, steps []sdk.Action, a *sdk
 
 		if !criticalStepFailed || child.AlwaysExecuted {
 			// Update step status
-			if err := w.updateStepStatus(ctx, buildID, w.currentJob.currentStep, sdk.StatusBuilding.String()); err != nil {
+			attempt := 1
+			if err := w.sendStepStatus(ctx, buildID, w.newStepStatus(sdk.StatusBuilding.String(), attempt)); err != nil {
 				log.Warning("Cannot update step (%d) status (%s) for build %d: %s\n", w.currentJob.currentStep, sdk.StatusDisabled.String(), buildID, err)
 			}
 			w.sendLog(buildID, fmt.Sprintf("Starting step %s\n", childName), w.currentJob.currentStep, false)
 
-			r = w.startAction(ctx, &child, buildID, params, secrets, w.currentJob.currentStep, childName)
+			r = w.startStep(ctx, &child, buildID, params, secrets, w.currentJob.currentStep, childName)
+			// Run the step again while its retry policy allows it
+			for r.Status == sdk.StatusFail.String() && ctx.Err() == nil && child.RetryPolicy.ShouldRetry(attempt, sdk.RetryOnFailure, int(r.ExitCode)) {
+				delay := child.RetryPolicy.Delay(attempt)
+				w.sendLog(buildID, fmt.Sprintf("Attempt %d of step %s failed, attempt %d/%d will start in %s\n", attempt, childName, attempt+1, child.RetryPolicy.MaxAttempts, delay), w.currentJob.currentStep, false)
+				failed := w.newStepStatus(r.Status, attempt)
+				failed.ExitCode = int(r.ExitCode)
+				if err := w.sendStepStatus(ctx, buildID, failed); err != nil {
+					log.Warning("Cannot update step (%d) status (%s) for build %d: %s", w.currentJob.currentStep, r.Status, buildID, err)
+				}
+
+				select {
+				case <-ctx.Done():
+				case <-time.After(delay):
+				}
+				if ctx.Err() != nil {
+					break
+				}
+
+				attempt++
+				if err := w.sendStepStatus(ctx, buildID, w.newStepStatus(sdk.StatusBuilding.String(), attempt)); err != nil {
+					log.Warning("Cannot update step (%d) status (%s) for build %d: %s", w.currentJob.currentStep, sdk.StatusBuilding.String(), buildID, err)
+				}
+				w.sendLog(buildID, fmt.Sprintf("Starting attempt %d of step %s\n", attempt, childName), w.currentJob.currentStep, false)
+				r = w.startStep(ctx, &child, buildID, params, secrets, w.currentJob.currentStep, childName)
+			}
 			if r.Status != sdk.StatusSuccess.String() && !child.Optional {
 				criticalStepFailed = true
 			}
@@ -263,7 +306,9 @@ func (w *currentWorker) runSteps(ctx context.Context, steps []sdk.Action, a *sdk
 			}
 
 			// Update step status
-			if err := w.updateStepStatus(ctx, buildID, w.currentJob.currentStep, r.Status); err != nil {
+			done := w.newStepStatus(r.Status, attempt)
+			done.ExitCode = int(r.ExitCode)
+			if err := w.sendStepStatus(ctx, buildID, done); err != nil {
 				log.Warning("Cannot update step (%d) status (%s) for build %d: %s", w.currentJob.currentStep, sdk.StatusDisabled.String(), buildID, err)
 			}
 		} else if criticalStepFailed && !child.AlwaysExecuted { // Update status of steps which are never built
@@ -284,13 +329,27 @@ func (w *currentWorker) runSteps(ctx context.Context, steps []sdk.Action, a *sdk
 }
 
 func (w *currentWorker) updateStepStatus(ctx context.Context, buildID int64, stepOrder int, status string) error {
-	step := sdk.StepStatus{
+	return w.sendStepStatus(ctx, buildID, sdk.StepStatus{
 		StepOrder: stepOrder,
 		Status:    status,
 		Start:     time.Now(),
 		Done:      time.Now(),
+	})
+}
+
+// newStepStatus returns the status of the given attempt of the current step
+func (w *currentWorker) newStepStatus(status string, attempt int) sdk.StepStatus {
+	return sdk.StepStatus{
+		StepOrder: w.currentJob.currentStep,
+		Status:    status,
+		Start:     time.Now(),
+		Done:      time.Now(),
+		Attempt:   attempt,
 	}
+}
 
+func (w *currentWorker) sendStepStatus(ctx context.Context, buildID int64, step sdk.StepStatus) error {
+	status, stepOrder := step.Status, step.StepOrder
 	var path string
 	if w.currentJob.wJob != nil {
 		path = fmt.Sprintf("/queue/workflows/%d/step", buildID)
@@ -47,6 +47,8 @@ func (w *currentWorker) takeWorkflowJob(ctx context.Context, job sdk.WorkflowNod
 
 	//This goroutine try to get the job every 5 seconds, if it fails, it cancel the build.
 	ctx, cancel := context.WithCancel(ctx)
+	w.setJobCancel(job.ID, cancel)
+	defer w.setJobCancel(0, nil)
 	tick := time.NewTicker(5 * time.Second)
 	go func(cancel context.CancelFunc, jobID int64, tick *time.Ticker) {
 		var nbConnrefused int
@@ -132,3 +134,21 @@ func (w *currentWorker) takeWorkflowJob(ctx context.Context, job sdk.WorkflowNod
 	log.Error("takeWorkflowJob> Could not send built result 10 times, giving up. job: %d", job.ID)
 	return false, lasterr
 }
+
+func (w *currentWorker) setJobCancel(jobID int64, cancel context.CancelFunc) {
+	w.jobCancel.Lock()
+	defer w.jobCancel.Unlock()
+	w.jobCancel.jobID = jobID
+	w.jobCancel.cancel = cancel
+}
+
+// killJob stops the job if it's the running one
+func (w *currentWorker) killJob(jobID int64) {
+	w.jobCancel.Lock()
+	defer w.jobCancel.Unlock()
+	if w.jobCancel.jobID != jobID || w.jobCancel.cancel == nil {
+		return
+	}
+	log.Info("killJob> Job %d has been stopped by the API - Cancelling context", jobID)
+	w.jobCancel.cancel()
+}
@@ -0,0 +1,84 @@
+package sdk
+
+import (
+	"fmt"
+	"time"
+)
+
+// Scopes of an access token, each scope includes the previous ones
+const (
+	AccessTokenScopeRead  = "read"
+	AccessTokenScopeRun   = "run"
+	AccessTokenScopeAdmin = "admin"
+)
+
+// AccessTokenScopes is the list of all the access token scopes
+var AccessTokenScopes = []string{AccessTokenScopeRead, AccessTokenScopeRun, AccessTokenScopeAdmin}
+
+// AccessToken is a named token used by scripts and bots to call the API on behalf of a user.
+// Its scope limits the actions allowed with the token, and it can be restricted to a single project.
+// The value of the token is only returned at creation, the API stores its hash.
+type AccessToken struct {
+	ID          int64      `json:"id" db:"id" cli:"id,key"`
+	UserID      int64      `json:"user_id" db:"user_id" cli:"-"`
+	Name        string     `json:"name" db:"name" cli:"name"`
+	Description string     `json:"description,omitempty" db:"description" cli:"description"`
+	Scope       string     `json:"scope" db:"scope" cli:"scope"`
+	ProjectKey  string     `json:"project_key,omitempty" db:"project_key" cli:"project"`
+	Token       string     `json:"token,omitempty" db:"-" cli:"token"`
+	Hash        string     `json:"-" db:"hash" cli:"-"`
+	Created     time.Time  `json:"created" db:"created" cli:"created"`
+	Expire      *time.Time `json:"expire,omitempty" db:"expire" cli:"expire"`
+	LastUsed    *time.Time `json:"last_used,omitempty" db:"last_used" cli:"last_used"`
+	Revoked     *time.Time `json:"revoked,omitempty" db:"revoked" cli:"revoked"`
+	RevokedBy   string     `json:"revoked_by,omitempty" db:"revoked_by" cli:"revoked_by"`
+}
+
+// IsValid checks the name, the scope and the expiration of a new access token
+func (t AccessToken) IsValid() error {
+	if t.Name == "" {
+		return NewError(ErrInvalidAccessToken, fmt.Errorf("name is mandatory"))
+	}
+	var found bool
+	for _, s := range AccessTokenScopes {
+		if t.Scope == s {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return NewError(ErrInvalidAccessToken, fmt.Errorf("scope must be one of %v", AccessTokenScopes))
+	}
+	if t.Expire != nil && t.Expire.Before(time.Now()) {
+		return NewError(ErrInvalidAccessToken, fmt.Errorf("expiration date is in the past"))
+	}
+	return nil
+}
+
+// IsActive returns false if the access token has been revoked or has expired
+func (t AccessToken) IsActive(now time.Time) bool {
+	if t.Revoked != nil {
+		return false
+	}
+	return t.Expire == nil || now.Before(*t.Expire)
+}
+
+// Types of the access token audits
+const (
+	AccessTokenAuditCreate = "create"
+	AccessTokenAuditRevoke = "revoke"
+	AccessTokenAuditUse    = "use"
+	AccessTokenAuditDenied = "denied"
+)
+
+// AccessTokenAudit records the creation, the revocation and each use of an access token
+type AccessTokenAudit struct {
+	ID            int64     `json:"id" db:"id" cli:"-"`
+	AccessTokenID int64     `json:"access_token_id" db:"access_token_id" cli:"-"`
+	Type          string    `json:"type" db:"type" cli:"type"`
+	Username      string    `json:"username" db:"username" cli:"username"`
+	Method        string    `json:"method,omitempty" db:"method" cli:"method"`
+	URL           string    `json:"url,omitempty" db:"url" cli:"url"`
+	RemoteAddr    string    `json:"remote_addr,omitempty" db:"remote_addr" cli:"remote_addr"`
+	Date          time.Time `json:"date" db:"date" cli:"date"`
+}
@@ -20,6 +20,8 @@ type Action struct {
 	Deprecated     bool          `json:"deprecated" yaml:"-"`
 	Optional       bool          `json:"optional" yaml:"-"`
 	AlwaysExecuted bool          `json:"always_executed" yaml:"-"`
+	Timeout        int64         `json:"timeout,omitempty" yaml:"-"` // Timeout of a step in seconds
+	RetryPolicy    *RetryPolicy  `json:"retry_policy,omitempty" yaml:"-"`
 	LastModified   int64         `json:"last_modified" cli:"modified"`
 }
 
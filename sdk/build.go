@@ -44,6 +44,22 @@ type ExecutedJob struct {
 	Reason     string       `json:"reason" db:"-"`
 	WorkerName string       `json:"worker_name" db:"-"`
 	WorkerID   string       `json:"worker_id" db:"-"`
+	// MatrixVariant is the name of the variant when the job is expanded by a matrix
+	MatrixVariant string `json:"matrix_variant,omitempty" db:"-"`
+	// Attempts are the previous attempts of a job retried by its retry policy
+	Attempts []JobAttempt `json:"attempts,omitempty" db:"-"`
+}
+
+// JobAttempt is a failed attempt of a job
+type JobAttempt struct {
+	Attempt    int          `json:"attempt"`
+	Reason     string       `json:"reason"`
+	ExitCode   int          `json:"exit_code,omitempty"`
+	WorkerName string       `json:"worker_name,omitempty"`
+	Model      string       `json:"model,omitempty"`
+	Start      time.Time    `json:"start"`
+	Done       time.Time    `json:"done"`
+	StepStatus []StepStatus `json:"step_status,omitempty"`
 }
 
 // ExecutedJobSummary is a light representation of ExecutedJob for CDS event
@@ -86,6 +102,10 @@ type StepStatus struct {
 	Status    string    `json:"status" db:"-"`
 	Start     time.Time `json:"start" db:"-"`
 	Done      time.Time `json:"done" db:"-"`
+	ExitCode  int       `json:"exit_code,omitempty" db:"-"`
+	// Attempt is the number of the attempt of a step retried by its retry policy
+	Attempt  int          `json:"attempt,omitempty" db:"-"`
+	Attempts []StepStatus `json:"attempts,omitempty" db:"-"`
 }
 
 // StepStatusSummary Represent a step and his status for CDS event
@@ -53,3 +53,19 @@ func (c *client) ServiceCallDELETE(stype string, query string) error {
 	_, _, _, err := c.Request(context.Background(), "DELETE", "/admin/services/call?type="+stype+"&query="+url.QueryEscape(query), nil)
 	return err
 }
+
+func (c *client) SecretReencryptionStatus() (*sdk.SecretReencryptionStatus, error) {
+	var status sdk.SecretReencryptionStatus
+	if _, err := c.GetJSON(context.Background(), "/admin/secret/reencryption", &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func (c *client) SecretReencryptionStart() (*sdk.SecretReencryptionStatus, error) {
+	var status sdk.SecretReencryptionStatus
+	if _, err := c.PostJSON(context.Background(), "/admin/secret/reencryption", nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
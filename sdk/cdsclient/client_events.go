@@ -0,0 +1,39 @@
+package cdsclient
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// EventsListen sends the events of the API in the channel, it reconnects until the context is done
+func (c *client) EventsListen(ctx context.Context, events chan<- sdk.Event) {
+	chanSSEvt := make(chan SSEvent)
+	sdk.GoRoutine("EventsListen", func() {
+		for ctx.Err() == nil {
+			if err := c.RequestSSEGet(ctx, "/events", chanSSEvt); err != nil {
+				log.Println(err)
+			}
+			time.Sleep(2 * time.Second)
+		}
+	})
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt := <-chanSSEvt:
+			content, _ := ioutil.ReadAll(evt.Data)
+			var e sdk.Event
+			// the first message of the stream is an ACK which is not an event
+			if err := json.Unmarshal(content, &e); err != nil || e.EventType == "" {
+				continue
+			}
+			events <- e
+		}
+	}
+}
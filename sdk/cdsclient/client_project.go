@@ -33,6 +33,11 @@ func (c *client) ProjectDelete(key string) error {
 	return err
 }
 
+func (c *client) ProjectVaultRoleUpdate(key, role string) error {
+	_, err := c.PutJSON(context.Background(), "/project/"+key+"/vault/role", sdk.Project{VaultRole: role}, nil)
+	return err
+}
+
 func (c *client) ProjectGet(key string, mods ...RequestModifier) (*sdk.Project, error) {
 	p := &sdk.Project{}
 	if _, err := c.GetJSON(context.Background(), "/project/"+key, p, mods...); err != nil {
@@ -0,0 +1,47 @@
+package cdsclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) ProjectFreezeWindowsList(projectKey string) ([]sdk.FreezeWindow, error) {
+	fs := []sdk.FreezeWindow{}
+	if _, err := c.GetJSON(context.Background(), "/project/"+projectKey+"/freeze", &fs); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+func (c *client) ProjectFreezeWindowCreate(projectKey string, f *sdk.FreezeWindow) error {
+	_, err := c.PostJSON(context.Background(), "/project/"+projectKey+"/freeze", f, f)
+	return err
+}
+
+func (c *client) ProjectFreezeWindowUpdate(projectKey string, f *sdk.FreezeWindow) error {
+	_, err := c.PutJSON(context.Background(), fmt.Sprintf("/project/%s/freeze/%d", projectKey, f.ID), f, f)
+	return err
+}
+
+func (c *client) ProjectFreezeWindowDelete(projectKey string, id int64) error {
+	_, _, _, err := c.Request(context.Background(), "DELETE", fmt.Sprintf("/project/%s/freeze/%d", projectKey, id), nil)
+	return err
+}
+
+func (c *client) ProjectFreezeWindowOverrides(projectKey string, id int64) ([]sdk.FreezeWindowOverride, error) {
+	overrides := []sdk.FreezeWindowOverride{}
+	if _, err := c.GetJSON(context.Background(), fmt.Sprintf("/project/%s/freeze/%d/override", projectKey, id), &overrides); err != nil {
+		return nil, err
+	}
+	return overrides, nil
+}
+
+func (c *client) ProjectFreezeWindowAudits(projectKey string) ([]sdk.FreezeWindowAudit, error) {
+	audits := []sdk.FreezeWindowAudit{}
+	if _, err := c.GetJSON(context.Background(), "/project/"+projectKey+"/freeze/audit", &audits); err != nil {
+		return nil, err
+	}
+	return audits, nil
+}
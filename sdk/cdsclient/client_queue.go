@@ -3,7 +3,10 @@ package cdsclient
 import (
 	"bytes"
 	"context"
+	"crypto/md5"
+	"crypto/sha512"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -107,8 +110,8 @@ func (c *client) QueuePolling(ctx context.Context, jobs chan<- sdk.WorkflowNodeJ
 							return
 						}
 
-						// push the job in the channel
-						if job.Status == sdk.StatusWaiting.String() && job.BookedBy.Name == "" {
+						// push the job in the channel, a job queued again by its retry policy waits for its backoff
+						if job.Status == sdk.StatusWaiting.String() && job.BookedBy.Name == "" && !job.Queued.After(time.Now()) {
 							jobs <- *job
 						}
 					}()
@@ -392,15 +395,13 @@ func (c *client) queueIndirectArtifactUpload(ctx context.Context, id int64, tag,
 		return errst
 	}
 
-	sha512sum, err512 := sdk.FileSHA512sum(filePath)
-	if err512 != nil {
-		return err512
-	}
-
-	md5sum, errmd5 := sdk.FileMd5sum(filePath)
-	if errmd5 != nil {
-		return errmd5
+	//Read the file once, the checksums are computed on the uploaded content
+	fileContent, errFileContent := ioutil.ReadAll(f)
+	if errFileContent != nil {
+		return errFileContent
 	}
+	sha512sum := sha512.Sum512(fileContent)
+	md5sum := md5.Sum(fileContent)
 
 	_, name := filepath.Split(filePath)
 
@@ -409,10 +410,10 @@ func (c *client) queueIndirectArtifactUpload(ctx context.Context, id int64, tag,
 		Name:      name,
 		Tag:       tag,
 		Ref:       ref,
-		Size:      stat.Size(),
+		Size:      int64(len(fileContent)),
 		Perm:      uint32(stat.Mode().Perm()),
-		MD5sum:    md5sum,
-		SHA512sum: sha512sum,
+		MD5sum:    hex.EncodeToString(md5sum[:]),
+		SHA512sum: hex.EncodeToString(sha512sum[:]),
 		Created:   time.Now(),
 	}
 
@@ -424,12 +425,6 @@ func (c *client) queueIndirectArtifactUpload(ctx context.Context, id int64, tag,
 		fmt.Printf("Uploading %s with to %s\n", art.Name, art.TempURL)
 	}
 
-	//Read the file once
-	fileContent, errFileContent := ioutil.ReadAll(f)
-	if errFileContent != nil {
-		return errFileContent
-	}
-
 	if err := c.queueIndirectArtifactTempURLPost(art.TempURL, fileContent); err != nil {
 		// If we got a 401 error from the objectstore, ask for a fresh temporary url and repost the artifact
 		if strings.Contains(err.Error(), "401 Unauthorized: Temp URL invalid") {
@@ -0,0 +1,49 @@
+package cdsclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) RoleList() ([]sdk.Role, error) {
+	roles := []sdk.Role{}
+	if _, err := c.GetJSON(context.Background(), "/role", &roles); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (c *client) RoleCreate(r *sdk.Role) error {
+	_, err := c.PostJSON(context.Background(), "/role", r, r)
+	return err
+}
+
+func (c *client) RoleUpdate(r *sdk.Role) error {
+	_, err := c.PutJSON(context.Background(), "/role/"+r.Name, r, r)
+	return err
+}
+
+func (c *client) RoleDelete(name string) error {
+	_, _, _, err := c.Request(context.Background(), "DELETE", "/role/"+name, nil)
+	return err
+}
+
+func (c *client) ProjectRoleBindingsList(projectKey string) ([]sdk.RoleBinding, error) {
+	bs := []sdk.RoleBinding{}
+	if _, err := c.GetJSON(context.Background(), "/project/"+projectKey+"/role", &bs); err != nil {
+		return nil, err
+	}
+	return bs, nil
+}
+
+func (c *client) ProjectRoleBindingCreate(projectKey string, b *sdk.RoleBinding) error {
+	_, err := c.PostJSON(context.Background(), "/project/"+projectKey+"/role", b, b)
+	return err
+}
+
+func (c *client) ProjectRoleBindingDelete(projectKey string, id int64) error {
+	_, _, _, err := c.Request(context.Background(), "DELETE", fmt.Sprintf("/project/%s/role/%d", projectKey, id), nil)
+	return err
+}
@@ -0,0 +1,59 @@
+package cdsclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) TemplateList() ([]sdk.WorkflowTemplate, error) {
+	ts := []sdk.WorkflowTemplate{}
+	if _, err := c.GetJSON(context.Background(), "/template", &ts); err != nil {
+		return nil, err
+	}
+	return ts, nil
+}
+
+func (c *client) TemplateGet(groupName, templateName string) (*sdk.WorkflowTemplate, error) {
+	var t sdk.WorkflowTemplate
+	if _, err := c.GetJSON(context.Background(), fmt.Sprintf("/group/%s/template/%s", groupName, templateName), &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (c *client) TemplatePush(groupName string, t *sdk.WorkflowTemplate) error {
+	_, err := c.TemplateGet(groupName, t.Name)
+	if err == nil {
+		_, err = c.PutJSON(context.Background(), fmt.Sprintf("/group/%s/template/%s", groupName, t.Name), t, t)
+		return err
+	}
+	if !sdk.ErrorIs(err, sdk.ErrWorkflowTemplateNotFound) {
+		return err
+	}
+	_, err = c.PostJSON(context.Background(), fmt.Sprintf("/group/%s/template", groupName), t, t)
+	return err
+}
+
+func (c *client) TemplateDelete(groupName, templateName string) error {
+	_, err := c.DeleteJSON(context.Background(), fmt.Sprintf("/group/%s/template/%s", groupName, templateName), nil)
+	return err
+}
+
+func (c *client) TemplateApply(projectKey, groupName, templateName string, req sdk.WorkflowTemplateRequest) ([]string, error) {
+	var msgs []string
+	path := fmt.Sprintf("/project/%s/template/%s/%s/apply", projectKey, groupName, templateName)
+	if _, err := c.PostJSON(context.Background(), path, req, &msgs); err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
+
+func (c *client) TemplateInstances(projectKey string) ([]sdk.WorkflowTemplateInstance, error) {
+	is := []sdk.WorkflowTemplateInstance{}
+	if _, err := c.GetJSON(context.Background(), fmt.Sprintf("/project/%s/template/instance", projectKey), &is); err != nil {
+		return nil, err
+	}
+	return is, nil
+}
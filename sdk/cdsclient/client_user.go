@@ -34,6 +34,22 @@ func (c *client) UserLogin(username, password string) (bool, string, error) {
 	return true, response.Password, nil
 }
 
+func (c *client) UserLoginOIDCDevice() (*sdk.UserOIDCDeviceAuthorization, error) {
+	var a sdk.UserOIDCDeviceAuthorization
+	if _, err := c.PostJSON(context.Background(), "/login/oidc/device", nil, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (c *client) UserLoginOIDCDeviceToken(deviceCode string) (*sdk.UserAPIResponse, error) {
+	var res sdk.UserAPIResponse
+	if _, err := c.PostJSON(context.Background(), "/login/oidc/device/token", sdk.UserOIDCDeviceTokenRequest{DeviceCode: deviceCode}, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
 func (c *client) UserList() ([]sdk.User, error) {
 	res := []sdk.User{}
 	if _, err := c.GetJSON(context.Background(), "/user", &res); err != nil {
@@ -125,6 +141,38 @@ func (c *client) FindToken(tokenValue string) (sdk.Token, error) {
 	return token, nil
 }
 
+// UserAccessTokenList lists the access tokens of the current user
+func (c *client) UserAccessTokenList() ([]sdk.AccessToken, error) {
+	ts := []sdk.AccessToken{}
+	if _, err := c.GetJSON(context.Background(), "/user/accesstoken", &ts); err != nil {
+		return nil, err
+	}
+	return ts, nil
+}
+
+// UserAccessTokenCreate creates an access token, the value of the token is only returned here
+func (c *client) UserAccessTokenCreate(t sdk.AccessToken) (*sdk.AccessToken, error) {
+	if _, err := c.PostJSON(context.Background(), "/user/accesstoken", t, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// UserAccessTokenRevoke revokes an access token of the current user
+func (c *client) UserAccessTokenRevoke(id int64) error {
+	_, err := c.DeleteJSON(context.Background(), fmt.Sprintf("/user/accesstoken/%d", id), nil)
+	return err
+}
+
+// UserAccessTokenAudits returns the last creation, revocation and uses of an access token of the current user
+func (c *client) UserAccessTokenAudits(id int64, limit int) ([]sdk.AccessTokenAudit, error) {
+	as := []sdk.AccessTokenAudit{}
+	if _, err := c.GetJSON(context.Background(), fmt.Sprintf("/user/accesstoken/%d/audit?limit=%d", id, limit), &as); err != nil {
+		return nil, err
+	}
+	return as, nil
+}
+
 // UpdateFavorite Update favorites (add or delete) return updated workflow or project
 func (c *client) UpdateFavorite(params sdk.FavoriteParams) (interface{}, error) {
 	switch params.Type {
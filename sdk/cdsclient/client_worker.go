@@ -27,14 +27,15 @@ func (c *client) WorkerDisable(ctx context.Context, id string) error {
 	return nil
 }
 
-func (c *client) WorkerRefresh(ctx context.Context) error {
+func (c *client) WorkerRefresh(ctx context.Context) (*sdk.WorkerHeartbeat, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 	url := fmt.Sprintf("/worker/refresh")
-	if _, err := c.PostJSON(ctx, url, nil, nil); err != nil {
-		return err
+	hb := &sdk.WorkerHeartbeat{}
+	if _, err := c.PostJSON(ctx, url, nil, hb); err != nil {
+		return nil, err
 	}
-	return nil
+	return hb, nil
 }
 
 func (c *client) WorkerRegister(ctx context.Context, form sdk.WorkerRegistrationForm) (*sdk.Worker, bool, error) {
@@ -32,6 +32,24 @@ func (c *client) WorkflowGet(projectKey, workflowName string) (*sdk.Workflow, er
 	return w, nil
 }
 
+func (c *client) WorkflowNotificationDeliveries(projectKey, workflowName string, limit int) ([]sdk.WorkflowNotificationDelivery, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/notifications/deliveries?limit=%d", projectKey, workflowName, limit)
+	ds := []sdk.WorkflowNotificationDelivery{}
+	if _, err := c.GetJSON(context.Background(), url, &ds); err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
+
+func (c *client) WorkflowApprovalAudits(projectKey, workflowName string) ([]sdk.WorkflowNodeRunApprovalAudit, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/approvals/audit", projectKey, workflowName)
+	audits := []sdk.WorkflowNodeRunApprovalAudit{}
+	if _, err := c.GetJSON(context.Background(), url, &audits); err != nil {
+		return nil, err
+	}
+	return audits, nil
+}
+
 func (c *client) WorkflowRunGet(projectKey string, workflowName string, number int64) (*sdk.WorkflowRun, error) {
 	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d", projectKey, workflowName, number)
 	run := sdk.WorkflowRun{}
@@ -130,6 +148,32 @@ func (c *client) WorkflowNodeRunJobStep(projectKey string, workflowName string,
 	return &buildState, nil
 }
 
+// WorkflowNodeRunJobStepLines returns at most limit lines of a step log starting after the offset first lines.
+// A negative offset returns the -offset last lines of the log.
+func (c *client) WorkflowNodeRunJobStepLines(projectKey string, workflowName string, number int64, nodeRunID, job int64, step int, offset, limit int64) (*sdk.LogLines, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d/job/%d/step/%d/lines", projectKey, workflowName, number, nodeRunID, job, step)
+	if offset < 0 {
+		url += fmt.Sprintf("?tail=%d", -offset)
+	} else {
+		url += fmt.Sprintf("?offset=%d&limit=%d", offset, limit)
+	}
+	lines := sdk.LogLines{}
+	if _, err := c.GetJSON(context.Background(), url, &lines); err != nil {
+		return nil, err
+	}
+	return &lines, nil
+}
+
+// WorkflowNodeRunLogsSearch returns the lines matching the regular expression in the logs of all the steps of a node run
+func (c *client) WorkflowNodeRunLogsSearch(projectKey string, workflowName string, number int64, nodeRunID int64, regex string, limit int) ([]sdk.LogLine, error) {
+	path := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d/logs/search?q=%s&limit=%d", projectKey, workflowName, number, nodeRunID, url.QueryEscape(regex), limit)
+	lines := []sdk.LogLine{}
+	if _, err := c.GetJSON(context.Background(), path, &lines); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
 func (c *client) WorkflowNodeRunArtifactDownload(projectKey string, workflowName string, a sdk.WorkflowNodeRunArtifact, w io.Writer) error {
 	var url = fmt.Sprintf("/project/%s/workflows/%s/artifact/%d", projectKey, workflowName, a.ID)
 	var reader io.ReadCloser
@@ -234,6 +278,17 @@ func (c *client) WorkflowNodeStop(projectKey string, workflowName string, number
 	return nodeRun, nil
 }
 
+func (c *client) WorkflowNodeRunApprove(projectKey string, workflowName string, number, nodeRunID int64, approved bool, comment string) (*sdk.WorkflowNodeRun, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d/approval", projectKey, workflowName, number, nodeRunID)
+	req := sdk.WorkflowNodeRunApprovalRequest{Approved: approved, Comment: comment}
+
+	nodeRun := &sdk.WorkflowNodeRun{}
+	if _, err := c.PostJSON(context.Background(), url, &req, nodeRun); err != nil {
+		return nil, err
+	}
+	return nodeRun, nil
+}
+
 func (c *client) WorkflowCachePush(projectKey, ref string, tarContent io.Reader) error {
 	store := new(sdk.ArtifactsStore)
 	_, _ = c.GetJSON(context.Background(), "/artifact/store", store)
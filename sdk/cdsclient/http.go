@@ -232,6 +232,9 @@ func (c *client) Stream(ctx context.Context, method string, path string, body io
 				req.Header.Add(SessionTokenHeader, c.config.Token)
 				req.SetBasicAuth(c.config.User, c.config.Token)
 			}
+			if c.config.AccessToken != "" {
+				req.Header.Set("Authorization", "Bearer "+c.config.AccessToken)
+			}
 		}
 
 		if c.config.Verbose {
@@ -332,6 +335,9 @@ func (c *client) UploadMultiPart(method string, path string, body *bytes.Buffer,
 			req.Header.Add(SessionTokenHeader, c.config.Token)
 			req.SetBasicAuth(c.config.User, c.config.Token)
 		}
+		if c.config.AccessToken != "" {
+			req.Header.Set("Authorization", "Bearer "+c.config.AccessToken)
+		}
 	}
 
 	resp, err := NoTimeout(c.HTTPClient).Do(req)
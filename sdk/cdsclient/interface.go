@@ -23,6 +23,8 @@ type AdminService interface {
 	ServiceCallPOST(stype string, url string, body []byte) ([]byte, error)
 	ServiceCallPUT(stype string, url string, body []byte) ([]byte, error)
 	ServiceCallDELETE(stype string, url string) error
+	SecretReencryptionStatus() (*sdk.SecretReencryptionStatus, error)
+	SecretReencryptionStart() (*sdk.SecretReencryptionStatus, error)
 }
 
 // ExportImportInterface exposes pipeline and application export and import function
@@ -152,6 +154,16 @@ type BroadcastClient interface {
 	BroadcastDelete(id string) error
 }
 
+// TemplateClient exposes workflow templates related functions
+type TemplateClient interface {
+	TemplateList() ([]sdk.WorkflowTemplate, error)
+	TemplateGet(groupName, templateName string) (*sdk.WorkflowTemplate, error)
+	TemplatePush(groupName string, t *sdk.WorkflowTemplate) error
+	TemplateDelete(groupName, templateName string) error
+	TemplateApply(projectKey, groupName, templateName string, req sdk.WorkflowTemplateRequest) ([]string, error)
+	TemplateInstances(projectKey string) ([]sdk.WorkflowTemplateInstance, error)
+}
+
 // PipelineClient exposes pipelines related functions
 type PipelineClient interface {
 	PipelineDelete(projectKey, name string) error
@@ -166,8 +178,11 @@ type ProjectClient interface {
 	ProjectDelete(projectKey string) error
 	ProjectGet(projectKey string, opts ...RequestModifier) (*sdk.Project, error)
 	ProjectList(withApplications, withWorkflow bool, filters ...Filter) ([]sdk.Project, error)
+	ProjectVaultRoleUpdate(projectKey, role string) error
 	ProjectKeysClient
 	ProjectVariablesClient
+	ProjectFreezeWindowsClient
+	ProjectRoleBindingsClient
 	ProjectGroupsImport(projectKey string, content io.Reader, format string, force bool) (sdk.Project, error)
 	ProjectPlatformImport(projectKey string, content io.Reader, format string, force bool) (sdk.ProjectPlatform, error)
 	ProjectPlatformGet(projectKey string, platformName string, clearPassword bool) (sdk.ProjectPlatform, error)
@@ -182,6 +197,16 @@ type ProjectKeysClient interface {
 	ProjectKeysDelete(projectKey string, keyProjectName string) error
 }
 
+// ProjectFreezeWindowsClient exposes project freeze windows related functions
+type ProjectFreezeWindowsClient interface {
+	ProjectFreezeWindowsList(projectKey string) ([]sdk.FreezeWindow, error)
+	ProjectFreezeWindowCreate(projectKey string, f *sdk.FreezeWindow) error
+	ProjectFreezeWindowUpdate(projectKey string, f *sdk.FreezeWindow) error
+	ProjectFreezeWindowDelete(projectKey string, id int64) error
+	ProjectFreezeWindowOverrides(projectKey string, id int64) ([]sdk.FreezeWindowOverride, error)
+	ProjectFreezeWindowAudits(projectKey string) ([]sdk.FreezeWindowAudit, error)
+}
+
 // ProjectVariablesClient exposes project variables related functions
 type ProjectVariablesClient interface {
 	ProjectVariablesList(key string) ([]sdk.Variable, error)
@@ -217,10 +242,16 @@ type UserClient interface {
 	UserGet(username string) (*sdk.User, error)
 	UserGetGroups(username string) (map[string][]sdk.Group, error)
 	UserLogin(username, password string) (bool, string, error)
+	UserLoginOIDCDevice() (*sdk.UserOIDCDeviceAuthorization, error)
+	UserLoginOIDCDeviceToken(deviceCode string) (*sdk.UserAPIResponse, error)
 	UserReset(username, email, callback string) error
 	UserSignup(username, fullname, email, callback string) error
 	ListAllTokens() ([]sdk.Token, error)
 	FindToken(token string) (sdk.Token, error)
+	UserAccessTokenList() ([]sdk.AccessToken, error)
+	UserAccessTokenCreate(t sdk.AccessToken) (*sdk.AccessToken, error)
+	UserAccessTokenRevoke(id int64) error
+	UserAccessTokenAudits(id int64, limit int) ([]sdk.AccessTokenAudit, error)
 	UpdateFavorite(params sdk.FavoriteParams) (interface{}, error)
 }
 
@@ -228,7 +259,7 @@ type UserClient interface {
 type WorkerClient interface {
 	WorkerModelBook(id int64) error
 	WorkerList(ctx context.Context) ([]sdk.Worker, error)
-	WorkerRefresh(ctx context.Context) error
+	WorkerRefresh(ctx context.Context) (*sdk.WorkerHeartbeat, error)
 	WorkerDisable(ctx context.Context, id string) error
 	WorkerModelAdd(name, modelType, patternName string, dockerModel *sdk.ModelDocker, vmModel *sdk.ModelVirtualMachine, groupID int64) (sdk.Model, error)
 	WorkerModelUpdate(ID int64, name string, modelType string, dockerModel *sdk.ModelDocker, vmModel *sdk.ModelVirtualMachine, groupID int64) (sdk.Model, error)
@@ -246,6 +277,7 @@ type WorkerClient interface {
 // HookClient exposes functions used for hooks services
 type HookClient interface {
 	PollVCSEvents(uuid string, workflowID int64, vcsServer string, timestamp int64) (events sdk.RepositoryEvents, interval time.Duration, err error)
+	HookChangedFiles(uuid, base, head string) ([]string, error)
 }
 
 // WorkflowClient exposes workflows functions
@@ -263,10 +295,15 @@ type WorkflowClient interface {
 	WorkflowRunNumberSet(projectKey string, workflowName string, number int64) error
 	WorkflowStop(projectKey string, workflowName string, number int64) (*sdk.WorkflowRun, error)
 	WorkflowNodeStop(projectKey string, workflowName string, number, fromNodeID int64) (*sdk.WorkflowNodeRun, error)
+	WorkflowNodeRunApprove(projectKey string, workflowName string, number, nodeRunID int64, approved bool, comment string) (*sdk.WorkflowNodeRun, error)
 	WorkflowNodeRun(projectKey string, name string, number int64, nodeRunID int64) (*sdk.WorkflowNodeRun, error)
 	WorkflowNodeRunArtifactDownload(projectKey string, name string, a sdk.WorkflowNodeRunArtifact, w io.Writer) error
 	WorkflowNodeRunJobStep(projectKey string, workflowName string, number int64, nodeRunID, job int64, step int) (*sdk.BuildState, error)
+	WorkflowNodeRunJobStepLines(projectKey string, workflowName string, number int64, nodeRunID, job int64, step int, offset, limit int64) (*sdk.LogLines, error)
+	WorkflowNodeRunLogsSearch(projectKey string, workflowName string, number int64, nodeRunID int64, regex string, limit int) ([]sdk.LogLine, error)
 	WorkflowNodeRunRelease(projectKey string, workflowName string, runNumber int64, nodeRunID int64, release sdk.WorkflowNodeRunRelease) error
+	WorkflowNotificationDeliveries(projectKey, workflowName string, limit int) ([]sdk.WorkflowNotificationDelivery, error)
+	WorkflowApprovalAudits(projectKey, workflowName string) ([]sdk.WorkflowNodeRunApprovalAudit, error)
 	WorkflowAllHooksList() ([]sdk.WorkflowNodeHook, error)
 	WorkflowCachePush(projectKey, ref string, tarContent io.Reader) error
 	WorkflowCachePull(projectKey, ref string) (io.Reader, error)
@@ -289,6 +326,21 @@ type PlatformClient interface {
 	PlatformModelDelete(name string) error
 }
 
+// ProjectRoleBindingsClient exposes project role bindings related functions
+type ProjectRoleBindingsClient interface {
+	ProjectRoleBindingsList(projectKey string) ([]sdk.RoleBinding, error)
+	ProjectRoleBindingCreate(projectKey string, b *sdk.RoleBinding) error
+	ProjectRoleBindingDelete(projectKey string, id int64) error
+}
+
+// RoleClient exposes roles related functions
+type RoleClient interface {
+	RoleList() ([]sdk.Role, error)
+	RoleCreate(r *sdk.Role) error
+	RoleUpdate(r *sdk.Role) error
+	RoleDelete(name string) error
+}
+
 // Interface is the main interface for cdsclient package
 type Interface interface {
 	ActionClient
@@ -298,6 +350,7 @@ type Interface interface {
 	ConfigUser() (map[string]string, error)
 	DownloadClient
 	EnvironmentClient
+	EventsListen(ctx context.Context, events chan<- sdk.Event)
 	ExportImportInterface
 	GroupClient
 	GRPCPluginsClient
@@ -310,8 +363,10 @@ type Interface interface {
 	Navbar() ([]sdk.NavbarProjectData, error)
 	Requirements() ([]sdk.Requirement, error)
 	RepositoriesManagerInterface
+	RoleClient
 	GetService() *sdk.Service
 	ServiceRegister(sdk.Service) (string, error)
+	TemplateClient
 	UserClient
 	WorkerClient
 	WorkflowClient
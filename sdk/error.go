@@ -159,6 +159,34 @@ var (
 	ErrIconBadSize                            = Error{ID: 142, Status: http.StatusBadRequest}
 	ErrWorkflowConditionBadOperator           = Error{ID: 143, Status: http.StatusBadRequest}
 	ErrColorBadFormat                         = Error{ID: 144, Status: http.StatusBadRequest}
+	ErrWorkflowConditionBadExpression         = Error{ID: 145, Status: http.StatusBadRequest}
+	ErrInvalidJobMatrix                       = Error{ID: 146, Status: http.StatusBadRequest}
+	ErrInvalidRetryPolicy                     = Error{ID: 147, Status: http.StatusBadRequest}
+	ErrWorkflowTemplateNotFound               = Error{ID: 148, Status: http.StatusNotFound}
+	ErrInvalidWorkflowTemplate                = Error{ID: 149, Status: http.StatusBadRequest}
+	ErrInvalidWorkflowTemplateParameters      = Error{ID: 150, Status: http.StatusBadRequest}
+	ErrInvalidWebhookNotification             = Error{ID: 151, Status: http.StatusBadRequest}
+	ErrInvalidApprovalGate                    = Error{ID: 152, Status: http.StatusBadRequest}
+	ErrApprovalNotPending                     = Error{ID: 153, Status: http.StatusConflict}
+	ErrApprovalForbidden                      = Error{ID: 154, Status: http.StatusForbidden}
+	ErrApprovalAlreadyDone                    = Error{ID: 155, Status: http.StatusConflict}
+	ErrFreezeWindowNotFound                   = Error{ID: 156, Status: http.StatusNotFound}
+	ErrInvalidFreezeWindow                    = Error{ID: 157, Status: http.StatusBadRequest}
+	ErrInvalidConcurrencyGroup                = Error{ID: 158, Status: http.StatusBadRequest}
+	ErrOIDCNotEnabled                         = Error{ID: 159, Status: http.StatusNotFound}
+	ErrOIDCAuthorizationPending               = Error{ID: 160, Status: http.StatusBadRequest}
+	ErrOIDCLoginFailed                        = Error{ID: 161, Status: http.StatusUnauthorized}
+	ErrInvalidAccessToken                     = Error{ID: 162, Status: http.StatusBadRequest}
+	ErrAccessTokenNotFound                    = Error{ID: 163, Status: http.StatusNotFound}
+	ErrAccessTokenForbidden                   = Error{ID: 164, Status: http.StatusForbidden}
+	ErrInvalidRole                            = Error{ID: 165, Status: http.StatusBadRequest}
+	ErrRoleNotFound                           = Error{ID: 166, Status: http.StatusNotFound}
+	ErrRoleUsed                               = Error{ID: 167, Status: http.StatusConflict}
+	ErrInvalidVaultReference                  = Error{ID: 168, Status: http.StatusBadRequest}
+	ErrVaultSecret                            = Error{ID: 169, Status: http.StatusBadGateway}
+	ErrSecretReencryptionRunning              = Error{ID: 170, Status: http.StatusConflict}
+	ErrJobNotQueuedYet                        = Error{ID: 171, Status: http.StatusConflict}
+	ErrFreezeWindowActive                     = Error{ID: 172, Status: http.StatusForbidden}
 )
 
 var errorsAmericanEnglish = map[int]string{
@@ -304,6 +332,34 @@ var errorsAmericanEnglish = map[int]string{
 	ErrIconBadSize.ID:                            "Bad icon size. Must be lower than 100Ko",
 	ErrWorkflowConditionBadOperator.ID:           "Your run conditions have bad operator",
 	ErrColorBadFormat.ID:                         "The format of color isn't correct. You must use hexadecimal format (example: #FFFF)",
+	ErrWorkflowConditionBadExpression.ID:         "Your run conditions have an invalid expression",
+	ErrInvalidJobMatrix.ID:                       "Invalid job matrix",
+	ErrInvalidRetryPolicy.ID:                     "Invalid retry policy",
+	ErrWorkflowTemplateNotFound.ID:               "Workflow template not found",
+	ErrInvalidWorkflowTemplate.ID:                "Invalid workflow template",
+	ErrInvalidWorkflowTemplateParameters.ID:      "Invalid workflow template parameters",
+	ErrInvalidWebhookNotification.ID:             "Invalid webhook notification",
+	ErrInvalidApprovalGate.ID:                    "Invalid approval gate",
+	ErrApprovalNotPending.ID:                     "The approval gate is not pending",
+	ErrApprovalForbidden.ID:                      "You are not allowed to approve this pipeline",
+	ErrApprovalAlreadyDone.ID:                    "You have already approved this pipeline",
+	ErrFreezeWindowNotFound.ID:                   "Freeze window not found",
+	ErrInvalidFreezeWindow.ID:                    "Invalid freeze window",
+	ErrInvalidConcurrencyGroup.ID:                "Invalid concurrency group",
+	ErrOIDCNotEnabled.ID:                         "OpenID Connect authentication is not enabled",
+	ErrOIDCAuthorizationPending.ID:               "Authorization is pending, the user has not completed the login yet",
+	ErrOIDCLoginFailed.ID:                        "OpenID Connect authentication failed",
+	ErrInvalidAccessToken.ID:                     "Invalid access token",
+	ErrAccessTokenNotFound.ID:                    "Access token not found",
+	ErrAccessTokenForbidden.ID:                   "Action not allowed by the scope of the access token",
+	ErrInvalidRole.ID:                            "Invalid role",
+	ErrRoleNotFound.ID:                           "Role not found",
+	ErrRoleUsed.ID:                               "Role is still bound to groups",
+	ErrInvalidVaultReference.ID:                  "Invalid Vault reference, it should be <mount>/<path>#<field>",
+	ErrVaultSecret.ID:                            "Unable to read the secret from Vault",
+	ErrSecretReencryptionRunning.ID:              "The re-encryption of the secrets is already running",
+	ErrJobNotQueuedYet.ID:                        "Job is waiting for the backoff delay of its retry policy",
+	ErrFreezeWindowActive.ID:                     "An active freeze window can only be updated or deleted by a CDS administrator",
 }
 
 var errorsFrench = map[int]string{
@@ -449,6 +505,34 @@ var errorsFrench = map[int]string{
 	ErrIconBadSize.ID:                            "Taille de l'icône trop importante. (max 100Ko)",
 	ErrWorkflowConditionBadOperator.ID:           "Opérateur de condition de lancement incorrect",
 	ErrColorBadFormat.ID:                         "Format de la couleur incorrect. Vous devez utiliser le format hexadécimal (exemple: #FFFF)",
+	ErrWorkflowConditionBadExpression.ID:         "Expression de condition de lancement invalide",
+	ErrInvalidJobMatrix.ID:                       "Matrice du job invalide",
+	ErrInvalidRetryPolicy.ID:                     "Politique de relance invalide",
+	ErrWorkflowTemplateNotFound.ID:               "Modèle de workflow non trouvé",
+	ErrInvalidWorkflowTemplate.ID:                "Modèle de workflow invalide",
+	ErrInvalidWorkflowTemplateParameters.ID:      "Paramètres du modèle de workflow invalides",
+	ErrInvalidWebhookNotification.ID:             "Notification webhook invalide",
+	ErrInvalidApprovalGate.ID:                    "Validation manuelle invalide",
+	ErrApprovalNotPending.ID:                     "La validation manuelle n'est pas en attente",
+	ErrApprovalForbidden.ID:                      "Vous n'êtes pas autorisé à valider ce pipeline",
+	ErrApprovalAlreadyDone.ID:                    "Vous avez déjà validé ce pipeline",
+	ErrFreezeWindowNotFound.ID:                   "Période de gel introuvable",
+	ErrInvalidFreezeWindow.ID:                    "Période de gel invalide",
+	ErrInvalidConcurrencyGroup.ID:                "Groupe de concurrence invalide",
+	ErrOIDCNotEnabled.ID:                         "L'authentification OpenID Connect n'est pas activée",
+	ErrOIDCAuthorizationPending.ID:               "Autorisation en attente, l'utilisateur n'a pas encore terminé sa connexion",
+	ErrOIDCLoginFailed.ID:                        "L'authentification OpenID Connect a échoué",
+	ErrInvalidAccessToken.ID:                     "Jeton d'accès invalide",
+	ErrAccessTokenNotFound.ID:                    "Jeton d'accès introuvable",
+	ErrAccessTokenForbidden.ID:                   "Action non autorisée par la portée du jeton d'accès",
+	ErrInvalidRole.ID:                            "Rôle invalide",
+	ErrRoleNotFound.ID:                           "Rôle introuvable",
+	ErrRoleUsed.ID:                               "Le rôle est encore attribué à des groupes",
+	ErrInvalidVaultReference.ID:                  "Référence Vault invalide, elle doit être de la forme <mount>/<path>#<field>",
+	ErrVaultSecret.ID:                            "Impossible de lire le secret dans Vault",
+	ErrSecretReencryptionRunning.ID:              "Le rechiffrement des secrets est déjà en cours",
+	ErrJobNotQueuedYet.ID:                        "Le job attend le délai de sa politique de relance",
+	ErrFreezeWindowActive.ID:                     "Une période de gel active ne peut être modifiée ou supprimée que par un administrateur CDS",
 }
 
 var errorsLanguages = []map[int]string{
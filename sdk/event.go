@@ -76,6 +76,10 @@ type EventRunWorkflowNode struct {
 	BranchName            string                    `json:"branch_name"`
 	NodeName              string                    `json:"node_name"`
 	StagesSummary         []StageSummary            `json:"stages_summary"`
+	// Approval fields are set if the node has an approval gate
+	ApprovalStatus    string   `json:"approval_status,omitempty"`
+	Approvers         []string `json:"approvers,omitempty"`
+	RequiredApprovers int      `json:"required_approvers,omitempty"`
 }
 
 // EventRunWorkflowJob contains event data for a workflow job node run
@@ -84,6 +88,9 @@ type EventRunWorkflowJob struct {
 	Status string `json:"status,omitempty"`
 	Start  int64  `json:"start,omitempty"`
 	Done   int64  `json:"done,omitempty"`
+	// Attempt is set when the job has been retried by its retry policy
+	Attempt     int         `json:"attempt,omitempty"`
+	LastAttempt *JobAttempt `json:"last_attempt,omitempty"`
 }
 
 // EventRunWorkflow contains event data for a workflow run
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/mitchellh/mapstructure"
 
@@ -75,6 +76,12 @@ func newSteps(a sdk.Action) []Step {
 		if act.AlwaysExecuted {
 			s["always_executed"] = act.AlwaysExecuted
 		}
+		if act.Timeout != 0 {
+			s["timeout"] = (time.Duration(act.Timeout) * time.Second).String()
+		}
+		if act.RetryPolicy != nil {
+			s["retry"] = newRetryPolicy(act.RetryPolicy)
+		}
 
 		switch act.Type {
 		case sdk.BuiltinAction:
@@ -635,6 +642,47 @@ func (s Step) IsFlagged(flag string) (bool, error) {
 	return bS, nil
 }
 
+// Timeout returns the timeout of the step in seconds, 0 if not set
+func (s Step) Timeout() (int64, error) {
+	t, ok := s["timeout"]
+	if !ok {
+		return 0, nil
+	}
+	tS, ok := t.(string)
+	if !ok {
+		return 0, fmt.Errorf("Malformatted Step : timeout must be a duration, ie: 10m")
+	}
+	d, err := time.ParseDuration(tS)
+	if err != nil || d < time.Second {
+		return 0, fmt.Errorf("Malformatted Step : invalid timeout %s", tS)
+	}
+	return int64(d / time.Second), nil
+}
+
+// RetryPolicy returns the retry policy of the step, nil if not set
+func (s Step) RetryPolicy() (*sdk.RetryPolicy, error) {
+	r, ok := s["retry"]
+	if !ok {
+		return nil, nil
+	}
+	var retry RetryPolicy
+	switch v := r.(type) {
+	case RetryPolicy:
+		retry = v
+	case *RetryPolicy:
+		retry = *v
+	default:
+		if err := mapstructure.Decode(r, &retry); err != nil {
+			return nil, fmt.Errorf("Malformatted Step : invalid retry: %v", err)
+		}
+	}
+	p, err := computeRetryPolicy(retry)
+	if err != nil {
+		return nil, fmt.Errorf("Malformatted Step : invalid retry: %v", err)
+	}
+	return p, nil
+}
+
 // Name returns true the step name if exist
 func (s Step) Name() (string, error) {
 	if stepAttr, ok := s["name"]; ok {
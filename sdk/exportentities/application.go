@@ -12,6 +12,7 @@ type Application struct {
 	VCSServer            string                              `json:"vcs_server,omitempty" yaml:"vcs_server,omitempty"`
 	RepositoryName       string                              `json:"repo,omitempty" yaml:"repo,omitempty"`
 	Permissions          map[string]int                      `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+	Roles                map[string][]string                 `json:"roles,omitempty" yaml:"roles,omitempty"`
 	Variables            map[string]VariableValue            `json:"variables,omitempty" yaml:"variables,omitempty"`
 	Keys                 map[string]KeyValue                 `json:"keys,omitempty" yaml:"keys,omitempty"`
 	VCSConnectionType    string                              `json:"vcs_connection_type,omitempty" yaml:"vcs_connection_type,omitempty"`
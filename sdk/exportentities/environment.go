@@ -10,6 +10,7 @@ type Environment struct {
 	Values      map[string]VariableValue `json:"values,omitempty" yaml:"values,omitempty"`
 	Keys        map[string]KeyValue      `json:"keys,omitempty" yaml:"keys,omitempty"`
 	Permissions map[string]int           `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+	Roles       map[string][]string      `json:"roles,omitempty" yaml:"roles,omitempty"`
 }
 
 //NewEnvironment returns an Environment from an sdk.Environment pointer
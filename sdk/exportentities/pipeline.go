@@ -5,6 +5,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ovh/cds/sdk"
 )
@@ -59,6 +60,29 @@ type Job struct {
 	Requirements   []Requirement `json:"requirements,omitempty" yaml:"requirements,omitempty"`
 	Optional       *bool         `json:"optional,omitempty" yaml:"optional,omitempty"`
 	AlwaysExecuted *bool         `json:"always_executed,omitempty" yaml:"always_executed,omitempty"`
+	Matrix         *JobMatrix    `json:"matrix,omitempty" yaml:"matrix,omitempty"`
+	Timeout        string        `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Retry          *RetryPolicy  `json:"retry,omitempty" yaml:"retry,omitempty"`
+}
+
+// JobMatrix represents exported sdk.JobMatrix
+type JobMatrix struct {
+	Variables map[string][]string `json:"variables,omitempty" yaml:"variables,omitempty"`
+	Overrides []JobMatrixOverride `json:"overrides,omitempty" yaml:"overrides,omitempty"`
+}
+
+// JobMatrixOverride represents exported sdk.JobMatrixOverride
+type JobMatrixOverride struct {
+	When         map[string]string `json:"when,omitempty" yaml:"when,omitempty"`
+	Requirements []Requirement     `json:"requirements,omitempty" yaml:"requirements,omitempty"`
+}
+
+// RetryPolicy represents exported sdk.RetryPolicy
+type RetryPolicy struct {
+	MaxAttempts int      `json:"max_attempts,omitempty" yaml:"max_attempts,omitempty" mapstructure:"max_attempts"`
+	Backoff     string   `json:"backoff,omitempty" yaml:"backoff,omitempty" mapstructure:"backoff"`
+	On          []string `json:"on,omitempty" yaml:"on,omitempty" mapstructure:"on"`
+	ExitCodes   []int    `json:"exit_codes,omitempty" yaml:"exit_codes,omitempty" mapstructure:"exit_codes"`
 }
 
 // Step represents exported step used in a job
@@ -68,7 +92,7 @@ type Step map[string]interface{}
 func (s Step) IsValid() bool {
 	keys := []string{}
 	for k := range s {
-		if k != "enabled" && k != "optional" && k != "always_executed" && k != "name" {
+		if k != "enabled" && k != "optional" && k != "always_executed" && k != "name" && k != "timeout" && k != "retry" {
 			keys = append(keys, k)
 		}
 	}
@@ -78,7 +102,7 @@ func (s Step) IsValid() bool {
 func (s Step) key() string {
 	keys := []string{}
 	for k := range s {
-		if k != "enabled" && k != "optional" && k != "always_executed" && k != "name" {
+		if k != "enabled" && k != "optional" && k != "always_executed" && k != "name" && k != "timeout" && k != "retry" {
 			keys = append(keys, k)
 		}
 	}
@@ -94,6 +118,7 @@ type Requirement struct {
 	Plugin   string             `json:"plugin,omitempty" yaml:"plugin,omitempty"`
 	Service  ServiceRequirement `json:"service,omitempty" yaml:"service,omitempty"`
 	Memory   string             `json:"memory,omitempty" yaml:"memory,omitempty"`
+	OSArch   string             `json:"os-architecture,omitempty" yaml:"os-architecture,omitempty"`
 }
 
 // ServiceRequirement represents an exported sdk.Requirement of type ServiceRequirement
@@ -218,6 +243,10 @@ func NewPipeline(pip sdk.Pipeline, withPermission bool) (p *Pipeline) {
 			case 0:
 				return
 			case 1:
+				if pip.Stages[0].Jobs[0].Matrix != nil || pip.Stages[0].Jobs[0].Timeout != 0 || pip.Stages[0].Jobs[0].RetryPolicy != nil {
+					p.Jobs = newJobs(pip.Stages[0].Jobs)
+					return
+				}
 				p.Steps = newSteps(pip.Stages[0].Jobs[0].Action)
 				p.Requirements = newRequirements(pip.Stages[0].Jobs[0].Action.Requirements)
 				return
@@ -280,6 +309,8 @@ func newRequirements(req []sdk.Requirement) []Requirement {
 			res = append(res, Requirement{Service: ServiceRequirement{Name: r.Name, Value: r.Value}})
 		case sdk.MemoryRequirement:
 			res = append(res, Requirement{Memory: r.Value})
+		case sdk.OSArchRequirement:
+			res = append(res, Requirement{OSArch: r.Value})
 		}
 	}
 	return res
@@ -293,6 +324,24 @@ func newJob(j sdk.Job) Job {
 	jo.Steps = newSteps(j.Action)
 	jo.Description = j.Action.Description
 	jo.Requirements = newRequirements(j.Action.Requirements)
+	if j.Timeout != 0 {
+		jo.Timeout = (time.Duration(j.Timeout) * time.Second).String()
+	}
+	jo.Retry = newRetryPolicy(j.RetryPolicy)
+	if j.Matrix != nil {
+		jo.Matrix = &JobMatrix{
+			Variables: make(map[string][]string, len(j.Matrix.Variables)),
+		}
+		for _, v := range j.Matrix.Variables {
+			jo.Matrix.Variables[v.Name] = v.Values
+		}
+		for _, o := range j.Matrix.Overrides {
+			jo.Matrix.Overrides = append(jo.Matrix.Overrides, JobMatrixOverride{
+				When:         o.When,
+				Requirements: newRequirements(o.Requirements),
+			})
+		}
+	}
 	return jo
 }
 
@@ -455,6 +504,12 @@ func computeSteps(steps []Step) ([]sdk.Action, error) {
 		if err != nil {
 			return nil, err
 		}
+		if a.Timeout, err = s.Timeout(); err != nil {
+			return nil, err
+		}
+		if a.RetryPolicy, err = s.RetryPolicy(); err != nil {
+			return nil, err
+		}
 		res[i] = *a
 	}
 	return res, nil
@@ -547,6 +602,10 @@ func computeJobRequirements(req []Requirement) []sdk.Requirement {
 			name = r.Service.Name
 			val = r.Service.Value
 			tpe = sdk.ServiceRequirement
+		} else if r.OSArch != "" {
+			name = r.OSArch
+			val = r.OSArch
+			tpe = sdk.OSArchRequirement
 		}
 		res[i] = sdk.Requirement{
 			Name:  name,
@@ -573,6 +632,29 @@ func computeJob(name string, j Job) (*sdk.Job, error) {
 	job.Action.Enabled = job.Enabled
 	job.Action.Requirements = computeJobRequirements(j.Requirements)
 
+	if j.Timeout != "" {
+		d, err := time.ParseDuration(j.Timeout)
+		if err != nil || d < time.Second {
+			return nil, fmt.Errorf("invalid timeout on job %s: %s", name, j.Timeout)
+		}
+		job.Timeout = int64(d / time.Second)
+	}
+
+	if j.Retry != nil {
+		p, err := computeRetryPolicy(*j.Retry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retry on job %s: %v", name, err)
+		}
+		job.RetryPolicy = p
+	}
+
+	if j.Matrix != nil {
+		job.Matrix = computeJobMatrix(*j.Matrix)
+		if err := job.Matrix.IsValid(); err != nil {
+			return nil, fmt.Errorf("invalid matrix on job %s: %v", name, err)
+		}
+	}
+
 	//Compute steps for the jobs
 	children, err := computeSteps(j.Steps)
 	if err != nil {
@@ -583,6 +665,61 @@ func computeJob(name string, j Job) (*sdk.Job, error) {
 	return &job, nil
 }
 
+func newRetryPolicy(p *sdk.RetryPolicy) *RetryPolicy {
+	if p == nil {
+		return nil
+	}
+	r := &RetryPolicy{
+		MaxAttempts: p.MaxAttempts,
+		On:          p.On,
+		ExitCodes:   p.ExitCodes,
+	}
+	if p.Backoff != 0 {
+		r.Backoff = (time.Duration(p.Backoff) * time.Second).String()
+	}
+	return r
+}
+
+// computeRetryPolicy returns the checked sdk.RetryPolicy
+func computeRetryPolicy(r RetryPolicy) (*sdk.RetryPolicy, error) {
+	p := &sdk.RetryPolicy{
+		MaxAttempts: r.MaxAttempts,
+		On:          r.On,
+		ExitCodes:   r.ExitCodes,
+	}
+	if r.Backoff != "" {
+		d, err := time.ParseDuration(r.Backoff)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid backoff %s", r.Backoff)
+		}
+		p.Backoff = int64(d / time.Second)
+	}
+	if err := p.IsValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// computeJobMatrix returns the matrix with its variables sorted by name
+func computeJobMatrix(m JobMatrix) *sdk.JobMatrix {
+	res := &sdk.JobMatrix{
+		Variables: make([]sdk.JobMatrixVariable, 0, len(m.Variables)),
+	}
+	for name, values := range m.Variables {
+		res.Variables = append(res.Variables, sdk.JobMatrixVariable{Name: name, Values: values})
+	}
+	sort.Slice(res.Variables, func(i, j int) bool {
+		return res.Variables[i].Name < res.Variables[j].Name
+	})
+	for _, o := range m.Overrides {
+		res.Overrides = append(res.Overrides, sdk.JobMatrixOverride{
+			When:         o.When,
+			Requirements: computeJobRequirements(o.Requirements),
+		})
+	}
+	return res
+}
+
 //Pipeline returns a sdk.Pipeline entity
 func (p PipelineV1) Pipeline() (pip *sdk.Pipeline, err error) {
 	pip = new(sdk.Pipeline)
@@ -538,6 +538,131 @@ steps:
 
 }
 
+func Test_ImportPipelineWithMatrix(t *testing.T) {
+	in := `name: build-all-images
+type: build
+jobs:
+  build:
+    requirements:
+    - binary: go
+    - os-architecture: linux/amd64
+    matrix:
+      variables:
+        go: ["1.10", "1.11"]
+        os: [linux, windows]
+      overrides:
+      - when:
+          os: windows
+        requirements:
+        - os-architecture: windows/amd64
+    steps:
+    - script: go version
+`
+
+	payload := &Pipeline{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	job := p.Stages[0].Jobs[0]
+	if !assert.NotNil(t, job.Matrix) {
+		t.FailNow()
+	}
+	assert.Equal(t, "go", job.Matrix.Variables[0].Name)
+	assert.Equal(t, "os", job.Matrix.Variables[1].Name)
+	assert.Len(t, job.Matrix.Variants(), 4)
+	assert.Equal(t, sdk.OSArchRequirement, job.Matrix.Overrides[0].Requirements[0].Type)
+
+	// the job is not collapsed into the pipeline on export
+	exported := NewPipelineV1(*p, false)
+	if assert.Len(t, exported.Jobs, 1) {
+		assert.Equal(t, []string{"linux", "windows"}, exported.Jobs[0].Matrix.Variables["os"])
+	}
+
+	payload.Jobs["build"].Matrix.Overrides[0].When["os"] = "darwin"
+	_, err = payload.Pipeline()
+	assert.Error(t, err)
+}
+
+func Test_ImportPipelineWithTimeout(t *testing.T) {
+	in := `name: build
+jobs:
+  build:
+    timeout: 1h30m
+    steps:
+    - script: make
+      timeout: 10m
+`
+
+	payload := &Pipeline{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	job := p.Stages[0].Jobs[0]
+	assert.Equal(t, int64(5400), job.Timeout)
+	assert.Equal(t, int64(600), job.Action.Actions[0].Timeout)
+
+	exported := NewPipelineV1(*p, false)
+	if assert.Len(t, exported.Jobs, 1) {
+		assert.Equal(t, "1h30m0s", exported.Jobs[0].Timeout)
+		assert.Equal(t, "10m0s", exported.Jobs[0].Steps[0]["timeout"])
+	}
+
+	j := payload.Jobs["build"]
+	j.Timeout = "soon"
+	payload.Jobs["build"] = j
+	_, err = payload.Pipeline()
+	assert.Error(t, err)
+}
+
+func Test_ImportPipelineWithRetry(t *testing.T) {
+	in := `name: build
+jobs:
+  build:
+    retry:
+      max_attempts: 3
+      backoff: 30s
+      on: [failure, worker_lost]
+      exit_codes: [137]
+    steps:
+    - script: make
+      retry:
+        max_attempts: 2
+`
+
+	payload := &Pipeline{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	job := p.Stages[0].Jobs[0]
+	if assert.NotNil(t, job.RetryPolicy) {
+		assert.Equal(t, 3, job.RetryPolicy.MaxAttempts)
+		assert.Equal(t, int64(30), job.RetryPolicy.Backoff)
+		assert.Equal(t, []string{sdk.RetryOnFailure, sdk.RetryOnWorkerLost}, job.RetryPolicy.On)
+		assert.Equal(t, []int{137}, job.RetryPolicy.ExitCodes)
+	}
+	if assert.NotNil(t, job.Action.Actions[0].RetryPolicy) {
+		assert.Equal(t, 2, job.Action.Actions[0].RetryPolicy.MaxAttempts)
+	}
+
+	exported := NewPipelineV1(*p, false)
+	if assert.Len(t, exported.Jobs, 1) && assert.NotNil(t, exported.Jobs[0].Retry) {
+		assert.Equal(t, "30s", exported.Jobs[0].Retry.Backoff)
+		assert.Equal(t, 3, exported.Jobs[0].Retry.MaxAttempts)
+	}
+
+	j := payload.Jobs["build"]
+	j.Retry.On = []string{"rain"}
+	payload.Jobs["build"] = j
+	_, err = payload.Pipeline()
+	assert.Error(t, err)
+}
+
 func Test_ImportPipelineWithGitClone(t *testing.T) {
 	in := `name: build-all-images
 requirements:
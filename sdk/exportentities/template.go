@@ -0,0 +1,57 @@
+package exportentities
+
+import (
+	"github.com/ovh/cds/sdk"
+)
+
+// Template is a struct to export sdk.WorkflowTemplate
+type Template struct {
+	Name         string              `json:"name" yaml:"name"`
+	Description  string              `json:"description,omitempty" yaml:"description,omitempty"`
+	Parameters   []TemplateParameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Workflow     string              `json:"workflow" yaml:"workflow"`
+	Pipelines    []string            `json:"pipelines,omitempty" yaml:"pipelines,omitempty"`
+	Applications []string            `json:"applications,omitempty" yaml:"applications,omitempty"`
+	Environments []string            `json:"environments,omitempty" yaml:"environments,omitempty"`
+}
+
+// TemplateParameter is a struct to export sdk.WorkflowTemplateParameter
+type TemplateParameter struct {
+	Key         string `json:"key" yaml:"key"`
+	Type        string `json:"type" yaml:"type"`
+	Required    bool   `json:"required,omitempty" yaml:"required,omitempty"`
+	Default     string `json:"default,omitempty" yaml:"default,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// NewTemplate returns a Template from an sdk.WorkflowTemplate
+func NewTemplate(t sdk.WorkflowTemplate) Template {
+	e := Template{
+		Name:         t.Name,
+		Description:  t.Description,
+		Workflow:     t.Workflow,
+		Pipelines:    t.Pipelines,
+		Applications: t.Applications,
+		Environments: t.Environments,
+	}
+	for _, p := range t.Parameters {
+		e.Parameters = append(e.Parameters, TemplateParameter(p))
+	}
+	return e
+}
+
+// WorkflowTemplate returns a sdk.WorkflowTemplate
+func (t Template) WorkflowTemplate() sdk.WorkflowTemplate {
+	wt := sdk.WorkflowTemplate{
+		Name:         t.Name,
+		Description:  t.Description,
+		Workflow:     t.Workflow,
+		Pipelines:    t.Pipelines,
+		Applications: t.Applications,
+		Environments: t.Environments,
+	}
+	for _, p := range t.Parameters {
+		wt.Parameters = append(wt.Parameters, sdk.WorkflowTemplateParameter(p))
+	}
+	return wt
+}
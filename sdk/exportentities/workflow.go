@@ -9,6 +9,7 @@ import (
 	"github.com/fsamin/go-dump"
 
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/expression"
 )
 
 // Workflow is the "as code" representation of a sdk.Workflow
@@ -31,24 +32,29 @@ type Workflow struct {
 	ProjectPlatformName string                      `json:"platform,omitempty" yaml:"platform,omitempty"`
 	PipelineHooks       []HookEntry                 `json:"pipeline_hooks,omitempty" yaml:"pipeline_hooks,omitempty"`
 	Permissions         map[string]int              `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+	Roles               map[string][]string         `json:"roles,omitempty" yaml:"roles,omitempty"`
 	Metadata            map[string]string           `json:"metadata,omitempty" yaml:"metadata,omitempty" db:"-"`
 	PurgeTags           []string                    `json:"purge_tags,omitempty" yaml:"purge_tags,omitempty" db:"-"`
 	HistoryLength       int64                       `json:"history_length,omitempty" yaml:"history_length,omitempty" db:"-"`
+	// CancelSupersededRuns stops the runs of the same branch when a new run is triggered by a hook
+	CancelSupersededRuns bool `json:"cancel_superseded_runs,omitempty" yaml:"cancel_superseded_runs,omitempty" db:"-"`
 }
 
 // NodeEntry represents a node as code
 type NodeEntry struct {
-	ID                  int64                       `json:"-" yaml:"-"`
-	DependsOn           []string                    `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
-	Conditions          *sdk.WorkflowNodeConditions `json:"conditions,omitempty" yaml:"conditions,omitempty"`
-	When                []string                    `json:"when,omitempty" yaml:"when,omitempty"` //This is use only for manual and success condition
-	PipelineName        string                      `json:"pipeline,omitempty" yaml:"pipeline,omitempty"`
-	ApplicationName     string                      `json:"application,omitempty" yaml:"application,omitempty"`
-	EnvironmentName     string                      `json:"environment,omitempty" yaml:"environment,omitempty"`
-	ProjectPlatformName string                      `json:"platform,omitempty" yaml:"platform,omitempty"`
-	OneAtATime          *bool                       `json:"one_at_a_time,omitempty" yaml:"one_at_a_time,omitempty"`
-	Payload             map[string]interface{}      `json:"payload,omitempty" yaml:"payload,omitempty"`
-	Parameters          map[string]string           `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	ID                  int64                        `json:"-" yaml:"-"`
+	DependsOn           []string                     `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+	Conditions          *sdk.WorkflowNodeConditions  `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	When                []string                     `json:"when,omitempty" yaml:"when,omitempty"` //This is use only for manual and success condition
+	PipelineName        string                       `json:"pipeline,omitempty" yaml:"pipeline,omitempty"`
+	ApplicationName     string                       `json:"application,omitempty" yaml:"application,omitempty"`
+	EnvironmentName     string                       `json:"environment,omitempty" yaml:"environment,omitempty"`
+	ProjectPlatformName string                       `json:"platform,omitempty" yaml:"platform,omitempty"`
+	OneAtATime          *bool                        `json:"one_at_a_time,omitempty" yaml:"one_at_a_time,omitempty"`
+	Approval            *sdk.WorkflowNodeApproval    `json:"approval,omitempty" yaml:"approval,omitempty"`
+	Concurrency         *sdk.WorkflowNodeConcurrency `json:"concurrency,omitempty" yaml:"concurrency,omitempty"`
+	Payload             map[string]interface{}       `json:"payload,omitempty" yaml:"payload,omitempty"`
+	Parameters          map[string]string            `json:"parameters,omitempty" yaml:"parameters,omitempty"`
 }
 
 // HookEntry represents a hook as code
@@ -88,6 +94,7 @@ func NewWorkflow(w sdk.Workflow, withPermission bool) (Workflow, error) {
 	}
 
 	exportedWorkflow.PurgeTags = w.PurgeTags
+	exportedWorkflow.CancelSupersededRuns = w.CancelSupersededRuns
 	nodes := w.Nodes(false)
 
 	if withPermission {
@@ -128,9 +135,10 @@ func NewWorkflow(w sdk.Workflow, withPermission bool) (Workflow, error) {
 			}
 		}
 
-		if len(conditions) > 0 || n.Context.Conditions.LuaScript != "" {
+		if len(conditions) > 0 || n.Context.Conditions.Expression != "" || n.Context.Conditions.LuaScript != "" {
 			entry.Conditions = &sdk.WorkflowNodeConditions{
 				PlainConditions: conditions,
+				Expression:      n.Context.Conditions.Expression,
 				LuaScript:       n.Context.Conditions.LuaScript,
 			}
 		}
@@ -149,6 +157,14 @@ func NewWorkflow(w sdk.Workflow, withPermission bool) (Workflow, error) {
 			entry.OneAtATime = &n.Context.Mutex
 		}
 
+		if n.Context.Approval != nil {
+			entry.Approval = n.Context.Approval
+		}
+
+		if n.Context.Concurrency != nil {
+			entry.Concurrency = n.Context.Concurrency
+		}
+
 		if n.Context.HasDefaultPayload() {
 			enc := dump.NewDefaultEncoder(nil)
 			enc.ExtraFields.DetailedMap = false
@@ -186,7 +202,7 @@ func NewWorkflow(w sdk.Workflow, withPermission bool) (Workflow, error) {
 		exportedWorkflow.EnvironmentName = entry.EnvironmentName
 		exportedWorkflow.ProjectPlatformName = entry.ProjectPlatformName
 		exportedWorkflow.DependsOn = entry.DependsOn
-		if entry.Conditions != nil && (len(entry.Conditions.PlainConditions) > 0 || entry.Conditions.LuaScript != "") {
+		if entry.Conditions != nil && (len(entry.Conditions.PlainConditions) > 0 || entry.Conditions.Expression != "" || entry.Conditions.LuaScript != "") {
 			exportedWorkflow.When = entry.When
 			exportedWorkflow.Conditions = entry.Conditions
 		}
@@ -297,6 +313,35 @@ func (w Workflow) checkValidity() error {
 		}
 	}
 
+	for name, e := range w.Entries() {
+		if e.Approval != nil {
+			if err := e.Approval.IsValid(); err != nil {
+				mError.Append(fmt.Errorf("Error: wrong usage: invalid approval of %s: %v", name, err))
+			}
+		}
+		if e.Concurrency != nil {
+			if err := e.Concurrency.IsValid(); err != nil {
+				mError.Append(fmt.Errorf("Error: wrong usage: invalid concurrency of %s: %v", name, err))
+			}
+		}
+		if e.Conditions == nil {
+			continue
+		}
+		for _, c := range e.Conditions.PlainConditions {
+			if _, ok := sdk.WorkflowConditionsOperators[c.Operator]; !ok {
+				mError.Append(fmt.Errorf("Error: wrong usage: invalid operator %s in conditions of %s", c.Operator, name))
+			}
+		}
+		if e.Conditions.Expression != "" {
+			if e.Conditions.LuaScript != "" {
+				mError.Append(fmt.Errorf("Error: wrong usage: expression and script are not allowed together in conditions of %s", name))
+			}
+			if err := expression.Check(e.Conditions.Expression); err != nil {
+				mError.Append(fmt.Errorf("Error: invalid expression in conditions of %s at %v", name, err))
+			}
+		}
+	}
+
 	if mError.IsEmpty() {
 		return nil
 	}
@@ -346,6 +391,7 @@ func (w Workflow) GetWorkflow() (*sdk.Workflow, error) {
 		return nil, err
 	}
 	wf.PurgeTags = w.PurgeTags
+	wf.CancelSupersededRuns = w.CancelSupersededRuns
 	if len(w.Metadata) > 0 {
 		wf.Metadata = make(map[string]string, len(w.Metadata))
 		for k, v := range w.Metadata {
@@ -478,6 +524,14 @@ func (e *NodeEntry) getNode(name string) (*sdk.WorkflowNode, error) {
 		node.Context.Mutex = *e.OneAtATime
 	}
 
+	if e.Approval != nil {
+		node.Context.Approval = e.Approval
+	}
+
+	if e.Concurrency != nil {
+		node.Context.Concurrency = e.Concurrency
+	}
+
 	return node, nil
 }
 
@@ -494,6 +548,12 @@ func (w *Workflow) processHooks(n *sdk.WorkflowNode) {
 					} else {
 						hType = sdk.HookConfigTypeString
 					}
+				case sdk.WebHookModelName:
+					if k == sdk.WebHookModelConfigSecret {
+						hType = sdk.HookConfigTypePassword
+					} else {
+						hType = sdk.HookConfigTypeString
+					}
 				default:
 					hType = sdk.HookConfigTypeString
 				}
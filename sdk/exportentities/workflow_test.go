@@ -175,6 +175,62 @@ func TestWorkflow_checkValidity(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Should raise an error on unknown condition operator",
+			fields: fields{
+				PipelineName: "pipeline",
+				Conditions: &sdk.WorkflowNodeConditions{
+					PlainConditions: []sdk.WorkflowNodeCondition{
+						{Variable: "cds.version", Operator: "between", Value: "1"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should not raise an error with typed condition operators",
+			fields: fields{
+				PipelineName: "pipeline",
+				Conditions: &sdk.WorkflowNodeConditions{
+					PlainConditions: []sdk.WorkflowNodeCondition{
+						{Variable: "cds.version", Operator: sdk.WorkflowConditionsOperatorGreaterThan, Value: "9"},
+						{Variable: "git.branch", Operator: sdk.WorkflowConditionsOperatorIn, Value: "master,develop"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should raise an error on approval requiring more approvers than eligible users",
+			fields: fields{
+				Workflow: map[string]NodeEntry{
+					"root": NodeEntry{
+						PipelineName: "pipeline",
+					},
+					"deploy": NodeEntry{
+						PipelineName: "pipeline",
+						DependsOn:    []string{"root"},
+						Approval: &sdk.WorkflowNodeApproval{
+							Users:        []string{"alice", "bob"},
+							MinApprovers: 3,
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should raise an error on concurrency group with an unknown scope",
+			fields: fields{
+				Workflow: map[string]NodeEntry{
+					"root": NodeEntry{
+						PipelineName: "pipeline",
+						Concurrency:  &sdk.WorkflowNodeConcurrency{Group: "deploy-prod", Scope: "region"},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -614,3 +670,34 @@ func TestWorkflow_GetWorkflow(t *testing.T) {
 		})
 	}
 }
+
+func TestNewWorkflowMasksHookSecrets(t *testing.T) {
+	w := sdk.Workflow{
+		Name: "my-workflow",
+		Root: &sdk.WorkflowNode{
+			Name:         "root",
+			Ref:          "root",
+			Context:      &sdk.WorkflowNodeContext{},
+			PipelineName: "build",
+			Hooks: []sdk.WorkflowNodeHook{{
+				Ref:               "1",
+				WorkflowHookModel: sdk.WebHookModel,
+				Config: sdk.WorkflowNodeHookConfig{
+					sdk.WebHookModelConfigMethod: {Value: "POST", Configurable: true},
+					sdk.WebHookModelConfigSecret: {Value: "my-secret", Configurable: true, Type: sdk.HookConfigTypePassword},
+				},
+			}},
+		},
+	}
+
+	exported, err := NewWorkflow(w, false)
+	assert.NoError(t, err)
+	if assert.Len(t, exported.PipelineHooks, 1) {
+		assert.Equal(t, sdk.PasswordPlaceholder, exported.PipelineHooks[0].Config[sdk.WebHookModelConfigSecret])
+		assert.Equal(t, "POST", exported.PipelineHooks[0].Config[sdk.WebHookModelConfigMethod])
+	}
+
+	masked := w.WithMaskedSecrets()
+	assert.Equal(t, sdk.PasswordPlaceholder, masked.Root.Hooks[0].Config[sdk.WebHookModelConfigSecret].Value)
+	assert.Equal(t, "my-secret", w.Root.Hooks[0].Config[sdk.WebHookModelConfigSecret].Value)
+}
@@ -0,0 +1,292 @@
+// Package expression implements the boolean expression language used in workflow run conditions.
+//
+// Example: git.branch == "master" && (cds.manual || git.tag =~ "^v")
+//
+// Variables are referenced with their dotted name. All values are strings, a variable used
+// alone is true if its value is "true". Ordering operators (<, <=, >, >=) compare values as
+// integers, semantic versions, floats or dates when possible, =~ and !~ match a regular expression.
+package expression
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Error is a parsing error located at a 1-based position in the expression
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("position %d: %s", e.Pos, e.Msg)
+}
+
+// Expression is a parsed boolean expression
+type Expression struct {
+	raw  string
+	root node
+}
+
+// String returns the raw expression
+func (e *Expression) String() string {
+	return e.raw
+}
+
+// Eval evaluates the expression against given variables
+func (e *Expression) Eval(vars map[string]string) (bool, error) {
+	return e.root.eval(vars)
+}
+
+// Check parses the expression and only returns the parsing error if any
+func Check(input string) error {
+	_, err := Parse(input)
+	return err
+}
+
+// Eval parses and evaluates the expression against given variables
+func Eval(input string, vars map[string]string) (bool, error) {
+	e, err := Parse(input)
+	if err != nil {
+		return false, err
+	}
+	return e.Eval(vars)
+}
+
+// Parse parses an expression
+func Parse(input string) (*Expression, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, &Error{Pos: 1, Msg: "empty expression"}
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("unexpected %s", describe(t))}
+	}
+	return &Expression{raw: input, root: root}, nil
+}
+
+type node interface {
+	eval(vars map[string]string) (bool, error)
+}
+
+type orNode struct {
+	left, right node
+}
+
+func (n orNode) eval(vars map[string]string) (bool, error) {
+	l, err := n.left.eval(vars)
+	if err != nil || l {
+		return l, err
+	}
+	return n.right.eval(vars)
+}
+
+type andNode struct {
+	left, right node
+}
+
+func (n andNode) eval(vars map[string]string) (bool, error) {
+	l, err := n.left.eval(vars)
+	if err != nil || !l {
+		return false, err
+	}
+	return n.right.eval(vars)
+}
+
+type notNode struct {
+	x node
+}
+
+func (n notNode) eval(vars map[string]string) (bool, error) {
+	b, err := n.x.eval(vars)
+	return !b, err
+}
+
+type operand struct {
+	variable bool
+	value    string
+}
+
+func (o operand) get(vars map[string]string) string {
+	if o.variable {
+		return vars[o.value]
+	}
+	return o.value
+}
+
+// eval makes an operand used alone a boolean
+func (o operand) eval(vars map[string]string) (bool, error) {
+	return o.get(vars) == "true", nil
+}
+
+type compareNode struct {
+	op          tokenKind
+	left, right operand
+	regex       *regexp.Regexp
+}
+
+func (n compareNode) eval(vars map[string]string) (bool, error) {
+	l, r := n.left.get(vars), n.right.get(vars)
+	switch n.op {
+	case tokenEq:
+		return l == r, nil
+	case tokenNe:
+		return l != r, nil
+	case tokenLt:
+		return sdk.WorkflowConditionsCompare(l, r) < 0, nil
+	case tokenLe:
+		return sdk.WorkflowConditionsCompare(l, r) <= 0, nil
+	case tokenGt:
+		return sdk.WorkflowConditionsCompare(l, r) > 0, nil
+	case tokenGe:
+		return sdk.WorkflowConditionsCompare(l, r) >= 0, nil
+	case tokenMatch, tokenNotMatch:
+		re := n.regex
+		if re == nil {
+			var err error
+			re, err = regexp.Compile(r)
+			if err != nil {
+				return false, fmt.Errorf("Unable to match string with regex %s (%v)", r, err)
+			}
+		}
+		return re.MatchString(l) == (n.op == tokenMatch), nil
+	}
+	return false, fmt.Errorf("unsupported operator %s", n.op)
+}
+
+type parser struct {
+	tokens []token
+	i      int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokenEOF {
+		p.i++
+	}
+	return t
+}
+
+func describe(t token) string {
+	switch t.kind {
+	case tokenEOF:
+		return t.kind.String()
+	case tokenString:
+		return fmt.Sprintf("string %q", t.value)
+	case tokenIdent, tokenNumber:
+		return fmt.Sprintf("%s %s", t.kind, t.value)
+	}
+	return fmt.Sprintf("'%s'", t.value)
+}
+
+// or := and ( "||" and )*
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+// and := unary ( "&&" unary )*
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+// unary := "!" unary | "(" or ")" | comparison
+func (p *parser) parseUnary() (node, error) {
+	switch t := p.peek(); t.kind {
+	case tokenNot:
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{x: x}, nil
+	case tokenLParen:
+		p.next()
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, &Error{Pos: closing.pos, Msg: fmt.Sprintf("expected ')' to close '(' at position %d, got %s", t.pos, describe(closing))}
+		}
+		return x, nil
+	}
+	return p.parseComparison()
+}
+
+// comparison := operand ( op operand )?
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	op := p.peek()
+	switch op.kind {
+	case tokenEq, tokenNe, tokenLt, tokenLe, tokenGt, tokenGe, tokenMatch, tokenNotMatch:
+	default:
+		return left, nil
+	}
+	p.next()
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	n := compareNode{op: op.kind, left: left, right: right}
+	if (op.kind == tokenMatch || op.kind == tokenNotMatch) && !right.variable {
+		re, err := regexp.Compile(right.value)
+		if err != nil {
+			return nil, &Error{Pos: op.pos, Msg: fmt.Sprintf("invalid regular expression %q: %v", right.value, err)}
+		}
+		n.regex = re
+	}
+	return n, nil
+}
+
+// operand := variable | string | number | true | false
+func (p *parser) parseOperand() (operand, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenIdent:
+		return operand{variable: true, value: t.value}, nil
+	case tokenString, tokenNumber, tokenTrue, tokenFalse:
+		return operand{value: t.value}, nil
+	}
+	return operand{}, &Error{Pos: t.pos, Msg: fmt.Sprintf("expected variable or value, got %s", describe(t))}
+}
@@ -0,0 +1,67 @@
+package expression
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEval(t *testing.T) {
+	vars := map[string]string{
+		"git.branch":  "master",
+		"git.tag":     "v1.2.0",
+		"cds.manual":  "false",
+		"cds.status":  "Success",
+		"cds.version": "10",
+		"cds.my-var":  "it's ok",
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`git.branch == "master"`, true},
+		{`git.branch != 'master'`, false},
+		{`cds.manual`, false},
+		{`!cds.manual`, true},
+		{`cds.manual == false`, true},
+		{`git.branch == "master" && (cds.manual || git.tag =~ "^v")`, true},
+		{`git.branch == "develop" || cds.status == "Success" && cds.manual`, false},
+		{`(git.branch == "develop" || cds.status == "Success") && !cds.manual`, true},
+		{`cds.version > 9`, true},
+		{`cds.version >= 10 && cds.version < 11`, true},
+		{`git.tag !~ "-rc"`, true},
+		{`cds.my-var == "it's ok"`, true},
+		{`unknown.var == ""`, true},
+	}
+	for _, tt := range tests {
+		got, err := Eval(tt.expr, vars)
+		assert.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.want, got, tt.expr)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		pos  int
+	}{
+		{``, 1},
+		{`git.branch ==`, 14},
+		{`git.branch == "master`, 15},
+		{`(git.branch == "master"`, 24},
+		{`git.branch == "master")`, 23},
+		{`git.branch = "master"`, 12},
+		{`git.branch == "master" && && cds.manual`, 27},
+		{`git.tag =~ "(["`, 9},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.expr)
+		if assert.Error(t, err, tt.expr) {
+			e, ok := err.(*Error)
+			if assert.True(t, ok, tt.expr) {
+				assert.Equal(t, tt.pos, e.Pos, "%s: %v", tt.expr, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package expression
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenTrue
+	tokenFalse
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenLParen
+	tokenRParen
+	tokenEq
+	tokenNe
+	tokenLt
+	tokenLe
+	tokenGt
+	tokenGe
+	tokenMatch
+	tokenNotMatch
+)
+
+var tokenNames = map[tokenKind]string{
+	tokenEOF:      "end of expression",
+	tokenIdent:    "variable",
+	tokenString:   "string",
+	tokenNumber:   "number",
+	tokenTrue:     "true",
+	tokenFalse:    "false",
+	tokenAnd:      "&&",
+	tokenOr:       "||",
+	tokenNot:      "!",
+	tokenLParen:   "(",
+	tokenRParen:   ")",
+	tokenEq:       "==",
+	tokenNe:       "!=",
+	tokenLt:       "<",
+	tokenLe:       "<=",
+	tokenGt:       ">",
+	tokenGe:       ">=",
+	tokenMatch:    "=~",
+	tokenNotMatch: "!~",
+}
+
+func (k tokenKind) String() string {
+	return tokenNames[k]
+}
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// two chars operators must be checked before one char operators
+var operators = []struct {
+	s    string
+	kind tokenKind
+}{
+	{"&&", tokenAnd},
+	{"||", tokenOr},
+	{"==", tokenEq},
+	{"!=", tokenNe},
+	{"<=", tokenLe},
+	{">=", tokenGe},
+	{"=~", tokenMatch},
+	{"!~", tokenNotMatch},
+	{"<", tokenLt},
+	{">", tokenGt},
+	{"!", tokenNot},
+	{"(", tokenLParen},
+	{")", tokenRParen},
+}
+
+// lex splits the input into tokens, positions are 1-based
+func lex(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+	i := 0
+	for i < len(runes) {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '"' || r == '\'':
+			start := i
+			i++
+			for i < len(runes) && runes[i] != r {
+				if runes[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(runes) {
+				return nil, &Error{Pos: start + 1, Msg: "unterminated string"}
+			}
+			i++
+			raw := string(runes[start:i])
+			if r == '\'' {
+				raw = `"` + strings.Replace(raw[1:len(raw)-1], `"`, `\"`, -1) + `"`
+			}
+			s, err := strconv.Unquote(raw)
+			if err != nil {
+				return nil, &Error{Pos: start + 1, Msg: fmt.Sprintf("invalid string %s", string(runes[start:i]))}
+			}
+			tokens = append(tokens, token{kind: tokenString, value: s, pos: start + 1})
+
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: string(runes[start:i]), pos: start + 1})
+
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || strings.ContainsRune("._-", runes[i])) {
+				i++
+			}
+			value := string(runes[start:i])
+			kind := tokenIdent
+			switch value {
+			case "true":
+				kind = tokenTrue
+			case "false":
+				kind = tokenFalse
+			}
+			tokens = append(tokens, token{kind: kind, value: value, pos: start + 1})
+
+		default:
+			var found bool
+			for _, op := range operators {
+				if strings.HasPrefix(string(runes[i:]), op.s) {
+					tokens = append(tokens, token{kind: op.kind, value: op.s, pos: i + 1})
+					i += len([]rune(op.s))
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, &Error{Pos: i + 1, Msg: fmt.Sprintf("unexpected character %q", r)}
+			}
+		}
+	}
+	tokens = append(tokens, token{kind: tokenEOF, pos: len(runes) + 1})
+	return tokens, nil
+}
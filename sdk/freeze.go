@@ -0,0 +1,107 @@
+package sdk
+
+import (
+	"fmt"
+	"time"
+)
+
+// FreezeWindow is a period during which the pipelines targeting an environment are not started.
+// A window without environment applies to all the environments of the project. A window is either
+// a date range, or recurrent with a cron expression starting a freeze of Duration seconds.
+type FreezeWindow struct {
+	ID              int64     `json:"id" db:"id" cli:"id,key"`
+	ProjectID       int64     `json:"project_id" db:"project_id" cli:"-"`
+	EnvironmentID   int64     `json:"environment_id,omitempty" db:"-" cli:"-"`
+	EnvironmentName string    `json:"environment_name,omitempty" db:"-" cli:"environment"`
+	Reason          string    `json:"reason" db:"reason" cli:"reason"`
+	Start           time.Time `json:"start,omitempty" db:"start_date" cli:"start"`
+	End             time.Time `json:"end,omitempty" db:"end_date" cli:"end"`
+	Cron            string    `json:"cron,omitempty" db:"cron" cli:"cron"`
+	Duration        int64     `json:"duration,omitempty" db:"duration" cli:"duration"`
+	// Timezone used to evaluate the cron expression, UTC by default
+	Timezone       string    `json:"timezone,omitempty" db:"timezone" cli:"timezone"`
+	OverrideGroups []string  `json:"override_groups,omitempty" db:"-" cli:"-"`
+	Author         string    `json:"author" db:"author" cli:"author"`
+	Created        time.Time `json:"created" db:"created" cli:"-"`
+}
+
+// FreezeWindowOverride is the audit of a pipeline started by a user despite an active freeze window
+type FreezeWindowOverride struct {
+	ID               int64     `json:"id" db:"id" cli:"-"`
+	FreezeWindowID   int64     `json:"freeze_window_id" db:"freeze_window_id" cli:"-"`
+	WorkflowName     string    `json:"workflow_name" db:"workflow_name" cli:"workflow"`
+	Number           int64     `json:"number" db:"number" cli:"number"`
+	WorkflowNodeName string    `json:"workflow_node_name" db:"workflow_node_name" cli:"pipeline"`
+	Username         string    `json:"username" db:"username" cli:"username"`
+	Date             time.Time `json:"date" db:"date" cli:"date"`
+}
+
+// Types of the audits of the freeze windows
+const (
+	FreezeWindowAuditUpdate = "update"
+	FreezeWindowAuditDelete = "delete"
+)
+
+// FreezeWindowAudit records the freeze window as it was before an update or a deletion
+type FreezeWindowAudit struct {
+	ID             int64        `json:"id" db:"id" cli:"-"`
+	ProjectID      int64        `json:"project_id" db:"project_id" cli:"-"`
+	FreezeWindowID int64        `json:"freeze_window_id" db:"freeze_window_id" cli:"freeze_window"`
+	Type           string       `json:"type" db:"type" cli:"type"`
+	Active         bool         `json:"active" db:"active" cli:"active"`
+	FreezeWindow   FreezeWindow `json:"freeze_window" db:"-" cli:"-"`
+	Username       string       `json:"username" db:"username" cli:"username"`
+	Date           time.Time    `json:"date" db:"date" cli:"date"`
+}
+
+// IsRecurrent returns true if the freeze window is defined by a cron expression
+func (f FreezeWindow) IsRecurrent() bool {
+	return f.Cron != ""
+}
+
+// Scope returns the name of the environment of the freeze window, or project if it applies to all the environments
+func (f FreezeWindow) Scope() string {
+	if f.EnvironmentName != "" {
+		return f.EnvironmentName
+	}
+	return "project"
+}
+
+// IsValid checks the freeze window, the cron expression is checked by the API
+func (f FreezeWindow) IsValid() error {
+	if f.Reason == "" {
+		return NewError(ErrInvalidFreezeWindow, fmt.Errorf("reason is mandatory"))
+	}
+	if f.IsRecurrent() {
+		if f.Duration <= 0 {
+			return NewError(ErrInvalidFreezeWindow, fmt.Errorf("duration is mandatory with a cron expression"))
+		}
+		if _, err := time.LoadLocation(f.Timezone); err != nil {
+			return NewError(ErrInvalidFreezeWindow, fmt.Errorf("invalid timezone %s", f.Timezone))
+		}
+		return nil
+	}
+	if f.Start.IsZero() || f.End.IsZero() {
+		return NewError(ErrInvalidFreezeWindow, fmt.Errorf("start and end are mandatory without cron expression"))
+	}
+	if !f.End.After(f.Start) {
+		return NewError(ErrInvalidFreezeWindow, fmt.Errorf("end must be after start"))
+	}
+	return nil
+}
+
+// CanOverride returns true if the user is a CDS administrator or a member of one of the groups allowed
+// to override the freeze window
+func (f FreezeWindow) CanOverride(u *User) bool {
+	if u.Admin {
+		return true
+	}
+	for _, g := range u.Groups {
+		for _, name := range f.OverrideGroups {
+			if g.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -8,15 +8,26 @@ const (
 	GitPollerModelName            = "Git Repository Poller"
 	KafkaHookModelName            = "Kafka hook"
 	RabbitMQHookModelName         = "RabbitMQ hook"
+	NATSHookModelName             = "NATS hook"
+	MQTTHookModelName             = "MQTT hook"
 	HookConfigProject             = "project"
 	HookConfigWorkflow            = "workflow"
 	HookConfigWorkflowID          = "workflow_id"
 	WebHookModelConfigMethod      = "method"
+	WebHookModelConfigSecret      = "secret"
+	WebHookModelConfigSignature   = "signature_header"
 	RepositoryWebHookModelMethod  = "method"
+	RepositoryWebHookModelEvents  = "events"
+	RepositoryWebHookModelSecret  = "webHookSecret"
+	HookConfigBranches            = "branches"
+	HookConfigTags                = "tags"
+	HookConfigIncludePaths        = "include_paths"
+	HookConfigExcludePaths        = "exclude_paths"
 	SchedulerModelCron            = "cron"
 	SchedulerModelTimezone        = "timezone"
 	SchedulerModelPayload         = "payload"
 	HookModelPlatform             = "platform"
+	HookModelFilter               = "filter"
 	KafkaHookModelConsumerGroup   = "consumer group"
 	KafkaHookModelTopic           = "topic"
 	RabbitMQHookModelQueue        = "queue"
@@ -24,6 +35,20 @@ const (
 	RabbitMQHookModelExchangeType = "exchange_type"
 	RabbitMQHookModelExchangeName = "exchange_name"
 	RabbitMQHookModelConsumerTag  = "consumer_tag"
+	NATSHookModelSubject          = "subject"
+	NATSHookModelQueueGroup       = "queue group"
+	MQTTHookModelTopic            = "topic"
+	MQTTHookModelQoS              = "qos"
+	MQTTHookModelClientID         = "client id"
+)
+
+// Kinds of events triggering a RepositoryWebHook, set as a comma separated list in its events configuration
+const (
+	RepositoryWebHookEventPush                   = "push"
+	RepositoryWebHookEventPullRequestOpened      = "pull_request:opened"
+	RepositoryWebHookEventPullRequestSynchronize = "pull_request:synchronize"
+	RepositoryWebHookEventPullRequestReopened    = "pull_request:reopened"
+	RepositoryWebHookEventPullRequestClosed      = "pull_request:closed"
 )
 
 // KafkaHookModel is the builtin hooks
@@ -50,6 +75,11 @@ var (
 				Configurable: true,
 				Type:         HookConfigTypeString,
 			},
+			HookModelFilter: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
 		},
 	}
 
@@ -90,6 +120,76 @@ var (
 				Configurable: true,
 				Type:         HookConfigTypeString,
 			},
+			HookModelFilter: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+		},
+	}
+
+	NATSHookModel = WorkflowHookModel{
+		Author:     "CDS",
+		Type:       WorkflowHookModelBuiltin,
+		Identifier: "github.com/ovh/cds/hook/builtin/nats",
+		Name:       NATSHookModelName,
+		Icon:       "Linkify",
+		DefaultConfig: WorkflowNodeHookConfig{
+			HookModelPlatform: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypePlatform,
+			},
+			NATSHookModelSubject: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			NATSHookModelQueueGroup: {
+				Value:        "",
+				Configurable: false,
+				Type:         HookConfigTypeString,
+			},
+			HookModelFilter: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+		},
+	}
+
+	MQTTHookModel = WorkflowHookModel{
+		Author:     "CDS",
+		Type:       WorkflowHookModelBuiltin,
+		Identifier: "github.com/ovh/cds/hook/builtin/mqtt",
+		Name:       MQTTHookModelName,
+		Icon:       "Linkify",
+		DefaultConfig: WorkflowNodeHookConfig{
+			HookModelPlatform: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypePlatform,
+			},
+			MQTTHookModelTopic: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			MQTTHookModelQoS: {
+				Value:        "1",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			MQTTHookModelClientID: {
+				Value:        "",
+				Configurable: false,
+				Type:         HookConfigTypeString,
+			},
+			HookModelFilter: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
 		},
 	}
 
@@ -105,6 +205,16 @@ var (
 				Configurable: true,
 				Type:         HookConfigTypeString,
 			},
+			WebHookModelConfigSecret: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypePassword,
+			},
+			WebHookModelConfigSignature: {
+				Value:        "X-Hub-Signature-256",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
 		},
 	}
 
@@ -120,6 +230,31 @@ var (
 				Configurable: false,
 				Type:         HookConfigTypeString,
 			},
+			RepositoryWebHookModelEvents: {
+				Value:        RepositoryWebHookEventPush,
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			HookConfigBranches: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			HookConfigTags: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			HookConfigIncludePaths: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			HookConfigExcludePaths: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
 		},
 	}
 
@@ -135,6 +270,26 @@ var (
 				Configurable: true,
 				Type:         HookConfigTypeString,
 			},
+			HookConfigBranches: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			HookConfigTags: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			HookConfigIncludePaths: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			HookConfigExcludePaths: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
 		},
 	}
 
@@ -18,12 +18,15 @@ type TaskExecution struct {
 	Timestamp           int64                   `json:"timestamp" cli:"timestamp"`
 	NbErrors            int64                   `json:"nb_errors" cli:"nb_errors"`
 	LastError           string                  `json:"last_error,omitempty" cli:"last_error"`
+	SkipReason          string                  `json:"skip_reason,omitempty" cli:"skip_reason"`
 	ProcessingTimestamp int64                   `json:"processing_timestamp" cli:"processing_timestamp"`
 	WorkflowRun         int64                   `json:"workflow_run" cli:"workflow_run"`
 	Config              WorkflowNodeHookConfig  `json:"config" cli:"-"`
 	WebHook             *WebHookExecution       `json:"webhook,omitempty" cli:"-"`
 	Kafka               *KafkaTaskExecution     `json:"kafka,omitempty" cli:"-"`
 	RabbitMQ            *RabbitMQTaskExecution  `json:"rabbitmq,omitempty" cli:"-"`
+	NATS                *NATSTaskExecution      `json:"nats,omitempty" cli:"-"`
+	MQTT                *MQTTTaskExecution      `json:"mqtt,omitempty" cli:"-"`
 	ScheduledTask       *ScheduledTaskExecution `json:"scheduled_task,omitempty" cli:"-"`
 	Status              string                  `json:"status" cli:"status"`
 }
@@ -45,6 +48,19 @@ type RabbitMQTaskExecution struct {
 	Message []byte `json:"message"`
 }
 
+// NATSTaskExecution contains specific data for a NATS hook
+type NATSTaskExecution struct {
+	Subject string              `json:"subject"`
+	Headers map[string][]string `json:"headers,omitempty"`
+	Message []byte              `json:"message"`
+}
+
+// MQTTTaskExecution contains specific data for a MQTT hook
+type MQTTTaskExecution struct {
+	Topic   string `json:"topic"`
+	Message []byte `json:"message"`
+}
+
 // ScheduledTaskExecution contains specific data for a scheduled task execution
 type ScheduledTaskExecution struct {
 	DateScheduledExecution string `json:"date_scheduled_execution"`
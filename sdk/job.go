@@ -1,5 +1,12 @@
 package sdk
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
 // This constant are the types of the kind of job of CDS: legacy and workflow
 const (
 	JobTypePipeline     = "pipeline_build_job"
@@ -14,4 +21,230 @@ type Job struct {
 	LastModified     int64                  `json:"last_modified"`
 	Action           Action                 `json:"action"`
 	Warnings         []PipelineBuildWarning `json:"warnings"`
+	Matrix           *JobMatrix             `json:"matrix,omitempty"`
+	// Timeout of the job in seconds, the timeout of the project is used if not set
+	Timeout     int64        `json:"timeout,omitempty"`
+	RetryPolicy *RetryPolicy `json:"retry_policy,omitempty"`
+}
+
+// JobMatrixMaxVariants is the maximum number of variants of a job
+const JobMatrixMaxVariants = 64
+
+// JobMatrix expands a job into one variant for each combination of the values of its variables.
+// Values of a variant are available in the job as cds.matrix.<name> variables.
+type JobMatrix struct {
+	Variables []JobMatrixVariable `json:"variables"`
+	Overrides []JobMatrixOverride `json:"overrides,omitempty"`
+}
+
+// JobMatrixVariable is a variable of a job matrix
+type JobMatrixVariable struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// JobMatrixOverride sets requirements on the variants matching all its values
+type JobMatrixOverride struct {
+	When         map[string]string `json:"when"`
+	Requirements []Requirement     `json:"requirements"`
+}
+
+// IsValid checks the variables and the overrides of the matrix
+func (m JobMatrix) IsValid() error {
+	if len(m.Variables) == 0 {
+		return fmt.Errorf("matrix has no variable")
+	}
+	values := make(map[string]map[string]bool, len(m.Variables))
+	nb := 1
+	for _, v := range m.Variables {
+		if v.Name == "" {
+			return fmt.Errorf("matrix variable name is empty")
+		}
+		if _, ok := values[v.Name]; ok {
+			return fmt.Errorf("matrix variable %s is defined twice", v.Name)
+		}
+		if len(v.Values) == 0 {
+			return fmt.Errorf("matrix variable %s has no value", v.Name)
+		}
+		values[v.Name] = make(map[string]bool, len(v.Values))
+		for _, val := range v.Values {
+			if values[v.Name][val] {
+				return fmt.Errorf("matrix variable %s has value %s twice", v.Name, val)
+			}
+			values[v.Name][val] = true
+		}
+		nb *= len(v.Values)
+		if nb > JobMatrixMaxVariants {
+			return fmt.Errorf("matrix has more than %d variants", JobMatrixMaxVariants)
+		}
+	}
+	for _, o := range m.Overrides {
+		if len(o.When) == 0 {
+			return fmt.Errorf("matrix override has no condition")
+		}
+		for name, val := range o.When {
+			if _, ok := values[name]; !ok {
+				return fmt.Errorf("matrix override references unknown variable %s", name)
+			}
+			if !values[name][val] {
+				return fmt.Errorf("matrix override references unknown value %s of variable %s", val, name)
+			}
+		}
+	}
+	return nil
+}
+
+// Variants returns all the combinations of the values of the matrix variables
+func (m JobMatrix) Variants() []map[string]string {
+	if len(m.Variables) == 0 {
+		return nil
+	}
+	variants := []map[string]string{{}}
+	for _, v := range m.Variables {
+		next := make([]map[string]string, 0, len(variants)*len(v.Values))
+		for _, variant := range variants {
+			for _, val := range v.Values {
+				n := make(map[string]string, len(variant)+1)
+				for k := range variant {
+					n[k] = variant[k]
+				}
+				n[v.Name] = val
+				next = append(next, n)
+			}
+		}
+		variants = next
+	}
+	return variants
+}
+
+// VariantName returns a readable name of a variant, ie: "go=1.11, os=linux"
+func (m JobMatrix) VariantName(variant map[string]string) string {
+	names := make([]string, 0, len(variant))
+	for k := range variant {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, k := range names {
+		parts[i] = k + "=" + variant[k]
+	}
+	return strings.Join(parts, ", ")
+}
+
+// VariantRequirements returns the requirements of a variant. Requirements of the matching overrides replace
+// the given requirements with the same type, and with the same name for types which can be set several times.
+func (m JobMatrix) VariantRequirements(variant map[string]string, reqs []Requirement) []Requirement {
+	res := make([]Requirement, len(reqs))
+	copy(res, reqs)
+	for _, o := range m.Overrides {
+		match := true
+		for name, val := range o.When {
+			if variant[name] != val {
+				match = false
+				break
+			}
+		}
+		if !match {
+			continue
+		}
+		for _, r := range o.Requirements {
+			filtered := res[:0]
+			for _, existing := range res {
+				if existing.Type == r.Type && (existing.Name == r.Name || requirementIsUnique(r.Type)) {
+					continue
+				}
+				filtered = append(filtered, existing)
+			}
+			res = append(filtered, r)
+		}
+	}
+	return res
+}
+
+// requirementIsUnique returns true for the requirement types a job can have only once
+func requirementIsUnique(t string) bool {
+	switch t {
+	case ModelRequirement, HostnameRequirement, OSArchRequirement, MemoryRequirement:
+		return true
+	}
+	return false
+}
+
+// These are the failures a retry policy can apply to
+const (
+	RetryOnFailure    = "failure"
+	RetryOnWorkerLost = "worker_lost"
+	RetryOnSpawnError = "spawn_error"
+)
+
+// RetryPolicyMaxAttempts is the maximum number of attempts of a retry policy
+const RetryPolicyMaxAttempts = 10
+
+// RetryPolicy describes how a failed job or step is retried. A policy without conditions
+// applies to all failures. Exit codes restrict the retry of failed steps to these codes.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts, including the first one
+	MaxAttempts int `json:"max_attempts"`
+	// Backoff is the delay in seconds before the second attempt, doubled for each following attempt
+	Backoff   int64    `json:"backoff,omitempty"`
+	On        []string `json:"on,omitempty"`
+	ExitCodes []int    `json:"exit_codes,omitempty"`
+}
+
+// IsValid checks the attempts, the backoff and the conditions of the policy
+func (p RetryPolicy) IsValid() error {
+	if p.MaxAttempts < 2 || p.MaxAttempts > RetryPolicyMaxAttempts {
+		return fmt.Errorf("max attempts must be between 2 and %d", RetryPolicyMaxAttempts)
+	}
+	if p.Backoff < 0 {
+		return fmt.Errorf("backoff must not be negative")
+	}
+	for _, o := range p.On {
+		switch o {
+		case RetryOnFailure, RetryOnWorkerLost, RetryOnSpawnError:
+		default:
+			return fmt.Errorf("unknown retry condition %s", o)
+		}
+	}
+	return nil
+}
+
+// Covers returns true if the policy applies to a failure for the given reason
+func (p *RetryPolicy) Covers(reason string, exitCode int) bool {
+	if p == nil {
+		return false
+	}
+	if len(p.On) == 0 && len(p.ExitCodes) == 0 {
+		return true
+	}
+	for _, o := range p.On {
+		if o == reason {
+			return true
+		}
+	}
+	if reason == RetryOnFailure {
+		for _, c := range p.ExitCodes {
+			if c == exitCode {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ShouldRetry returns true if a new attempt has to be done after the given attempt failed
+func (p *RetryPolicy) ShouldRetry(attempt int, reason string, exitCode int) bool {
+	return p.Covers(reason, exitCode) && attempt < p.MaxAttempts
+}
+
+// Delay returns the delay before the attempt following the given one
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	d := time.Duration(p.Backoff) * time.Second
+	for i := 1; i < attempt && d < time.Hour; i++ {
+		d *= 2
+	}
+	if d > time.Hour {
+		d = time.Hour
+	}
+	return d
 }
@@ -0,0 +1,72 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobMatrix(t *testing.T) {
+	m := JobMatrix{
+		Variables: []JobMatrixVariable{
+			{Name: "go", Values: []string{"1.10", "1.11"}},
+			{Name: "os", Values: []string{"linux", "windows"}},
+		},
+		Overrides: []JobMatrixOverride{
+			{
+				When:         map[string]string{"os": "windows"},
+				Requirements: []Requirement{{Name: "windows/amd64", Type: OSArchRequirement, Value: "windows/amd64"}},
+			},
+		},
+	}
+	assert.NoError(t, m.IsValid())
+
+	variants := m.Variants()
+	if assert.Len(t, variants, 4) {
+		assert.Equal(t, "go=1.10, os=linux", m.VariantName(variants[0]))
+		assert.Equal(t, "go=1.11, os=windows", m.VariantName(variants[3]))
+	}
+
+	reqs := []Requirement{
+		{Name: "go", Type: BinaryRequirement, Value: "go"},
+		{Name: "linux/amd64", Type: OSArchRequirement, Value: "linux/amd64"},
+	}
+	assert.Equal(t, reqs, m.VariantRequirements(variants[0], reqs))
+	windowsReqs := m.VariantRequirements(variants[1], reqs)
+	if assert.Len(t, windowsReqs, 2) {
+		assert.Equal(t, "go", windowsReqs[0].Value)
+		assert.Equal(t, "windows/amd64", windowsReqs[1].Value)
+	}
+	// the requirements of the job are not modified
+	assert.Equal(t, "linux/amd64", reqs[1].Value)
+
+	m.Variables[1].Values = append(m.Variables[1].Values, "linux")
+	assert.Error(t, m.IsValid())
+	m.Variables[1].Values = []string{"linux"}
+	assert.Error(t, m.IsValid())
+}
+
+func TestRetryPolicy(t *testing.T) {
+	p := &RetryPolicy{MaxAttempts: 3, Backoff: 10, On: []string{RetryOnWorkerLost}, ExitCodes: []int{137}}
+	assert.NoError(t, p.IsValid())
+
+	assert.True(t, p.ShouldRetry(1, RetryOnWorkerLost, 0))
+	assert.True(t, p.ShouldRetry(2, RetryOnFailure, 137))
+	assert.False(t, p.ShouldRetry(1, RetryOnFailure, 1))
+	assert.False(t, p.ShouldRetry(1, RetryOnSpawnError, 0))
+	assert.False(t, p.ShouldRetry(3, RetryOnWorkerLost, 0))
+
+	assert.Equal(t, 10*time.Second, p.Delay(1))
+	assert.Equal(t, 20*time.Second, p.Delay(2))
+	p.Backoff = 3600
+	assert.Equal(t, time.Hour, p.Delay(5))
+
+	var none *RetryPolicy
+	assert.False(t, none.ShouldRetry(1, RetryOnFailure, 1))
+	assert.True(t, (&RetryPolicy{MaxAttempts: 2}).ShouldRetry(1, RetryOnSpawnError, 0))
+
+	assert.Error(t, RetryPolicy{MaxAttempts: 1}.IsValid())
+	assert.Error(t, RetryPolicy{MaxAttempts: 11}.IsValid())
+	assert.Error(t, RetryPolicy{MaxAttempts: 2, On: []string{"rain"}}.IsValid())
+}
@@ -1,6 +1,8 @@
 package sdk
 
 import (
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/golang/protobuf/ptypes"
@@ -21,3 +23,82 @@ func NewLog(pbJobID int64, value string, pipelineBuildID int64, stepOrder int) *
 
 	return l
 }
+
+// LogLine is a line of a step log, lines are numbered from 1
+type LogLine struct {
+	JobID     int64  `json:"job_id" cli:"job"`
+	StepOrder int64  `json:"step_order" cli:"step"`
+	Number    int64  `json:"number" cli:"line"`
+	Value     string `json:"value" cli:"value"`
+}
+
+// LogLines is a range of lines of a step log
+type LogLines struct {
+	Lines []LogLine `json:"lines"`
+	Total int64     `json:"total"`
+}
+
+// CountLines returns the number of lines of the log
+func (l *Log) CountLines() int64 {
+	if l.Val == "" {
+		return 0
+	}
+	n := int64(strings.Count(l.Val, "\n"))
+	if !strings.HasSuffix(l.Val, "\n") {
+		n++
+	}
+	return n
+}
+
+// Lines returns at most limit lines of the log, skipping the offset first lines.
+// A negative offset counts lines from the end of the log, a limit lower or equal to 0 means no limit.
+func (l *Log) Lines(offset, limit int64) LogLines {
+	res := LogLines{Lines: []LogLine{}, Total: l.CountLines()}
+	if offset < 0 {
+		offset += res.Total
+		if offset < 0 {
+			offset = 0
+		}
+	}
+	l.forEachLine(func(number int64, value string) bool {
+		if number <= offset {
+			return true
+		}
+		res.Lines = append(res.Lines, LogLine{JobID: l.PipelineBuildJobID, StepOrder: l.StepOrder, Number: number, Value: value})
+		return limit <= 0 || int64(len(res.Lines)) < limit
+	})
+	return res
+}
+
+// Search returns at most limit lines of the log matching the regular expression
+func (l *Log) Search(re *regexp.Regexp, limit int) []LogLine {
+	res := []LogLine{}
+	if limit <= 0 {
+		return res
+	}
+	l.forEachLine(func(number int64, value string) bool {
+		if re.MatchString(value) {
+			res = append(res, LogLine{JobID: l.PipelineBuildJobID, StepOrder: l.StepOrder, Number: number, Value: value})
+		}
+		return len(res) < limit
+	})
+	return res
+}
+
+// forEachLine calls f on each line of the log without splitting the whole value, it stops when f returns false
+func (l *Log) forEachLine(f func(number int64, value string) bool) {
+	s := l.Val
+	var number int64
+	for s != "" {
+		number++
+		var line string
+		if i := strings.IndexByte(s, '\n'); i >= 0 {
+			line, s = s[:i], s[i+1:]
+		} else {
+			line, s = s, ""
+		}
+		if !f(number, strings.TrimSuffix(line, "\r")) {
+			return
+		}
+	}
+}
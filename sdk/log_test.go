@@ -0,0 +1,46 @@
+package sdk
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogLines(t *testing.T) {
+	l := &Log{PipelineBuildJobID: 1, StepOrder: 2, Val: "one\ntwo\r\nthree\nfour\n"}
+	assert.Equal(t, int64(4), l.CountLines())
+
+	values := func(lines LogLines) []string {
+		res := []string{}
+		for _, line := range lines.Lines {
+			res = append(res, line.Value)
+		}
+		return res
+	}
+
+	assert.Equal(t, []string{"one", "two"}, values(l.Lines(0, 2)))
+	assert.Equal(t, []string{"three", "four"}, values(l.Lines(-2, 0)))
+	assert.Equal(t, []string{"two", "three"}, values(l.Lines(1, 2)))
+	assert.Equal(t, []string{"one", "two", "three", "four"}, values(l.Lines(-10, 0)))
+	assert.Empty(t, values(l.Lines(10, 0)))
+
+	lines := l.Lines(2, 1)
+	assert.Equal(t, int64(4), lines.Total)
+	assert.Equal(t, LogLine{JobID: 1, StepOrder: 2, Number: 3, Value: "three"}, lines.Lines[0])
+
+	l.Val = "no trailing newline"
+	assert.Equal(t, int64(1), l.CountLines())
+	l.Val = ""
+	assert.Equal(t, int64(0), l.CountLines())
+}
+
+func TestLogSearch(t *testing.T) {
+	l := &Log{PipelineBuildJobID: 1, StepOrder: 0, Val: "ok\nError: first\nok\nerror: second\nError: third\n"}
+	found := l.Search(regexp.MustCompile("^Error"), 10)
+	if assert.Len(t, found, 2) {
+		assert.Equal(t, int64(2), found[0].Number)
+		assert.Equal(t, "Error: third", found[1].Value)
+	}
+	assert.Len(t, l.Search(regexp.MustCompile("(?i)error"), 2), 2)
+}
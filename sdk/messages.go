@@ -67,11 +67,26 @@ var (
 	MsgSpawnInfoWorkerForJob               = &Message{"MsgSpawnInfoWorkerForJob", trad{FR: "Ce worker %s a été créé pour lancer ce job", EN: "This worker %s was created to take this action"}, nil}
 	MsgSpawnInfoWorkerForJobError          = &Message{"MsgSpawnInfoWorkerForJobError", trad{FR: "Ce worker %s a été créé pour lancer ce job, mais ne possède pas tous les pré-requis. Vérifiez que les prérequis suivants:%s", EN: "This worker %s was created to take this action, but does not have all prerequisites. Please verify the following prerequisites:%s"}, nil}
 	MsgSpawnInfoJobError                   = &Message{"MsgSpawnInfoJobError", trad{FR: "Impossible de lancer ce job : %s", EN: "Unable to run this job: %s"}, nil}
+	MsgSpawnInfoVaultSecretError           = &Message{"MsgSpawnInfoVaultSecretError", trad{FR: "Impossible de résoudre les variables Vault de ce job : %s", EN: "Unable to resolve the Vault variables of this job: %s"}, nil}
+	MsgSpawnInfoJobTimeout                 = &Message{"MsgSpawnInfoJobTimeout", trad{FR: "Le job a été arrêté car il a dépassé son timeout de %s", EN: "The job has been stopped as it exceeded its timeout of %s"}, nil}
+	MsgSpawnInfoJobRetry                   = &Message{"MsgSpawnInfoJobRetry", trad{FR: "La tentative %d du job a échoué (%s), la tentative %d/%d démarrera dans %s", EN: "Attempt %d of the job failed (%s), attempt %d/%d will start in %s"}, nil}
+	MsgSpawnInfoJobWorkerLost              = &Message{"MsgSpawnInfoJobWorkerLost", trad{FR: "Le worker %s qui exécutait le job a été perdu", EN: "The worker %s running the job has been lost"}, nil}
+	MsgSpawnInfoJobSpawnFailed             = &Message{"MsgSpawnInfoJobSpawnFailed", trad{FR: "Aucun worker n'a pu être démarré pour le job après %d tentatives", EN: "No worker could be spawned for the job after %d attempts"}, nil}
 	MsgWorkflowStarting                    = &Message{"MsgWorkflowStarting", trad{FR: "Le workflow %s#%s a été démarré", EN: "Workflow %s#%s has been started"}, nil}
 	MsgWorkflowError                       = &Message{"MsgWorkflowError", trad{FR: "Une erreur est survenue: %v", EN: "An error has occured: %v"}, nil}
 	MsgWorkflowNodeStop                    = &Message{"MsgWorkflowNodeStop", trad{FR: "Le pipeline a été arrété par %s", EN: "The pipeline has been stopped by %s"}, nil}
 	MsgWorkflowNodeMutex                   = &Message{"MsgWorkflowNodeMutex", trad{FR: "Le pipeline %s est mis en attente tant qu'il est en cours sur un autre run", EN: "The pipeline %s is waiting while it's running on another run"}, nil}
 	MsgWorkflowNodeMutexRelease            = &Message{"MsgWorkflowNodeMutexRelease", trad{FR: "Lancement du pipeline %s", EN: "Triggering pipeline %s"}, nil}
+	MsgWorkflowNodeApprovalWaiting         = &Message{"MsgWorkflowNodeApprovalWaiting", trad{FR: "Le pipeline %s attend %d validation(s)", EN: "The pipeline %s is waiting for %d approval(s)"}, nil}
+	MsgWorkflowNodeApproved                = &Message{"MsgWorkflowNodeApproved", trad{FR: "Le pipeline %s a été validé par %s: %s", EN: "The pipeline %s has been approved by %s: %s"}, nil}
+	MsgWorkflowNodeRejected                = &Message{"MsgWorkflowNodeRejected", trad{FR: "Le pipeline %s a été rejeté par %s: %s", EN: "The pipeline %s has been rejected by %s: %s"}, nil}
+	MsgWorkflowNodeApprovalExpired         = &Message{"MsgWorkflowNodeApprovalExpired", trad{FR: "La validation du pipeline %s a expiré après %s", EN: "The approval of the pipeline %s expired after %s"}, nil}
+	MsgWorkflowNodeFrozen                  = &Message{"MsgWorkflowNodeFrozen", trad{FR: "Le pipeline %s n'a pas été lancé à cause de la période de gel sur %s: %s", EN: "The pipeline %s has not been started because of the freeze window on %s: %s"}, nil}
+	MsgWorkflowNodeFreezeOverridden        = &Message{"MsgWorkflowNodeFreezeOverridden", trad{FR: "La période de gel sur %s (%s) a été outrepassée par %s pour le pipeline %s", EN: "The freeze window on %s (%s) has been overridden by %s for the pipeline %s"}, nil}
+	MsgWorkflowNodeConcurrencyQueued       = &Message{"MsgWorkflowNodeConcurrencyQueued", trad{FR: "Le pipeline %s est mis en attente dans le groupe de concurrence %s derrière %s #%d %s", EN: "The pipeline %s is queued in the concurrency group %s behind %s #%d %s"}, nil}
+	MsgWorkflowNodeConcurrencyRelease      = &Message{"MsgWorkflowNodeConcurrencyRelease", trad{FR: "Le groupe de concurrence %s a été libéré, lancement du pipeline %s", EN: "The concurrency group %s has been released, triggering pipeline %s"}, nil}
+	MsgWorkflowNodeConcurrencySuperseded   = &Message{"MsgWorkflowNodeConcurrencySuperseded", trad{FR: "Le pipeline %s a été annulé car remplacé par le run #%d sur la branche %s", EN: "The pipeline %s has been cancelled because it has been superseded by the run #%d on branch %s"}, nil}
+	MsgWorkflowRunSuperseded               = &Message{"MsgWorkflowRunSuperseded", trad{FR: "Le workflow a été arrêté car remplacé par le run #%d sur la branche %s", EN: "The workflow has been stopped because it has been superseded by the run #%d on branch %s"}, nil}
 	MsgWorkflowImportedUpdated             = &Message{"MsgWorkflowImportedUpdated", trad{FR: "Le workflow %s a été mis à jour", EN: "Workflow %s has been updated"}, nil}
 	MsgWorkflowImportedInserted            = &Message{"MsgWorkflowImportedInserted", trad{FR: "Le workflow %s a été créé", EN: "Workflow %s has been created"}, nil}
 	MsgSpawnInfoHatcheryCannotStartJob     = &Message{"MsgSpawnInfoHatcheryCannotStart", trad{FR: "Aucune hatchery n'a pu démarrer de worker respectant vos pré-requis de job, merci de les vérifier.", EN: "No hatchery can spawn a worker corresponding your job's requirements. Please check your job's requirements."}, nil}
@@ -128,6 +143,11 @@ var Messages = map[string]*Message{
 	MsgSpawnInfoWorkerForJob.ID:               MsgSpawnInfoWorkerForJob,
 	MsgSpawnInfoWorkerForJobError.ID:          MsgSpawnInfoWorkerForJobError,
 	MsgSpawnInfoJobError.ID:                   MsgSpawnInfoJobError,
+	MsgSpawnInfoVaultSecretError.ID:           MsgSpawnInfoVaultSecretError,
+	MsgSpawnInfoJobTimeout.ID:                 MsgSpawnInfoJobTimeout,
+	MsgSpawnInfoJobRetry.ID:                   MsgSpawnInfoJobRetry,
+	MsgSpawnInfoJobWorkerLost.ID:              MsgSpawnInfoJobWorkerLost,
+	MsgSpawnInfoJobSpawnFailed.ID:             MsgSpawnInfoJobSpawnFailed,
 	MsgWorkflowStarting.ID:                    MsgWorkflowStarting,
 	MsgWorkflowError.ID:                       MsgWorkflowError,
 	MsgWorkflowNodeStop.ID:                    MsgWorkflowNodeStop,
@@ -135,6 +155,16 @@ var Messages = map[string]*Message{
 	MsgWorkflowImportedInserted.ID:            MsgWorkflowImportedInserted,
 	MsgWorkflowNodeMutex.ID:                   MsgWorkflowNodeMutex,
 	MsgWorkflowNodeMutexRelease.ID:            MsgWorkflowNodeMutexRelease,
+	MsgWorkflowNodeApprovalWaiting.ID:         MsgWorkflowNodeApprovalWaiting,
+	MsgWorkflowNodeApproved.ID:                MsgWorkflowNodeApproved,
+	MsgWorkflowNodeRejected.ID:                MsgWorkflowNodeRejected,
+	MsgWorkflowNodeApprovalExpired.ID:         MsgWorkflowNodeApprovalExpired,
+	MsgWorkflowNodeFrozen.ID:                  MsgWorkflowNodeFrozen,
+	MsgWorkflowNodeFreezeOverridden.ID:        MsgWorkflowNodeFreezeOverridden,
+	MsgWorkflowNodeConcurrencyQueued.ID:       MsgWorkflowNodeConcurrencyQueued,
+	MsgWorkflowNodeConcurrencyRelease.ID:      MsgWorkflowNodeConcurrencyRelease,
+	MsgWorkflowNodeConcurrencySuperseded.ID:   MsgWorkflowNodeConcurrencySuperseded,
+	MsgWorkflowRunSuperseded.ID:               MsgWorkflowRunSuperseded,
 	MsgSpawnInfoHatcheryCannotStartJob.ID:     MsgSpawnInfoHatcheryCannotStartJob,
 	MsgWorkflowRunBranchDeleted.ID:            MsgWorkflowRunBranchDeleted,
 }
@@ -1,14 +1,21 @@
 package sdk
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
 
 //UserNotificationSettingsType of notification
 type UserNotificationSettingsType string
 
 //const
 const (
-	EmailUserNotification  UserNotificationSettingsType = "email"
-	JabberUserNotification UserNotificationSettingsType = "jabber"
+	EmailUserNotification   UserNotificationSettingsType = "email"
+	JabberUserNotification  UserNotificationSettingsType = "jabber"
+	WebhookUserNotification UserNotificationSettingsType = "webhook"
 )
 
 //UserNotificationEventType always/never/change
@@ -80,6 +87,118 @@ func (n *JabberEmailUserNotificationSettings) JSON() string {
 	return string(b)
 }
 
+// WebhookUserNotificationSettings are the settings of the HTTP requests sent on workflow events.
+// The URL, the headers and the body are templates interpolated with the parameters of the run.
+type WebhookUserNotificationSettings struct {
+	OnSuccess UserNotificationEventType `json:"on_success"`
+	OnFailure UserNotificationEventType `json:"on_failure"`
+	OnStart   bool                      `json:"on_start"`
+	URL       string                    `json:"url"`
+	Method    string                    `json:"method,omitempty"`
+	Headers   map[string]string         `json:"headers,omitempty"`
+	Secret    string                    `json:"secret,omitempty"`
+	Body      string                    `json:"body,omitempty"`
+}
+
+//Success returns always/never/change
+func (n *WebhookUserNotificationSettings) Success() UserNotificationEventType {
+	return n.OnSuccess
+}
+
+//Failure returns always/never/change
+func (n *WebhookUserNotificationSettings) Failure() UserNotificationEventType {
+	return n.OnFailure
+}
+
+//Start returns true if the notification is sent on start
+func (n *WebhookUserNotificationSettings) Start() bool {
+	return n.OnStart
+}
+
+//JSON returns json as string
+func (n *WebhookUserNotificationSettings) JSON() string {
+	b, _ := json.Marshal(n)
+	return string(b)
+}
+
+// WithMaskedSecrets returns a copy of the settings whose header values and secret are replaced by the password placeholder.
+// The headers and the secret are encrypted in database and masked in the API responses.
+func (n *WebhookUserNotificationSettings) WithMaskedSecrets() *WebhookUserNotificationSettings {
+	c := *n
+	if n.Headers != nil {
+		c.Headers = make(map[string]string, len(n.Headers))
+		for k := range n.Headers {
+			c.Headers[k] = PasswordPlaceholder
+		}
+	}
+	if n.Secret != "" {
+		c.Secret = PasswordPlaceholder
+	}
+	return &c
+}
+
+// WithRestoredSecrets returns a copy of the settings whose masked header values and secret are set to the values of stored
+func (n *WebhookUserNotificationSettings) WithRestoredSecrets(stored *WebhookUserNotificationSettings) *WebhookUserNotificationSettings {
+	c := *n
+	if n.Headers != nil {
+		c.Headers = make(map[string]string, len(n.Headers))
+		for k, v := range n.Headers {
+			if v == PasswordPlaceholder {
+				sv, ok := stored.Headers[k]
+				if !ok {
+					continue
+				}
+				v = sv
+			}
+			c.Headers[k] = v
+		}
+	}
+	if n.Secret == PasswordPlaceholder {
+		c.Secret = stored.Secret
+	}
+	return &c
+}
+
+// IsMasked returns true if a header value or the secret is masked
+func (n *WebhookUserNotificationSettings) IsMasked() bool {
+	for _, v := range n.Headers {
+		if v == PasswordPlaceholder {
+			return true
+		}
+	}
+	return n.Secret == PasswordPlaceholder
+}
+
+// IsValid checks the url and the method of the webhook
+func (n *WebhookUserNotificationSettings) IsValid() error {
+	if !strings.HasPrefix(n.URL, "http://") && !strings.HasPrefix(n.URL, "https://") {
+		return NewError(ErrInvalidWebhookNotification, fmt.Errorf("invalid url %s", n.URL))
+	}
+	switch n.Method {
+	case "", http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return NewError(ErrInvalidWebhookNotification, fmt.Errorf("invalid method %s", n.Method))
+	}
+	return nil
+}
+
+// WorkflowNotificationDelivery is an attempt to send a webhook notification
+type WorkflowNotificationDelivery struct {
+	ID                int64     `json:"id" db:"id" cli:"-"`
+	WorkflowID        int64     `json:"workflow_id" db:"workflow_id" cli:"-"`
+	NotificationID    int64     `json:"notification_id" db:"workflow_notification_id" cli:"-"`
+	WorkflowRunNumber int64     `json:"workflow_run_number" db:"workflow_run_number" cli:"run"`
+	WorkflowNodeRunID int64     `json:"workflow_node_run_id" db:"workflow_node_run_id" cli:"-"`
+	Status            string    `json:"status" db:"status" cli:"status"`
+	Method            string    `json:"method" db:"method" cli:"method"`
+	URL               string    `json:"url" db:"url" cli:"url"`
+	Attempt           int       `json:"attempt" db:"attempt" cli:"attempt"`
+	StatusCode        int       `json:"status_code" db:"status_code" cli:"code"`
+	Error             string    `json:"error,omitempty" db:"error" cli:"error"`
+	Duration          int64     `json:"duration" db:"duration" cli:"duration"`
+	Created           time.Time `json:"created" db:"created" cli:"created"`
+}
+
 // UserNotificationTemplate is the notification content
 type UserNotificationTemplate struct {
 	Subject string `json:"subject,omitempty"`
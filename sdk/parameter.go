@@ -166,7 +166,7 @@ func ParametersFromPlatform(ppf PlatformConfig) map[string]string {
 func variablesToParameters(prefix string, variables []Variable) []Parameter {
 	res := make([]Parameter, 0, len(variables))
 	for _, t := range variables {
-		if NeedPlaceholder(t.Type) {
+		if NeedPlaceholder(t.Type) || t.Type == VaultVariable {
 			continue
 		}
 		t.Name = prefix + "." + t.Name
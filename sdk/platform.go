@@ -4,6 +4,8 @@ package sdk
 const (
 	KafkaPlatformModel    = "Kafka"
 	RabbitMQPlatformModel = "RabbitMQ"
+	NATSPlatformModel     = "NATS"
+	MQTTPlatformModel     = "MQTT"
 )
 
 // Here are the default hooks
@@ -15,11 +17,15 @@ var (
 		&SchedulerModel,
 		&KafkaHookModel,
 		&RabbitMQHookModel,
+		&NATSHookModel,
+		&MQTTHookModel,
 	}
 
 	BuiltinPlatformModels = []*PlatformModel{
 		&KafkaPlatform,
 		&RabbitMQPlatform,
+		&NATSPlatform,
+		&MQTTPlatform,
 	}
 	// KafkaPlatform represent a kafka platform
 	KafkaPlatform = PlatformModel{
@@ -61,6 +67,46 @@ var (
 		Disabled: false,
 		Hook:     true,
 	}
+	// NATSPlatform represent a NATS platform
+	NATSPlatform = PlatformModel{
+		Name:       NATSPlatformModel,
+		Author:     "CDS",
+		Identifier: "github.com/ovh/cds/platform/builtin/nats",
+		Icon:       "",
+		DefaultConfig: PlatformConfig{
+			"url": PlatformConfigValue{
+				Type: PlatformConfigTypeString,
+			},
+			"username": PlatformConfigValue{
+				Type: PlatformConfigTypeString,
+			},
+			"password": PlatformConfigValue{
+				Type: PlatformConfigTypePassword,
+			},
+		},
+		Disabled: false,
+		Hook:     true,
+	}
+	// MQTTPlatform represent a MQTT platform
+	MQTTPlatform = PlatformModel{
+		Name:       MQTTPlatformModel,
+		Author:     "CDS",
+		Identifier: "github.com/ovh/cds/platform/builtin/mqtt",
+		Icon:       "",
+		DefaultConfig: PlatformConfig{
+			"broker url": PlatformConfigValue{
+				Type: PlatformConfigTypeString,
+			},
+			"username": PlatformConfigValue{
+				Type: PlatformConfigTypeString,
+			},
+			"password": PlatformConfigValue{
+				Type: PlatformConfigTypePassword,
+			},
+		},
+		Disabled: false,
+		Hook:     true,
+	}
 )
 
 // PlatformConfig represent the configuration of a plateform
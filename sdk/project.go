@@ -37,6 +37,8 @@ type Project struct {
 	Platforms         []ProjectPlatform  `json:"platforms" yaml:"platforms" db:"-" cli:"-"`
 	Features          map[string]bool    `json:"features" yaml:"features" db:"-" cli:"-"`
 	Favorite          bool               `json:"favorite" yaml:"favorite" db:"-" cli:"favorite"`
+	JobTimeout        int64              `json:"job_timeout" yaml:"job_timeout" db:"job_timeout" cli:"job_timeout"` // Default timeout of the jobs in seconds
+	VaultRole         string             `json:"vault_role" yaml:"vault_role" db:"vault_role" cli:"vault_role"`     // Vault role used to resolve the vault variables
 }
 
 // IsValid returns error if the project is not valid
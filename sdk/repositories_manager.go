@@ -326,6 +326,7 @@ type VCSHook struct {
 	Disable     bool     `json:"disable"`
 	InsecureSSL bool     `json:"insecure_ssl"`
 	Workflow    bool     `json:"workflow"`
+	Secret      string   `json:"secret,omitempty"`
 }
 
 // VCSCommitStatus represents a status on a VCS repository
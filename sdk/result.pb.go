@@ -27,6 +27,7 @@ type Result struct {
 	Reason     string                     `protobuf:"bytes,5,opt,name=reason" json:"reason,omitempty"`
 	RemoteTime *google_protobuf.Timestamp `protobuf:"bytes,6,opt,name=remoteTime" json:"remoteTime,omitempty"`
 	Duration   string                     `protobuf:"bytes,7,opt,name=duration" json:"duration,omitempty"`
+	ExitCode   int32                      `protobuf:"varint,8,opt,name=exitCode" json:"exitCode,omitempty"`
 }
 
 func (m *Result) Reset()                    { *m = Result{} }
@@ -83,6 +84,13 @@ func (m *Result) GetDuration() string {
 	return ""
 }
 
+func (m *Result) GetExitCode() int32 {
+	if m != nil {
+		return m.ExitCode
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Result)(nil), "github.com.ovh.cds.sdk.Result")
 }
@@ -90,20 +98,20 @@ func init() {
 func init() { proto.RegisterFile("result.proto", fileDescriptor1) }
 
 var fileDescriptor1 = []byte{
-	// 228 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x4c, 0x8e, 0x31, 0x4f, 0xc3, 0x30,
-	0x10, 0x85, 0xe5, 0x84, 0xa6, 0x60, 0x10, 0x83, 0x87, 0xca, 0xca, 0x42, 0xc4, 0x94, 0xc9, 0x95,
-	0x60, 0x63, 0x44, 0x2c, 0xac, 0x51, 0x27, 0xb6, 0xa4, 0x3e, 0x52, 0xab, 0x71, 0xaf, 0xf2, 0x9d,
-	0xfb, 0x4b, 0xf9, 0x41, 0x28, 0x76, 0x83, 0x18, 0xbf, 0xd3, 0xfb, 0xde, 0x3d, 0xf9, 0x10, 0x80,
-	0xe2, 0xc4, 0xe6, 0x1c, 0x90, 0x51, 0x6d, 0x46, 0xc7, 0x87, 0x38, 0x98, 0x3d, 0x7a, 0x83, 0x97,
-	0x83, 0xd9, 0x5b, 0x32, 0x64, 0x8f, 0xf5, 0xd3, 0x88, 0x38, 0x4e, 0xb0, 0x4d, 0xa9, 0x21, 0x7e,
-	0x6f, 0xd9, 0x79, 0x20, 0xee, 0xfd, 0x39, 0x8b, 0xcf, 0x3f, 0x42, 0x56, 0x5d, 0x6a, 0x52, 0x8f,
-	0xb2, 0x70, 0x56, 0x8b, 0x46, 0xb4, 0x65, 0x57, 0x38, 0xab, 0xb4, 0x5c, 0x0f, 0xd1, 0x4d, 0xf6,
-	0xf3, 0x43, 0x17, 0xe9, 0xb8, 0xa0, 0xda, 0xc8, 0x8a, 0xb8, 0xe7, 0x48, 0xba, 0x6c, 0x44, 0x7b,
-	0xd7, 0x5d, 0x69, 0x36, 0x2e, 0x10, 0xc8, 0xe1, 0x49, 0xdf, 0x64, 0xe3, 0x8a, 0xb3, 0x11, 0xa0,
-	0x27, 0x3c, 0xe9, 0x55, 0x36, 0x32, 0xa9, 0x37, 0x29, 0x03, 0x78, 0x64, 0xd8, 0x39, 0x0f, 0xba,
-	0x6a, 0x44, 0x7b, 0xff, 0x52, 0x9b, 0x3c, 0xda, 0x2c, 0xa3, 0xcd, 0x6e, 0x19, 0xdd, 0xfd, 0x4b,
-	0xab, 0x5a, 0xde, 0xda, 0x18, 0x7a, 0x9e, 0xdf, 0xad, 0x53, 0xeb, 0x1f, 0xbf, 0xaf, 0xbe, 0x4a,
-	0xb2, 0xc7, 0xa1, 0x4a, 0x15, 0xaf, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x6e, 0xb8, 0xaf, 0xc7,
-	0x2d, 0x01, 0x00, 0x00,
+	// 239 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x8e, 0x31, 0x4f, 0xc3, 0x30,
+	0x10, 0x85, 0xe5, 0x86, 0xa4, 0xc5, 0x20, 0x06, 0x0f, 0x95, 0x95, 0x85, 0x88, 0x29, 0x93, 0x2b,
+	0xc1, 0xc6, 0x08, 0x2c, 0xac, 0x56, 0x27, 0xb6, 0xa4, 0x3e, 0x52, 0xab, 0x71, 0xaf, 0xb2, 0xcf,
+	0x15, 0x3f, 0x9b, 0x9f, 0x80, 0x62, 0x37, 0x15, 0xe3, 0x67, 0xbf, 0xef, 0xdd, 0xe3, 0xf7, 0x1e,
+	0x42, 0x1c, 0x49, 0x9d, 0x3c, 0x12, 0x8a, 0xf5, 0x60, 0x69, 0x1f, 0x7b, 0xb5, 0x43, 0xa7, 0xf0,
+	0xbc, 0x57, 0x3b, 0x13, 0x54, 0x30, 0x87, 0xfa, 0x71, 0x40, 0x1c, 0x46, 0xd8, 0xa4, 0x54, 0x1f,
+	0xbf, 0x37, 0x64, 0x1d, 0x04, 0xea, 0xdc, 0x29, 0x8b, 0x4f, 0xbf, 0x8c, 0x57, 0x3a, 0x35, 0x89,
+	0x07, 0xbe, 0xb0, 0x46, 0xb2, 0x86, 0xb5, 0x85, 0x5e, 0x58, 0x23, 0x24, 0x5f, 0xf6, 0xd1, 0x8e,
+	0xe6, 0xf3, 0x43, 0x2e, 0xd2, 0xe3, 0x8c, 0x62, 0xcd, 0xab, 0x40, 0x1d, 0xc5, 0x20, 0x8b, 0x86,
+	0xb5, 0xb7, 0xfa, 0x42, 0x93, 0x71, 0x06, 0x1f, 0x2c, 0x1e, 0xe5, 0x4d, 0x36, 0x2e, 0x38, 0x19,
+	0x1e, 0xba, 0x80, 0x47, 0x59, 0x66, 0x23, 0x93, 0x78, 0xe5, 0xdc, 0x83, 0x43, 0x82, 0xad, 0x75,
+	0x20, 0xab, 0x86, 0xb5, 0x77, 0xcf, 0xb5, 0xca, 0xa3, 0xd5, 0x3c, 0x5a, 0x6d, 0xe7, 0xd1, 0xfa,
+	0x5f, 0x5a, 0xd4, 0x7c, 0x65, 0xa2, 0xef, 0x68, 0x3a, 0xb7, 0x4c, 0xad, 0x57, 0x9e, 0xfe, 0xe0,
+	0xc7, 0xd2, 0x3b, 0x1a, 0x90, 0xab, 0x86, 0xb5, 0xa5, 0xbe, 0xf2, 0x5b, 0xf9, 0x55, 0x04, 0x73,
+	0xe8, 0xab, 0x54, 0xff, 0xf2, 0x37, 0x00, 0x4a, 0x3a, 0x91, 0x8d, 0x49, 0x01, 0x00, 0x00,
 }
@@ -0,0 +1,203 @@
+package sdk
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Named permissions granted by the roles
+const (
+	PermissionNameRead            = "read"
+	PermissionNameRun             = "run"
+	PermissionNameDeploy          = "deploy"
+	PermissionNameApprove         = "approve"
+	PermissionNameManageVariables = "manage-variables"
+	PermissionNameEdit            = "edit"
+)
+
+// PermissionNames is the list of all the named permissions
+var PermissionNames = []string{
+	PermissionNameRead,
+	PermissionNameRun,
+	PermissionNameDeploy,
+	PermissionNameApprove,
+	PermissionNameManageVariables,
+	PermissionNameEdit,
+}
+
+// Built-in roles, matching the permission levels given to the groups on projects, applications, pipelines,
+// environments and workflows
+const (
+	RoleRead             = "read"
+	RoleReadExecute      = "read-execute"
+	RoleReadWriteExecute = "read-write-execute"
+)
+
+// Types of object on which a role can be bound
+const (
+	RoleBindingProject     = "project"
+	RoleBindingApplication = "application"
+	RoleBindingPipeline    = "pipeline"
+	RoleBindingEnvironment = "environment"
+	RoleBindingWorkflow    = "workflow"
+)
+
+// RoleBindingTypes is the list of all the types of object on which a role can be bound
+var RoleBindingTypes = []string{RoleBindingProject, RoleBindingApplication, RoleBindingPipeline, RoleBindingEnvironment, RoleBindingWorkflow}
+
+var roleNamePattern = regexp.MustCompile("^[a-z0-9._-]{1,64}$")
+
+// Role is a named set of permissions. The built-in roles are equivalent to the permission levels,
+// the other roles are defined by the CDS administrators.
+type Role struct {
+	ID          int64    `json:"id,omitempty" cli:"-"`
+	Name        string   `json:"name" cli:"name,key"`
+	Description string   `json:"description,omitempty" cli:"description"`
+	Permissions []string `json:"permissions" cli:"permissions"`
+	BuiltIn     bool     `json:"builtin" cli:"builtin"`
+	// Level is the permission level of a built-in role
+	Level int `json:"level,omitempty" cli:"-"`
+}
+
+// RoleBinding grants a role to a group on a project, or on an application, a pipeline, an environment or a workflow
+// of the project. The role is granted in addition to the permission level of the group.
+type RoleBinding struct {
+	ID         int64  `json:"id" cli:"id,key"`
+	RoleName   string `json:"role" cli:"role"`
+	GroupID    int64  `json:"group_id,omitempty" cli:"-"`
+	GroupName  string `json:"group" cli:"group"`
+	ProjectID  int64  `json:"project_id,omitempty" cli:"-"`
+	ProjectKey string `json:"project_key,omitempty" cli:"-"`
+	ObjectType string `json:"type" cli:"type"`
+	ObjectName string `json:"name,omitempty" cli:"name"`
+}
+
+// UserPermissionsRoles are the named permissions given by the role bindings, by object
+type UserPermissionsRoles map[string][]string
+
+// RolePermissionKey returns the key of an object in UserPermissionsRoles, the name is empty for a project
+func RolePermissionKey(objectType, projectKey, name string) string {
+	if objectType == RoleBindingProject {
+		return objectType + ":" + projectKey
+	}
+	return objectType + ":" + UserPermissionKey(projectKey, name)
+}
+
+// Add adds named permissions on an object, without duplicates
+func (p UserPermissionsRoles) Add(key string, permissions ...string) {
+	for _, n := range permissions {
+		if !p.Has(key, n) {
+			p[key] = append(p[key], n)
+		}
+	}
+}
+
+// Has returns true if the named permission is given on the object
+func (p UserPermissionsRoles) Has(key, permission string) bool {
+	for _, n := range p[key] {
+		if n == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// BuiltinRoles returns the built-in roles
+func BuiltinRoles() []Role {
+	return []Role{
+		{
+			Name:        RoleRead,
+			Description: "Read permission",
+			Permissions: []string{PermissionNameRead},
+			BuiltIn:     true,
+			Level:       4,
+		},
+		{
+			Name:        RoleReadExecute,
+			Description: "Read and execute permission",
+			Permissions: []string{PermissionNameRead, PermissionNameRun, PermissionNameDeploy, PermissionNameApprove},
+			BuiltIn:     true,
+			Level:       5,
+		},
+		{
+			Name:        RoleReadWriteExecute,
+			Description: "Read, write and execute permission",
+			Permissions: PermissionNames,
+			BuiltIn:     true,
+			Level:       7,
+		},
+	}
+}
+
+// BuiltinRole returns the built-in role with the given name, or nil
+func BuiltinRole(name string) *Role {
+	for _, r := range BuiltinRoles() {
+		if r.Name == name {
+			return &r
+		}
+	}
+	return nil
+}
+
+// BuiltinRoleByLevel returns the built-in role equivalent to a permission level, or nil if the level gives no permission
+func BuiltinRoleByLevel(level int) *Role {
+	roles := BuiltinRoles()
+	for i := len(roles) - 1; i >= 0; i-- {
+		if level >= roles[i].Level {
+			return &roles[i]
+		}
+	}
+	return nil
+}
+
+// Has returns true if the role grants the named permission
+func (r Role) Has(permission string) bool {
+	for _, p := range r.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValid checks the name and the permissions of a role
+func (r Role) IsValid() error {
+	if !roleNamePattern.MatchString(r.Name) {
+		return NewError(ErrInvalidRole, fmt.Errorf("invalid name %s, it should match %s", r.Name, roleNamePattern))
+	}
+	if BuiltinRole(r.Name) != nil {
+		return NewError(ErrInvalidRole, fmt.Errorf("%s is a built-in role", r.Name))
+	}
+	if len(r.Permissions) == 0 {
+		return NewError(ErrInvalidRole, fmt.Errorf("permissions are mandatory"))
+	}
+	for _, p := range r.Permissions {
+		var found bool
+		for _, n := range PermissionNames {
+			if p == n {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return NewError(ErrInvalidRole, fmt.Errorf("unknown permission %s, it should be one of %v", p, PermissionNames))
+		}
+	}
+	return nil
+}
+
+// IsValid checks the type of object of a role binding
+func (b RoleBinding) IsValid() error {
+	if b.RoleName == "" || b.GroupName == "" {
+		return NewError(ErrInvalidRole, fmt.Errorf("role and group are mandatory"))
+	}
+	for _, t := range RoleBindingTypes {
+		if b.ObjectType == t {
+			if (t == RoleBindingProject) != (b.ObjectName == "") {
+				return NewError(ErrInvalidRole, fmt.Errorf("a name is mandatory on a %s, and forbidden on a project", t))
+			}
+			return nil
+		}
+	}
+	return NewError(ErrInvalidRole, fmt.Errorf("unknown type %s, it should be one of %v", b.ObjectType, RoleBindingTypes))
+}
@@ -0,0 +1,33 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuiltinRoleByLevel(t *testing.T) {
+	assert.Nil(t, BuiltinRoleByLevel(0))
+	assert.Equal(t, RoleRead, BuiltinRoleByLevel(4).Name)
+	assert.Equal(t, RoleReadExecute, BuiltinRoleByLevel(5).Name)
+	assert.Equal(t, RoleReadWriteExecute, BuiltinRoleByLevel(7).Name)
+
+	assert.True(t, BuiltinRoleByLevel(5).Has(PermissionNameDeploy))
+	assert.False(t, BuiltinRoleByLevel(5).Has(PermissionNameManageVariables))
+}
+
+func TestRoleIsValid(t *testing.T) {
+	assert.NoError(t, Role{Name: "deployer", Permissions: []string{PermissionNameRead, PermissionNameDeploy}}.IsValid())
+	assert.Error(t, Role{Name: "Deployer", Permissions: []string{PermissionNameRead}}.IsValid())
+	assert.Error(t, Role{Name: RoleRead, Permissions: []string{PermissionNameRead}}.IsValid())
+	assert.Error(t, Role{Name: "deployer"}.IsValid())
+	assert.Error(t, Role{Name: "deployer", Permissions: []string{"delete"}}.IsValid())
+}
+
+func TestRoleBindingIsValid(t *testing.T) {
+	assert.NoError(t, RoleBinding{RoleName: "deployer", GroupName: "ops", ObjectType: RoleBindingProject}.IsValid())
+	assert.NoError(t, RoleBinding{RoleName: "deployer", GroupName: "ops", ObjectType: RoleBindingEnvironment, ObjectName: "prod"}.IsValid())
+	assert.Error(t, RoleBinding{RoleName: "deployer", GroupName: "ops", ObjectType: RoleBindingEnvironment}.IsValid())
+	assert.Error(t, RoleBinding{RoleName: "deployer", GroupName: "ops", ObjectType: RoleBindingProject, ObjectName: "prod"}.IsValid())
+	assert.Error(t, RoleBinding{RoleName: "deployer", GroupName: "ops", ObjectType: "key"}.IsValid())
+}
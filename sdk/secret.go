@@ -0,0 +1,22 @@
+package sdk
+
+import "time"
+
+// SecretReencryptionStatus is the progress of the re-encryption of the stored secrets with the current master key
+type SecretReencryptionStatus struct {
+	KeyID       string                    `json:"key_id" cli:"key_id"`
+	Running     bool                      `json:"running" cli:"running"`
+	Started     *time.Time                `json:"started,omitempty" cli:"started"`
+	Ended       *time.Time                `json:"ended,omitempty" cli:"ended"`
+	Reencrypted int64                     `json:"reencrypted" cli:"reencrypted"`
+	Error       string                    `json:"error,omitempty" cli:"error"`
+	Tables      []SecretReencryptionTable `json:"tables" cli:"-"`
+}
+
+// SecretReencryptionTable counts the secrets of a column, and the secrets already encrypted with the current master key.
+// For the JSON columns storing secrets, it counts the rows and the rows already re-encrypted.
+type SecretReencryptionTable struct {
+	Name  string `json:"name" cli:"name,key"`
+	Total int64  `json:"total" cli:"total"`
+	Done  int64  `json:"done" cli:"done"`
+}
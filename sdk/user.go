@@ -35,6 +35,8 @@ type UserPermissions struct {
 	WorkflowsPerm    UserPermissionsMap `json:"WorkflowsPerm,omitempty"`
 	PipelinesPerm    UserPermissionsMap `json:"PipelinesPerm,omitempty"`
 	EnvironmentsPerm UserPermissionsMap `json:"EnvironmentsPerm,omitempty"`
+	// RolesPerm are the named permissions given by the role bindings
+	RolesPerm UserPermissionsRoles `json:"RolesPerm,omitempty"`
 }
 
 // UserPermissionsMap is a type of map. The in key the key and name of the object and value is the level of permissions
@@ -65,6 +67,21 @@ type UserAPIResponse struct {
 	Token    string `json:"token,omitempty"`
 }
 
+// UserOIDCDeviceAuthorization is the code to enter on the OpenID Connect provider to login from a device without browser
+type UserOIDCDeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval,omitempty"`
+}
+
+// UserOIDCDeviceTokenRequest asks for the session of a device once the user has entered the code
+type UserOIDCDeviceTokenRequest struct {
+	DeviceCode string `json:"device_code"`
+}
+
 // UserEmailPattern  pattern for user email address
 const UserEmailPattern = "(\\w[-._\\w]*\\w@\\w[-._\\w]*\\w\\.\\w{2,3})"
 
@@ -186,6 +186,10 @@ func easyjson9e1087fdDecodeGithubComOvhCdsSdk1(in *jlexer.Lexer, out *UserPermis
 			if data := in.Raw(); in.Ok() {
 				in.AddError((out.EnvironmentsPerm).UnmarshalJSON(data))
 			}
+		case "RolesPerm":
+			if data := in.Raw(); in.Ok() {
+				in.AddError(json.Unmarshal(data, &out.RolesPerm))
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -302,6 +306,16 @@ func easyjson9e1087fdEncodeGithubComOvhCdsSdk1(out *jwriter.Writer, in UserPermi
 		}
 		out.Raw((in.EnvironmentsPerm).MarshalJSON())
 	}
+	if len(in.RolesPerm) != 0 {
+		const prefix string = ",\"RolesPerm\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw(json.Marshal(in.RolesPerm))
+	}
 	out.RawByte('}')
 }
 
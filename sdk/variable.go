@@ -1,6 +1,9 @@
 package sdk
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // Variable represent a variable for a project or pipeline
 type Variable struct {
@@ -27,6 +30,8 @@ const (
 	BooleanVariable    = "boolean"
 	NumberVariable     = "number"
 	RepositoryVariable = "repository"
+	// VaultVariable references a secret stored in a Vault KV v2 engine, resolved when a job starts
+	VaultVariable = "vault"
 )
 
 var (
@@ -38,6 +43,7 @@ var (
 		KeyVariable,
 		BooleanVariable,
 		NumberVariable,
+		VaultVariable,
 	}
 )
 
@@ -51,6 +57,20 @@ func NeedPlaceholder(t string) bool {
 	}
 }
 
+// ParseVaultReference parses the value of a vault variable: <mount>/<path>#<field>
+func ParseVaultReference(ref string) (mount, path, field string, err error) {
+	i := strings.LastIndex(ref, "#")
+	if i < 0 {
+		return "", "", "", ErrInvalidVaultReference
+	}
+	p, field := strings.Trim(ref[:i], "/"), ref[i+1:]
+	j := strings.Index(p, "/")
+	if j <= 0 || j == len(p)-1 || field == "" {
+		return "", "", "", ErrInvalidVaultReference
+	}
+	return p[:j], p[j+1:], field, nil
+}
+
 // VariableFind return a variable given its name if it exists in array
 func VariableFind(vars []Variable, s string) *Variable {
 	for _, v := range vars {
@@ -0,0 +1,20 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVaultReference(t *testing.T) {
+	mount, path, field, err := ParseVaultReference("secret/myapp/db#password")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", mount)
+	assert.Equal(t, "myapp/db", path)
+	assert.Equal(t, "password", field)
+
+	for _, ref := range []string{"secret/myapp/db", "secret#password", "secret/#password", "/myapp#password", "secret/myapp#"} {
+		_, _, _, err := ParseVaultReference(ref)
+		assert.Error(t, err, ref)
+	}
+}
@@ -41,6 +41,8 @@ type VCSAuthorizedClient interface {
 	Commits(ctx context.Context, repo, branch, since, until string) ([]VCSCommit, error)
 	Commit(ctx context.Context, repo, hash string) (VCSCommit, error)
 	CommitsBetweenRefs(ctx context.Context, repo, base, head string) ([]VCSCommit, error)
+	// ChangedFiles returns the files changed between base and head, or by the head commit if base is empty
+	ChangedFiles(ctx context.Context, repo, base, head string) ([]string, error)
 
 	// PullRequests
 	PullRequests(context.Context, string) ([]VCSPullRequest, error)
@@ -21,6 +21,12 @@ type Worker struct {
 	Uptodate      bool      `json:"up_to_date" cli:"-"`
 }
 
+// WorkerHeartbeat is returned to a worker refreshing its last beat
+type WorkerHeartbeat struct {
+	// KillJobID is set when the job run by the worker has been stopped by the API, ie: on timeout
+	KillJobID int64 `json:"kill_job_id,omitempty"`
+}
+
 // WorkerRegistrationForm represents the arguments needed to register a worker
 type WorkerRegistrationForm struct {
 	Name               string
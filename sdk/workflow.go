@@ -38,6 +38,7 @@ type Workflow struct {
 	Usage                   *Usage                 `json:"usage,omitempty" db:"-" cli:"-"`
 	HistoryLength           int64                  `json:"history_length" db:"history_length" cli:"-"`
 	PurgeTags               []string               `json:"purge_tags,omitempty" db:"-" cli:"-"`
+	CancelSupersededRuns    bool                   `json:"cancel_superseded_runs,omitempty" db:"cancel_superseded_runs" cli:"-"`
 	Notifications           []WorkflowNotification `json:"notifications,omitempty" db:"-" cli:"-"`
 	FromRepository          string                 `json:"from_repository,omitempty" db:"from_repository" cli:"from"`
 	DerivedFromWorkflowID   int64                  `json:"derived_from_workflow_id,omitempty" db:"derived_from_workflow_id" cli:"-"`
@@ -113,6 +114,12 @@ func ParseWorkflowUserNotificationSettings(t UserNotificationSettingsType, userN
 			return nil, ErrParseUserNotification
 		}
 		return &x, nil
+	case WebhookUserNotification:
+		var x WebhookUserNotificationSettings
+		if err := json.Unmarshal(userNotif, &x); err != nil {
+			return nil, ErrParseUserNotification
+		}
+		return &x, nil
 	default:
 		return nil, ErrNotSupportedUserNotification
 	}
@@ -874,9 +881,10 @@ type WorkflowNodeTrigger struct {
 	WorkflowDestNode   WorkflowNode `json:"workflow_dest_node" db:"-"`
 }
 
-//WorkflowNodeConditions is either an array of WorkflowNodeCondition or a lua script
+//WorkflowNodeConditions is either an array of WorkflowNodeCondition and a boolean expression, or a lua script
 type WorkflowNodeConditions struct {
 	PlainConditions []WorkflowNodeCondition `json:"plain,omitempty" yaml:"check,omitempty"`
+	Expression      string                  `json:"expression,omitempty" yaml:"expression,omitempty"`
 	LuaScript       string                  `json:"lua_script,omitempty" yaml:"script,omitempty"`
 }
 
@@ -889,18 +897,20 @@ type WorkflowNodeCondition struct {
 
 //WorkflowNodeContext represents a context attached on a node
 type WorkflowNodeContext struct {
-	ID                        int64                  `json:"id" db:"id"`
-	WorkflowNodeID            int64                  `json:"workflow_node_id" db:"workflow_node_id"`
-	ApplicationID             int64                  `json:"application_id" db:"application_id"`
-	Application               *Application           `json:"application,omitempty" db:"-"`
-	Environment               *Environment           `json:"environment,omitempty" db:"-"`
-	EnvironmentID             int64                  `json:"environment_id" db:"environment_id"`
-	ProjectPlatform           *ProjectPlatform       `json:"project_platform" db:"-"`
-	ProjectPlatformID         int64                  `json:"project_platform_id" db:"project_platform_id"`
-	DefaultPayload            interface{}            `json:"default_payload,omitempty" db:"-"`
-	DefaultPipelineParameters []Parameter            `json:"default_pipeline_parameters,omitempty" db:"-"`
-	Conditions                WorkflowNodeConditions `json:"conditions,omitempty" db:"-"`
-	Mutex                     bool                   `json:"mutex"`
+	ID                        int64                    `json:"id" db:"id"`
+	WorkflowNodeID            int64                    `json:"workflow_node_id" db:"workflow_node_id"`
+	ApplicationID             int64                    `json:"application_id" db:"application_id"`
+	Application               *Application             `json:"application,omitempty" db:"-"`
+	Environment               *Environment             `json:"environment,omitempty" db:"-"`
+	EnvironmentID             int64                    `json:"environment_id" db:"environment_id"`
+	ProjectPlatform           *ProjectPlatform         `json:"project_platform" db:"-"`
+	ProjectPlatformID         int64                    `json:"project_platform_id" db:"project_platform_id"`
+	DefaultPayload            interface{}              `json:"default_payload,omitempty" db:"-"`
+	DefaultPipelineParameters []Parameter              `json:"default_pipeline_parameters,omitempty" db:"-"`
+	Conditions                WorkflowNodeConditions   `json:"conditions,omitempty" db:"-"`
+	Mutex                     bool                     `json:"mutex"`
+	Approval                  *WorkflowNodeApproval    `json:"approval,omitempty" db:"-"`
+	Concurrency               *WorkflowNodeConcurrency `json:"concurrency,omitempty" db:"-"`
 }
 
 // HasDefaultPayload returns true if the node has a default payload
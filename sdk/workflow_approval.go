@@ -0,0 +1,175 @@
+package sdk
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Approval status of a workflow node run
+const (
+	ApprovalPending  = "Pending"
+	ApprovalApproved = "Approved"
+	ApprovalRejected = "Rejected"
+	ApprovalExpired  = "Expired"
+)
+
+// WorkflowNodeApproval is an approval gate on a workflow node: the node run waits for the approval
+// of distinct eligible users before starting. If no group and no user are set, all the users
+// allowed to execute the workflow are eligible.
+type WorkflowNodeApproval struct {
+	Groups       []string `json:"groups,omitempty" yaml:"groups,omitempty"`
+	Users        []string `json:"users,omitempty" yaml:"users,omitempty"`
+	MinApprovers int      `json:"min_approvers,omitempty" yaml:"min_approvers,omitempty"`
+	// ForbidCommitAuthor forbids the author of the commit to approve the node run
+	ForbidCommitAuthor bool `json:"forbid_commit_author,omitempty" yaml:"forbid_commit_author,omitempty"`
+	// Timeout in seconds after which a pending approval expires, 0 for no timeout
+	Timeout int64 `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// IsValid checks the approval gate
+func (a WorkflowNodeApproval) IsValid() error {
+	if a.MinApprovers < 0 {
+		return NewError(ErrInvalidApprovalGate, fmt.Errorf("min_approvers must be positive"))
+	}
+	if a.Timeout < 0 {
+		return NewError(ErrInvalidApprovalGate, fmt.Errorf("timeout must be positive"))
+	}
+	if len(a.Users) > 0 && len(a.Groups) == 0 && a.RequiredApprovers() > len(a.Users) {
+		return NewError(ErrInvalidApprovalGate, fmt.Errorf("%d approvers are required but only %d users are eligible", a.RequiredApprovers(), len(a.Users)))
+	}
+	return nil
+}
+
+// RequiredApprovers returns the number of distinct approvers needed, at least one
+func (a WorkflowNodeApproval) RequiredApprovers() int {
+	if a.MinApprovers < 1 {
+		return 1
+	}
+	return a.MinApprovers
+}
+
+// IsEligible returns true if the user is listed in the users of the gate or is a member of one of its groups
+func (a WorkflowNodeApproval) IsEligible(u *User) bool {
+	if len(a.Users) == 0 && len(a.Groups) == 0 {
+		return true
+	}
+	for _, username := range a.Users {
+		if username == u.Username {
+			return true
+		}
+	}
+	for _, g := range u.Groups {
+		for _, name := range a.Groups {
+			if g.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WorkflowNodeRunApproval is the state of the approval gate of a workflow node run
+type WorkflowNodeRunApproval struct {
+	Gate              WorkflowNodeApproval              `json:"gate"`
+	Status            string                            `json:"status"`
+	CommitAuthorEmail string                            `json:"commit_author_email,omitempty"`
+	Expire            *time.Time                        `json:"expire,omitempty"`
+	Decisions         []WorkflowNodeRunApprovalDecision `json:"decisions,omitempty"`
+}
+
+// WorkflowNodeRunApprovalDecision is the approval or the rejection of a workflow node run by a user
+type WorkflowNodeRunApprovalDecision struct {
+	Username string    `json:"username"`
+	Approved bool      `json:"approved"`
+	Comment  string    `json:"comment,omitempty"`
+	Date     time.Time `json:"date"`
+}
+
+// WorkflowNodeRunApprovalAudit records a decision on the approval gate of a workflow node run
+type WorkflowNodeRunApprovalAudit struct {
+	ID                int64     `json:"id" db:"id" cli:"-"`
+	ProjectID         int64     `json:"project_id" db:"project_id" cli:"-"`
+	WorkflowID        int64     `json:"workflow_id" db:"workflow_id" cli:"-"`
+	WorkflowName      string    `json:"workflow_name" db:"workflow_name" cli:"workflow"`
+	Number            int64     `json:"num" db:"num" cli:"run"`
+	WorkflowNodeRunID int64     `json:"workflow_node_run_id" db:"workflow_node_run_id" cli:"-"`
+	WorkflowNodeName  string    `json:"workflow_node_name" db:"workflow_node_name" cli:"node"`
+	Username          string    `json:"username" db:"username" cli:"username"`
+	Approved          bool      `json:"approved" db:"approved" cli:"approved"`
+	Comment           string    `json:"comment" db:"comment" cli:"comment"`
+	Status            string    `json:"status" db:"status" cli:"status"`
+	Date              time.Time `json:"date" db:"date" cli:"date"`
+}
+
+// WorkflowNodeRunApprovalRequest is the body of an approval request on a workflow node run
+type WorkflowNodeRunApprovalRequest struct {
+	Approved bool   `json:"approved"`
+	Comment  string `json:"comment"`
+}
+
+// NewWorkflowNodeRunApproval returns the pending state of an approval gate
+func NewWorkflowNodeRunApproval(gate WorkflowNodeApproval, commitAuthorEmail string) *WorkflowNodeRunApproval {
+	a := &WorkflowNodeRunApproval{Gate: gate, Status: ApprovalPending, CommitAuthorEmail: commitAuthorEmail}
+	if gate.Timeout > 0 {
+		expire := time.Now().Add(time.Duration(gate.Timeout) * time.Second)
+		a.Expire = &expire
+	}
+	return a
+}
+
+// Approvers returns the usernames of the users who approved the node run
+func (a WorkflowNodeRunApproval) Approvers() []string {
+	var res []string
+	for _, d := range a.Decisions {
+		if d.Approved {
+			res = append(res, d.Username)
+		}
+	}
+	return res
+}
+
+// IsCommitAuthor returns true if the verified email of the user is the email of the author of the commit
+func (a WorkflowNodeRunApproval) IsCommitAuthor(u *User) bool {
+	if a.CommitAuthorEmail == "" || u.Email == "" || !u.Auth.EmailVerified {
+		return false
+	}
+	return strings.EqualFold(u.Email, a.CommitAuthorEmail)
+}
+
+// Decide records the decision of the user and updates the status of the approval
+func (a *WorkflowNodeRunApproval) Decide(u *User, approved bool, comment string) error {
+	if a.Status != ApprovalPending {
+		return ErrApprovalNotPending
+	}
+	if !a.Gate.IsEligible(u) {
+		return ErrApprovalForbidden
+	}
+	if a.Gate.ForbidCommitAuthor && a.IsCommitAuthor(u) {
+		return NewError(ErrApprovalForbidden, fmt.Errorf("the author of the commit cannot approve this pipeline"))
+	}
+	for _, d := range a.Decisions {
+		if d.Username == u.Username {
+			return ErrApprovalAlreadyDone
+		}
+	}
+
+	a.Decisions = append(a.Decisions, WorkflowNodeRunApprovalDecision{
+		Username: u.Username,
+		Approved: approved,
+		Comment:  comment,
+		Date:     time.Now(),
+	})
+	switch {
+	case !approved:
+		a.Status = ApprovalRejected
+	case len(a.Approvers()) >= a.Gate.RequiredApprovers():
+		a.Status = ApprovalApproved
+	}
+	return nil
+}
+
+// String returns the status of the approval with the number of approvers, ie. Pending (1/2)
+func (a WorkflowNodeRunApproval) String() string {
+	return fmt.Sprintf("%s (%d/%d)", a.Status, len(a.Approvers()), a.Gate.RequiredApprovers())
+}
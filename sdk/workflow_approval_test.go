@@ -0,0 +1,34 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowNodeRunApproval_Decide(t *testing.T) {
+	gate := WorkflowNodeApproval{Groups: []string{"ops"}, Users: []string{"carol"}, MinApprovers: 2, ForbidCommitAuthor: true}
+	ops := []Group{{Name: "ops"}}
+	a := NewWorkflowNodeRunApproval(gate, "alice@corp.com")
+	assert.Nil(t, a.Expire)
+
+	assert.Equal(t, ErrApprovalForbidden, a.Decide(&User{Username: "dave"}, true, ""))
+	err := a.Decide(&User{Username: "alice", Email: "Alice@corp.com", Auth: Auth{EmailVerified: true}, Groups: ops}, true, "")
+	assert.True(t, ErrorIs(err, ErrApprovalForbidden))
+	// only the verified email of the user is matched with the commit author
+	assert.False(t, a.IsCommitAuthor(&User{Username: "alice@corp.com", Fullname: "alice@corp.com", Email: "alice@corp.com"}))
+
+	assert.NoError(t, a.Decide(&User{Username: "bob", Groups: ops}, true, "lgtm"))
+	assert.Equal(t, ErrApprovalAlreadyDone, a.Decide(&User{Username: "bob", Groups: ops}, true, ""))
+	assert.Equal(t, "Pending (1/2)", a.String())
+
+	assert.NoError(t, a.Decide(&User{Username: "carol"}, true, ""))
+	assert.Equal(t, ApprovalApproved, a.Status)
+	assert.Equal(t, []string{"bob", "carol"}, a.Approvers())
+	assert.Equal(t, ErrApprovalNotPending, a.Decide(&User{Username: "erin", Groups: ops}, false, ""))
+
+	a = NewWorkflowNodeRunApproval(WorkflowNodeApproval{Timeout: 60}, "")
+	assert.NotNil(t, a.Expire)
+	assert.NoError(t, a.Decide(&User{Username: "bob"}, false, "not now"))
+	assert.Equal(t, ApprovalRejected, a.Status)
+}
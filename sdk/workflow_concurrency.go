@@ -0,0 +1,54 @@
+package sdk
+
+import (
+	"fmt"
+)
+
+// Scopes of a concurrency group
+const (
+	ConcurrencyScopeProject  = "project"
+	ConcurrencyScopeInstance = "instance"
+)
+
+// Status of a workflow node run in its concurrency group
+const (
+	ConcurrencyQueued  = "Queued"
+	ConcurrencyRunning = "Running"
+)
+
+// WorkflowNodeConcurrency puts a workflow node in a named concurrency group: the node runs of a group, from
+// all the workflows of the project or of the instance, never run at the same time and are queued in FIFO order.
+type WorkflowNodeConcurrency struct {
+	Group string `json:"group" yaml:"group"`
+	// Scope of the group, project by default
+	Scope string `json:"scope,omitempty" yaml:"scope,omitempty"`
+	// CancelSuperseded stops the queued runs of the node on the same branch when a new run is queued
+	CancelSuperseded bool `json:"cancel_superseded,omitempty" yaml:"cancel_superseded,omitempty"`
+}
+
+// IsValid checks the concurrency group
+func (c WorkflowNodeConcurrency) IsValid() error {
+	if !NamePatternRegex.MatchString(c.Group) {
+		return NewError(ErrInvalidConcurrencyGroup, fmt.Errorf("group name %s should match %s", c.Group, NamePattern))
+	}
+	switch c.Scope {
+	case "", ConcurrencyScopeProject, ConcurrencyScopeInstance:
+		return nil
+	}
+	return NewError(ErrInvalidConcurrencyGroup, fmt.Errorf("scope must be %s or %s", ConcurrencyScopeProject, ConcurrencyScopeInstance))
+}
+
+// Key returns the identifier of the group, the name of a project group is prefixed by the project key
+func (c WorkflowNodeConcurrency) Key(projectKey string) string {
+	if c.Scope == ConcurrencyScopeInstance {
+		return c.Group
+	}
+	return projectKey + "/" + c.Group
+}
+
+// WorkflowNodeRunConcurrency is the state of a workflow node run in its concurrency group
+type WorkflowNodeRunConcurrency struct {
+	Group  string `json:"group"`
+	Key    string `json:"key"`
+	Status string `json:"status"`
+}
@@ -0,0 +1,21 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowNodeConcurrency(t *testing.T) {
+	c := WorkflowNodeConcurrency{Group: "deploy-prod"}
+	assert.NoError(t, c.IsValid())
+	assert.Equal(t, "PROJ/deploy-prod", c.Key("PROJ"))
+
+	c.Scope = ConcurrencyScopeInstance
+	assert.NoError(t, c.IsValid())
+	assert.Equal(t, "deploy-prod", c.Key("PROJ"))
+
+	c.Scope = "region"
+	assert.True(t, ErrorIs(c.IsValid(), ErrInvalidConcurrencyGroup))
+	assert.True(t, ErrorIs(WorkflowNodeConcurrency{Group: "deploy prod"}.IsValid(), ErrInvalidConcurrencyGroup))
+}
@@ -3,7 +3,11 @@ package sdk
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/blang/semver"
 
 	"github.com/ovh/cds/sdk/interpolate"
 )
@@ -17,6 +21,11 @@ const (
 	WorkflowConditionsOperatorGreaterThan        = "gt"
 	WorkflowConditionsOperatorGreaterOrEqualThan = "ge"
 	WorkflowConditionsOperatorRegex              = "regex"
+	WorkflowConditionsOperatorIn                 = "in"
+	WorkflowConditionsOperatorNotIn              = "notin"
+	WorkflowConditionsOperatorContains           = "contains"
+	WorkflowConditionsOperatorStartsWith         = "startswith"
+	WorkflowConditionsOperatorExists             = "exists"
 )
 
 // Workflow conditions operator
@@ -29,9 +38,17 @@ var (
 		WorkflowConditionsOperatorGreaterThan:        ">",
 		WorkflowConditionsOperatorGreaterOrEqualThan: ">=",
 		WorkflowConditionsOperatorRegex:              "match",
+		WorkflowConditionsOperatorIn:                 "in",
+		WorkflowConditionsOperatorNotIn:              "not in",
+		WorkflowConditionsOperatorContains:           "contains",
+		WorkflowConditionsOperatorStartsWith:         "starts with",
+		WorkflowConditionsOperatorExists:             "exists",
 	}
 )
 
+// date layouts accepted by ordering operators
+var workflowConditionsDateLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"}
+
 //WorkflowCheckConditions checks conditions given a list of parameters
 func WorkflowCheckConditions(conditions []WorkflowNodeCondition, params []Parameter) (bool, error) {
 	mapParams := ParametersToMap(params)
@@ -59,16 +76,16 @@ func WorkflowCheckConditions(conditions []WorkflowNodeCondition, params []Parame
 			conditionsOK = conditionsOK && cond.Value != mapParams[cond.Variable]
 
 		case WorkflowConditionsOperatorLessThan:
-			conditionsOK = conditionsOK && strings.Compare(mapParams[cond.Variable], cond.Value) < 0
+			conditionsOK = conditionsOK && WorkflowConditionsCompare(mapParams[cond.Variable], cond.Value) < 0
 
 		case WorkflowConditionsOperatorLessOrEqualThan:
-			conditionsOK = conditionsOK && strings.Compare(mapParams[cond.Variable], cond.Value) <= 0
+			conditionsOK = conditionsOK && WorkflowConditionsCompare(mapParams[cond.Variable], cond.Value) <= 0
 
 		case WorkflowConditionsOperatorGreaterThan:
-			conditionsOK = conditionsOK && strings.Compare(mapParams[cond.Variable], cond.Value) > 0
+			conditionsOK = conditionsOK && WorkflowConditionsCompare(mapParams[cond.Variable], cond.Value) > 0
 
 		case WorkflowConditionsOperatorGreaterOrEqualThan:
-			conditionsOK = conditionsOK && strings.Compare(mapParams[cond.Variable], cond.Value) >= 0
+			conditionsOK = conditionsOK && WorkflowConditionsCompare(mapParams[cond.Variable], cond.Value) >= 0
 
 		case WorkflowConditionsOperatorRegex:
 			match, err := regexp.MatchString(cond.Value, mapParams[cond.Variable])
@@ -76,8 +93,134 @@ func WorkflowCheckConditions(conditions []WorkflowNodeCondition, params []Parame
 				return false, fmt.Errorf("Unable to match string with regex %s (%v)", cond.Value, err)
 			}
 			conditionsOK = conditionsOK && match
+
+		case WorkflowConditionsOperatorIn:
+			conditionsOK = conditionsOK && workflowConditionsInList(mapParams[cond.Variable], cond.Value)
+
+		case WorkflowConditionsOperatorNotIn:
+			conditionsOK = conditionsOK && !workflowConditionsInList(mapParams[cond.Variable], cond.Value)
+
+		case WorkflowConditionsOperatorContains:
+			conditionsOK = conditionsOK && strings.Contains(mapParams[cond.Variable], cond.Value)
+
+		case WorkflowConditionsOperatorStartsWith:
+			conditionsOK = conditionsOK && strings.HasPrefix(mapParams[cond.Variable], cond.Value)
+
+		case WorkflowConditionsOperatorExists:
+			_, exists := mapParams[cond.Variable]
+			// An empty value or "true" checks the existence, "false" checks the absence
+			if cond.Value == "false" {
+				exists = !exists
+			}
+			conditionsOK = conditionsOK && exists
 		}
 	}
 
 	return conditionsOK, nil
 }
+
+// WorkflowConditionsCompare compares two values and returns an integer: 0 if a==b, -1 if a < b, and +1 if a > b.
+// Values are compared as integers, semantic versions, versions made of dot-separated numbers, floats or dates when both
+// values share one of these types, and as strings otherwise.
+func WorkflowConditionsCompare(a, b string) int {
+	if ia, erra := strconv.ParseInt(a, 10, 64); erra == nil {
+		if ib, errb := strconv.ParseInt(b, 10, 64); errb == nil {
+			switch {
+			case ia < ib:
+				return -1
+			case ia > ib:
+				return 1
+			}
+			return 0
+		}
+	}
+
+	if va, erra := semver.Parse(strings.TrimPrefix(a, "v")); erra == nil {
+		if vb, errb := semver.Parse(strings.TrimPrefix(b, "v")); errb == nil {
+			return va.Compare(vb)
+		}
+	}
+
+	if sa, oka := workflowConditionsVersionSegments(a); oka {
+		if sb, okb := workflowConditionsVersionSegments(b); okb {
+			for i := 0; i < len(sa) || i < len(sb); i++ {
+				var na, nb int64
+				if i < len(sa) {
+					na = sa[i]
+				}
+				if i < len(sb) {
+					nb = sb[i]
+				}
+				switch {
+				case na < nb:
+					return -1
+				case na > nb:
+					return 1
+				}
+			}
+			return 0
+		}
+	}
+
+	if fa, erra := strconv.ParseFloat(a, 64); erra == nil {
+		if fb, errb := strconv.ParseFloat(b, 64); errb == nil {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			}
+			return 0
+		}
+	}
+
+	for _, layout := range workflowConditionsDateLayouts {
+		ta, erra := time.Parse(layout, a)
+		if erra != nil {
+			continue
+		}
+		tb, errb := time.Parse(layout, b)
+		if errb != nil {
+			continue
+		}
+		switch {
+		case ta.Before(tb):
+			return -1
+		case ta.After(tb):
+			return 1
+		}
+		return 0
+	}
+
+	return strings.Compare(a, b)
+}
+
+// workflowConditionsVersionSegments returns the numbers of a version made of dot-separated numbers, such as 1.10 or 1.2.3.4
+func workflowConditionsVersionSegments(v string) ([]int64, bool) {
+	segments := strings.Split(v, ".")
+	if len(segments) < 2 {
+		return nil, false
+	}
+	res := make([]int64, len(segments))
+	for i, s := range segments {
+		if s == "" || strings.TrimLeft(s, "0123456789") != "" {
+			return nil, false
+		}
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, false
+		}
+		res[i] = n
+	}
+	return res, true
+}
+
+// workflowConditionsInList checks if value is one of the comma separated items of list
+func workflowConditionsInList(value, list string) bool {
+	for _, item := range strings.Split(list, ",") {
+		if strings.TrimSpace(item) == value {
+			return true
+		}
+	}
+	return false
+}
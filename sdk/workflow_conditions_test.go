@@ -0,0 +1,65 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowConditionsCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "10", b: "9", want: 1},
+		{a: "9", b: "10", want: -1},
+		{a: "42", b: "42", want: 0},
+		{a: "1.10.0", b: "1.9.0", want: 1},
+		{a: "v2.0.0", b: "v2.0.0-rc1", want: 1},
+		{a: "1.10", b: "1.9", want: 1},
+		{a: "1.5", b: "1.25", want: -1},
+		{a: "1.2.3.10", b: "1.2.3.9", want: 1},
+		{a: "1.2", b: "1.2.0", want: 0},
+		{a: "1.2", b: "10", want: -1},
+		{a: "-1.5", b: "-2", want: 1},
+		{a: "1e3", b: "999", want: 1},
+		{a: "2018-07-01", b: "2018-06-30", want: 1},
+		{a: "2018-07-01T10:00:00Z", b: "2018-07-01T11:00:00Z", want: -1},
+		{a: "abc", b: "abd", want: -1},
+		{a: "10", b: "abc", want: -1},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, WorkflowConditionsCompare(tt.a, tt.b), "compare %s and %s", tt.a, tt.b)
+	}
+}
+
+func TestWorkflowCheckConditions(t *testing.T) {
+	params := []Parameter{
+		{Name: "cds.version", Type: StringParameter, Value: "10"},
+		{Name: "git.branch", Type: StringParameter, Value: "feat/conditions"},
+		{Name: "git.tag", Type: StringParameter, Value: "1.10.0"},
+	}
+
+	tests := []struct {
+		name      string
+		condition WorkflowNodeCondition
+		want      bool
+	}{
+		{"gt integer", WorkflowNodeCondition{Variable: "cds.version", Operator: WorkflowConditionsOperatorGreaterThan, Value: "9"}, true},
+		{"le integer", WorkflowNodeCondition{Variable: "cds.version", Operator: WorkflowConditionsOperatorLessOrEqualThan, Value: "9"}, false},
+		{"ge semver", WorkflowNodeCondition{Variable: "git.tag", Operator: WorkflowConditionsOperatorGreaterOrEqualThan, Value: "1.9.0"}, true},
+		{"in", WorkflowNodeCondition{Variable: "git.branch", Operator: WorkflowConditionsOperatorIn, Value: "master, feat/conditions"}, true},
+		{"not in", WorkflowNodeCondition{Variable: "git.branch", Operator: WorkflowConditionsOperatorNotIn, Value: "master,develop"}, true},
+		{"contains", WorkflowNodeCondition{Variable: "git.branch", Operator: WorkflowConditionsOperatorContains, Value: "cond"}, true},
+		{"starts with", WorkflowNodeCondition{Variable: "git.branch", Operator: WorkflowConditionsOperatorStartsWith, Value: "master"}, false},
+		{"exists", WorkflowNodeCondition{Variable: "git.tag", Operator: WorkflowConditionsOperatorExists}, true},
+		{"not exists", WorkflowNodeCondition{Variable: "git.hash", Operator: WorkflowConditionsOperatorExists, Value: "false"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := WorkflowCheckConditions([]WorkflowNodeCondition{tt.condition}, params)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, ok)
+		})
+	}
+}
@@ -21,6 +21,60 @@ func (w *Workflow) FilterHooksConfig(s ...string) {
 	}
 }
 
+// WithMaskedSecrets returns a copy of the workflow whose hook and notification secrets are masked, the hooks and
+// the notifications of w are not modified
+func (w Workflow) WithMaskedSecrets() Workflow {
+	if w.Root != nil {
+		root := w.Root.withMaskedHookSecrets()
+		w.Root = &root
+	}
+	if w.Joins != nil {
+		joins := make([]WorkflowNodeJoin, len(w.Joins))
+		for i, j := range w.Joins {
+			triggers := make([]WorkflowNodeJoinTrigger, len(j.Triggers))
+			for k, t := range j.Triggers {
+				t.WorkflowDestNode = t.WorkflowDestNode.withMaskedHookSecrets()
+				triggers[k] = t
+			}
+			j.Triggers = triggers
+			joins[i] = j
+		}
+		w.Joins = joins
+	}
+	if w.Notifications != nil {
+		notifs := make([]WorkflowNotification, len(w.Notifications))
+		for i, n := range w.Notifications {
+			if s, ok := n.Settings.(*WebhookUserNotificationSettings); ok {
+				n.Settings = s.WithMaskedSecrets()
+			}
+			notifs[i] = n
+		}
+		w.Notifications = notifs
+	}
+	return w
+}
+
+func (n WorkflowNode) withMaskedHookSecrets() WorkflowNode {
+	if n.Hooks != nil {
+		hooks := make([]WorkflowNodeHook, len(n.Hooks))
+		for i, h := range n.Hooks {
+			h.Config = h.Config.Clone()
+			h.Config.MaskSecrets()
+			hooks[i] = h
+		}
+		n.Hooks = hooks
+	}
+	if n.Triggers != nil {
+		triggers := make([]WorkflowNodeTrigger, len(n.Triggers))
+		for i, t := range n.Triggers {
+			t.WorkflowDestNode = t.WorkflowDestNode.withMaskedHookSecrets()
+			triggers[i] = t
+		}
+		n.Triggers = triggers
+	}
+	return n
+}
+
 // GetHooks returns the list of all hooks in the workflow tree
 func (w *Workflow) GetHooks() map[string]WorkflowNodeHook {
 	if w == nil {
@@ -96,17 +150,45 @@ const WorkflowHookModelBuiltin = "builtin"
 //WorkflowNodeHookConfig represents the configguration for a WorkflowNodeHook
 type WorkflowNodeHookConfig map[string]WorkflowNodeHookConfigValue
 
-//Values return values of the WorkflowNodeHookConfig
+//Values return values of the WorkflowNodeHookConfig, the secrets are masked
 func (cfg WorkflowNodeHookConfig) Values() map[string]string {
 	r := make(map[string]string)
 	for k, v := range cfg {
 		if v.Configurable {
 			r[k] = v.Value
+			if cfg.IsSecret(k) && v.Value != "" {
+				r[k] = PasswordPlaceholder
+			}
 		}
 	}
 	return r
 }
 
+// IsSecret returns true if the value of the key is a secret: it is encrypted in database and masked in the API responses and the exports
+func (cfg WorkflowNodeHookConfig) IsSecret(k string) bool {
+	v, ok := cfg[k]
+	return ok && (v.Type == HookConfigTypePassword || k == WebHookModelConfigSecret || k == RepositoryWebHookModelSecret)
+}
+
+// MaskSecrets replaces the values of the secrets by the password placeholder
+func (cfg WorkflowNodeHookConfig) MaskSecrets() {
+	for k, v := range cfg {
+		if cfg.IsSecret(k) && v.Value != "" {
+			v.Value = PasswordPlaceholder
+			cfg[k] = v
+		}
+	}
+}
+
+// Clone returns a copy of the configuration
+func (cfg WorkflowNodeHookConfig) Clone() WorkflowNodeHookConfig {
+	c := make(WorkflowNodeHookConfig, len(cfg))
+	for k, v := range cfg {
+		c[k] = v
+	}
+	return c
+}
+
 // WorkflowNodeHookConfigValue represents the value of a node hook config
 type WorkflowNodeHookConfigValue struct {
 	Value        string `json:"value"`
@@ -119,6 +201,8 @@ const (
 	HookConfigTypeString = "string"
 	// HookConfigTypePlatform type platform
 	HookConfigTypePlatform = "platform"
+	// HookConfigTypePassword type password
+	HookConfigTypePassword = "password"
 )
 
 //WorkflowHookModel represents a hook which can be used in workflows.
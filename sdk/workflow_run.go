@@ -176,6 +176,8 @@ type WorkflowNodeRun struct {
 	VCSServer             string                             `json:"vcs_server"`
 	CanBeRun              bool                               `json:"can_be_run"`
 	Header                WorkflowRunHeaders                 `json:"header,omitempty"`
+	Approval              *WorkflowNodeRunApproval           `json:"approval,omitempty"`
+	Concurrency           *WorkflowNodeRunConcurrency        `json:"concurrency,omitempty"`
 }
 
 // WorkflowNodeRunVulnerabilityReport represents vulnerabilities report for the current node run
@@ -249,6 +251,30 @@ type WorkflowNodeRunArtifact struct {
 	Created           time.Time `json:"created,omitempty" db:"created"`
 	TempURL           string    `json:"temp_url,omitempty" db:"-"`
 	TempURLSecretKey  string    `json:"-" db:"-"`
+	BlobSHA512sum     string    `json:"-" db:"blob_sha512sum"`
+}
+
+// WorkflowArtifactBlob is the content of workflow node run artifacts, stored once for all the artifacts with the same SHA512.
+// A blob is verified if its SHA512 has been computed by the API.
+type WorkflowArtifactBlob struct {
+	SHA512sum  string    `json:"sha512sum" db:"sha512sum"`
+	Size       int64     `json:"size" db:"size"`
+	Container  string    `json:"container" db:"container"`
+	Name       string    `json:"name" db:"name"`
+	ObjectPath string    `json:"object_path" db:"object_path"`
+	Created    time.Time `json:"created" db:"created"`
+	LastUsed   time.Time `json:"last_used" db:"last_used"`
+	Verified   bool      `json:"verified" db:"verified"`
+}
+
+//GetName returns the name of the blob object
+func (b *WorkflowArtifactBlob) GetName() string {
+	return b.Name
+}
+
+//GetPath returns the path of the blob object
+func (b *WorkflowArtifactBlob) GetPath() string {
+	return b.Container
 }
 
 // Equal returns true if w WorkflowNodeRunArtifact equals c
@@ -280,6 +306,7 @@ type WorkflowNodeJobRun struct {
 	Parameters             []Parameter        `json:"parameters,omitempty"`
 	Status                 string             `json:"status"`
 	Retry                  int                `json:"retry"`
+	Attempt                int                `json:"attempt"`
 	SpawnAttempts          []int64            `json:"spawn_attempts,omitempty"`
 	Queued                 time.Time          `json:"queued,omitempty"`
 	QueuedSeconds          int64              `json:"queued_seconds,omitempty"`
@@ -352,6 +379,8 @@ type WorkflowNodeRunManual struct {
 	Payload            interface{} `json:"payload" db:"-"`
 	PipelineParameters []Parameter `json:"pipeline_parameter" db:"-"`
 	User               User        `json:"user" db:"-"`
+	// FreezeOverride starts the pipelines despite the active freeze windows, if the user is allowed to
+	FreezeOverride bool `json:"freeze_override,omitempty" db:"-"`
 }
 
 //GetName returns the name the artifact
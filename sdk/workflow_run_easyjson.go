@@ -71,6 +71,8 @@ func easyjsonD7860c2dDecodeGithubComOvhCdsSdk(in *jlexer.Lexer, out *WorkflowNod
 			out.Status = string(in.String())
 		case "retry":
 			out.Retry = int(in.Int())
+		case "attempt":
+			out.Attempt = int(in.Int())
 		case "spawn_attempts":
 			if in.IsNull() {
 				in.Skip()
@@ -298,6 +300,16 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk(out *jwriter.Writer, in WorkflowNo
 		}
 		out.Int(int(in.Retry))
 	}
+	{
+		const prefix string = ",\"attempt\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Attempt))
+	}
 	if len(in.SpawnAttempts) != 0 {
 		const prefix string = ",\"spawn_attempts\":"
 		if first {
@@ -545,6 +557,29 @@ func easyjsonD7860c2dDecodeGithubComOvhCdsSdk6(in *jlexer.Lexer, out *GRPCPlugin
 			out.SHA512sum = string(in.String())
 		case "temp_url":
 			out.TempURL = string(in.String())
+		case "entrypoints":
+			if in.IsNull() {
+				in.Skip()
+				out.Entrypoints = nil
+			} else {
+				in.Delim('[')
+				if out.Entrypoints == nil {
+					if !in.IsDelim(']') {
+						out.Entrypoints = make([]string, 0, 4)
+					} else {
+						out.Entrypoints = []string{}
+					}
+				} else {
+					out.Entrypoints = (out.Entrypoints)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v18 string
+					v18 = string(in.String())
+					out.Entrypoints = append(out.Entrypoints, v18)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		case "cmd":
 			out.Cmd = string(in.String())
 		case "args":
@@ -563,9 +598,9 @@ func easyjsonD7860c2dDecodeGithubComOvhCdsSdk6(in *jlexer.Lexer, out *GRPCPlugin
 					out.Args = (out.Args)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v18 string
-					v18 = string(in.String())
-					out.Args = append(out.Args, v18)
+					var v19 string
+					v19 = string(in.String())
+					out.Args = append(out.Args, v19)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -586,11 +621,9 @@ func easyjsonD7860c2dDecodeGithubComOvhCdsSdk6(in *jlexer.Lexer, out *GRPCPlugin
 					out.Requirements = (out.Requirements)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v19 Requirement
-					if data := in.Raw(); in.Ok() {
-						in.AddError((v19).UnmarshalJSON(data))
-					}
-					out.Requirements = append(out.Requirements, v19)
+					var v20 Requirement
+					(v20).UnmarshalEasyJSON(in)
+					out.Requirements = append(out.Requirements, v20)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -708,6 +741,25 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk6(out *jwriter.Writer, in GRPCPlugi
 		}
 		out.String(string(in.TempURL))
 	}
+	if len(in.Entrypoints) != 0 {
+		const prefix string = ",\"entrypoints\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v22, v23 := range in.Entrypoints {
+				if v22 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v23))
+			}
+			out.RawByte(']')
+		}
+	}
 	if in.Cmd != "" {
 		const prefix string = ",\"cmd\":"
 		if first {
@@ -728,11 +780,11 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk6(out *jwriter.Writer, in GRPCPlugi
 		}
 		{
 			out.RawByte('[')
-			for v21, v22 := range in.Args {
-				if v21 > 0 {
+			for v24, v25 := range in.Args {
+				if v24 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v22))
+				out.String(string(v25))
 			}
 			out.RawByte(']')
 		}
@@ -747,11 +799,11 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk6(out *jwriter.Writer, in GRPCPlugi
 		}
 		{
 			out.RawByte('[')
-			for v23, v24 := range in.Requirements {
-				if v23 > 0 {
+			for v26, v27 := range in.Requirements {
+				if v26 > 0 {
 					out.RawByte(',')
 				}
-				out.Raw((v24).MarshalJSON())
+				(v27).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
@@ -817,9 +869,9 @@ func easyjsonD7860c2dDecodeGithubComOvhCdsSdk5(in *jlexer.Lexer, out *Group) {
 					out.Admins = (out.Admins)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v27 User
-					easyjsonD7860c2dDecodeGithubComOvhCdsSdk7(in, &v27)
-					out.Admins = append(out.Admins, v27)
+					var v30 User
+					easyjsonD7860c2dDecodeGithubComOvhCdsSdk7(in, &v30)
+					out.Admins = append(out.Admins, v30)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -840,9 +892,9 @@ func easyjsonD7860c2dDecodeGithubComOvhCdsSdk5(in *jlexer.Lexer, out *Group) {
 					out.Users = (out.Users)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v28 User
-					easyjsonD7860c2dDecodeGithubComOvhCdsSdk7(in, &v28)
-					out.Users = append(out.Users, v28)
+					var v31 User
+					easyjsonD7860c2dDecodeGithubComOvhCdsSdk7(in, &v31)
+					out.Users = append(out.Users, v31)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -863,9 +915,9 @@ func easyjsonD7860c2dDecodeGithubComOvhCdsSdk5(in *jlexer.Lexer, out *Group) {
 					out.Tokens = (out.Tokens)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v29 Token
-					easyjsonD7860c2dDecodeGithubComOvhCdsSdk8(in, &v29)
-					out.Tokens = append(out.Tokens, v29)
+					var v32 Token
+					easyjsonD7860c2dDecodeGithubComOvhCdsSdk8(in, &v32)
+					out.Tokens = append(out.Tokens, v32)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -914,11 +966,11 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk5(out *jwriter.Writer, in Group) {
 		}
 		{
 			out.RawByte('[')
-			for v30, v31 := range in.Admins {
-				if v30 > 0 {
+			for v33, v34 := range in.Admins {
+				if v33 > 0 {
 					out.RawByte(',')
 				}
-				easyjsonD7860c2dEncodeGithubComOvhCdsSdk7(out, v31)
+				easyjsonD7860c2dEncodeGithubComOvhCdsSdk7(out, v34)
 			}
 			out.RawByte(']')
 		}
@@ -933,11 +985,11 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk5(out *jwriter.Writer, in Group) {
 		}
 		{
 			out.RawByte('[')
-			for v32, v33 := range in.Users {
-				if v32 > 0 {
+			for v35, v36 := range in.Users {
+				if v35 > 0 {
 					out.RawByte(',')
 				}
-				easyjsonD7860c2dEncodeGithubComOvhCdsSdk7(out, v33)
+				easyjsonD7860c2dEncodeGithubComOvhCdsSdk7(out, v36)
 			}
 			out.RawByte(']')
 		}
@@ -952,11 +1004,11 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk5(out *jwriter.Writer, in Group) {
 		}
 		{
 			out.RawByte('[')
-			for v34, v35 := range in.Tokens {
-				if v34 > 0 {
+			for v37, v38 := range in.Tokens {
+				if v37 > 0 {
 					out.RawByte(',')
 				}
-				easyjsonD7860c2dEncodeGithubComOvhCdsSdk8(out, v35)
+				easyjsonD7860c2dEncodeGithubComOvhCdsSdk8(out, v38)
 			}
 			out.RawByte(']')
 		}
@@ -1141,9 +1193,9 @@ func easyjsonD7860c2dDecodeGithubComOvhCdsSdk7(in *jlexer.Lexer, out *User) {
 					out.Groups = (out.Groups)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v36 Group
-					easyjsonD7860c2dDecodeGithubComOvhCdsSdk5(in, &v36)
-					out.Groups = append(out.Groups, v36)
+					var v39 Group
+					easyjsonD7860c2dDecodeGithubComOvhCdsSdk5(in, &v39)
+					out.Groups = append(out.Groups, v39)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -1166,17 +1218,15 @@ func easyjsonD7860c2dDecodeGithubComOvhCdsSdk7(in *jlexer.Lexer, out *User) {
 					out.Favorites = (out.Favorites)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v37 Favorite
-					easyjsonD7860c2dDecodeGithubComOvhCdsSdk9(in, &v37)
-					out.Favorites = append(out.Favorites, v37)
+					var v40 Favorite
+					easyjsonD7860c2dDecodeGithubComOvhCdsSdk9(in, &v40)
+					out.Favorites = append(out.Favorites, v40)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
 		case "permissions":
-			if data := in.Raw(); in.Ok() {
-				in.AddError((out.Permissions).UnmarshalJSON(data))
-			}
+			(out.Permissions).UnmarshalEasyJSON(in)
 		default:
 			in.SkipRecursive()
 		}
@@ -1251,11 +1301,11 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk7(out *jwriter.Writer, in User) {
 		}
 		{
 			out.RawByte('[')
-			for v38, v39 := range in.Groups {
-				if v38 > 0 {
+			for v41, v42 := range in.Groups {
+				if v41 > 0 {
 					out.RawByte(',')
 				}
-				easyjsonD7860c2dEncodeGithubComOvhCdsSdk5(out, v39)
+				easyjsonD7860c2dEncodeGithubComOvhCdsSdk5(out, v42)
 			}
 			out.RawByte(']')
 		}
@@ -1282,11 +1332,11 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk7(out *jwriter.Writer, in User) {
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v40, v41 := range in.Favorites {
-				if v40 > 0 {
+			for v43, v44 := range in.Favorites {
+				if v43 > 0 {
 					out.RawByte(',')
 				}
-				easyjsonD7860c2dEncodeGithubComOvhCdsSdk9(out, v41)
+				easyjsonD7860c2dEncodeGithubComOvhCdsSdk9(out, v44)
 			}
 			out.RawByte(']')
 		}
@@ -1299,7 +1349,7 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk7(out *jwriter.Writer, in User) {
 		} else {
 			out.RawString(prefix)
 		}
-		out.Raw((in.Permissions).MarshalJSON())
+		(in.Permissions).MarshalEasyJSON(out)
 	}
 	out.RawByte('}')
 }
@@ -1338,9 +1388,9 @@ func easyjsonD7860c2dDecodeGithubComOvhCdsSdk9(in *jlexer.Lexer, out *Favorite)
 					out.ProjectIDs = (out.ProjectIDs)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v42 int64
-					v42 = int64(in.Int64())
-					out.ProjectIDs = append(out.ProjectIDs, v42)
+					var v45 int64
+					v45 = int64(in.Int64())
+					out.ProjectIDs = append(out.ProjectIDs, v45)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -1361,9 +1411,9 @@ func easyjsonD7860c2dDecodeGithubComOvhCdsSdk9(in *jlexer.Lexer, out *Favorite)
 					out.WorkflowIDs = (out.WorkflowIDs)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v43 int64
-					v43 = int64(in.Int64())
-					out.WorkflowIDs = append(out.WorkflowIDs, v43)
+					var v46 int64
+					v46 = int64(in.Int64())
+					out.WorkflowIDs = append(out.WorkflowIDs, v46)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -1394,11 +1444,11 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk9(out *jwriter.Writer, in Favorite)
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v44, v45 := range in.ProjectIDs {
-				if v44 > 0 {
+			for v47, v48 := range in.ProjectIDs {
+				if v47 > 0 {
 					out.RawByte(',')
 				}
-				out.Int64(int64(v45))
+				out.Int64(int64(v48))
 			}
 			out.RawByte(']')
 		}
@@ -1415,11 +1465,11 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk9(out *jwriter.Writer, in Favorite)
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v46, v47 := range in.WorkflowIDs {
-				if v46 > 0 {
+			for v49, v50 := range in.WorkflowIDs {
+				if v49 > 0 {
 					out.RawByte(',')
 				}
-				out.Int64(int64(v47))
+				out.Int64(int64(v50))
 			}
 			out.RawByte(']')
 		}
@@ -1550,15 +1600,15 @@ func easyjsonD7860c2dDecodeGithubComOvhCdsSdk10(in *jlexer.Lexer, out *SpawnMsg)
 					out.Args = (out.Args)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v48 interface{}
-					if m, ok := v48.(easyjson.Unmarshaler); ok {
+					var v51 interface{}
+					if m, ok := v51.(easyjson.Unmarshaler); ok {
 						m.UnmarshalEasyJSON(in)
-					} else if m, ok := v48.(json.Unmarshaler); ok {
+					} else if m, ok := v51.(json.Unmarshaler); ok {
 						_ = m.UnmarshalJSON(in.Raw())
 					} else {
-						v48 = in.Interface()
+						v51 = in.Interface()
 					}
-					out.Args = append(out.Args, v48)
+					out.Args = append(out.Args, v51)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -1599,16 +1649,16 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk10(out *jwriter.Writer, in SpawnMsg
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v49, v50 := range in.Args {
-				if v49 > 0 {
+			for v52, v53 := range in.Args {
+				if v52 > 0 {
 					out.RawByte(',')
 				}
-				if m, ok := v50.(easyjson.Marshaler); ok {
+				if m, ok := v53.(easyjson.Marshaler); ok {
 					m.MarshalEasyJSON(out)
-				} else if m, ok := v50.(json.Marshaler); ok {
+				} else if m, ok := v53.(json.Marshaler); ok {
 					out.Raw(m.MarshalJSON())
 				} else {
-					out.Raw(json.Marshal(v50))
+					out.Raw(json.Marshal(v53))
 				}
 			}
 			out.RawByte(']')
@@ -1661,8 +1711,26 @@ func easyjsonD7860c2dDecodeGithubComOvhCdsSdk3(in *jlexer.Lexer, out *Service) {
 				}
 				*out.GroupID = int64(in.Int64())
 			}
+		case "group":
+			if in.IsNull() {
+				in.Skip()
+				out.Group = nil
+			} else {
+				if out.Group == nil {
+					out.Group = new(Group)
+				}
+				easyjsonD7860c2dDecodeGithubComOvhCdsSdk5(in, &*out.Group)
+			}
 		case "monitoring_status":
 			easyjsonD7860c2dDecodeGithubComOvhCdsSdk11(in, &out.MonitoringStatus)
+		case "config":
+			if m, ok := out.Config.(easyjson.Unmarshaler); ok {
+				m.UnmarshalEasyJSON(in)
+			} else if m, ok := out.Config.(json.Unmarshaler); ok {
+				_ = m.UnmarshalJSON(in.Raw())
+			} else {
+				out.Config = in.Interface()
+			}
 		case "is_shared_infra":
 			out.IsSharedInfra = bool(in.Bool())
 		case "version":
@@ -1767,6 +1835,20 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk3(out *jwriter.Writer, in Service)
 			out.Int64(int64(*in.GroupID))
 		}
 	}
+	{
+		const prefix string = ",\"group\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if in.Group == nil {
+			out.RawString("null")
+		} else {
+			easyjsonD7860c2dEncodeGithubComOvhCdsSdk5(out, *in.Group)
+		}
+	}
 	{
 		const prefix string = ",\"monitoring_status\":"
 		if first {
@@ -1777,6 +1859,22 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk3(out *jwriter.Writer, in Service)
 		}
 		easyjsonD7860c2dEncodeGithubComOvhCdsSdk11(out, in.MonitoringStatus)
 	}
+	{
+		const prefix string = ",\"config\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if m, ok := in.Config.(easyjson.Marshaler); ok {
+			m.MarshalEasyJSON(out)
+		} else if m, ok := in.Config.(json.Marshaler); ok {
+			out.Raw(m.MarshalJSON())
+		} else {
+			out.Raw(json.Marshal(in.Config))
+		}
+	}
 	{
 		const prefix string = ",\"is_shared_infra\":"
 		if first {
@@ -1848,9 +1946,9 @@ func easyjsonD7860c2dDecodeGithubComOvhCdsSdk11(in *jlexer.Lexer, out *Monitorin
 					out.Lines = (out.Lines)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v51 MonitoringStatusLine
-					easyjsonD7860c2dDecodeGithubComOvhCdsSdk12(in, &v51)
-					out.Lines = append(out.Lines, v51)
+					var v54 MonitoringStatusLine
+					easyjsonD7860c2dDecodeGithubComOvhCdsSdk12(in, &v54)
+					out.Lines = append(out.Lines, v54)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -1891,11 +1989,11 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk11(out *jwriter.Writer, in Monitori
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v52, v53 := range in.Lines {
-				if v52 > 0 {
+			for v55, v56 := range in.Lines {
+				if v55 > 0 {
 					out.RawByte(',')
 				}
-				easyjsonD7860c2dEncodeGithubComOvhCdsSdk12(out, v53)
+				easyjsonD7860c2dEncodeGithubComOvhCdsSdk12(out, v56)
 			}
 			out.RawByte(']')
 		}
@@ -2127,9 +2225,9 @@ func easyjsonD7860c2dDecodeGithubComOvhCdsSdk1(in *jlexer.Lexer, out *ExecutedJo
 					out.StepStatus = (out.StepStatus)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v54 StepStatus
-					easyjsonD7860c2dDecodeGithubComOvhCdsSdk13(in, &v54)
-					out.StepStatus = append(out.StepStatus, v54)
+					var v57 StepStatus
+					easyjsonD7860c2dDecodeGithubComOvhCdsSdk13(in, &v57)
+					out.StepStatus = append(out.StepStatus, v57)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -2140,6 +2238,31 @@ func easyjsonD7860c2dDecodeGithubComOvhCdsSdk1(in *jlexer.Lexer, out *ExecutedJo
 			out.WorkerName = string(in.String())
 		case "worker_id":
 			out.WorkerID = string(in.String())
+		case "matrix_variant":
+			out.MatrixVariant = string(in.String())
+		case "attempts":
+			if in.IsNull() {
+				in.Skip()
+				out.Attempts = nil
+			} else {
+				in.Delim('[')
+				if out.Attempts == nil {
+					if !in.IsDelim(']') {
+						out.Attempts = make([]JobAttempt, 0, 1)
+					} else {
+						out.Attempts = []JobAttempt{}
+					}
+				} else {
+					out.Attempts = (out.Attempts)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v58 JobAttempt
+					easyjsonD7860c2dDecodeGithubComOvhCdsSdk14(in, &v58)
+					out.Attempts = append(out.Attempts, v58)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		case "pipeline_action_id":
 			out.PipelineActionID = int64(in.Int64())
 		case "pipeline_stage_id":
@@ -2149,7 +2272,7 @@ func easyjsonD7860c2dDecodeGithubComOvhCdsSdk1(in *jlexer.Lexer, out *ExecutedJo
 		case "last_modified":
 			out.LastModified = int64(in.Int64())
 		case "action":
-			easyjsonD7860c2dDecodeGithubComOvhCdsSdk14(in, &out.Action)
+			easyjsonD7860c2dDecodeGithubComOvhCdsSdk15(in, &out.Action)
 		case "warnings":
 			if in.IsNull() {
 				in.Skip()
@@ -2166,13 +2289,35 @@ func easyjsonD7860c2dDecodeGithubComOvhCdsSdk1(in *jlexer.Lexer, out *ExecutedJo
 					out.Warnings = (out.Warnings)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v55 PipelineBuildWarning
-					easyjsonD7860c2dDecodeGithubComOvhCdsSdk15(in, &v55)
-					out.Warnings = append(out.Warnings, v55)
+					var v59 PipelineBuildWarning
+					easyjsonD7860c2dDecodeGithubComOvhCdsSdk16(in, &v59)
+					out.Warnings = append(out.Warnings, v59)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
+		case "matrix":
+			if in.IsNull() {
+				in.Skip()
+				out.Matrix = nil
+			} else {
+				if out.Matrix == nil {
+					out.Matrix = new(JobMatrix)
+				}
+				easyjsonD7860c2dDecodeGithubComOvhCdsSdk17(in, &*out.Matrix)
+			}
+		case "timeout":
+			out.Timeout = int64(in.Int64())
+		case "retry_policy":
+			if in.IsNull() {
+				in.Skip()
+				out.RetryPolicy = nil
+			} else {
+				if out.RetryPolicy == nil {
+					out.RetryPolicy = new(RetryPolicy)
+				}
+				easyjsonD7860c2dDecodeGithubComOvhCdsSdk18(in, &*out.RetryPolicy)
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -2199,11 +2344,11 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk1(out *jwriter.Writer, in ExecutedJ
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v56, v57 := range in.StepStatus {
-				if v56 > 0 {
+			for v60, v61 := range in.StepStatus {
+				if v60 > 0 {
 					out.RawByte(',')
 				}
-				easyjsonD7860c2dEncodeGithubComOvhCdsSdk13(out, v57)
+				easyjsonD7860c2dEncodeGithubComOvhCdsSdk13(out, v61)
 			}
 			out.RawByte(']')
 		}
@@ -2238,6 +2383,35 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk1(out *jwriter.Writer, in ExecutedJ
 		}
 		out.String(string(in.WorkerID))
 	}
+	if in.MatrixVariant != "" {
+		const prefix string = ",\"matrix_variant\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.MatrixVariant))
+	}
+	if len(in.Attempts) != 0 {
+		const prefix string = ",\"attempts\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v62, v63 := range in.Attempts {
+				if v62 > 0 {
+					out.RawByte(',')
+				}
+				easyjsonD7860c2dEncodeGithubComOvhCdsSdk14(out, v63)
+			}
+			out.RawByte(']')
+		}
+	}
 	{
 		const prefix string = ",\"pipeline_action_id\":"
 		if first {
@@ -2286,7 +2460,7 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk1(out *jwriter.Writer, in ExecutedJ
 		} else {
 			out.RawString(prefix)
 		}
-		easyjsonD7860c2dEncodeGithubComOvhCdsSdk14(out, in.Action)
+		easyjsonD7860c2dEncodeGithubComOvhCdsSdk15(out, in.Action)
 	}
 	{
 		const prefix string = ",\"warnings\":"
@@ -2300,77 +2474,48 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk1(out *jwriter.Writer, in ExecutedJ
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v58, v59 := range in.Warnings {
-				if v58 > 0 {
+			for v64, v65 := range in.Warnings {
+				if v64 > 0 {
 					out.RawByte(',')
 				}
-				easyjsonD7860c2dEncodeGithubComOvhCdsSdk15(out, v59)
+				easyjsonD7860c2dEncodeGithubComOvhCdsSdk16(out, v65)
 			}
 			out.RawByte(']')
 		}
 	}
-	out.RawByte('}')
-}
-func easyjsonD7860c2dDecodeGithubComOvhCdsSdk15(in *jlexer.Lexer, out *PipelineBuildWarning) {
-	isTopLevel := in.IsStart()
-	if in.IsNull() {
-		if isTopLevel {
-			in.Consumed()
-		}
-		in.Skip()
-		return
-	}
-	in.Delim('{')
-	for !in.IsDelim('}') {
-		key := in.UnsafeString()
-		in.WantColon()
-		if in.IsNull() {
-			in.Skip()
-			in.WantComma()
-			continue
-		}
-		switch key {
-		case "type":
-			out.Type = string(in.String())
-		case "action":
-			easyjsonD7860c2dDecodeGithubComOvhCdsSdk14(in, &out.Action)
-		default:
-			in.SkipRecursive()
+	if in.Matrix != nil {
+		const prefix string = ",\"matrix\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
 		}
-		in.WantComma()
-	}
-	in.Delim('}')
-	if isTopLevel {
-		in.Consumed()
+		easyjsonD7860c2dEncodeGithubComOvhCdsSdk17(out, *in.Matrix)
 	}
-}
-func easyjsonD7860c2dEncodeGithubComOvhCdsSdk15(out *jwriter.Writer, in PipelineBuildWarning) {
-	out.RawByte('{')
-	first := true
-	_ = first
-	{
-		const prefix string = ",\"type\":"
+	if in.Timeout != 0 {
+		const prefix string = ",\"timeout\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Type))
+		out.Int64(int64(in.Timeout))
 	}
-	{
-		const prefix string = ",\"action\":"
+	if in.RetryPolicy != nil {
+		const prefix string = ",\"retry_policy\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		easyjsonD7860c2dEncodeGithubComOvhCdsSdk14(out, in.Action)
+		easyjsonD7860c2dEncodeGithubComOvhCdsSdk18(out, *in.RetryPolicy)
 	}
 	out.RawByte('}')
 }
-func easyjsonD7860c2dDecodeGithubComOvhCdsSdk14(in *jlexer.Lexer, out *Action) {
+func easyjsonD7860c2dDecodeGithubComOvhCdsSdk18(in *jlexer.Lexer, out *RetryPolicy) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2389,97 +2534,56 @@ func easyjsonD7860c2dDecodeGithubComOvhCdsSdk14(in *jlexer.Lexer, out *Action) {
 			continue
 		}
 		switch key {
-		case "id":
-			out.ID = int64(in.Int64())
-		case "name":
-			out.Name = string(in.String())
-		case "step_name":
-			out.StepName = string(in.String())
-		case "type":
-			out.Type = string(in.String())
-		case "description":
-			out.Description = string(in.String())
-		case "requirements":
-			if in.IsNull() {
-				in.Skip()
-				out.Requirements = nil
-			} else {
-				in.Delim('[')
-				if out.Requirements == nil {
-					if !in.IsDelim(']') {
-						out.Requirements = make([]Requirement, 0, 1)
-					} else {
-						out.Requirements = []Requirement{}
-					}
-				} else {
-					out.Requirements = (out.Requirements)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v60 Requirement
-					if data := in.Raw(); in.Ok() {
-						in.AddError((v60).UnmarshalJSON(data))
-					}
-					out.Requirements = append(out.Requirements, v60)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
-		case "parameters":
+		case "max_attempts":
+			out.MaxAttempts = int(in.Int())
+		case "backoff":
+			out.Backoff = int64(in.Int64())
+		case "on":
 			if in.IsNull() {
 				in.Skip()
-				out.Parameters = nil
+				out.On = nil
 			} else {
 				in.Delim('[')
-				if out.Parameters == nil {
+				if out.On == nil {
 					if !in.IsDelim(']') {
-						out.Parameters = make([]Parameter, 0, 1)
+						out.On = make([]string, 0, 4)
 					} else {
-						out.Parameters = []Parameter{}
+						out.On = []string{}
 					}
 				} else {
-					out.Parameters = (out.Parameters)[:0]
+					out.On = (out.On)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v61 Parameter
-					easyjsonD7860c2dDecodeGithubComOvhCdsSdk2(in, &v61)
-					out.Parameters = append(out.Parameters, v61)
+					var v66 string
+					v66 = string(in.String())
+					out.On = append(out.On, v66)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "actions":
+		case "exit_codes":
 			if in.IsNull() {
 				in.Skip()
-				out.Actions = nil
+				out.ExitCodes = nil
 			} else {
 				in.Delim('[')
-				if out.Actions == nil {
+				if out.ExitCodes == nil {
 					if !in.IsDelim(']') {
-						out.Actions = make([]Action, 0, 1)
+						out.ExitCodes = make([]int, 0, 8)
 					} else {
-						out.Actions = []Action{}
+						out.ExitCodes = []int{}
 					}
 				} else {
-					out.Actions = (out.Actions)[:0]
+					out.ExitCodes = (out.ExitCodes)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v62 Action
-					easyjsonD7860c2dDecodeGithubComOvhCdsSdk14(in, &v62)
-					out.Actions = append(out.Actions, v62)
+					var v67 int
+					v67 = int(in.Int())
+					out.ExitCodes = append(out.ExitCodes, v67)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "enabled":
-			out.Enabled = bool(in.Bool())
-		case "deprecated":
-			out.Deprecated = bool(in.Bool())
-		case "optional":
-			out.Optional = bool(in.Bool())
-		case "always_executed":
-			out.AlwaysExecuted = bool(in.Bool())
-		case "last_modified":
-			out.LastModified = int64(in.Int64())
 		default:
 			in.SkipRecursive()
 		}
@@ -2490,77 +2594,667 @@ func easyjsonD7860c2dDecodeGithubComOvhCdsSdk14(in *jlexer.Lexer, out *Action) {
 		in.Consumed()
 	}
 }
-func easyjsonD7860c2dEncodeGithubComOvhCdsSdk14(out *jwriter.Writer, in Action) {
+func easyjsonD7860c2dEncodeGithubComOvhCdsSdk18(out *jwriter.Writer, in RetryPolicy) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"id\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.Int64(int64(in.ID))
-	}
-	{
-		const prefix string = ",\"name\":"
+		const prefix string = ",\"max_attempts\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Name))
+		out.Int(int(in.MaxAttempts))
 	}
-	if in.StepName != "" {
-		const prefix string = ",\"step_name\":"
+	if in.Backoff != 0 {
+		const prefix string = ",\"backoff\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.StepName))
+		out.Int64(int64(in.Backoff))
 	}
-	{
-		const prefix string = ",\"type\":"
+	if len(in.On) != 0 {
+		const prefix string = ",\"on\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Type))
-	}
-	{
-		const prefix string = ",\"description\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v68, v69 := range in.On {
+				if v68 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v69))
+			}
+			out.RawByte(']')
 		}
-		out.String(string(in.Description))
 	}
-	{
-		const prefix string = ",\"requirements\":"
+	if len(in.ExitCodes) != 0 {
+		const prefix string = ",\"exit_codes\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		if in.Requirements == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
-			out.RawString("null")
-		} else {
+		{
 			out.RawByte('[')
-			for v63, v64 := range in.Requirements {
-				if v63 > 0 {
+			for v70, v71 := range in.ExitCodes {
+				if v70 > 0 {
 					out.RawByte(',')
 				}
-				out.Raw((v64).MarshalJSON())
+				out.Int(int(v71))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjsonD7860c2dDecodeGithubComOvhCdsSdk17(in *jlexer.Lexer, out *JobMatrix) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "variables":
+			if in.IsNull() {
+				in.Skip()
+				out.Variables = nil
+			} else {
+				in.Delim('[')
+				if out.Variables == nil {
+					if !in.IsDelim(']') {
+						out.Variables = make([]JobMatrixVariable, 0, 1)
+					} else {
+						out.Variables = []JobMatrixVariable{}
+					}
+				} else {
+					out.Variables = (out.Variables)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v72 JobMatrixVariable
+					easyjsonD7860c2dDecodeGithubComOvhCdsSdk19(in, &v72)
+					out.Variables = append(out.Variables, v72)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "overrides":
+			if in.IsNull() {
+				in.Skip()
+				out.Overrides = nil
+			} else {
+				in.Delim('[')
+				if out.Overrides == nil {
+					if !in.IsDelim(']') {
+						out.Overrides = make([]JobMatrixOverride, 0, 2)
+					} else {
+						out.Overrides = []JobMatrixOverride{}
+					}
+				} else {
+					out.Overrides = (out.Overrides)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v73 JobMatrixOverride
+					easyjsonD7860c2dDecodeGithubComOvhCdsSdk20(in, &v73)
+					out.Overrides = append(out.Overrides, v73)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD7860c2dEncodeGithubComOvhCdsSdk17(out *jwriter.Writer, in JobMatrix) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"variables\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if in.Variables == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v74, v75 := range in.Variables {
+				if v74 > 0 {
+					out.RawByte(',')
+				}
+				easyjsonD7860c2dEncodeGithubComOvhCdsSdk19(out, v75)
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Overrides) != 0 {
+		const prefix string = ",\"overrides\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v76, v77 := range in.Overrides {
+				if v76 > 0 {
+					out.RawByte(',')
+				}
+				easyjsonD7860c2dEncodeGithubComOvhCdsSdk20(out, v77)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjsonD7860c2dDecodeGithubComOvhCdsSdk20(in *jlexer.Lexer, out *JobMatrixOverride) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "when":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.When = make(map[string]string)
+				} else {
+					out.When = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v78 string
+					v78 = string(in.String())
+					(out.When)[key] = v78
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "requirements":
+			if in.IsNull() {
+				in.Skip()
+				out.Requirements = nil
+			} else {
+				in.Delim('[')
+				if out.Requirements == nil {
+					if !in.IsDelim(']') {
+						out.Requirements = make([]Requirement, 0, 1)
+					} else {
+						out.Requirements = []Requirement{}
+					}
+				} else {
+					out.Requirements = (out.Requirements)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v79 Requirement
+					(v79).UnmarshalEasyJSON(in)
+					out.Requirements = append(out.Requirements, v79)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD7860c2dEncodeGithubComOvhCdsSdk20(out *jwriter.Writer, in JobMatrixOverride) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"when\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if in.When == nil && (out.Flags&jwriter.NilMapAsEmpty) == 0 {
+			out.RawString(`null`)
+		} else {
+			out.RawByte('{')
+			v80First := true
+			for v80Name, v80Value := range in.When {
+				if v80First {
+					v80First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v80Name))
+				out.RawByte(':')
+				out.String(string(v80Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	{
+		const prefix string = ",\"requirements\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if in.Requirements == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v81, v82 := range in.Requirements {
+				if v81 > 0 {
+					out.RawByte(',')
+				}
+				(v82).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjsonD7860c2dDecodeGithubComOvhCdsSdk19(in *jlexer.Lexer, out *JobMatrixVariable) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "name":
+			out.Name = string(in.String())
+		case "values":
+			if in.IsNull() {
+				in.Skip()
+				out.Values = nil
+			} else {
+				in.Delim('[')
+				if out.Values == nil {
+					if !in.IsDelim(']') {
+						out.Values = make([]string, 0, 4)
+					} else {
+						out.Values = []string{}
+					}
+				} else {
+					out.Values = (out.Values)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v83 string
+					v83 = string(in.String())
+					out.Values = append(out.Values, v83)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD7860c2dEncodeGithubComOvhCdsSdk19(out *jwriter.Writer, in JobMatrixVariable) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"name\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"values\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if in.Values == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v84, v85 := range in.Values {
+				if v84 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v85))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjsonD7860c2dDecodeGithubComOvhCdsSdk16(in *jlexer.Lexer, out *PipelineBuildWarning) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "type":
+			out.Type = string(in.String())
+		case "action":
+			easyjsonD7860c2dDecodeGithubComOvhCdsSdk15(in, &out.Action)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD7860c2dEncodeGithubComOvhCdsSdk16(out *jwriter.Writer, in PipelineBuildWarning) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"type\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"action\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		easyjsonD7860c2dEncodeGithubComOvhCdsSdk15(out, in.Action)
+	}
+	out.RawByte('}')
+}
+func easyjsonD7860c2dDecodeGithubComOvhCdsSdk15(in *jlexer.Lexer, out *Action) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = int64(in.Int64())
+		case "name":
+			out.Name = string(in.String())
+		case "step_name":
+			out.StepName = string(in.String())
+		case "type":
+			out.Type = string(in.String())
+		case "description":
+			out.Description = string(in.String())
+		case "requirements":
+			if in.IsNull() {
+				in.Skip()
+				out.Requirements = nil
+			} else {
+				in.Delim('[')
+				if out.Requirements == nil {
+					if !in.IsDelim(']') {
+						out.Requirements = make([]Requirement, 0, 1)
+					} else {
+						out.Requirements = []Requirement{}
+					}
+				} else {
+					out.Requirements = (out.Requirements)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v86 Requirement
+					(v86).UnmarshalEasyJSON(in)
+					out.Requirements = append(out.Requirements, v86)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "parameters":
+			if in.IsNull() {
+				in.Skip()
+				out.Parameters = nil
+			} else {
+				in.Delim('[')
+				if out.Parameters == nil {
+					if !in.IsDelim(']') {
+						out.Parameters = make([]Parameter, 0, 1)
+					} else {
+						out.Parameters = []Parameter{}
+					}
+				} else {
+					out.Parameters = (out.Parameters)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v87 Parameter
+					easyjsonD7860c2dDecodeGithubComOvhCdsSdk2(in, &v87)
+					out.Parameters = append(out.Parameters, v87)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "actions":
+			if in.IsNull() {
+				in.Skip()
+				out.Actions = nil
+			} else {
+				in.Delim('[')
+				if out.Actions == nil {
+					if !in.IsDelim(']') {
+						out.Actions = make([]Action, 0, 1)
+					} else {
+						out.Actions = []Action{}
+					}
+				} else {
+					out.Actions = (out.Actions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v88 Action
+					easyjsonD7860c2dDecodeGithubComOvhCdsSdk15(in, &v88)
+					out.Actions = append(out.Actions, v88)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "enabled":
+			out.Enabled = bool(in.Bool())
+		case "deprecated":
+			out.Deprecated = bool(in.Bool())
+		case "optional":
+			out.Optional = bool(in.Bool())
+		case "always_executed":
+			out.AlwaysExecuted = bool(in.Bool())
+		case "timeout":
+			out.Timeout = int64(in.Int64())
+		case "retry_policy":
+			if in.IsNull() {
+				in.Skip()
+				out.RetryPolicy = nil
+			} else {
+				if out.RetryPolicy == nil {
+					out.RetryPolicy = new(RetryPolicy)
+				}
+				easyjsonD7860c2dDecodeGithubComOvhCdsSdk18(in, &*out.RetryPolicy)
+			}
+		case "last_modified":
+			out.LastModified = int64(in.Int64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD7860c2dEncodeGithubComOvhCdsSdk15(out *jwriter.Writer, in Action) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.ID))
+	}
+	{
+		const prefix string = ",\"name\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Name))
+	}
+	if in.StepName != "" {
+		const prefix string = ",\"step_name\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.StepName))
+	}
+	{
+		const prefix string = ",\"type\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"description\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Description))
+	}
+	{
+		const prefix string = ",\"requirements\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if in.Requirements == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v89, v90 := range in.Requirements {
+				if v89 > 0 {
+					out.RawByte(',')
+				}
+				(v90).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
@@ -2577,11 +3271,11 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk14(out *jwriter.Writer, in Action)
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v65, v66 := range in.Parameters {
-				if v65 > 0 {
+			for v91, v92 := range in.Parameters {
+				if v91 > 0 {
 					out.RawByte(',')
 				}
-				easyjsonD7860c2dEncodeGithubComOvhCdsSdk2(out, v66)
+				easyjsonD7860c2dEncodeGithubComOvhCdsSdk2(out, v92)
 			}
 			out.RawByte(']')
 		}
@@ -2598,11 +3292,11 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk14(out *jwriter.Writer, in Action)
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v67, v68 := range in.Actions {
-				if v67 > 0 {
+			for v93, v94 := range in.Actions {
+				if v93 > 0 {
 					out.RawByte(',')
 				}
-				easyjsonD7860c2dEncodeGithubComOvhCdsSdk14(out, v68)
+				easyjsonD7860c2dEncodeGithubComOvhCdsSdk15(out, v94)
 			}
 			out.RawByte(']')
 		}
@@ -2647,6 +3341,26 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk14(out *jwriter.Writer, in Action)
 		}
 		out.Bool(bool(in.AlwaysExecuted))
 	}
+	if in.Timeout != 0 {
+		const prefix string = ",\"timeout\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.Timeout))
+	}
+	if in.RetryPolicy != nil {
+		const prefix string = ",\"retry_policy\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		easyjsonD7860c2dEncodeGithubComOvhCdsSdk18(out, *in.RetryPolicy)
+	}
 	{
 		const prefix string = ",\"last_modified\":"
 		if first {
@@ -2659,6 +3373,171 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk14(out *jwriter.Writer, in Action)
 	}
 	out.RawByte('}')
 }
+func easyjsonD7860c2dDecodeGithubComOvhCdsSdk14(in *jlexer.Lexer, out *JobAttempt) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "attempt":
+			out.Attempt = int(in.Int())
+		case "reason":
+			out.Reason = string(in.String())
+		case "exit_code":
+			out.ExitCode = int(in.Int())
+		case "worker_name":
+			out.WorkerName = string(in.String())
+		case "model":
+			out.Model = string(in.String())
+		case "start":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Start).UnmarshalJSON(data))
+			}
+		case "done":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Done).UnmarshalJSON(data))
+			}
+		case "step_status":
+			if in.IsNull() {
+				in.Skip()
+				out.StepStatus = nil
+			} else {
+				in.Delim('[')
+				if out.StepStatus == nil {
+					if !in.IsDelim(']') {
+						out.StepStatus = make([]StepStatus, 0, 1)
+					} else {
+						out.StepStatus = []StepStatus{}
+					}
+				} else {
+					out.StepStatus = (out.StepStatus)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v95 StepStatus
+					easyjsonD7860c2dDecodeGithubComOvhCdsSdk13(in, &v95)
+					out.StepStatus = append(out.StepStatus, v95)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD7860c2dEncodeGithubComOvhCdsSdk14(out *jwriter.Writer, in JobAttempt) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"attempt\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Attempt))
+	}
+	{
+		const prefix string = ",\"reason\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Reason))
+	}
+	if in.ExitCode != 0 {
+		const prefix string = ",\"exit_code\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.ExitCode))
+	}
+	if in.WorkerName != "" {
+		const prefix string = ",\"worker_name\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.WorkerName))
+	}
+	if in.Model != "" {
+		const prefix string = ",\"model\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Model))
+	}
+	{
+		const prefix string = ",\"start\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((in.Start).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"done\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((in.Done).MarshalJSON())
+	}
+	if len(in.StepStatus) != 0 {
+		const prefix string = ",\"step_status\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v96, v97 := range in.StepStatus {
+				if v96 > 0 {
+					out.RawByte(',')
+				}
+				easyjsonD7860c2dEncodeGithubComOvhCdsSdk13(out, v97)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
 func easyjsonD7860c2dDecodeGithubComOvhCdsSdk13(in *jlexer.Lexer, out *StepStatus) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
@@ -2690,6 +3569,33 @@ func easyjsonD7860c2dDecodeGithubComOvhCdsSdk13(in *jlexer.Lexer, out *StepStatu
 			if data := in.Raw(); in.Ok() {
 				in.AddError((out.Done).UnmarshalJSON(data))
 			}
+		case "exit_code":
+			out.ExitCode = int(in.Int())
+		case "attempt":
+			out.Attempt = int(in.Int())
+		case "attempts":
+			if in.IsNull() {
+				in.Skip()
+				out.Attempts = nil
+			} else {
+				in.Delim('[')
+				if out.Attempts == nil {
+					if !in.IsDelim(']') {
+						out.Attempts = make([]StepStatus, 0, 1)
+					} else {
+						out.Attempts = []StepStatus{}
+					}
+				} else {
+					out.Attempts = (out.Attempts)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v98 StepStatus
+					easyjsonD7860c2dDecodeGithubComOvhCdsSdk13(in, &v98)
+					out.Attempts = append(out.Attempts, v98)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -2744,5 +3650,44 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk13(out *jwriter.Writer, in StepStat
 		}
 		out.Raw((in.Done).MarshalJSON())
 	}
+	if in.ExitCode != 0 {
+		const prefix string = ",\"exit_code\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.ExitCode))
+	}
+	if in.Attempt != 0 {
+		const prefix string = ",\"attempt\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Attempt))
+	}
+	if len(in.Attempts) != 0 {
+		const prefix string = ",\"attempts\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v99, v100 := range in.Attempts {
+				if v99 > 0 {
+					out.RawByte(',')
+				}
+				easyjsonD7860c2dEncodeGithubComOvhCdsSdk13(out, v100)
+			}
+			out.RawByte(']')
+		}
+	}
 	out.RawByte('}')
 }
@@ -0,0 +1,198 @@
+package sdk
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Types of workflow template parameters
+const (
+	TemplateParameterTypeString  = "string"
+	TemplateParameterTypeBoolean = "boolean"
+	TemplateParameterTypeNumber  = "number"
+)
+
+var templateParameterKeyRegex = regexp.MustCompile("^[a-zA-Z][a-zA-Z0-9_]*$")
+
+// templateParameterStringIndicators are the YAML indicators which can't start a plain scalar
+const templateParameterStringIndicators = "-?:,[]{}#&*!|>%@`"
+
+// WorkflowTemplate is a versioned template stored in a group. It is rendered with the values of its
+// parameters into a workflow and its pipelines, applications and environments, in the exportentities yaml format.
+type WorkflowTemplate struct {
+	ID           int64                       `json:"id" db:"id"`
+	GroupID      int64                       `json:"group_id" db:"group_id"`
+	Name         string                      `json:"name" db:"name"`
+	Description  string                      `json:"description" db:"description"`
+	Version      int64                       `json:"version" db:"version"`
+	LastModified time.Time                   `json:"last_modified" db:"last_modified"`
+	Parameters   []WorkflowTemplateParameter `json:"parameters" db:"-"`
+	Workflow     string                      `json:"workflow" db:"-"`
+	Pipelines    []string                    `json:"pipelines,omitempty" db:"-"`
+	Applications []string                    `json:"applications,omitempty" db:"-"`
+	Environments []string                    `json:"environments,omitempty" db:"-"`
+	Group        *Group                      `json:"group,omitempty" db:"-"`
+}
+
+// WorkflowTemplateParameter is a typed input of a workflow template
+type WorkflowTemplateParameter struct {
+	Key         string `json:"key"`
+	Type        string `json:"type"`
+	Required    bool   `json:"required"`
+	Default     string `json:"default,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// IsValid checks the name, the parameters and the content of the template
+func (t WorkflowTemplate) IsValid() error {
+	if !NamePatternRegex.MatchString(t.Name) {
+		return NewError(ErrInvalidWorkflowTemplate, fmt.Errorf("invalid name %s, it should match %s", t.Name, NamePattern))
+	}
+	if t.Workflow == "" {
+		return NewError(ErrInvalidWorkflowTemplate, fmt.Errorf("workflow is mandatory"))
+	}
+	keys := make(map[string]bool, len(t.Parameters))
+	for _, p := range t.Parameters {
+		if !templateParameterKeyRegex.MatchString(p.Key) {
+			return NewError(ErrInvalidWorkflowTemplate, fmt.Errorf("invalid parameter key %s", p.Key))
+		}
+		if keys[p.Key] {
+			return NewError(ErrInvalidWorkflowTemplate, fmt.Errorf("duplicated parameter %s", p.Key))
+		}
+		keys[p.Key] = true
+		switch p.Type {
+		case TemplateParameterTypeString, TemplateParameterTypeBoolean, TemplateParameterTypeNumber:
+		default:
+			return NewError(ErrInvalidWorkflowTemplate, fmt.Errorf("invalid type %s of parameter %s", p.Type, p.Key))
+		}
+		if p.Default != "" {
+			if _, err := p.value(p.Default); err != nil {
+				return NewError(ErrInvalidWorkflowTemplate, fmt.Errorf("invalid default value of parameter %s: %v", p.Key, err))
+			}
+		}
+	}
+	return nil
+}
+
+// value converts the given value to the type of the parameter
+func (p WorkflowTemplateParameter) value(v string) (interface{}, error) {
+	switch p.Type {
+	case TemplateParameterTypeString:
+		if !isTemplateSafeString(v) {
+			return nil, fmt.Errorf("%q can't be written in a YAML file without quotes or escaping", v)
+		}
+		return v, nil
+	case TemplateParameterTypeBoolean:
+		return strconv.ParseBool(v)
+	case TemplateParameterTypeNumber:
+		return strconv.ParseFloat(v, 64)
+	}
+	return nil, fmt.Errorf("unknown type %s", p.Type)
+}
+
+// isTemplateSafeString checks that a string value is rendered as it is in a YAML plain or quoted scalar,
+// without changing the structure of the rendered file
+func isTemplateSafeString(v string) bool {
+	if strings.ContainsAny(v, "\n\r\t\"'\\") || strings.Contains(v, ": ") || strings.Contains(v, " #") || strings.HasSuffix(v, ":") {
+		return false
+	}
+	return !strings.ContainsAny(v[:1], templateParameterStringIndicators+" ") && !strings.HasSuffix(v, " ")
+}
+
+// zero returns the value of the parameter when it is not given
+func (p WorkflowTemplateParameter) zero() interface{} {
+	switch p.Type {
+	case TemplateParameterTypeBoolean:
+		return false
+	case TemplateParameterTypeNumber:
+		return float64(0)
+	}
+	return ""
+}
+
+// ParametersValues checks the given values against the parameters of the template and returns them typed,
+// with the default values of the parameters not given
+func (t WorkflowTemplate) ParametersValues(values map[string]string) (map[string]interface{}, error) {
+	res := make(map[string]interface{}, len(t.Parameters))
+	for _, p := range t.Parameters {
+		v, ok := values[p.Key]
+		if !ok || v == "" {
+			if p.Required && p.Default == "" {
+				return nil, NewError(ErrInvalidWorkflowTemplateParameters, fmt.Errorf("parameter %s is required", p.Key))
+			}
+			v = p.Default
+		}
+		if v == "" {
+			res[p.Key] = p.zero()
+			continue
+		}
+		tv, err := p.value(v)
+		if err != nil {
+			return nil, NewError(ErrInvalidWorkflowTemplateParameters, fmt.Errorf("invalid value of parameter %s: %v", p.Key, err))
+		}
+		res[p.Key] = tv
+	}
+	for k := range values {
+		var found bool
+		for _, p := range t.Parameters {
+			if p.Key == k {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, NewError(ErrInvalidWorkflowTemplateParameters, fmt.Errorf("unknown parameter %s", k))
+		}
+	}
+	return res, nil
+}
+
+// WorkflowTemplateVersion is the content of a template at a given version
+type WorkflowTemplateVersion struct {
+	WorkflowTemplateID int64            `json:"workflow_template_id" db:"workflow_template_id"`
+	Version            int64            `json:"version" db:"version"`
+	Author             string           `json:"author" db:"author"`
+	Created            time.Time        `json:"created" db:"created"`
+	Template           WorkflowTemplate `json:"template" db:"-"`
+}
+
+// WorkflowTemplateRequest contains the values used to render a template in a project
+type WorkflowTemplateRequest struct {
+	WorkflowName string            `json:"workflow_name"`
+	Parameters   map[string]string `json:"parameters,omitempty"`
+}
+
+// WorkflowTemplateResult is the yaml of the entities rendered from a template
+type WorkflowTemplateResult struct {
+	Workflow     string   `json:"workflow"`
+	Pipelines    []string `json:"pipelines,omitempty"`
+	Applications []string `json:"applications,omitempty"`
+	Environments []string `json:"environments,omitempty"`
+}
+
+// WorkflowTemplateInstance links a workflow to the template version it has been generated from.
+// The export of the workflow made at that time is kept to detect manual changes.
+type WorkflowTemplateInstance struct {
+	ID                      int64                   `json:"id" db:"id"`
+	WorkflowTemplateID      int64                   `json:"workflow_template_id" db:"workflow_template_id"`
+	ProjectID               int64                   `json:"project_id" db:"project_id"`
+	WorkflowID              *int64                  `json:"workflow_id,omitempty" db:"workflow_id"`
+	WorkflowTemplateVersion int64                   `json:"workflow_template_version" db:"workflow_template_version"`
+	WorkflowExport          string                  `json:"-" db:"workflow_export"`
+	Created                 time.Time               `json:"created" db:"created"`
+	LastModified            time.Time               `json:"last_modified" db:"last_modified"`
+	Request                 WorkflowTemplateRequest `json:"request" db:"-"`
+	Template                *WorkflowTemplate       `json:"template,omitempty" db:"-"`
+	Drift                   *WorkflowTemplateDrift  `json:"drift,omitempty" db:"-"`
+}
+
+// WorkflowTemplateDrift describes the differences between a workflow and its template
+type WorkflowTemplateDrift struct {
+	// Outdated is true when the template has a newer version than the one applied
+	Outdated bool `json:"outdated"`
+	// Modified is true when the workflow has been changed since the template was applied
+	Modified bool `json:"modified"`
+}
@@ -10,6 +10,7 @@ export class Job {
     warnings: Array<ActionWarning>;
     worker_name: string;
     worker_id: string;
+    matrix_variant: string;
 
     // UI parameter
     hasChanged: boolean;
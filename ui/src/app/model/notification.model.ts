@@ -2,6 +2,8 @@ import {Environment} from './environment.model';
 import {Pipeline} from './pipeline.model';
 
 export const notificationTypes = ['jabber', 'email'];
+export const workflowNotificationTypes = ['jabber', 'email', 'webhook'];
+export const webhookNotificationMethods = ['POST', 'PUT', 'PATCH', 'GET', 'DELETE'];
 export const notificationOnSuccess = ['always', 'change', 'never'];
 export const notificationOnFailure = ['always', 'change', 'never'];
 
@@ -28,6 +30,13 @@ export class UserNotificationSettings {
     recipients: Array<string>;
     template: UserNotificationTemplate;
 
+    // webhook settings
+    url: string;
+    method: string;
+    headers: {[key: string]: string};
+    secret: string;
+    body: string;
+
     constructor() {
         this.on_success = notificationOnSuccess[1];
         this.on_failure = notificationOnFailure[0];
@@ -25,6 +25,8 @@ export class Project {
     permission: number;
     last_modified: string;
     workflow_migration: string;
+    job_timeout: number;
+    vault_role: string;
     vcs_servers: Array<RepositoriesManager>;
     keys: Array<Key>;
     platforms: Array<ProjectPlatform>;
@@ -43,6 +43,8 @@ export class TaskExecution {
     webhook: Webhook;
     rabbitmq: RabbitMQ;
     kafka: Kafka;
+    nats: NATS;
+    mqtt: MQTT;
     scheduled_task?: any;
     status: HookStatus;
 }
@@ -61,3 +63,14 @@ export class Kafka {
     message: string;
 }
 
+export class NATS {
+    subject: string;
+    headers: Map<string, string[]>;
+    message: string;
+}
+
+export class MQTT {
+    topic: string;
+    message: string;
+}
+
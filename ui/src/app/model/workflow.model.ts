@@ -28,6 +28,7 @@ export class Workflow {
     usage: Usage;
     history_length: number;
     purge_tags: Array<string>;
+    cancel_superseded_runs: boolean;
     notifications: Array<WorkflowNotification>;
     from_repository: string;
     favorite: boolean;
@@ -732,6 +733,7 @@ export class WorkflowNodeTrigger {
 // WorkflowTriggerConditions is either a lua script to check conditions or a set of WorkflowTriggerCondition
 export class WorkflowNodeConditions {
     lua_script: string;
+    expression: string;
     plain: Array<WorkflowNodeCondition>;
 }
 
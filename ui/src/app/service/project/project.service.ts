@@ -76,6 +76,16 @@ export class ProjectService {
         return this._http.put<Project>('/project/' + project.key, project);
     }
 
+    /**
+     * Update the Vault role of the project, only allowed to CDS administrators.
+     * @param key Project unique key
+     * @param role Vault role
+     * @returns {Observable<Project>}
+     */
+    updateVaultRole(key: string, role: string): Observable<Project> {
+        return this._http.put<Project>('/project/' + key + '/vault/role', {vault_role: role});
+    }
+
     /**
      * Update favorite project.
      * @param project Project updated
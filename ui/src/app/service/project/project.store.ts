@@ -335,6 +335,26 @@ export class ProjectStore {
         }));
     }
 
+    /**
+     * Update the Vault role of a project
+     * @param key Project key
+     * @param role Vault role
+     * @returns {Project}
+     */
+    updateProjectVaultRole(key: string, role: string): Observable<Project> {
+        return this._projectService.updateVaultRole(key, role).pipe(map(res => {
+            // update project cache
+            let cache = this._projectCache.getValue();
+            if (cache.get(res.key)) {
+                let pToUpdate = cache.get(res.key);
+                pToUpdate.last_modified = res.last_modified;
+                pToUpdate.vault_role = res.vault_role;
+                this._projectCache.next(cache.set(res.key, pToUpdate));
+            }
+            return res;
+        }));
+    }
+
     /**
      * Update a project favorite
      * @param projectKey Project key to Update
@@ -48,6 +48,28 @@ export class UserService {
         }));
     }
 
+    /**
+     * LogIn user with a session created by the API after an OpenID Connect login
+     * @param sessionToken Session token
+     * @returns {Observable<User>}
+     */
+    loginWithSession(sessionToken: string): Observable<User> {
+        let headers = new HttpHeaders().set(this._authStore.localStorageSessionKey, sessionToken);
+        return this._http.get<User>('/user/me', {headers: headers}).pipe(map(u => {
+            u.token = sessionToken;
+            this._authStore.addUser(u, true);
+            return u;
+        }));
+    }
+
+    /**
+     * Check if the API allows to login with an OpenID Connect provider
+     * @returns {Observable<boolean>}
+     */
+    isOIDCEnabled(): Observable<boolean> {
+        return this._http.get<any>('/login/oidc/config').pipe(map(c => c.enabled));
+    }
+
     resetPassword(user: User, href: string) {
         let request = {
             user: user,
@@ -25,7 +25,7 @@ describe('CDS: LoginComponent', () => {
                 UserService,
                 AuthentificationStore,
                 { provide: Router, useClass: MockRouter},
-                { provide: ActivatedRoute, useValue: { queryParams: Observable.of({redirection: null})} },
+                { provide: ActivatedRoute, useValue: { queryParams: Observable.of({redirection: null}), fragment: Observable.of(null)} },
             ],
             imports : [
                 AppModule,
@@ -56,6 +56,7 @@ describe('CDS: LoginComponent', () => {
         // Start detecting change in model
         fixture.detectChanges();
         tick(50);
+        http.expectOne('http://localhost:8081/login/oidc/config').flush({enabled: false});
 
         // Simulate user typing
         let inputUsername = compiled.querySelector('input[name="username"]');
@@ -1,5 +1,6 @@
 import {Component} from '@angular/core';
 import {ActivatedRoute, Router} from '@angular/router';
+import {environment} from '../../../../environments/environment';
 import {User} from '../../../model/user.model';
 import {AuthentificationStore} from '../../../service/auth/authentification.store';
 import {UserService} from '../../../service/user/user.service';
@@ -14,6 +15,7 @@ export class LoginComponent extends AccountComponent {
 
     user: User;
     redirect: string;
+    oidcEnabled = false;
 
     constructor(private _userService: UserService, private _router: Router,
         _authStore: AuthentificationStore, private _route: ActivatedRoute) {
@@ -23,6 +25,20 @@ export class LoginComponent extends AccountComponent {
         this._route.queryParams.subscribe(queryParams => {
            this.redirect = queryParams.redirect;
         });
+
+        // The API redirects here with the session after an OpenID Connect login
+        this._route.fragment.subscribe(fragment => {
+            if (fragment && fragment.indexOf('session=') === 0) {
+                this._userService.loginWithSession(fragment.substring('session='.length)).subscribe(() => {
+                    this._router.navigate(['home']);
+                });
+            }
+        });
+        this._userService.isOIDCEnabled().subscribe(enabled => this.oidcEnabled = enabled);
+    }
+
+    signInWithOIDC() {
+        window.location.href = environment.apiURL + '/login/oidc';
     }
 
     signIn() {
@@ -96,6 +96,10 @@ export class HooksTaskComponent {
                 this.selectedExecutionBody = this.decodeBody(e.rabbitmq.message);
             } else if (e.kafka) {
                 this.selectedExecutionBody = this.decodeBody(e.kafka.message);
+            } else if (e.nats) {
+                this.selectedExecutionBody = this.decodeBody(e.nats.message);
+            } else if (e.mqtt) {
+                this.selectedExecutionBody = this.decodeBody(e.mqtt.message);
             }
         };
     }
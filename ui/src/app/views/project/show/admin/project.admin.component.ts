@@ -65,6 +65,16 @@ export class ProjectAdminComponent implements OnInit {
         }
     };
 
+    onSubmitVaultRole() {
+        this.loading = true;
+        this._projectStore.updateProjectVaultRole(this.project.key, this.project.vault_role).subscribe(() => {
+            this.loading = false;
+            this._toast.success('', this._translate.instant('project_update_msg_ok'));
+        }, () => {
+            this.loading = false;
+        });
+    }
+
     deleteProject(): void {
         this._projectStore.deleteProject(this.project.key).subscribe(() => {
             this.loading = false;
@@ -20,7 +20,7 @@ import {DurationService} from '../../../../../shared/duration/duration.service';
 export class WorkflowRunNodePipelineComponent implements OnInit, OnDestroy {
 
     nodeRun: WorkflowNodeRun;
-    jobTime: Map<number, string>;
+    jobTime: Map<string, string>;
 
     @Input() workflowName: string;
     @Input() project: Project;
@@ -38,7 +38,9 @@ export class WorkflowRunNodePipelineComponent implements OnInit, OnDestroy {
     queryParamsSub: Subscription;
     pipelineStatusEnum = PipelineStatus;
     selectedRunJob: WorkflowNodeJobRun;
-    mapJobStatus: Map<number, {status: string, warnings: number}> = new Map<number, {status: string, warnings: number}>();
+    mapJobStatus: Map<string, {status: string, warnings: number}> = new Map<string, {status: string, warnings: number}>();
+    // jobs of the stages as run, a matrix job is displayed once by variant
+    stageJobs: Map<number, Array<Job>> = new Map<number, Array<Job>>();
     mapStepStatus: Map<string, StepStatus> = new Map<string, StepStatus>();
 
     previousStatus: string;
@@ -72,6 +74,7 @@ export class WorkflowRunNodePipelineComponent implements OnInit, OnDestroy {
       } else if (queryParams['actionId']) {
         let job = new Job();
         job.pipeline_action_id = parseInt(queryParams['actionId'], 10);
+        job.matrix_variant = queryParams['variant'];
         this.manual = true;
         this.selectedJob(job);
       }
@@ -81,6 +84,7 @@ export class WorkflowRunNodePipelineComponent implements OnInit, OnDestroy {
       let queryParams = cloneDeep(this._route.snapshot.queryParams);
       queryParams['stageId'] = null;
       queryParams['actionId'] = null;
+      queryParams['variant'] = null;
       queryParams['stepOrder'] = null;
       queryParams['line'] = null;
       this.manual = true;
@@ -100,7 +104,8 @@ export class WorkflowRunNodePipelineComponent implements OnInit, OnDestroy {
     selectedJob(j: Job): void {
         this.nodeRun.stages.forEach(s => {
             if (s.run_jobs) {
-                let runJob = s.run_jobs.find(rj => rj.job.pipeline_action_id === j.pipeline_action_id);
+                let runJob = s.run_jobs.find(rj => this.jobKey(rj.job) === this.jobKey(j)) ||
+                    s.run_jobs.find(rj => rj.job.pipeline_action_id === j.pipeline_action_id);
                 if (runJob) {
                     this.selectedRunJob = runJob;
                 }
@@ -108,6 +113,13 @@ export class WorkflowRunNodePipelineComponent implements OnInit, OnDestroy {
         });
     }
 
+    jobKey(j: Job): string {
+        if (j.matrix_variant) {
+            return j.pipeline_action_id + '|' + j.matrix_variant;
+        }
+        return j.pipeline_action_id + '';
+    }
+
     refreshNodeRun(data: WorkflowNodeRun): void {
         let previousRun = this.nodeRun;
         this.nodeRun = data;
@@ -123,13 +135,18 @@ export class WorkflowRunNodePipelineComponent implements OnInit, OnDestroy {
                     (s.status === PipelineStatus.WAITING || s.status === PipelineStatus.BUILDING)) {
                   this.selectedJob(s.jobs[0]);
                 }
+                if (s.run_jobs && s.run_jobs.length) {
+                    this.stageJobs.set(s.id, s.run_jobs.map(rj => rj.job));
+                } else {
+                    this.stageJobs.delete(s.id);
+                }
                 if (s.run_jobs) {
                     s.run_jobs.forEach((rj, rjIndex) => {
                         let warnings = 0;
                         // Update map step status
                         if (rj.job.step_status) {
                             rj.job.step_status.forEach(ss => {
-                                this.mapStepStatus[rj.job.pipeline_action_id + '-' + ss.step_order] = ss;
+                                this.mapStepStatus[this.jobKey(rj.job) + '-' + ss.step_order] = ss;
                                 if (ss.status === PipelineStatus.FAIL && rj.job.action.actions[ss.step_order] &&
                                     rj.job.action.actions[ss.step_order].optional) {
                                     warnings++;
@@ -138,7 +155,7 @@ export class WorkflowRunNodePipelineComponent implements OnInit, OnDestroy {
                         }
 
                         // Update job status
-                        this.mapJobStatus.set(rj.job.pipeline_action_id, {status: rj.status, warnings});
+                        this.mapJobStatus.set(this.jobKey(rj.job), {status: rj.status, warnings});
 
                         // Select temp job
                         if (!this.selectedRunJob && sIndex === 0 && rjIndex === 0) {
@@ -156,7 +173,7 @@ export class WorkflowRunNodePipelineComponent implements OnInit, OnDestroy {
     }
 
     updateTime(): void {
-        this.jobTime = new Map<number, string>();
+        this.jobTime = new Map<string, string>();
         let stillRunning = false;
         if (this.nodeRun.stages) {
             this.nodeRun.stages.forEach(s => {
@@ -165,23 +182,23 @@ export class WorkflowRunNodePipelineComponent implements OnInit, OnDestroy {
                        switch (rj.status) {
                            case this.pipelineStatusEnum.WAITING:
                                stillRunning = true;
-                               this.jobTime.set(rj.job.pipeline_action_id, this._durationService.duration(new Date(rj.queued), new Date()));
+                               this.jobTime.set(this.jobKey(rj.job), this._durationService.duration(new Date(rj.queued), new Date()));
                                break;
                            case this.pipelineStatusEnum.BUILDING:
                                stillRunning = true;
-                               this.jobTime.set(rj.job.pipeline_action_id, this._durationService.duration(new Date(rj.start), new Date()));
+                               this.jobTime.set(this.jobKey(rj.job), this._durationService.duration(new Date(rj.start), new Date()));
                                break;
                            case this.pipelineStatusEnum.SUCCESS:
                            case this.pipelineStatusEnum.FAIL:
                            case this.pipelineStatusEnum.STOPPED:
-                               this.jobTime.set(rj.job.pipeline_action_id,
+                               this.jobTime.set(this.jobKey(rj.job),
                                    this._durationService.duration( new Date(rj.start), new Date(rj.done) ));
                                break;
                        }
 
                        if (rj.job.step_status) {
                            rj.job.step_status.forEach(ss => {
-                               this.mapStepStatus.set(rj.job.pipeline_action_id + '-' + ss.step_order, ss);
+                               this.mapStepStatus.set(this.jobKey(rj.job) + '-' + ss.step_order, ss);
                            });
                        }
                    });
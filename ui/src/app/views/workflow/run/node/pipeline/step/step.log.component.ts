@@ -105,7 +105,8 @@ export class WorkflowStepLogComponent implements OnInit, OnDestroy {
 
         this.queryParamsSubscription = this._route.queryParams.subscribe((qps) => {
           let activeStep = parseInt(qps['stageId'], 10) === this.job.pipeline_stage_id &&
-            parseInt(qps['actionId'], 10) === this.job.pipeline_action_id && parseInt(qps['stepOrder'], 10) === this.stepOrder;
+            parseInt(qps['actionId'], 10) === this.job.pipeline_action_id && parseInt(qps['stepOrder'], 10) === this.stepOrder &&
+            (qps['variant'] || '') === (this.job.matrix_variant || '');
 
           if (activeStep) {
             this.showLog = true;
@@ -221,6 +222,7 @@ export class WorkflowStepLogComponent implements OnInit, OnDestroy {
       let qps = Object.assign({}, this._route.snapshot.queryParams, {
         stageId: this.job.pipeline_stage_id,
         actionId: this.job.pipeline_action_id,
+        variant: this.job.matrix_variant || null,
         stepOrder: this.stepOrder,
         line: lineNumber
       });
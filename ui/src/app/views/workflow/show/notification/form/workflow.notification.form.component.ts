@@ -1,6 +1,12 @@
 import {Component, EventEmitter, Input, Output} from '@angular/core';
 import {cloneDeep} from 'lodash';
-import {notificationOnFailure, notificationOnSuccess, notificationTypes} from '../../../../../model/notification.model';
+import {
+    notificationOnFailure,
+    notificationOnSuccess,
+    UserNotificationTemplate,
+    webhookNotificationMethods,
+    workflowNotificationTypes
+} from '../../../../../model/notification.model';
 import {Project} from '../../../../../model/project.model';
 import {Workflow, WorkflowNode, WorkflowNotification} from '../../../../../model/workflow.model';
 
@@ -19,6 +25,13 @@ export class WorkflowNotificationFormComponent {
             if (this._notification.settings.recipients) {
                 this.selectedUsers = this._notification.settings.recipients.join(',');
             }
+            if (!this._notification.settings.template) {
+                this._notification.settings.template = new UserNotificationTemplate();
+            }
+            if (this._notification.settings.headers) {
+                this.webhookHeaders = Object.keys(this._notification.settings.headers)
+                    .map(k => k + ': ' + this._notification.settings.headers[k]).join('\n');
+            }
 
             this.initNotif();
         }
@@ -29,6 +42,8 @@ export class WorkflowNotificationFormComponent {
     }
 
     types: Array<string>;
+    methods: Array<string>;
+    webhookHeaders: string;
     notifOnSuccess: Array<string>;
     notifOnFailure: Array<string>;
     selectedUsers: string;
@@ -62,7 +77,8 @@ export class WorkflowNotificationFormComponent {
     constructor() {
         this.notifOnSuccess = notificationOnSuccess;
         this.notifOnFailure = notificationOnFailure;
-        this.types = notificationTypes;
+        this.types = workflowNotificationTypes;
+        this.methods = webhookNotificationMethods;
     }
 
     initNotif(): void {
@@ -94,6 +110,17 @@ export class WorkflowNotificationFormComponent {
         if (this.selectedUsers) {
             this.notification.settings.recipients = this.selectedUsers.split(',');
         }
+        if (this.notification.type === 'webhook') {
+            this.notification.settings.headers = {};
+            if (this.webhookHeaders) {
+                this.webhookHeaders.split('\n').forEach(line => {
+                    let i = line.indexOf(':');
+                    if (i > 0) {
+                        this.notification.settings.headers[line.substring(0, i).trim()] = line.substring(i + 1).trim();
+                    }
+                });
+            }
+        }
         this.updatedNotification.emit(this.notification);
     }
 }
@@ -44,6 +44,7 @@ export class ActionStepSummaryComponent implements OnInit {
       ], {queryParams: {
           stageId: this.stage.id,
           actionId: this.job.job.pipeline_action_id,
+          variant: this.job.job.matrix_variant || null,
           stepOrder: this.actionStatus.step_order,
           name: this.workflowNode.name,
       }});
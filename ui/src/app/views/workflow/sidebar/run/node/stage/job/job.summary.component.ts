@@ -46,6 +46,7 @@ export class JobStepSummaryComponent implements OnInit {
             queryParams: {
                 stageId: this.stage.id,
                 actionId: this.job.job.pipeline_action_id,
+                variant: this.job.job.matrix_variant || null,
                 selectedNodeRunId: this.job.workflow_node_run_id,
                 selectedNodeRunNum: this.workflowRun.num,
                 selectedNodeId: this._route.snapshot.queryParams['selectedNodeId'],